@@ -0,0 +1,163 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// API anahtarları api_keys_file'da tutulur; dosyada yalnız anahtarın SHA-256
+// özeti bulunur, düz metin yalnız CreateAPIKey'de bir kez döner. Dosya her
+// istekte mtime/boyuta bakılarak yeniden okunur (CLI ile eklenen/silinen anahtar
+// düğümü yeniden başlatmadan geçerli olur).
+
+var (
+	ErrAPIKeyExists   = errors.New("api key name already exists")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// APIKey: kayıtlı anahtar (düz metin değil)
+type APIKey struct {
+	Name       string    `json:"name"`
+	Hash       string    `json:"hash"` // sha256(anahtar) hex
+	Scopes     []string  `json:"scopes"`
+	RatePerMin int       `json:"rate_per_min,omitempty"` // 0 => api_rate_limit
+	Created    time.Time `json:"created"`
+}
+
+// keyStore: dosyadan önbelleğe alınmış anahtarlar
+type keyStore struct {
+	mu   sync.Mutex
+	path string
+	mod  time.Time
+	size int64
+	keys []APIKey
+}
+
+var keys keyStore
+
+// lookup: token'a ait anahtar (sabit zamanlı karşılaştırma)
+func (s *keyStore) lookup(path, token string) (APIKey, bool) {
+	if path == "" {
+		return APIKey{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fi, err := os.Stat(path); err != nil {
+		s.keys, s.mod, s.size = nil, time.Time{}, 0
+	} else if path != s.path || !fi.ModTime().Equal(s.mod) || fi.Size() != s.size {
+		if ks, err := loadAPIKeys(path); err == nil {
+			s.keys, s.mod, s.size = ks, fi.ModTime(), fi.Size()
+		}
+	}
+	s.path = path
+	sum := sha256.Sum256([]byte(token))
+	h := hex.EncodeToString(sum[:])
+	var found APIKey
+	ok := false
+	for _, k := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(k.Hash)) == 1 {
+			found, ok = k, true
+		}
+	}
+	return found, ok
+}
+
+func loadAPIKeys(path string) ([]APIKey, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []APIKey{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ks []APIKey
+	if err := json.Unmarshal(b, &ks); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ks, nil
+}
+
+func saveAPIKeys(path string, ks []APIKey) error {
+	b, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// CreateAPIKey: yeni anahtar üretip dosyaya ekler; düz metin anahtarı döner
+func CreateAPIKey(path, name string, scopes []string, ratePerMin int) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || path == "" {
+		return "", errors.New("api key name and api_keys_file required")
+	}
+	if len(scopes) == 0 {
+		return "", errors.New("at least one scope required")
+	}
+	for _, s := range scopes {
+		if !ValidScope(s) {
+			return "", fmt.Errorf("unknown scope %q (read, wallet, mining, admin)", s)
+		}
+	}
+	if ratePerMin < 0 {
+		return "", errors.New("rate cannot be negative")
+	}
+	ks, err := loadAPIKeys(path)
+	if err != nil {
+		return "", err
+	}
+	for _, k := range ks {
+		if k.Name == name {
+			return "", ErrAPIKeyExists
+		}
+	}
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := "qc_" + hex.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(token))
+	ks = append(ks, APIKey{
+		Name: name, Hash: hex.EncodeToString(sum[:]), Scopes: scopes,
+		RatePerMin: ratePerMin, Created: time.Now().UTC(),
+	})
+	return token, saveAPIKeys(path, ks)
+}
+
+// ListAPIKeys: kayıtlı anahtarlar, ada göre
+func ListAPIKeys(path string) ([]APIKey, error) {
+	ks, err := loadAPIKeys(path)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ks, func(i, j int) bool { return ks[i].Name < ks[j].Name })
+	return ks, nil
+}
+
+// RevokeAPIKey: anahtarı dosyadan siler
+func RevokeAPIKey(path, name string) error {
+	ks, err := loadAPIKeys(path)
+	if err != nil {
+		return err
+	}
+	for i, k := range ks {
+		if k.Name == name {
+			return saveAPIKeys(path, append(ks[:i], ks[i+1:]...))
+		}
+	}
+	return ErrAPIKeyNotFound
+}
@@ -0,0 +1,424 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"quantumcoin/config"
+)
+
+// HTTP API erişim denetimi (bkz. Protect). Her istek bir kimliğe (Principal)
+// çözülür; kimliğin kapsamları rotanın istediği kapsamı içermelidir:
+//
+//	read   -> zincir, mempool, explorer, havuz, WebSocket (varsayılan)
+//	wallet -> düğüm cüzdanıyla harcama/imza, stake, burn, PSBT, multisig
+//	mining -> blok üretimi ve şablon/iş uçları (solo)
+//	admin  -> madenci kontrolü, regtest, webhook yönetimi, pprof (hepsini içerir)
+//
+// Kimlik sırası: "Authorization: Bearer <anahtar>" ya da "X-API-Key" (apikey-new),
+// Basic "__cookie__:<token>" (api_cookie_file), Basic rpc_user/rpc_password
+// (admin). Kimliksiz loopback istemci api_loopback_scopes'u alır — ancak
+// Origin başlığı yoksa, aynı hosta aitse ya da cors_origins'te ise (başka
+// sitelerin tarayıcı üzerinden yerel düğüme istek atmasına karşı). Diğer
+// kimliksiz istemciler api_public_scopes'u alır.
+//
+// Tanınmayan kimlik ya da kimliksiz istemcide eksik kapsam 401, kimlikli
+// istemcide eksik kapsam 403 döner. read dışı rotalar denetim kaydına yazılır.
+
+// Kapsamlar
+const (
+	ScopeRead   = "read"
+	ScopeWallet = "wallet"
+	ScopeMining = "mining"
+	ScopeAdmin  = "admin"
+)
+
+// Kimlik türleri
+const (
+	KindKey       = "key"
+	KindCookie    = "cookie"
+	KindRPC       = "rpc"
+	KindLoopback  = "loopback"
+	KindAnonymous = "anonymous"
+)
+
+// CookieUser: cookie dosyasındaki Basic kullanıcı adı
+const CookieUser = "__cookie__"
+
+// Principal: isteğin çözülmüş kimliği
+type Principal struct {
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"`
+	Scopes []string `json:"scopes"`
+	rate   int      // dakikalık istek sınırı (0 => api_rate_limit)
+}
+
+// Has: kapsam verilmiş mi (admin hepsini içerir)
+func (p *Principal) Has(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticated: anahtar, cookie ya da RPC kimliğiyle gelmiş mi
+func (p *Principal) Authenticated() bool {
+	return p.Kind == KindKey || p.Kind == KindCookie || p.Kind == KindRPC
+}
+
+type principalKey struct{}
+
+// PrincipalFrom: Protect'in isteğe iliştirdiği kimlik (Protect dışında nil)
+func PrincipalFrom(r *http.Request) *Principal {
+	p, _ := r.Context().Value(principalKey{}).(*Principal)
+	return p
+}
+
+// routeScopes: rota -> gereken kapsam. "/" ile biten anahtar önek eşler.
+var routeScopes = map[string]string{
+	"/api/wallet/new":      ScopeWallet,
+	"/api/wallet/address":  ScopeWallet,
+	"/api/tx/send":         ScopeWallet,
+	"/api/tx/burn":         ScopeWallet,
+	"/api/burn":            ScopeWallet,
+	"/api/stake/start":     ScopeWallet,
+	"/api/stake/unstake":   ScopeWallet,
+	"/api/stake/withdraw":  ScopeWallet,
+	"/api/psbt/":           ScopeWallet,
+	"/api/multisig/new":    ScopeWallet,
+	"/api/mine":            ScopeMining,
+	"/api/mine/job":        ScopeMining,
+	"/api/mine/submit":     ScopeMining,
+	"/api/mining/":         ScopeMining,
+	"/api/miner/start":     ScopeAdmin,
+	"/api/miner/stop":      ScopeAdmin,
+	"/api/dev/fastmine":    ScopeAdmin,
+	"/api/game/score":      ScopeAdmin,
+	"/api/regtest/":        ScopeAdmin,
+	"/api/webhooks":        ScopeAdmin,
+	"/api/webhooks/":       ScopeAdmin,
+	"/api/admin/":          ScopeAdmin,
+	"/debug/pprof/":        ScopeAdmin, // ayrı dinleyici (pprof_addr; bkz. metrics.go)
+	"/api/miner/status":    ScopeRead,
+	"/api/multisig/list":   ScopeRead,
+	"/api/stake/status":    ScopeRead,
+	"/api/stake/history":   ScopeRead,
+	"/api/psbt/inspect":    ScopeRead,
+	"/api/wallet/balance/": ScopeRead,
+}
+
+// RouteScope: yolun istediği kapsam (tam eşleşme, sonra en uzun önek, yoksa read)
+func RouteScope(path string) string {
+	if s, ok := routeScopes[path]; ok {
+		return s
+	}
+	best, scope := 0, ScopeRead
+	for p, s := range routeScopes {
+		if strings.HasSuffix(p, "/") && strings.HasPrefix(path, p) && len(p) > best {
+			best, scope = len(p), s
+		}
+	}
+	return scope
+}
+
+// ValidScope: bilinen kapsam adı mı
+func ValidScope(s string) bool {
+	switch s {
+	case ScopeRead, ScopeWallet, ScopeMining, ScopeAdmin:
+		return true
+	}
+	return false
+}
+
+// authConfig: Init'ten gelen yapılandırma (yoksa varsayılan)
+func authConfig() *config.Config {
+	if cfg != nil {
+		return cfg
+	}
+	return config.Default()
+}
+
+var (
+	cookieOnce  sync.Once
+	cookieToken string
+	limiter     = newRateLimiter()
+	audit       auditLog
+)
+
+// Protect: h'yi CORS, kimlik/kapsam denetimi, hız sınırı ve denetim kaydıyla
+// sarar. İlk çağrıda api_cookie_file yazılır.
+func Protect(h http.Handler) http.Handler {
+	cookieOnce.Do(writeCookie)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := authConfig()
+		originOK := applyCORS(w, r, c)
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		start := time.Now()
+		scope := RouteScope(r.URL.Path)
+		p, ok := authenticate(r, c, originOK)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer observeRequest(h, r, rec, start)
+		if scope != ScopeRead {
+			defer func() { audit.record(c, p, r, scope, rec.status, start) }()
+		}
+		switch {
+		case !ok:
+			rec.deny(http.StatusUnauthorized, "invalid credentials")
+			return
+		case !p.Has(scope):
+			if p.Authenticated() {
+				rec.deny(http.StatusForbidden, "missing scope "+scope)
+			} else {
+				rec.deny(http.StatusUnauthorized, "scope "+scope+" requires an api key")
+			}
+			return
+		}
+		if retry, allowed := limiter.allow(p, r, c); !allowed {
+			rec.Header().Set("Retry-After", strconv.Itoa(int(retry.Seconds()+0.999)))
+			j(rec, http.StatusTooManyRequests, map[string]string{"error": "rate limit exceeded"})
+			return
+		}
+
+		h.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), principalKey{}, p)))
+	})
+}
+
+// authenticate: isteğin kimliği; ok=false => sunulan kimlik tanınmadı
+func authenticate(r *http.Request, c *config.Config, originOK bool) (*Principal, bool) {
+	token := strings.TrimSpace(r.Header.Get("X-API-Key"))
+	if auth := r.Header.Get("Authorization"); token == "" && len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		token = strings.TrimSpace(auth[7:])
+	}
+	if token != "" {
+		k, ok := keys.lookup(c.APIKeysFile, token)
+		if !ok {
+			return &Principal{Name: "invalid-key", Kind: KindAnonymous}, false
+		}
+		// anahtar, kimliksiz istemcinin kapsamlarını da taşır
+		scopes := append(scopeList(c.APIPublicScopes, config.Default().APIPublicScopes), k.Scopes...)
+		return &Principal{Name: k.Name, Kind: KindKey, Scopes: scopes, rate: k.RatePerMin}, true
+	}
+	if user, pass, ok := r.BasicAuth(); ok {
+		switch {
+		case user == CookieUser && cookieToken != "" &&
+			subtle.ConstantTimeCompare([]byte(pass), []byte(cookieToken)) == 1:
+			return &Principal{Name: CookieUser, Kind: KindCookie, Scopes: []string{ScopeAdmin}}, true
+		case c.RPCPassword != "" &&
+			subtle.ConstantTimeCompare([]byte(user), []byte(c.RPCUser)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(pass), []byte(c.RPCPassword)) == 1:
+			return &Principal{Name: user, Kind: KindRPC, Scopes: []string{ScopeAdmin}}, true
+		}
+		return &Principal{Name: user, Kind: KindAnonymous}, false
+	}
+	if originOK && isLoopback(r) {
+		return &Principal{Name: "loopback", Kind: KindLoopback, Scopes: scopeList(c.APILoopbackScopes, config.Default().APILoopbackScopes)}, true
+	}
+	return &Principal{Name: "anonymous", Kind: KindAnonymous, Scopes: scopeList(c.APIPublicScopes, config.Default().APIPublicScopes)}, true
+}
+
+// scopeList: "none" girdisini düşürür; boş liste => varsayılan
+func scopeList(in, def []string) []string {
+	if len(in) == 0 {
+		in = def
+	}
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s != "none" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// applyCORS: Origin izinliyse CORS başlıklarını yazar. Dönüş: Origin yok,
+// aynı hosta ait ya da izinli (loopback kapsamları için).
+func applyCORS(w http.ResponseWriter, r *http.Request, c *config.Config) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	w.Header().Add("Vary", "Origin")
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		return true
+	}
+	allowed := false
+	for _, o := range c.CORSOrigins {
+		if o == "*" || strings.EqualFold(strings.TrimRight(o, "/"), origin) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	return true
+}
+
+// isLoopback: istemci loopback adresinden mi bağlandı
+func isLoopback(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// clientIP: RemoteAddr'ın host kısmı
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// writeCookie: rastgele token üretip api_cookie_file'a "__cookie__:<token>" yazar (0600)
+func writeCookie() {
+	c := authConfig()
+	if c.APICookieFile == "" {
+		return
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		logger.Error("cookie: random token failed", "err", err)
+		return
+	}
+	token := hex.EncodeToString(buf)
+	if err := os.WriteFile(c.APICookieFile, []byte(CookieUser+":"+token), 0o600); err != nil {
+		logger.Error("cookie: write failed", "file", c.APICookieFile, "err", err)
+		return
+	}
+	cookieToken = token
+}
+
+// ReadCookie: cookie dosyasından Basic kullanıcı/parola (CLI istemcileri için)
+func ReadCookie(path string) (user, pass string, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	user, pass, _ = strings.Cut(strings.TrimSpace(string(b)), ":")
+	return user, pass, nil
+}
+
+// statusRecorder: denetim kaydı ve metrikler için yanıt kodunu yakalar
+// (WebSocket için Hijack, akış için Flush alttaki yazıcıya iletilir)
+type statusRecorder struct {
+	http.ResponseWriter
+	status   int
+	wrote    bool
+	hijacked bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wrote {
+		s.status, s.wrote = code, true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wrote = true
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		s.wrote = true
+		f.Flush()
+	}
+}
+
+// Unwrap: http.ResponseController için
+func (s *statusRecorder) Unwrap() http.ResponseWriter { return s.ResponseWriter }
+
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("api: response writer does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err == nil {
+		s.status, s.wrote, s.hijacked = http.StatusSwitchingProtocols, true, true
+	}
+	return conn, rw, err
+}
+
+// deny: JSON hata yazar; 401'de Basic/Bearer şemalarını bildirir
+func (s *statusRecorder) deny(code int, msg string) {
+	if code == http.StatusUnauthorized {
+		s.Header().Set("WWW-Authenticate", `Bearer realm="quantumcoin-api", Basic realm="quantumcoin-api"`)
+	}
+	j(s, code, map[string]string{"error": msg})
+}
+
+// auditLog: read dışı çağrıların JSON satır kaydı (api_audit_log; "" => log)
+type auditLog struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+type auditEntry struct {
+	Time      time.Time `json:"time"`
+	Principal string    `json:"principal"`
+	Kind      string    `json:"kind"`
+	IP        string    `json:"ip"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Scope     string    `json:"scope"`
+	Status    int       `json:"status"`
+	Millis    int64     `json:"ms"`
+}
+
+func (a *auditLog) record(c *config.Config, p *Principal, r *http.Request, scope string, status int, start time.Time) {
+	e := auditEntry{
+		Time: start.UTC(), Principal: p.Name, Kind: p.Kind, IP: clientIP(r),
+		Method: r.Method, Path: r.URL.Path, Scope: scope, Status: status,
+		Millis: time.Since(start).Milliseconds(),
+	}
+	if c.APIAuditLog == "" {
+		logger.Info("audit", "principal", e.Principal, "kind", e.Kind, "ip", e.IP,
+			"method", e.Method, "path", e.Path, "scope", e.Scope, "status", e.Status, "ms", e.Millis)
+		return
+	}
+	b, _ := json.Marshal(e)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.f == nil || a.path != c.APIAuditLog {
+		if a.f != nil {
+			a.f.Close()
+		}
+		f, err := os.OpenFile(c.APIAuditLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			a.f = nil
+			logger.Error("audit: open failed", "file", c.APIAuditLog, "err", err, "entry", string(b))
+			return
+		}
+		a.f, a.path = f, c.APIAuditLog
+	}
+	a.f.Write(append(b, '\n'))
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+)
+
+// RegisterBurnRoutes, yakım ve arz uçlarını mux'a ekler.
+//
+//	POST /api/burn { address, amount } -> { txid } | { tx, signingHashes }
+//	GET  /api/burns[?limit=]           -> yakım geçmişi (en yeni önce; varsayılan 100)
+//	GET  /api/supply                   -> basılan / yakılan / dolaşımdaki arz
+func RegisterBurnRoutes(mux Router) {
+	mux.HandleFunc("/api/burn", burnCreate)
+	mux.HandleFunc("/api/burns", burnHistory)
+	mux.HandleFunc("/api/supply", supplyStats)
+}
+
+var burnCreate = ownerTx(func(req ownerTxReq) (*blockchain.Transaction, error) {
+	return blockchain.NewBurnTransaction(req.Address, req.Amount, bc)
+})
+
+func burnHistory(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			j(w, http.StatusBadRequest, map[string]string{"error": "bad limit"})
+			return
+		}
+		limit = n
+	}
+	burns := bc.Burns(limit)
+	if burns == nil {
+		burns = []blockchain.BurnRecord{}
+	}
+	j(w, http.StatusOK, wire.BurnHistory{Supply: bc.Supply(), Burns: burns})
+}
+
+func supplyStats(w http.ResponseWriter, _ *http.Request) {
+	j(w, http.StatusOK, bc.Supply())
+}
@@ -0,0 +1,373 @@
+// Package client: QuantumCoin HTTP API için tipli Go istemcisi. Gövde tipleri
+// uçlarla aynı api/wire tipleridir; uç listesi ve şemalar için düğümün
+// GET /api/openapi.json belgesine bakın.
+//
+// Uçların bir kısmı yalnız düğümde (quantumcoin run/api), bir kısmı yalnız
+// api.StartHTTP sunucusunda (cmd/apitest) vardır; her yöntemin yorumunda
+// belirtilir. Ortak uçlar (explorer, mining, stake, burn, multisig) ikisinde de çalışır.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+)
+
+// Client: API istemcisi. APIKey verilirse "Authorization: Bearer", yoksa
+// Password verilirse Basic (rpc_user/rpc_password ya da __cookie__:<token>) gönderilir.
+type Client struct {
+	BaseURL  string // örn. http://127.0.0.1:8081
+	APIKey   string
+	User     string
+	Password string
+	HTTP     *http.Client
+}
+
+// New: baseURL için istemci ("8081", ":8081" ya da "host:port" da kabul edilir)
+func New(baseURL string) *Client {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	switch {
+	case baseURL == "":
+		baseURL = "http://127.0.0.1:8081"
+	case !strings.Contains(baseURL, ":"):
+		baseURL = "http://127.0.0.1:" + baseURL
+	case strings.HasPrefix(baseURL, ":"):
+		baseURL = "http://127.0.0.1" + baseURL
+	case !strings.Contains(baseURL, "://"):
+		baseURL = "http://" + baseURL
+	}
+	return &Client{BaseURL: baseURL, HTTP: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Error: 2xx dışı yanıt
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api: http %d", e.Status)
+	}
+	return fmt.Sprintf("api: http %d: %s", e.Status, e.Message)
+}
+
+// IsStatus: err belirtilen HTTP durumuyla dönen bir *Error mı
+func IsStatus(err error, status int) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Status == status
+}
+
+// Do: method path'e query ve in (JSON) ile istek atar, yanıtı out'a çözer
+// (out nil => gövde atılır; *string => düz metin)
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.APIKey != "":
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	case c.Password != "":
+		req.SetBasicAuth(c.User, c.Password)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var e wire.Error
+		msg := strings.TrimSpace(string(raw))
+		if json.Unmarshal(raw, &e) == nil {
+			if e.Error != "" {
+				msg = e.Error
+			} else if e.Message != "" {
+				msg = e.Message
+			}
+		}
+		return &Error{Status: resp.StatusCode, Message: msg}
+	}
+	switch o := out.(type) {
+	case nil:
+		return nil
+	case *string:
+		*o = string(raw)
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("api: %s %s: bad response: %w", method, path, err)
+	}
+	return nil
+}
+
+func get[T any](ctx context.Context, c *Client, path string, query url.Values) (*T, error) {
+	var out T
+	if err := c.Do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func post[T any](ctx context.Context, c *Client, path string, in any) (*T, error) {
+	var out T
+	if err := c.Do(ctx, http.MethodPost, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func vals(kv ...string) url.Values {
+	v := url.Values{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			v.Set(kv[i], kv[i+1])
+		}
+	}
+	return v
+}
+
+// ----- genel -----
+
+// Health: düğümde /api/health, api sunucusunda /health (orada /api/health web
+// arayüzüne düşer); ilki başarısızsa ikincisi denenir
+func (c *Client) Health(ctx context.Context) (*wire.Health, error) {
+	h, err := get[wire.Health](ctx, c, "/api/health", nil)
+	if err != nil && ctx.Err() == nil {
+		if h2, err2 := get[wire.Health](ctx, c, "/health", nil); err2 == nil {
+			return h2, nil
+		}
+	}
+	return h, err
+}
+
+// WaitReady: Health başarılı olana ya da ctx bitene kadar every aralıkla dener
+func (c *Client) WaitReady(ctx context.Context, every time.Duration) (*wire.Health, error) {
+	for {
+		h, err := c.Health(ctx)
+		if err == nil && h.OK {
+			return h, nil
+		}
+		select {
+		case <-ctx.Done():
+			if err == nil {
+				err = ctx.Err()
+			}
+			return nil, err
+		case <-time.After(every):
+		}
+	}
+}
+
+// OpenAPI: sunucunun OpenAPI belgesi
+func (c *Client) OpenAPI(ctx context.Context) (map[string]any, error) {
+	var out map[string]any
+	return out, c.Do(ctx, http.MethodGet, "/api/openapi.json", nil, nil, &out)
+}
+
+// ----- zincir / explorer -----
+
+// Block: yükseklik ya da hash ile blok (api sunucusu)
+func (c *Client) Block(ctx context.Context, id string) (*wire.Block, error) {
+	return get[wire.Block](ctx, c, "/api/block", vals("id", id))
+}
+
+// Mempool: bekleyen işlemler (api sunucusu)
+func (c *Client) Mempool(ctx context.Context) ([]wire.MempoolTx, error) {
+	var out []wire.MempoolTx
+	return out, c.Do(ctx, http.MethodGet, "/api/mempool", nil, nil, &out)
+}
+
+// Tx: işlem, konumu ve harcayanları
+func (c *Client) Tx(ctx context.Context, txid string) (*wire.TxView, error) {
+	return get[wire.TxView](ctx, c, "/api/tx/"+url.PathEscape(txid), nil)
+}
+
+// Address: adres özeti (tx_index)
+func (c *Client) Address(ctx context.Context, address string) (*blockchain.AddressSummary, error) {
+	return get[blockchain.AddressSummary](ctx, c, "/api/address/"+url.PathEscape(address), nil)
+}
+
+// AddressTxs: adresin işlemleri, yeniden eskiye (tx_index)
+func (c *Client) AddressTxs(ctx context.Context, address string, offset, limit int) (*wire.AddressTxs, error) {
+	return get[wire.AddressTxs](ctx, c, "/api/address/"+url.PathEscape(address)+"/txs",
+		vals("offset", strconv.Itoa(offset), "limit", strconv.Itoa(limit)))
+}
+
+// Search: yükseklik, blok hash'i, txid ya da adres çözümü
+func (c *Client) Search(ctx context.Context, query string) (*wire.SearchResult, error) {
+	return get[wire.SearchResult](ctx, c, "/api/search", vals("q", query))
+}
+
+// Supply: arz özeti
+func (c *Client) Supply(ctx context.Context) (*blockchain.SupplyStats, error) {
+	return get[blockchain.SupplyStats](ctx, c, "/api/supply", nil)
+}
+
+// Stats: zincir geneli toplamlar (tx_index)
+func (c *Client) Stats(ctx context.Context) (*wire.ChainStats, error) {
+	return get[wire.ChainStats](ctx, c, "/api/stats", nil)
+}
+
+// ----- cüzdan -----
+
+// AddressBalance: bakiye ve harcanabilir bakiye (api sunucusu)
+func (c *Client) AddressBalance(ctx context.Context, address string) (*wire.AddressBalance, error) {
+	return get[wire.AddressBalance](ctx, c, "/api/address/balance", vals("addr", address))
+}
+
+// Balance: bakiye (düğüm)
+func (c *Client) Balance(ctx context.Context, address string) (*wire.BalanceResponse, error) {
+	return get[wire.BalanceResponse](ctx, c, "/api/wallet/balance/"+url.PathEscape(address), nil)
+}
+
+// UTXOs: adresin harcanmamış çıktıları (api sunucusu)
+func (c *Client) UTXOs(ctx context.Context, address string) ([]wire.UTXO, error) {
+	var out []wire.UTXO
+	return out, c.Do(ctx, http.MethodGet, "/api/address/utxos", vals("addr", address), nil, &out)
+}
+
+// BuildTx: imzasız işlem ve imza hash'leri (api sunucusu)
+func (c *Client) BuildTx(ctx context.Context, req wire.BuildTxRequest) (*wire.UnsignedTx, error) {
+	return post[wire.UnsignedTx](ctx, c, "/api/tx/build", req)
+}
+
+// Broadcast: imzalı işlemi mempool'a ekler (api sunucusu)
+func (c *Client) Broadcast(ctx context.Context, tx wire.Tx) (*wire.BroadcastResult, error) {
+	return post[wire.BroadcastResult](ctx, c, "/api/tx/send", tx)
+}
+
+// TxStatus: onay durumu (api sunucusu)
+func (c *Client) TxStatus(ctx context.Context, txid string) (*wire.TxStatus, error) {
+	return get[wire.TxStatus](ctx, c, "/api/tx/status", vals("id", txid))
+}
+
+// Send: düğüm cüzdanından (ya da PrivHex ile) gönderim (düğüm). Success=false
+// yanıtları da 200 döner; neden Message alanındadır.
+func (c *Client) Send(ctx context.Context, req wire.SendRequest) (*wire.SendResponse, error) {
+	return post[wire.SendResponse](ctx, c, "/api/tx/send", req)
+}
+
+// NewWallet: düğüm deposunda yeni cüzdan (düğüm)
+func (c *Client) NewWallet(ctx context.Context) (*wire.WalletResponse, error) {
+	return post[wire.WalletResponse](ctx, c, "/api/wallet/new", nil)
+}
+
+// WalletAddress: düğümün varsayılan madenci adresi (düğüm)
+func (c *Client) WalletAddress(ctx context.Context) (*wire.WalletResponse, error) {
+	return get[wire.WalletResponse](ctx, c, "/api/wallet/address", nil)
+}
+
+// Burn, StakeStart, Unstake, Withdraw: adresin kendi işlemi; anahtar düğüm
+// cüzdanındaysa imzalanıp yayınlanır ({ txid }), değilse imzasız döner.
+func (c *Client) Burn(ctx context.Context, req wire.OwnerTxRequest) (*wire.OwnerTxResult, error) {
+	return post[wire.OwnerTxResult](ctx, c, "/api/burn", req)
+}
+
+func (c *Client) StakeStart(ctx context.Context, req wire.OwnerTxRequest) (*wire.OwnerTxResult, error) {
+	return post[wire.OwnerTxResult](ctx, c, "/api/stake/start", req)
+}
+
+func (c *Client) Unstake(ctx context.Context, req wire.OwnerTxRequest) (*wire.OwnerTxResult, error) {
+	return post[wire.OwnerTxResult](ctx, c, "/api/stake/unstake", req)
+}
+
+func (c *Client) Withdraw(ctx context.Context, req wire.OwnerTxRequest) (*wire.OwnerTxResult, error) {
+	return post[wire.OwnerTxResult](ctx, c, "/api/stake/withdraw", req)
+}
+
+// NewMultisig: m-of-n adres oluşturur
+func (c *Client) NewMultisig(ctx context.Context, req wire.MultisigRequest) (*wire.Multisig, error) {
+	return post[wire.Multisig](ctx, c, "/api/multisig/new", req)
+}
+
+// ----- madencilik -----
+
+// BlockTemplate: harici montaj için blok şablonu (bits 0 => düğüm varsayılanı)
+func (c *Client) BlockTemplate(ctx context.Context, address string, bits int) (*blockchain.BlockTemplate, error) {
+	q := vals("address", address)
+	if bits > 0 {
+		q.Set("bits", strconv.Itoa(bits))
+	}
+	return get[blockchain.BlockTemplate](ctx, c, "/api/mining/getblocktemplate", q)
+}
+
+// SubmitBlock: çözülmüş bloğu gönderir; bayat blok 409 (*Error) döner
+func (c *Client) SubmitBlock(ctx context.Context, s *blockchain.BlockSubmission) (*wire.SubmitBlockResult, error) {
+	return post[wire.SubmitBlockResult](ctx, c, "/api/mining/submitblock", s)
+}
+
+// Generate: anında count blok kazar (yalnız regtest)
+func (c *Client) Generate(ctx context.Context, count int, address string) (*wire.GenerateResult, error) {
+	return post[wire.GenerateResult](ctx, c, "/api/regtest/generate", wire.GenerateRequest{Count: count, Address: address})
+}
+
+// MineJob / MineSubmit: web madenci işi ve çözümü (düğüm)
+func (c *Client) MineJob(ctx context.Context, address string) (*wire.MineJob, error) {
+	return get[wire.MineJob](ctx, c, "/api/mine/job", vals("address", address))
+}
+
+func (c *Client) MineSubmit(ctx context.Context, jobID string, nonce uint64) (*wire.MineSubmitResult, error) {
+	return post[wire.MineSubmitResult](ctx, c, "/api/mine/submit", wire.WorkSubmit{JobID: jobID, Nonce: nonce})
+}
+
+// MineBlock: düğümde tek blok kazar (düğüm)
+func (c *Client) MineBlock(ctx context.Context, address string) (*wire.MineResult, error) {
+	return post[wire.MineResult](ctx, c, "/api/mine", wire.AddressRequest{Address: address})
+}
+
+// MinerStart, MinerStop, MinerStatus: düğüm içi madenci kontrolü (düğüm)
+func (c *Client) MinerStart(ctx context.Context, address string) (*wire.MinerState, error) {
+	return post[wire.MinerState](ctx, c, "/api/miner/start", wire.AddressRequest{Address: address})
+}
+
+func (c *Client) MinerStop(ctx context.Context) (*wire.MinerState, error) {
+	return post[wire.MinerState](ctx, c, "/api/miner/stop", nil)
+}
+
+func (c *Client) MinerStatus(ctx context.Context) (*wire.MinerStatus, error) {
+	return get[wire.MinerStatus](ctx, c, "/api/miner/status", nil)
+}
+
+// LogLevels / SetLogLevel: alt sistem log seviyeleri (admin; subsystem "all" => hepsi)
+func (c *Client) LogLevels(ctx context.Context) (*wire.LogLevels, error) {
+	return get[wire.LogLevels](ctx, c, "/api/admin/loglevel", nil)
+}
+
+func (c *Client) SetLogLevel(ctx context.Context, subsystem, level string) (*wire.LogLevels, error) {
+	return post[wire.LogLevels](ctx, c, "/api/admin/loglevel", wire.SetLogLevelRequest{Subsystem: subsystem, Level: level})
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+	"quantumcoin/wallet"
+)
+
+// RegisterExplorerRoutes, indeks destekli explorer uçlarını mux'a ekler.
+// Adres, zengin listesi ve istatistik uçları tx_index açık olmalıdır (aksi
+// halde 503); işlem, arama ve arz uçları indeks yoksa zinciri tarar.
+//
+//	GET /api/tx/{txid}                            -> işlem, blok/konum, onay, çıktıları harcayanlar
+//	GET /api/address/{addr}                       -> bakiye, alınan/gönderilen, işlem sayısı
+//	GET /api/address/{addr}/txs?offset=&limit=    -> adresin işlemleri (yeniden eskiye)
+//	GET /api/search?q=                            -> yükseklik | blok hash | txid | adres çözümü
+//	GET /api/richlist?limit=                      -> en çok varlığı olan adresler
+//	GET /api/supply/{minted|circulating|burned|premine|max} -> düz metin sayı
+//	GET /api/stats                                -> zincir geneli toplamlar
+//	GET /api/stats/daily?days=                    -> günlük aralık/zorluk/işlem/ücret serisi
+func RegisterExplorerRoutes(mux Router) {
+	mux.HandleFunc("/api/tx/", explorerTx)
+	mux.HandleFunc("/api/address/", explorerAddress)
+	mux.HandleFunc("/api/search", explorerSearch)
+	mux.HandleFunc("/api/richlist", explorerRichList)
+	mux.HandleFunc("/api/supply/", explorerSupplyValue)
+	mux.HandleFunc("/api/stats", explorerStats)
+	mux.HandleFunc("/api/stats/daily", explorerDaily)
+}
+
+const (
+	explorerPageDefault = 25
+	explorerPageMax     = 100
+)
+
+// explorerIndex: indeks kapalıysa 503 yazıp nil döner
+func explorerIndex(w http.ResponseWriter) *blockchain.Index {
+	ix := bc.Index()
+	if ix == nil {
+		j(w, http.StatusServiceUnavailable, map[string]string{"error": "chain index disabled (set tx_index)"})
+	}
+	return ix
+}
+
+func explorerTx(w http.ResponseWriter, r *http.Request) {
+	id := strings.ToLower(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/tx/"), "/"))
+	txid, err := hex.DecodeString(id)
+	if err != nil || len(txid) == 0 {
+		j(w, http.StatusBadRequest, map[string]string{"error": "bad txid"})
+		return
+	}
+	tx, height, ok := bc.FindTransaction(txid)
+	if blk := bc.GetBlockByIndex(height); ok && blk != nil {
+		v := wire.TxView{
+			Tx: mapTxToDTO(tx), Status: "confirmed", Height: height,
+			BlockHash: hex.EncodeToString(blk.Hash), Confirmations: bc.GetBestHeight() - height + 1,
+		}
+		for pos, btx := range blk.Transactions {
+			if btx == tx {
+				v.Position = pos
+			}
+		}
+		if ix := bc.Index(); ix != nil {
+			v.SpentBy = make([]string, len(tx.Outputs))
+			for n := range tx.Outputs {
+				if s, ok := ix.Spender(id, n); ok {
+					v.SpentBy[n] = s.TxID + ":" + strconv.Itoa(s.Input)
+				}
+			}
+		}
+		j(w, http.StatusOK, v)
+		return
+	}
+	for _, tx := range bc.PendingTxs() {
+		if hex.EncodeToString(tx.ID) == id {
+			j(w, http.StatusOK, wire.TxView{Tx: mapTxToDTO(tx), Status: "mempool", Height: -1})
+			return
+		}
+	}
+	j(w, http.StatusNotFound, map[string]string{"error": "transaction not found"})
+}
+
+func explorerAddress(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/address/"), "/")
+	addr, sub, _ := strings.Cut(rest, "/")
+	if _, _, err := wallet.DecodeAddress(addr); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "address: " + err.Error()})
+		return
+	}
+	ix := explorerIndex(w)
+	if ix == nil {
+		return
+	}
+	switch sub {
+	case "":
+		j(w, http.StatusOK, ix.Address(addr))
+	case "txs":
+		q := r.URL.Query()
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		if limit <= 0 {
+			limit = explorerPageDefault
+		}
+		limit = min(limit, explorerPageMax)
+		offset = max(offset, 0)
+		txs, total := ix.AddressTxs(addr, offset, limit)
+		j(w, http.StatusOK, wire.AddressTxs{Address: addr, Total: total, Offset: offset, Limit: limit, Txs: txs})
+	default:
+		j(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
+
+// explorerSearch: sorguyu sırayla yükseklik, blok hash'i, txid (zincir ve
+// mempool) ve adres olarak çözer
+func explorerSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		j(w, http.StatusBadRequest, map[string]string{"error": "missing q"})
+		return
+	}
+	blockResult := func(b *blockchain.Block) wire.SearchResult {
+		hash, height := hex.EncodeToString(b.Hash), b.Index
+		return wire.SearchResult{Query: q, Type: "block", Height: &height, Hash: hash, Path: "/api/block?id=" + hash}
+	}
+	if h, err := strconv.Atoi(q); err == nil {
+		if b := bc.GetBlockByIndex(h); b != nil {
+			j(w, http.StatusOK, blockResult(b))
+			return
+		}
+	}
+	if raw, err := hex.DecodeString(strings.ToLower(q)); err == nil && len(raw) > 0 {
+		if b := bc.GetBlockByHash(raw); b != nil {
+			j(w, http.StatusOK, blockResult(b))
+			return
+		}
+		id := hex.EncodeToString(raw)
+		found := false
+		if _, _, ok := bc.FindTransaction(raw); ok {
+			found = true
+		}
+		for _, tx := range bc.PendingTxs() {
+			found = found || hex.EncodeToString(tx.ID) == id
+		}
+		if found {
+			j(w, http.StatusOK, wire.SearchResult{Query: q, Type: "tx", TxID: id, Path: "/api/tx/" + id})
+			return
+		}
+	}
+	if _, _, err := wallet.DecodeAddress(q); err == nil {
+		j(w, http.StatusOK, wire.SearchResult{Query: q, Type: "address", Address: q, Path: "/api/address/" + q})
+		return
+	}
+	j(w, http.StatusNotFound, map[string]string{"error": "no block, transaction or address matches " + q})
+}
+
+func explorerRichList(w http.ResponseWriter, r *http.Request) {
+	ix := explorerIndex(w)
+	if ix == nil {
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = explorerPageMax
+	}
+	j(w, http.StatusOK, ix.RichList(min(limit, 1000)))
+}
+
+// explorerSupplyValue: arz kalemini düz metin döner (liste siteleri için)
+func explorerSupplyValue(w http.ResponseWriter, r *http.Request) {
+	s := bc.Supply()
+	values := map[string]int{
+		"minted":      s.Minted,
+		"circulating": s.Circulating,
+		"burned":      s.Burned,
+		"premine":     s.Premine,
+		"max":         s.MaxSupply,
+	}
+	v, ok := values[strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/supply/"), "/")]
+	if !ok {
+		j(w, http.StatusNotFound, map[string]string{"error": "unknown supply field (minted, circulating, burned, premine, max)"})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(strconv.Itoa(v)))
+}
+
+func explorerStats(w http.ResponseWriter, _ *http.Request) {
+	ix := explorerIndex(w)
+	if ix == nil {
+		return
+	}
+	difficulty := 0
+	if last := bc.GetLastBlock(); last != nil {
+		difficulty = last.Difficulty
+	}
+	j(w, http.StatusOK, wire.ChainStats{ChainTotals: ix.Totals(), Difficulty: difficulty, Mempool: len(bc.PendingTxs()), Supply: bc.Supply()})
+}
+
+func explorerDaily(w http.ResponseWriter, r *http.Request) {
+	ix := explorerIndex(w)
+	if ix == nil {
+		return
+	}
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days <= 0 {
+		days = 30
+	}
+	j(w, http.StatusOK, ix.Daily(days))
+}
@@ -6,19 +6,23 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"quantumcoin/api/wire"
 	"quantumcoin/blockchain"
 	"quantumcoin/config"
-	"quantumcoin/miner"  // pasif; endpoint'ler duruyor
-	"quantumcoin/wallet" // address decode/utxo filtre
-	"quantumcoin/webui"  // gömülü web arayüz
+	"quantumcoin/logging"
+	"quantumcoin/miner" // solo web madenci işleri
+	"quantumcoin/webui" // gömülü web arayüz
 )
 
 var (
 	bc  *blockchain.Blockchain
 	cfg *config.Config
+
+	logger = logging.For(logging.SubAPI)
 )
 
 // Init: API katmanına bağımlılıkları enjekte et
@@ -50,35 +54,14 @@ func resolveHTTPAddr(addr string) string {
 	return addr
 }
 
-// —————————————————————————————————————————————
-// Basit CORS sarmalayıcı (Web Miner & Web Cüzdan)
-func withCORS(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET,POST,OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-		h.ServeHTTP(w, r)
-	})
-}
-
 // —————————————————————————————————————————————
 
 func health(w http.ResponseWriter, _ *http.Request) {
-	type resp struct {
-		OK      bool   `json:"ok"`
-		Version string `json:"version"`
-		Time    string `json:"time"`
-		Height  int    `json:"height"`
-	}
 	h := 0
 	if bc != nil {
 		h = bc.GetBestHeight()
 	}
-	j(w, http.StatusOK, resp{
+	j(w, http.StatusOK, wire.Health{
 		OK:      true,
 		Version: "api.v1",
 		Time:    time.Now().Format(time.RFC3339),
@@ -88,22 +71,16 @@ func health(w http.ResponseWriter, _ *http.Request) {
 
 // minimal explorer uçları
 func listBlocks(w http.ResponseWriter, r *http.Request) {
-	type blockMeta struct {
-		Index    int    `json:"index"`
-		Hash     string `json:"hash"`
-		PrevHash string `json:"prevHash"`
-		Miner    string `json:"miner"`
-		TxCount  int    `json:"txCount"`
-	}
 	if bc == nil {
-		j(w, http.StatusOK, []blockMeta{})
+		j(w, http.StatusOK, []wire.BlockSummary{})
 		return
 	}
 	// yeni→eski
-	res := make([]blockMeta, 0, len(bc.Blocks))
-	for i := len(bc.Blocks) - 1; i >= 0; i-- {
-		b := bc.Blocks[i]
-		res = append(res, blockMeta{
+	blocks := bc.GetAllBlocks()
+	res := make([]wire.BlockSummary, 0, len(blocks))
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		res = append(res, wire.BlockSummary{
 			Index:    b.Index,
 			Hash:     hex.EncodeToString(b.Hash),
 			PrevHash: hex.EncodeToString(b.PrevHash),
@@ -115,42 +92,41 @@ func listBlocks(w http.ResponseWriter, r *http.Request) {
 }
 
 func getBlock(w http.ResponseWriter, r *http.Request) {
-	// /api/block?id=<hashOrHeight> (şimdilik stub)
-	id := r.URL.Query().Get("id")
+	// /api/block?id=<hashOrHeight>
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
 	if id == "" {
 		j(w, http.StatusBadRequest, map[string]string{"error": "missing id"})
 		return
 	}
-	j(w, http.StatusNotFound, map[string]string{"error": "not implemented yet"})
+	var blk *blockchain.Block
+	if h, err := strconv.Atoi(id); err == nil {
+		blk = bc.GetBlockByIndex(h)
+	} else if hash, err := hex.DecodeString(id); err == nil {
+		blk = bc.GetBlockByHash(hash)
+	}
+	if blk == nil {
+		j(w, http.StatusNotFound, map[string]string{"error": "block not found"})
+		return
+	}
+	j(w, http.StatusOK, rpcBlockView(blk, true))
 }
 
 func listMempool(w http.ResponseWriter, r *http.Request) {
-	type txMeta struct {
-		ID       string  `json:"id"`
-		Sender   string  `json:"sender"`
-		Amount   float64 `json:"amount"`
-		InCount  int     `json:"inputs"`
-		OutCount int     `json:"outputs"`
-		Time     string  `json:"time"`
-		Verifies bool    `json:"verifies"`
-		Coinbase bool    `json:"coinbase"`
-	}
-
 	if bc == nil {
-		j(w, http.StatusOK, []txMeta{})
+		j(w, http.StatusOK, []wire.MempoolTx{})
 		return
 	}
-	out := make([]txMeta, 0, len(bc.PendingTxs()))
+	out := make([]wire.MempoolTx, 0, len(bc.PendingTxs()))
 	for _, tx := range bc.PendingTxs() {
 		tm := tx.Timestamp.UTC().Format(time.RFC3339)
-		out = append(out, txMeta{
+		out = append(out, wire.MempoolTx{
 			ID:       hex.EncodeToString(tx.ID),
 			Sender:   tx.Sender,
 			Amount:   tx.Amount,
 			InCount:  len(tx.Inputs),
 			OutCount: len(tx.Outputs),
 			Time:     tm,
-			Verifies: tx.Verify(),
+			Verifies: bc.VerifyTransaction(tx),
 			Coinbase: tx.IsCoinbase(),
 		})
 	}
@@ -158,50 +134,48 @@ func listMempool(w http.ResponseWriter, r *http.Request) {
 }
 
 // —————————————————————————————————————————————
-// Web Miner uçları (pasif — sadece mevcut dursun)
-type webMineGetResp struct {
-	Challenge  string `json:"challenge"`
-	Difficulty int    `json:"difficulty"`
-}
-type webMinePostReq struct {
-	Address   string `json:"address"`
-	Challenge string `json:"challenge"`
-	Nonce     uint32 `json:"nonce"`
-	Hash      string `json:"hash,omitempty"`
-}
-type webMinePostResp struct {
-	Accepted  bool   `json:"accepted"`
-	Hash      string `json:"hash"`
-	Message   string `json:"message,omitempty"`
-	Rewarded  bool   `json:"rewarded,omitempty"`
-	BlockHash string `json:"blockHash,omitempty"`
-}
+// Web Miner uçları: havuz açıkken pool.go uçlarına, kapalıyken WebJobs'a (solo) yönlenir.
+//
+//	GET  /api/mine?address=         -> iş (left/right hex, bits, nonceFormat "be64")
+//	POST /api/mine { jobId, nonce } -> { accepted, hash, message }
+
+// WebJobs: solo web madenci işleri (main.go atar; nil => web madenci kapalı)
+var WebJobs *miner.WebJobs
 
 func webMineHandler(w http.ResponseWriter, r *http.Request) {
+	// Havuz modu: gerçek blok adayına dayalı iş + pay muhasebesi (bkz. pool.go)
+	if Pool != nil {
+		switch r.Method {
+		case http.MethodGet:
+			poolJob(w, r)
+			return
+		case http.MethodPost:
+			poolSubmit(w, r)
+			return
+		}
+	}
+	if WebJobs == nil && r.Method != http.MethodOptions {
+		j(w, http.StatusServiceUnavailable, map[string]string{"error": "web mining disabled"})
+		return
+	}
 	switch r.Method {
 	case http.MethodGet:
-		ch, diff := miner.CurrentWebChallenge()
-		j(w, http.StatusOK, webMineGetResp{Challenge: ch, Difficulty: diff})
+		job, err := WebJobs.NewJob(r.URL.Query().Get("address"))
+		if err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		j(w, http.StatusOK, job)
 		return
 
 	case http.MethodPost:
-		var req webMinePostReq
+		var req wire.WorkSubmit
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
 			return
 		}
-		ok, hashHex := miner.VerifyWebSolution(req.Challenge, req.Nonce, miner.WebDifficulty)
-		if !ok {
-			j(w, http.StatusOK, webMinePostResp{Accepted: false, Hash: hashHex, Message: "invalid or below difficulty"})
-			return
-		}
-		// Şimdilik ödül entegrasyonu yok (pasif)
-		j(w, http.StatusOK, webMinePostResp{
-			Accepted: true,
-			Hash:     hashHex,
-			Message:  "accepted (stub)",
-			Rewarded: false,
-		})
+		// Reddedilen çözümler de 200 döner; neden Message alanındadır
+		j(w, http.StatusOK, WebJobs.Submit(req.JobID, req.Nonce))
 		return
 
 	case http.MethodOptions:
@@ -222,12 +196,10 @@ func getAddressBalance(w http.ResponseWriter, r *http.Request) {
 		j(w, http.StatusBadRequest, map[string]string{"error": "missing addr"})
 		return
 	}
-	bal := bc.GetBalance(addr)
-	spendable := bc.GetSpendableBalance(addr)
-	j(w, http.StatusOK, map[string]any{
-		"address":   addr,
-		"balance":   bal,
-		"spendable": spendable,
+	j(w, http.StatusOK, wire.AddressBalance{
+		Address:   addr,
+		Balance:   bc.GetBalance(addr),
+		Spendable: bc.GetSpendableBalance(addr),
 	})
 }
 
@@ -238,40 +210,20 @@ func getAddressUTXOs(w http.ResponseWriter, r *http.Request) {
 		j(w, http.StatusBadRequest, map[string]string{"error": "missing addr"})
 		return
 	}
-	pkh := wallet.Base58DecodeAddress(addr)
-	type utxoItem struct {
-		TxID string `json:"txid"`
-		N    int    `json:"n"`
-		Amt  int    `json:"amount"`
-	}
-	var out []utxoItem
-	for txID, outs := range bc.UTXO {
-		for i, o := range outs {
-			if o.IsLockedWithKey(pkh) {
-				out = append(out, utxoItem{TxID: txID, N: i, Amt: o.Amount})
-			}
-		}
+	out := []wire.UTXO{}
+	for _, u := range bc.UnspentOutputs(addr) {
+		out = append(out, wire.UTXO{TxID: u.TxID, N: u.Index, Amount: u.Amount})
 	}
 	j(w, http.StatusOK, out)
 }
 
 // ----- DTO Katmanı (hex-string ile konuşmak için) -----
 
-type txInDTO struct {
-	TxID      string `json:"txid"` // hex
-	OutIndex  int    `json:"n"`
-	Signature string `json:"signature,omitempty"` // hex(encodeSig)
-	PubKey    string `json:"pubKey,omitempty"`    // hex(65B)
-}
-
-type txDTO struct {
-	ID        string                         `json:"id,omitempty"` // hex
-	Inputs    []txInDTO                      `json:"inputs"`
-	Outputs   []blockchain.TransactionOutput `json:"outputs"`
-	Timestamp string                         `json:"timestamp"` // RFC3339
-	Sender    string                         `json:"sender"`
-	Amount    float64                        `json:"amount"`
-}
+// txDTO / txInDTO: gövde tipleri api/wire'da (OpenAPI ve istemciyle ortak)
+type (
+	txDTO   = wire.Tx
+	txInDTO = wire.TxInput
+)
 
 func mapTxToDTO(tx *blockchain.Transaction) txDTO {
 	d := txDTO{
@@ -281,6 +233,7 @@ func mapTxToDTO(tx *blockchain.Transaction) txDTO {
 		Timestamp: tx.Timestamp.UTC().Format(time.RFC3339),
 		Sender:    tx.Sender,
 		Amount:    tx.Amount,
+		Version:   tx.Version,
 	}
 	for i, in := range tx.Inputs {
 		var sigHex, pubHex string
@@ -296,6 +249,13 @@ func mapTxToDTO(tx *blockchain.Transaction) txDTO {
 			Signature: sigHex,
 			PubKey:    pubHex,
 		}
+		if len(in.Redeem) > 0 {
+			d.Inputs[i].Redeem = hex.EncodeToString(in.Redeem)
+			d.Inputs[i].Sigs = make([]string, len(in.Sigs))
+			for k, s := range in.Sigs {
+				d.Inputs[i].Sigs[k] = hex.EncodeToString(s)
+			}
+		}
 	}
 	return d
 }
@@ -306,6 +266,7 @@ func mapDTOToTx(d txDTO) (*blockchain.Transaction, error) {
 		Outputs: d.Outputs,
 		Sender:  d.Sender,
 		Amount:  d.Amount,
+		Version: d.Version,
 	}
 	// ID
 	if d.ID != "" {
@@ -352,6 +313,22 @@ func mapDTOToTx(d txDTO) (*blockchain.Transaction, error) {
 			Signature: sigb,
 			PubKey:    pubb,
 		}
+		if in.Redeem != "" {
+			rb, err := hex.DecodeString(in.Redeem)
+			if err != nil {
+				return nil, fmt.Errorf("bad redeem hex: %w", err)
+			}
+			tx.Inputs[i].Redeem = rb
+			tx.Inputs[i].Sigs = make([][]byte, len(in.Sigs))
+			for k, sh := range in.Sigs {
+				if tx.Inputs[i].Sigs[k], err = hex.DecodeString(sh); err != nil {
+					return nil, fmt.Errorf("bad sigs hex: %w", err)
+				}
+			}
+		}
+	}
+	if err := tx.CheckSignatureEncodings(); err != nil {
+		return nil, err
 	}
 	return tx, nil
 }
@@ -364,11 +341,7 @@ func buildUnsignedTx(w http.ResponseWriter, r *http.Request) {
 		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
 		return
 	}
-	var req struct {
-		From   string `json:"from"`
-		To     string `json:"to"`
-		Amount int    `json:"amount"`
-	}
+	var req wire.BuildTxRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
 		return
@@ -382,13 +355,28 @@ func buildUnsignedTx(w http.ResponseWriter, r *http.Request) {
 		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
 	}
-	hashes := blockchain.SigningHashes(tx)
+	if req.Version == 0 {
+		req.Version = blockchain.TxVersionSegregated
+	}
+	if err := tx.SetVersion(req.Version); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	hashes, err := signingHashes(tx)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	j(w, http.StatusOK, wire.UnsignedTx{Tx: mapTxToDTO(tx), SigningHashes: hashes})
+}
 
-	type resp struct {
-		Tx            txDTO    `json:"tx"`
-		SigningHashes []string `json:"signingHashes"`
+// signingHashes: harici imzalayan için input özetleri (harcanan çıktılar zincirden)
+func signingHashes(tx *blockchain.Transaction) ([]string, error) {
+	prevs, err := bc.SpentOutputs(tx)
+	if err != nil {
+		return nil, err
 	}
-	j(w, http.StatusOK, resp{Tx: mapTxToDTO(tx), SigningHashes: hashes})
+	return blockchain.SigningHashes(tx, prevs)
 }
 
 // POST /api/tx/send — imzalı tx’i mempool’a ekler (coinbase hariç Verify şart)
@@ -413,7 +401,7 @@ func sendTx(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !tx.IsCoinbase() && !tx.Verify() {
+	if !bc.VerifyTransaction(tx) {
 		j(w, http.StatusBadRequest, map[string]string{"error": "invalid tx signature"})
 		return
 	}
@@ -421,10 +409,7 @@ func sendTx(w http.ResponseWriter, r *http.Request) {
 		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
 	}
-	j(w, http.StatusOK, map[string]any{
-		"accepted": true,
-		"id":       hex.EncodeToString(tx.ID),
-	})
+	j(w, http.StatusOK, wire.BroadcastResult{Accepted: true, ID: hex.EncodeToString(tx.ID)})
 }
 
 // GET /api/tx/status?id=<hex>
@@ -434,42 +419,36 @@ func getTxStatus(w http.ResponseWriter, r *http.Request) {
 		j(w, http.StatusBadRequest, map[string]string{"error": "missing id"})
 		return
 	}
-	inBlock := false
-	if bc != nil {
-	outer:
-		for _, b := range bc.Blocks {
-			for _, tx := range b.Transactions {
-				if hex.EncodeToString(tx.ID) == id {
-					inBlock = true
-					break outer
-				}
-			}
-		}
+	txid, err := hex.DecodeString(id)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "bad id"})
+		return
+	}
+	res := wire.TxStatus{ID: id}
+	if _, height, ok := bc.FindTransaction(txid); ok {
+		res.InBlock, res.Height = true, &height
+		res.Confirmations = bc.GetBestHeight() - height + 1
 	}
-	inMempool := false
 	for _, tx := range bc.PendingTxs() {
 		if hex.EncodeToString(tx.ID) == id {
-			inMempool = true
+			res.InMempool = true
 			break
 		}
 	}
-	j(w, http.StatusOK, map[string]any{
-		"id":        id,
-		"inBlock":   inBlock,
-		"inMempool": inMempool,
-	})
+	j(w, http.StatusOK, res)
 }
 
 // —————————————————————————————————————————————
 
-func StartHTTP(addr string) error {
-	if bc == nil {
-		return fmt.Errorf("api not initialized: call api.Init first")
-	}
-	addr = resolveHTTPAddr(addr)
-
+// NewMux: StartHTTP'nin tüm rotaları (web arayüzü dahil); Protect ile sarılmamış
+func NewMux() *http.ServeMux {
 	mux := http.NewServeMux()
+	registerServerRoutes(mux)
+	return mux
+}
 
+// registerServerRoutes: NewMux'ın rotaları (ServerOpenAPIOperations ile belgelenir)
+func registerServerRoutes(mux Router) {
 	// API route'lar
 	mux.HandleFunc("/health", health)
 	mux.HandleFunc("/api/blocks", listBlocks)
@@ -480,16 +459,42 @@ func StartHTTP(addr string) error {
 	mux.HandleFunc("/api/tx/build", buildUnsignedTx)
 	mux.HandleFunc("/api/tx/send", sendTx)
 	mux.HandleFunc("/api/tx/status", getTxStatus)
-	mux.HandleFunc("/api/mine", webMineHandler) // pasif
+	mux.HandleFunc("/api/mine", webMineHandler) // havuz ya da solo web işleri
+	RegisterPSBTRoutes(mux)
+	RegisterPoolRoutes(mux)
+	RegisterMultisigRoutes(mux)
+	RegisterMiningRoutes(mux)
+	RegisterRegtestRoutes(mux)
+	RegisterStakeRoutes(mux)
+	RegisterBurnRoutes(mux)
+	RegisterRPCRoutes(mux)
+	RegisterWSRoutes(mux)
+	RegisterWebhookRoutes(mux)
+	RegisterExplorerRoutes(mux)
+	RegisterMetricsRoute(mux)
+	RegisterLogRoutes(mux)
+	RegisterOpenAPIRoute(mux, ServerOpenAPIOperations())
 
 	// ⤵️ Web UI (embed) — en sonda mount et
 	if h, err := webui.Handler(); err == nil {
 		mux.Handle("/", h) // / ve gerisi -> web cüzdan (SPA)
 	}
+}
+
+// ServerOpenAPIOperations: NewMux'ın belgelediği uçlar (Operations + ServerOperations)
+func ServerOpenAPIOperations() []Operation {
+	return append(append([]Operation{}, Operations...), ServerOperations...)
+}
+
+func StartHTTP(addr string) error {
+	if bc == nil {
+		return fmt.Errorf("api not initialized: call api.Init first")
+	}
+	addr = resolveHTTPAddr(addr)
 
 	srv := &http.Server{
 		Addr:              addr,
-		Handler:           withCORS(mux),
+		Handler:           Protect(NewMux()), // CORS + kimlik/kapsam + hız sınırı (auth.go)
 		ReadTimeout:       5 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
 		WriteTimeout:      10 * time.Second,
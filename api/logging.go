@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/logging"
+)
+
+// RegisterLogRoutes, log seviyelerini çalışırken okuma/değiştirme ucunu ekler
+// (admin kapsamı). Değişiklik yalnız bellekte kalır; kalıcı ayar log_level'dır.
+//
+//	GET  /api/admin/loglevel                          -> { levels }
+//	POST /api/admin/loglevel  { subsystem, level }    -> { levels }
+func RegisterLogRoutes(mux Router) {
+	mux.HandleFunc("/api/admin/loglevel", logLevel)
+}
+
+func logLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req wire.SetLogLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+			return
+		}
+		if err := logging.SetLevel(req.Subsystem, req.Level); err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		by := ""
+		if p := PrincipalFrom(r); p != nil {
+			by = p.Name
+		}
+		logger.Info("log level changed", "subsystem", req.Subsystem, "new_level", req.Level, "by", by)
+	default:
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	j(w, http.StatusOK, wire.LogLevels{Levels: logging.Levels()})
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"time"
+
+	"quantumcoin/metrics"
+)
+
+// HTTP metrikleri (Protect her isteği kaydeder). route etiketi mux deseni
+// olduğundan yol parametreleri (txid, adres) sayıyı şişirmez.
+var (
+	mHTTPRequests = metrics.NewCounterVec("qc_http_requests_total", "HTTP API requests by route, method and status code", "route", "method", "code")
+	mHTTPDuration = metrics.NewHistogramVec("qc_http_request_duration_seconds", "HTTP API request latency (WebSocket sessions excluded)", nil, "route")
+	mHTTPInFlight = metrics.NewGauge("qc_http_requests_in_flight", "HTTP API requests being served")
+)
+
+// RegisterMetricsRoute: GET /metrics (Prometheus metin biçimi; read kapsamı)
+func RegisterMetricsRoute(mux Router) {
+	mux.Handle("/metrics", metrics.Handler())
+}
+
+func observeRequest(h http.Handler, r *http.Request, rec *statusRecorder, start time.Time) {
+	route := routeLabel(h, r)
+	mHTTPRequests.With(route, methodLabel(r.Method), strconv.Itoa(rec.status)).Inc()
+	if !rec.hijacked {
+		mHTTPDuration.With(route).ObserveSince(start)
+	}
+}
+
+// routeLabel: isteği karşılayan mux deseni ("/" => web arayüzü); mux değilse "other"
+func routeLabel(h http.Handler, r *http.Request) string {
+	mux, ok := h.(*http.ServeMux)
+	if !ok {
+		return "other"
+	}
+	if _, pattern := mux.Handler(r); pattern != "" {
+		return pattern
+	}
+	return "unmatched"
+}
+
+func methodLabel(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodPost, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodPatch:
+		return m
+	}
+	return "other"
+}
+
+// StartPprof: net/http/pprof'u addr'de ayrı bir dinleyicide sunar (pprof_addr).
+// Rotalar admin kapsamı ister (Protect); kimliksiz loopback istemci ancak
+// api_loopback_scopes admin içeriyorsa erişir.
+func StartPprof(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           Protect(mux),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+	"quantumcoin/wallet"
+)
+
+// BlockSubmitted main.go tarafından set edilir (yayın + kayıt):
+//
+//	api.BlockSubmitted = func(b *blockchain.Block) { p2p.BroadcastMessage(p2p.BlockMessage(b)); bc.SaveToFile(...) }
+var BlockSubmitted func(b *blockchain.Block)
+
+// MinerControl main.go tarafından set edilir: süreç içi madenciyi başlatır
+// (start=true; address boşsa düğüm cüzdanı) ya da durdurur. RPC setgenerate
+// ve /api/miner/start|stop aynı yolu kullanır; nil => madenci yok.
+var MinerControl func(start bool, address string) (wire.MinerState, error)
+
+// RegisterMiningRoutes, harici blok montajı uçlarını mux'a ekler.
+//
+//	GET  /api/mining/getblocktemplate?address=&bits= -> BlockTemplate
+//	POST /api/mining/submitblock { block } | { version, height, previousHash, timestamp, bits, nonce, miner, transactions }
+//	                                                   -> { accepted, hash, height }
+//
+// bits düğümün varsayılan zorluğunun altına inemez; yüksek bits verilebilir.
+func RegisterMiningRoutes(mux Router) {
+	mux.HandleFunc("/api/mining/getblocktemplate", getBlockTemplate)
+	mux.HandleFunc("/api/mining/submitblock", submitBlock)
+}
+
+// BlockTemplateFor: address için şablon (HTTP ve RPC ortak)
+func BlockTemplateFor(address string, bits int) (*blockchain.BlockTemplate, error) {
+	address = strings.TrimSpace(address)
+	if _, _, err := wallet.DecodeAddress(address); err != nil {
+		return nil, err
+	}
+	if bits < cfg.DefaultDifficultyBits {
+		bits = cfg.DefaultDifficultyBits
+	}
+	return bc.GetBlockTemplate(address, bits)
+}
+
+// SubmitBlock: çözülmüş bloğu zincire ekler ve BlockSubmitted'ı çağırır (HTTP ve RPC ortak)
+func SubmitBlock(s *blockchain.BlockSubmission) (*blockchain.Block, error) {
+	blk, err := s.ToBlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := bc.SubmitBlock(blk, cfg.DefaultDifficultyBits); err != nil {
+		return blk, err
+	}
+	if BlockSubmitted != nil {
+		BlockSubmitted(blk)
+	}
+	return blk, nil
+}
+
+func getBlockTemplate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	bits, _ := strconv.Atoi(r.URL.Query().Get("bits"))
+	t, err := BlockTemplateFor(r.URL.Query().Get("address"), bits)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	j(w, http.StatusOK, t)
+}
+
+func submitBlock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var req blockchain.BlockSubmission
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+		return
+	}
+	blk, err := SubmitBlock(&req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, blockchain.ErrStaleBlock) || errors.Is(err, blockchain.ErrDuplicateBlock) {
+			status = http.StatusConflict
+		}
+		j(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	j(w, http.StatusOK, wire.SubmitBlockResult{Accepted: true, Hash: hex.EncodeToString(blk.Hash), Height: blk.Index})
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/wallet"
+)
+
+// RegisterMultisigRoutes, m-of-n adres uçlarını mux'a ekler.
+//
+//	POST /api/multisig/new  { m, pubkeys: [hex...] } -> { address, descriptor }
+//	GET  /api/multisig/list                          -> [{ address, descriptor }]
+//
+// Ortak imzalama /api/psbt/* üzerinden yapılır.
+func RegisterMultisigRoutes(mux Router) {
+	mux.HandleFunc("/api/multisig/new", multisigNew)
+	mux.HandleFunc("/api/multisig/list", multisigList)
+}
+
+type multisigDTO = wire.Multisig
+
+func toMultisigDTO(d *wallet.MultisigDescriptor) multisigDTO {
+	return multisigDTO{Address: d.Address(), Descriptor: d.String(), M: d.M, N: len(d.PubKeys)}
+}
+
+func multisigNew(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var req wire.MultisigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+		return
+	}
+	pubs := make([][]byte, 0, len(req.PubKeys))
+	for _, h := range req.PubKeys {
+		p, err := hex.DecodeString(h)
+		if err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": "bad pubkey hex"})
+			return
+		}
+		pubs = append(pubs, p)
+	}
+	d, err := wallet.NewMultisigDescriptor(req.M, pubs)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := wallet.SaveMultisig(d); err != nil {
+		j(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	j(w, http.StatusOK, toMultisigDTO(d))
+}
+
+func multisigList(w http.ResponseWriter, _ *http.Request) {
+	out := []multisigDTO{}
+	for _, addr := range wallet.ListMultisig() {
+		if d, ok := wallet.LookupMultisig(addr); ok {
+			out = append(out, toMultisigDTO(d))
+		}
+	}
+	j(w, http.StatusOK, out)
+}
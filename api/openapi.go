@@ -0,0 +1,474 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+	"quantumcoin/pool"
+	"quantumcoin/stake"
+	"quantumcoin/webhook"
+)
+
+// OpenAPI 3 belgesi (GET /api/openapi.json). Uçlar Operation tablolarında
+// tanımlanır; gövde şemaları handler'ların kullandığı Go tiplerinden
+// (çoğunlukla api/wire) yansımayla üretilir, yani alan değişiklikleri belgeye
+// kendiliğinden yansır. Yeni rota eklendiğinde tabloya da eklenmelidir;
+// CheckOpenAPI belgelenen ama yönlendirilmeyen ve yetki gerektirip
+// belgelenmeyen rotaları bildirir (düğüm açılışta loglar, cmd/apitest denetler).
+//
+// Tablolar: Operations (Register*Routes ile eklenen ortak rotalar),
+// ServerOperations (yalnız StartHTTP) ve düğümün kendi rotaları (main.go).
+
+// Operation: belgelenen uç
+type Operation struct {
+	Method   string // GET | POST
+	Path     string // yol parametresi: "{ad}"
+	Summary  string
+	Tag      string
+	Query    []Param
+	Request  any // gövde tipi örneği (nil => gövde yok)
+	Response any // 200 gövdesi; string => text/plain, nil => gövde yok
+
+	Enabled func() bool // nil => her zaman; false dönerse uç belgeye girmez (örn. regtest)
+}
+
+func (op Operation) enabled() bool { return op.Enabled == nil || op.Enabled() }
+
+// Router: Register*Routes'un rotaları kaydettiği mux (*http.ServeMux uyar;
+// rota listesini toplamak isteyen sarmalayıcılar da verilebilir)
+type Router interface {
+	Handle(pattern string, handler http.Handler)
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// Param: sorgu ya da yol parametresi (Type: string | integer | boolean)
+type Param struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+}
+
+func q(name, typ, desc string) Param { return Param{Name: name, Type: typ, Description: desc} }
+func qr(name, typ, desc string) Param {
+	return Param{Name: name, Type: typ, Required: true, Description: desc}
+}
+
+// Operations: Register*Routes rotaları (her iki sunucuda ortak)
+var Operations = []Operation{
+	{Method: "GET", Path: "/api/openapi.json", Tag: "meta", Summary: "This OpenAPI document", Response: map[string]any{}},
+	{Method: "GET", Path: "/metrics", Tag: "meta", Summary: "Prometheus metrics (text format 0.0.4)", Response: ""},
+	{Method: "GET", Path: "/api/admin/loglevel", Tag: "meta", Summary: "Log level per subsystem", Response: wire.LogLevels{}},
+	{Method: "POST", Path: "/api/admin/loglevel", Tag: "meta", Summary: "Change a subsystem's log level at runtime (subsystem \"all\" sets every one)",
+		Request: wire.SetLogLevelRequest{}, Response: wire.LogLevels{}},
+
+	// explorer
+	{Method: "GET", Path: "/api/tx/{txid}", Tag: "explorer", Summary: "Transaction with location, confirmations and spenders", Response: wire.TxView{}},
+	{Method: "GET", Path: "/api/address/{address}", Tag: "explorer", Summary: "Address balance summary (tx_index)", Response: blockchain.AddressSummary{}},
+	{Method: "GET", Path: "/api/address/{address}/txs", Tag: "explorer", Summary: "Address transactions, newest first (tx_index)",
+		Query: []Param{q("offset", "integer", ""), q("limit", "integer", "default 25, max 100")}, Response: wire.AddressTxs{}},
+	{Method: "GET", Path: "/api/search", Tag: "explorer", Summary: "Resolve height, block hash, txid or address",
+		Query: []Param{qr("q", "string", "")}, Response: wire.SearchResult{}},
+	{Method: "GET", Path: "/api/richlist", Tag: "explorer", Summary: "Top addresses by holdings (tx_index)",
+		Query: []Param{q("limit", "integer", "default 100, max 1000")}, Response: []blockchain.RichEntry{}},
+	{Method: "GET", Path: "/api/supply/{field}", Tag: "explorer", Summary: "Single supply value as plain text (minted, circulating, burned, premine, max)", Response: ""},
+	{Method: "GET", Path: "/api/stats", Tag: "explorer", Summary: "Chain-wide totals (tx_index)", Response: wire.ChainStats{}},
+	{Method: "GET", Path: "/api/stats/daily", Tag: "explorer", Summary: "Daily interval/difficulty/tx/fee series (tx_index)",
+		Query: []Param{q("days", "integer", "default 30")}, Response: []blockchain.DayStats{}},
+
+	// burn
+	{Method: "POST", Path: "/api/burn", Tag: "burn", Summary: "Burn coins (signed with wallet key or returned unsigned)", Request: wire.OwnerTxRequest{}, Response: wire.OwnerTxResult{}},
+	{Method: "GET", Path: "/api/burns", Tag: "burn", Summary: "Burn history, newest first", Query: []Param{q("limit", "integer", "default 100")}, Response: wire.BurnHistory{}},
+	{Method: "GET", Path: "/api/supply", Tag: "burn", Summary: "Minted, burned and circulating supply", Response: blockchain.SupplyStats{}},
+
+	// stake
+	{Method: "POST", Path: "/api/stake/start", Tag: "stake", Summary: "Lock coins as stake", Request: wire.OwnerTxRequest{}, Response: wire.OwnerTxResult{}},
+	{Method: "POST", Path: "/api/stake/unstake", Tag: "stake", Summary: "Start cooldown for an unlocked stake", Request: wire.OwnerTxRequest{}, Response: wire.OwnerTxResult{}},
+	{Method: "POST", Path: "/api/stake/withdraw", Tag: "stake", Summary: "Release stakes whose cooldown ended", Request: wire.OwnerTxRequest{}, Response: wire.OwnerTxResult{}},
+	{Method: "GET", Path: "/api/stake/status", Tag: "stake", Summary: "Stakes, totals and pool earnings", Query: []Param{qr("address", "string", "")}, Response: StakeInfo{}},
+	{Method: "GET", Path: "/api/stake/history", Tag: "stake", Summary: "Epoch distributions", Query: []Param{q("address", "string", "")}, Response: []stake.Distribution{}},
+
+	// psbt / multisig
+	{Method: "POST", Path: "/api/psbt/create", Tag: "psbt", Summary: "Create unsigned PSBT", Request: wire.PSBTCreateRequest{}, Response: wire.PSBTResponse{}},
+	{Method: "POST", Path: "/api/psbt/inspect", Tag: "psbt", Summary: "Decode PSBT", Request: wire.PSBTRequest{}, Response: blockchain.PSBTSummary{}},
+	{Method: "POST", Path: "/api/psbt/sign", Tag: "psbt", Summary: "Sign own inputs with priv_hex", Request: wire.PSBTRequest{}, Response: wire.PSBTResponse{}},
+	{Method: "POST", Path: "/api/psbt/combine", Tag: "psbt", Summary: "Merge partial signatures (psbts)", Request: wire.PSBTRequest{}, Response: wire.PSBTResponse{}},
+	{Method: "POST", Path: "/api/psbt/finalize", Tag: "psbt", Summary: "Finalize and optionally broadcast", Request: wire.PSBTRequest{}, Response: wire.PSBTFinalized{}},
+	{Method: "POST", Path: "/api/multisig/new", Tag: "multisig", Summary: "Create m-of-n address", Request: wire.MultisigRequest{}, Response: wire.Multisig{}},
+	{Method: "GET", Path: "/api/multisig/list", Tag: "multisig", Summary: "Known multisig addresses", Response: []wire.Multisig{}},
+
+	// pool
+	{Method: "GET", Path: "/api/pool/stats", Tag: "pool", Summary: "Pool summary", Response: pool.Stats{}},
+	{Method: "GET", Path: "/api/pool/miner", Tag: "pool", Summary: "Miner balance, pending, paid and share difficulty", Query: []Param{qr("address", "string", "")}, Response: pool.MinerStats{}},
+	{Method: "GET", Path: "/api/pool/blocks", Tag: "pool", Summary: "Found blocks and their distribution", Response: []pool.FoundBlock{}},
+	{Method: "GET", Path: "/api/pool/payouts", Tag: "pool", Summary: "Payout history", Query: []Param{q("address", "string", "")}, Response: []pool.Payout{}},
+	{Method: "GET", Path: "/api/pool/job", Tag: "pool", Summary: "Web miner share job", Query: []Param{qr("address", "string", "address[.worker]")}, Response: pool.Job{}},
+	{Method: "POST", Path: "/api/pool/submit", Tag: "pool", Summary: "Submit share", Request: wire.WorkSubmit{}, Response: pool.SubmitResult{}},
+
+	// mining / regtest
+	{Method: "GET", Path: "/api/mining/getblocktemplate", Tag: "mining", Summary: "Block template for external assembly",
+		Query: []Param{qr("address", "string", "coinbase address"), q("bits", "integer", "difficulty (>= node default)")}, Response: blockchain.BlockTemplate{}},
+	{Method: "POST", Path: "/api/mining/submitblock", Tag: "mining", Summary: "Submit solved block", Request: blockchain.BlockSubmission{}, Response: wire.SubmitBlockResult{}},
+	{Method: "POST", Path: "/api/regtest/generate", Tag: "mining", Summary: "Mine blocks instantly (regtest only)", Request: wire.GenerateRequest{}, Response: wire.GenerateResult{}, Enabled: generateEnabled},
+
+	// webhooks
+	{Method: "GET", Path: "/api/webhooks", Tag: "webhooks", Summary: "Registered hooks (secrets hidden)", Response: []webhook.Hook{}},
+	{Method: "POST", Path: "/api/webhooks", Tag: "webhooks", Summary: "Register hook (returns secret once)", Request: webhook.Hook{}, Response: webhook.Hook{}},
+	{Method: "POST", Path: "/api/webhooks/remove", Tag: "webhooks", Summary: "Remove hook", Request: idRequest{}, Response: map[string]string{}},
+	{Method: "POST", Path: "/api/webhooks/ping", Tag: "webhooks", Summary: "Queue ping delivery", Request: idRequest{}, Response: webhook.Delivery{}},
+	{Method: "POST", Path: "/api/webhooks/redeliver", Tag: "webhooks", Summary: "Retry delivery", Request: idRequest{}, Response: webhook.Delivery{}},
+	{Method: "GET", Path: "/api/webhooks/deliveries", Tag: "webhooks", Summary: "Deliveries, newest first",
+		Query: []Param{q("hook", "string", ""), q("status", "string", "pending|delivered|failed|cancelled"), q("limit", "integer", "default 100")}, Response: []webhook.Delivery{}},
+
+	// rpc / ws
+	{Method: "POST", Path: "/rpc", Tag: "rpc", Summary: "JSON-RPC 2.0 (single or batch; method list: help)", Request: rpcRequest{}, Response: rpcResponse{}},
+	{Method: "GET", Path: "/ws", Tag: "ws", Summary: "WebSocket subscriptions (tip, reorg, mempool, miner, txids, addresses)"},
+}
+
+// ServerOperations: yalnız StartHTTP'nin rotaları (düğüm bunları kendi uçlarıyla sunar)
+var ServerOperations = []Operation{
+	{Method: "GET", Path: "/health", Tag: "chain", Summary: "Liveness and height", Response: wire.Health{}},
+	{Method: "GET", Path: "/api/blocks", Tag: "chain", Summary: "All blocks, newest first", Response: []wire.BlockSummary{}},
+	{Method: "GET", Path: "/api/block", Tag: "chain", Summary: "Block by height or hash", Query: []Param{qr("id", "string", "height or hash")}, Response: wire.Block{}},
+	{Method: "GET", Path: "/api/mempool", Tag: "chain", Summary: "Pending transactions", Response: []wire.MempoolTx{}},
+	{Method: "GET", Path: "/api/address/balance", Tag: "wallet", Summary: "Address balance", Query: []Param{qr("addr", "string", "")}, Response: wire.AddressBalance{}},
+	{Method: "GET", Path: "/api/address/utxos", Tag: "wallet", Summary: "Address unspent outputs", Query: []Param{qr("addr", "string", "")}, Response: []wire.UTXO{}},
+	{Method: "POST", Path: "/api/tx/build", Tag: "wallet", Summary: "Build unsigned transaction and signing hashes", Request: wire.BuildTxRequest{}, Response: wire.UnsignedTx{}},
+	{Method: "POST", Path: "/api/tx/send", Tag: "wallet", Summary: "Broadcast signed transaction", Request: wire.Tx{}, Response: wire.BroadcastResult{}},
+	{Method: "GET", Path: "/api/tx/status", Tag: "wallet", Summary: "Confirmation status", Query: []Param{qr("id", "string", "txid hex")}, Response: wire.TxStatus{}},
+	{Method: "GET", Path: "/api/mine", Tag: "mining", Summary: "Web miner job (pool share job when pool mode is on)", Query: []Param{qr("address", "string", "")}, Response: wire.WebJob{}},
+	{Method: "POST", Path: "/api/mine", Tag: "mining", Summary: "Submit web miner solution", Request: wire.WorkSubmit{}, Response: wire.WebResult{}},
+}
+
+// idRequest: { id } gövdeli uçlar
+type idRequest struct {
+	ID string `json:"id"`
+}
+
+// RegisterOpenAPIRoute, ops'un belgesini GET /api/openapi.json olarak sunar
+func RegisterOpenAPIRoute(mux Router, ops []Operation) {
+	var (
+		once sync.Once
+		body []byte
+	)
+	mux.HandleFunc("/api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { body, _ = json.MarshalIndent(OpenAPI(ops), "", "  ") })
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})
+}
+
+// OpenAPI: ops için OpenAPI 3.0 belgesi
+func OpenAPI(ops []Operation) map[string]any {
+	g := &schemaGen{defs: map[string]any{}, names: map[reflect.Type]string{}}
+	paths := map[string]map[string]any{}
+	tags := map[string]bool{}
+	for _, op := range ops {
+		if !op.enabled() {
+			continue
+		}
+		item := paths[op.Path]
+		if item == nil {
+			item = map[string]any{}
+			paths[op.Path] = item
+		}
+		item[strings.ToLower(op.Method)] = g.operation(op)
+		tags[op.Tag] = true
+	}
+	tagList := make([]map[string]string, 0, len(tags))
+	for t := range tags {
+		tagList = append(tagList, map[string]string{"name": t})
+	}
+	sort.Slice(tagList, func(i, j int) bool { return tagList[i]["name"] < tagList[j]["name"] })
+	g.defs["Error"] = g.structSchema(reflect.TypeOf(wire.Error{}))
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "QuantumCoin HTTP API",
+			"version": "api.v1",
+			"description": "Scopes (x-scope): read, wallet, mining, admin. Credentials: Bearer or X-API-Key " +
+				"(apikey-new), Basic __cookie__:<token> (api_cookie_file) or rpc_user/rpc_password.",
+		},
+		"tags":  tagList,
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": g.defs,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]string{"type": "http", "scheme": "bearer"},
+				"apiKey": map[string]string{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"basic":  map[string]string{"type": "http", "scheme": "basic"},
+			},
+		},
+	}
+}
+
+func (g *schemaGen) operation(op Operation) map[string]any {
+	scope := RouteScope(op.Path)
+	o := map[string]any{
+		"summary":     op.Summary,
+		"tags":        []string{op.Tag},
+		"operationId": operationID(op),
+		"x-scope":     scope,
+	}
+	security := []map[string][]string{{"bearer": {}}, {"apiKey": {}}, {"basic": {}}}
+	if scope == ScopeRead {
+		security = append(security, map[string][]string{}) // kimliksiz de olur
+	}
+	o["security"] = security
+
+	var params []map[string]any
+	for _, seg := range strings.Split(op.Path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params = append(params, map[string]any{
+				"name": strings.Trim(seg, "{}"), "in": "path", "required": true,
+				"schema": map[string]string{"type": "string"},
+			})
+		}
+	}
+	for _, p := range op.Query {
+		params = append(params, map[string]any{
+			"name": p.Name, "in": "query", "required": p.Required, "description": p.Description,
+			"schema": map[string]string{"type": p.Type},
+		})
+	}
+	if len(params) > 0 {
+		o["parameters"] = params
+	}
+	if op.Request != nil {
+		o["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(op.Request))}},
+		}
+	}
+	ok := map[string]any{"description": "OK"}
+	switch v := op.Response.(type) {
+	case nil:
+	case string:
+		ok["content"] = map[string]any{"text/plain": map[string]any{"schema": map[string]string{"type": "string"}}}
+	default:
+		ok["content"] = map[string]any{"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(v))}}
+	}
+	o["responses"] = map[string]any{
+		"200": ok,
+		"default": map[string]any{
+			"description": "Error (401 unauthenticated, 403 missing scope, 429 rate limited, 4xx/5xx handler errors)",
+			"content":     map[string]any{"application/json": map[string]any{"schema": map[string]string{"$ref": "#/components/schemas/Error"}}},
+		},
+	}
+	return o
+}
+
+// operationID: "GET /api/stake/status" -> "getApiStakeStatus"
+func operationID(op Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, part := range strings.FieldsFunc(op.Path, func(r rune) bool {
+		return r == '/' || r == '.' || r == '{' || r == '}' || r == '_'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// schemaGen: Go tiplerinden JSON şeması (encoding/json kurallarıyla)
+type schemaGen struct {
+	defs  map[string]any
+	names map[reflect.Type]string
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	case t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]any{}
+	case t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"} // []byte => base64
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + g.define(t)}
+	}
+	return map[string]any{}
+}
+
+// define: adlandırılmış tipi components'e ekler; ad çakışırsa paket adıyla öneklenir
+func (g *schemaGen) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := exportName(t.Name())
+	if _, taken := g.defs[name]; taken || name == "Error" {
+		pkg := t.PkgPath()
+		name = exportName(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+	g.names[t] = name
+	g.defs[name] = map[string]any{} // özyinelemeli tipler için yer tutucu
+	g.defs[name] = g.structSchema(t)
+	return name
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	g.fields(t, props, &required)
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+// fields: dışa açık alanlar; gömülü yapılar düzleştirilir
+func (g *schemaGen) fields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, props, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(opts, "string") {
+			props[name] = map[string]any{"type": "string"}
+		} else {
+			props[name] = g.schema(f.Type)
+		}
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func exportName(s string) string {
+	s = strings.NewReplacer("[", "_", "]", "", ".", "_", "/", "_", "*", "").Replace(s)
+	if s == "" {
+		return "Object"
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// CheckOpenAPI: belge ile mux'ı karşılaştırır. Bildirir: mux'ın yönlendirmediği
+// (ya da yalnız "/" web arayüzüne düşen) belgelenmiş uçlar ve mux'ta olup
+// belgelenmemiş yetki isteyen rotalar (auth.go rota tablosu).
+func CheckOpenAPI(mux *http.ServeMux, ops []Operation) []string {
+	var problems []string
+	documented := map[string]bool{}
+	for _, op := range ops {
+		if !op.enabled() {
+			continue
+		}
+		path := samplePath(op.Path)
+		documented[path] = true
+		if !routed(mux, op.Method, path) {
+			problems = append(problems, op.Method+" "+op.Path+": documented but not routed")
+		}
+	}
+	for route, scope := range routeScopes {
+		if scope == ScopeRead {
+			continue
+		}
+		if !registered(mux, route) {
+			continue
+		}
+		found := false
+		for p := range documented {
+			if p == route || (strings.HasSuffix(route, "/") && strings.HasPrefix(p, route)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, route+": "+scope+" route is not documented")
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// samplePath: "{ad}" parametrelerini örnek değerle doldurur
+func samplePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, s := range parts {
+		if strings.HasPrefix(s, "{") {
+			parts[i] = "x"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// registered: route mux'ta kendi deseniyle mi kayıtlı (üst önekin yakalaması sayılmaz)
+func registered(mux *http.ServeMux, route string) bool {
+	r, err := http.NewRequest(http.MethodGet, route, nil)
+	if err != nil {
+		return false
+	}
+	_, pattern := mux.Handler(r)
+	return pattern == route
+}
+
+func routed(mux *http.ServeMux, method, path string) bool {
+	r, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		return false
+	}
+	_, pattern := mux.Handler(r)
+	return pattern != "" && pattern != "/"
+}
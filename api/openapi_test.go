@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"quantumcoin/config"
+)
+
+// recordingMux: kayıt edilen rota desenlerini toplar
+type recordingMux struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func (m *recordingMux) Handle(pattern string, h http.Handler) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.Handle(pattern, h)
+}
+
+func (m *recordingMux) HandleFunc(pattern string, h func(http.ResponseWriter, *http.Request)) {
+	m.patterns = append(m.patterns, pattern)
+	m.ServeMux.HandleFunc(pattern, h)
+}
+
+// TestOpenAPIMatchesRoutes: belgelenen her uç yönlendirilmeli, kayıtlı her rota belgelenmeli
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	// regtest: ağa bağlı uçlar (generate) da kayıtlı ve belgeli olsun
+	saved := cfg
+	defer func() { cfg = saved }()
+	cfg = config.Default()
+	if err := cfg.UseNetwork(config.NetworkRegtest); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := &recordingMux{ServeMux: http.NewServeMux()}
+	registerServerRoutes(mux)
+	ops := ServerOpenAPIOperations()
+
+	for _, op := range ops {
+		if !op.enabled() {
+			t.Errorf("%s %s: disabled on regtest", op.Method, op.Path)
+			continue
+		}
+		if !routed(mux.ServeMux, op.Method, samplePath(op.Path)) {
+			t.Errorf("%s %s: documented but not routed", op.Method, op.Path)
+		}
+	}
+
+	for _, route := range mux.patterns {
+		if route == "/" {
+			continue // web arayüzü (SPA)
+		}
+		found := false
+		for _, op := range ops {
+			p := samplePath(op.Path)
+			if p == route || (strings.HasSuffix(route, "/") && strings.HasPrefix(p, route)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("%s: routed but not documented", route)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/pool"
+)
+
+// Pool: havuz modu açıksa main tarafından atanır (nil => havuz kapalı)
+var Pool *pool.Pool
+
+// RegisterPoolRoutes, havuz uçlarını mux'a ekler.
+//
+//	GET  /api/pool/stats                 -> havuz özeti
+//	GET  /api/pool/miner?address=        -> bakiye / bekleyen / ödenen / pay zorluğu
+//	GET  /api/pool/blocks                -> bulunan bloklar ve dağıtımları
+//	GET  /api/pool/payouts[?address=]    -> ödeme geçmişi
+//	GET  /api/pool/job?address=[.işçi]   -> web madenci işi
+//	POST /api/pool/submit { jobId, nonce } -> pay sonucu
+func RegisterPoolRoutes(mux Router) {
+	mux.HandleFunc("/api/pool/stats", poolOnly(poolStats))
+	mux.HandleFunc("/api/pool/miner", poolOnly(poolMiner))
+	mux.HandleFunc("/api/pool/blocks", poolOnly(poolBlocks))
+	mux.HandleFunc("/api/pool/payouts", poolOnly(poolPayouts))
+	mux.HandleFunc("/api/pool/job", poolOnly(poolJob))
+	mux.HandleFunc("/api/pool/submit", poolOnly(poolSubmit))
+}
+
+func poolOnly(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if Pool == nil {
+			j(w, http.StatusServiceUnavailable, map[string]string{"error": "pool mode disabled"})
+			return
+		}
+		h(w, r)
+	}
+}
+
+func poolStats(w http.ResponseWriter, _ *http.Request) {
+	j(w, http.StatusOK, Pool.Stats())
+}
+
+func poolMiner(w http.ResponseWriter, r *http.Request) {
+	addr := strings.TrimSpace(r.URL.Query().Get("address"))
+	if addr == "" {
+		j(w, http.StatusBadRequest, map[string]string{"error": "address required"})
+		return
+	}
+	j(w, http.StatusOK, Pool.Miner(addr))
+}
+
+func poolBlocks(w http.ResponseWriter, _ *http.Request) {
+	j(w, http.StatusOK, Pool.Blocks())
+}
+
+func poolPayouts(w http.ResponseWriter, r *http.Request) {
+	j(w, http.StatusOK, Pool.Payouts(strings.TrimSpace(r.URL.Query().Get("address"))))
+}
+
+func poolJob(w http.ResponseWriter, r *http.Request) {
+	job, err := Pool.NewJob(r.URL.Query().Get("address"))
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	j(w, http.StatusOK, job)
+}
+
+func poolSubmit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var req wire.WorkSubmit
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+		return
+	}
+	// Reddedilen paylar da 200 döner; neden Message alanındadır
+	res, _ := Pool.Submit(req.JobID, req.Nonce)
+	j(w, http.StatusOK, res)
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+	"quantumcoin/wallet"
+)
+
+// TxBroadcaster main.go tarafından set edilir (P2P yayını):
+//
+//	api.TxBroadcaster = func(tx *blockchain.Transaction) { p2p.BroadcastMessage(p2p.TxMessage(tx)) }
+var TxBroadcaster func(tx *blockchain.Transaction)
+
+// RegisterPSBTRoutes, kısmi imzalı işlem (PSBT) uçlarını mux'a ekler.
+//
+//	POST /api/psbt/create   { from, to, amount }        -> { psbt, summary }
+//	POST /api/psbt/inspect  { psbt }                    -> summary
+//	POST /api/psbt/sign     { psbt, priv_hex }          -> { psbt, signed, summary }
+//	POST /api/psbt/combine  { psbts: [...] }            -> { psbt, summary }
+//	POST /api/psbt/finalize { psbt, broadcast }         -> { id, tx, accepted }
+func RegisterPSBTRoutes(mux Router) {
+	mux.HandleFunc("/api/psbt/create", psbtCreate)
+	mux.HandleFunc("/api/psbt/inspect", psbtInspect)
+	mux.HandleFunc("/api/psbt/sign", psbtSign)
+	mux.HandleFunc("/api/psbt/combine", psbtCombine)
+	mux.HandleFunc("/api/psbt/finalize", psbtFinalize)
+}
+
+type (
+	psbtReq  = wire.PSBTRequest
+	psbtResp = wire.PSBTResponse
+)
+
+// decodePSBTReq: POST gövdesini çözer; hata varsa yanıtı yazar ve false döner.
+func decodePSBTReq(w http.ResponseWriter, r *http.Request, into any) bool {
+	if r.Method != http.MethodPost {
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(into); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// writePSBT: PSBT'yi encode edip özetiyle birlikte döner.
+func writePSBT(w http.ResponseWriter, p *blockchain.PSBT, signed int) {
+	enc, err := p.Encode()
+	if err != nil {
+		j(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	sum, err := p.Inspect()
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	j(w, http.StatusOK, psbtResp{PSBT: enc, Signed: signed, Summary: sum})
+}
+
+func psbtCreate(w http.ResponseWriter, r *http.Request) {
+	var req wire.PSBTCreateRequest
+	if !decodePSBTReq(w, r, &req) {
+		return
+	}
+	if bc == nil {
+		j(w, http.StatusServiceUnavailable, map[string]string{"error": "blockchain not ready"})
+		return
+	}
+	if !wallet.ValidateAddress(req.From) || !wallet.ValidateAddress(req.To) {
+		j(w, http.StatusBadRequest, map[string]string{"error": "invalid address"})
+		return
+	}
+	p, err := blockchain.NewPSBT(req.From, req.To, req.Amount, bc)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writePSBT(w, p, 0)
+}
+
+func psbtInspect(w http.ResponseWriter, r *http.Request) {
+	var req psbtReq
+	if !decodePSBTReq(w, r, &req) {
+		return
+	}
+	p, err := blockchain.DecodePSBT(req.PSBT)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	sum, err := p.Inspect()
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	j(w, http.StatusOK, sum)
+}
+
+func psbtSign(w http.ResponseWriter, r *http.Request) {
+	var req psbtReq
+	if !decodePSBTReq(w, r, &req) {
+		return
+	}
+	p, err := blockchain.DecodePSBT(req.PSBT)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	priv, err := wallet.ImportPrivateKeyHex(strings.TrimSpace(req.PrivHex))
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "invalid priv_hex: " + err.Error()})
+		return
+	}
+	n, err := p.Sign(priv)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writePSBT(w, p, n)
+}
+
+func psbtCombine(w http.ResponseWriter, r *http.Request) {
+	var req psbtReq
+	if !decodePSBTReq(w, r, &req) {
+		return
+	}
+	parts := make([]*blockchain.PSBT, 0, len(req.PSBTs))
+	for _, s := range req.PSBTs {
+		p, err := blockchain.DecodePSBT(s)
+		if err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		parts = append(parts, p)
+	}
+	p, err := blockchain.CombinePSBT(parts...)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writePSBT(w, p, 0)
+}
+
+func psbtFinalize(w http.ResponseWriter, r *http.Request) {
+	var req psbtReq
+	if !decodePSBTReq(w, r, &req) {
+		return
+	}
+	p, err := blockchain.DecodePSBT(req.PSBT)
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	tx, err := p.Finalize()
+	if err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	accepted := false
+	if req.Broadcast {
+		if bc == nil {
+			j(w, http.StatusServiceUnavailable, map[string]string{"error": "blockchain not ready"})
+			return
+		}
+		if err := bc.AddTransaction(tx); err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if TxBroadcaster != nil {
+			TxBroadcaster(tx)
+		}
+		accepted = true
+	}
+	j(w, http.StatusOK, wire.PSBTFinalized{ID: hex.EncodeToString(tx.ID), Tx: mapTxToDTO(tx), Accepted: accepted})
+}
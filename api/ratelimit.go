@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"quantumcoin/config"
+)
+
+// Token-bucket hız sınırı: anahtar başına ("k:<ad>", anahtarın rate_per_min'i
+// ya da api_rate_limit) ve diğer istemciler için IP başına ("ip:<adres>").
+// Kova api_rate_burst kapasitelidir ve dakikada rate jeton dolar. Cookie/RPC
+// kimliği ile kimliksiz loopback istemciler (yerel operatör) sınırlanmaz.
+
+const limiterIdle = 10 * time.Minute // bu süre dokunulmayan kovalar budanır
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	pruned  time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{buckets: map[string]*bucket{}, pruned: time.Now()}
+}
+
+// allow: isteğe jeton düşer; yoksa kaç süre sonra deneneceğini döner
+func (l *rateLimiter) allow(p *Principal, r *http.Request, c *config.Config) (time.Duration, bool) {
+	rate, key := c.APIRateLimit, "ip:"+clientIP(r)
+	switch p.Kind {
+	case KindCookie, KindRPC, KindLoopback:
+		return 0, true
+	case KindKey:
+		key = "k:" + p.Name
+		if p.rate > 0 {
+			rate = p.rate
+		}
+	}
+	if rate <= 0 {
+		return 0, true
+	}
+	burst := float64(max(c.APIRateBurst, 1))
+	perSec := float64(rate) / 60
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.pruned) > limiterIdle {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) > limiterIdle {
+				delete(l.buckets, k)
+			}
+		}
+		l.pruned = now
+	}
+	b := l.buckets[key]
+	if b == nil {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*perSec)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / perSec * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
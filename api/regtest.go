@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+	"quantumcoin/wallet"
+)
+
+// maxGenerate: tek çağrıda üretilebilecek en fazla blok
+const maxGenerate = 1000
+
+// ErrGenerateDisabled: generate yalnız regtest'te açık
+var ErrGenerateDisabled = errors.New("generate is only available on regtest")
+
+// RegisterRegtestRoutes, entegrasyon testleri için anında blok üretimini ekler
+// (yalnız ağ parametrelerinde Generate açıksa).
+//
+//	POST /api/regtest/generate { count, address } -> { hashes, height }
+func RegisterRegtestRoutes(mux Router) {
+	if !generateEnabled() {
+		return
+	}
+	mux.HandleFunc("/api/regtest/generate", generateBlocks)
+}
+
+func generateEnabled() bool { return cfg != nil && cfg.Params().Generate }
+
+// Generate: address'e count blok kazar (regtest zorluğu önemsiz); her blok
+// BlockSubmitted'a iletilir (HTTP ve RPC ortak)
+func Generate(count int, address string) ([]*blockchain.Block, error) {
+	if !cfg.Params().Generate {
+		return nil, ErrGenerateDisabled
+	}
+	address = strings.TrimSpace(address)
+	if _, _, err := wallet.DecodeAddress(address); err != nil {
+		return nil, err
+	}
+	if count <= 0 || count > maxGenerate {
+		return nil, fmt.Errorf("count must be 1..%d", maxGenerate)
+	}
+	out := make([]*blockchain.Block, 0, count)
+	for i := 0; i < count; i++ {
+		blk, err := bc.MineBlock(address, cfg.DefaultDifficultyBits)
+		if err != nil {
+			return out, err
+		}
+		if BlockSubmitted != nil {
+			BlockSubmitted(blk)
+		}
+		out = append(out, blk)
+	}
+	return out, nil
+}
+
+func generateBlocks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var req wire.GenerateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+		return
+	}
+	blocks, err := Generate(req.Count, req.Address)
+	if err != nil && len(blocks) == 0 {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	hashes := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		hashes = append(hashes, hex.EncodeToString(b.Hash))
+	}
+	resp := wire.GenerateResult{Hashes: hashes, Height: bc.GetBestHeight()}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	j(w, http.StatusOK, resp)
+}
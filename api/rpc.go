@@ -0,0 +1,357 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// JSON-RPC 2.0 (POST /rpc). Tek istek ya da toplu (batch) dizi kabul edilir;
+// id'siz istekler bildirimdir ve yanıt üretmez. params konumsal dizi ya da
+// adlandırılmış nesne olabilir (adlar rpcMethod.Params sırasıyla eşlenir).
+// Yöntem listesi ve açıklamaları: rpc_methods.go ya da "help" yöntemi.
+//
+// Yetki: istek Protect'in çözdüğü kimlikle (anahtar, cookie, rpc_user/rpc_password,
+// loopback) değerlendirilir; bkz. auth.go. rpc_password ayarlıysa kimliksiz
+// çağrı reddedilir. /rpc rotası read ister; her yöntemin kendi kapsamı
+// (rpcMethod.Scope) ayrıca denetlenir: cüzdan harcaması/yeni adres wallet,
+// getblocktemplate/submitblock mining, madenci/regtest/log kontrolü admin.
+// Çıplak loopback bağlantısı yetki sayılmaz (tarayıcı üzerinden CSRF'e karşı
+// yalnız Origin denetiminden geçmiş loopback kimliği, api_loopback_scopes ile).
+// Gövde application/json olmalıdır (form/text gönderimleri preflight'sız gelemesin).
+
+// Standart ve uygulama hata kodları
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+
+	rpcMiscError       = -1  // genel uygulama hatası
+	rpcForbidden       = -2  // yetkisiz yöntem
+	rpcWalletError     = -4  // anahtar yok, imza hatası
+	rpcInvalidAddress  = -5  // adres/hash/yükseklik çözülemedi ya da bulunamadı
+	rpcVerifyRejected  = -26 // işlem/blok zincir kurallarınca reddedildi
+	maxRPCBody         = 4 << 20
+	maxRPCBatch        = 100
+	rpcProtocolVersion = "2.0"
+)
+
+// RPCError: JSON-RPC hata nesnesi
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string { return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message) }
+
+func rpcErrorf(code int, format string, a ...any) *RPCError {
+	return &RPCError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"` // hata yoksa her zaman dolu (null dahil)
+	Error   *RPCError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// rpcMethod: yöntem tanımı (belgelendirme help ile sunulur)
+type rpcMethod struct {
+	Params  []string // adlandırılmış parametre sırası; "?" soneki opsiyonel demektir
+	Summary string
+	Scope   string // gereken kapsam ("" => read)
+	Call    func(p rpcParams) (any, error)
+}
+
+// scope: yöntemin istediği kapsam
+func (m rpcMethod) scope() string {
+	if m.Scope == "" {
+		return ScopeRead
+	}
+	return m.Scope
+}
+
+// rpcParams: konumsal ya da adlandırılmış parametre erişimi
+type rpcParams struct {
+	pos   []json.RawMessage
+	named map[string]json.RawMessage
+	names []string
+}
+
+func parseRPCParams(raw json.RawMessage, names []string) (rpcParams, error) {
+	p := rpcParams{names: names}
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return p, nil
+	}
+	switch raw[0] {
+	case '[':
+		if err := json.Unmarshal(raw, &p.pos); err != nil {
+			return p, err
+		}
+		if len(p.pos) > len(names) {
+			return p, fmt.Errorf("too many params (max %d)", len(names))
+		}
+	case '{':
+		if err := json.Unmarshal(raw, &p.named); err != nil {
+			return p, err
+		}
+		for k := range p.named {
+			if !p.known(k) {
+				return p, fmt.Errorf("unknown param %q", k)
+			}
+		}
+	default:
+		return p, fmt.Errorf("params must be an array or object")
+	}
+	return p, nil
+}
+
+func (p rpcParams) known(name string) bool {
+	for _, n := range p.names {
+		if strings.TrimSuffix(n, "?") == name {
+			return true
+		}
+	}
+	return false
+}
+
+// raw: i. parametre (konumsal ya da adıyla); yoksa nil
+func (p rpcParams) raw(i int) json.RawMessage {
+	if p.named != nil {
+		if i < len(p.names) {
+			return p.named[strings.TrimSuffix(p.names[i], "?")]
+		}
+		return nil
+	}
+	if i < len(p.pos) {
+		return p.pos[i]
+	}
+	return nil
+}
+
+func (p rpcParams) has(i int) bool {
+	r := p.raw(i)
+	return len(r) > 0 && !bytes.Equal(r, []byte("null"))
+}
+
+// decode: i. parametreyi v'ye çözer; zorunluysa ve yoksa hata
+func (p rpcParams) decode(i int, v any) error {
+	name := "param"
+	if i < len(p.names) {
+		name = strings.TrimSuffix(p.names[i], "?")
+	}
+	if !p.has(i) {
+		if i < len(p.names) && strings.HasSuffix(p.names[i], "?") {
+			return nil
+		}
+		return rpcErrorf(rpcInvalidParams, "missing param %q", name)
+	}
+	if err := json.Unmarshal(p.raw(i), v); err != nil {
+		return rpcErrorf(rpcInvalidParams, "param %q: %v", name, err)
+	}
+	return nil
+}
+
+func (p rpcParams) str(i int) (string, error) {
+	var s string
+	err := p.decode(i, &s)
+	return strings.TrimSpace(s), err
+}
+
+func (p rpcParams) num(i, def int) (int, error) {
+	n := def
+	err := p.decode(i, &n)
+	return n, err
+}
+
+// RegisterRPCRoutes, JSON-RPC uç noktasını mux'a ekler.
+//
+//	POST /rpc  { jsonrpc:"2.0", method, params, id } | [ ...batch ]
+func RegisterRPCRoutes(mux Router) {
+	mux.HandleFunc("/rpc", serveRPC)
+}
+
+func serveRPC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		j(w, http.StatusUnsupportedMediaType, map[string]string{"error": "content type must be application/json"})
+		return
+	}
+	p := rpcPrincipal(r)
+	if cfg.RPCPassword != "" && !p.Authenticated() {
+		w.Header().Set("WWW-Authenticate", `Basic realm="quantumcoin-rpc"`)
+		j(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRPCBody))
+	if err != nil {
+		j(w, http.StatusOK, rpcResponse{JSONRPC: rpcProtocolVersion, Error: rpcErrorf(rpcParseError, "%v", err), ID: json.RawMessage("null")})
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			j(w, http.StatusOK, rpcResponse{JSONRPC: rpcProtocolVersion, Error: rpcErrorf(rpcParseError, "%v", err), ID: json.RawMessage("null")})
+			return
+		}
+		if len(batch) == 0 || len(batch) > maxRPCBatch {
+			j(w, http.StatusOK, rpcResponse{JSONRPC: rpcProtocolVersion, Error: rpcErrorf(rpcInvalidRequest, "batch must have 1..%d requests", maxRPCBatch), ID: json.RawMessage("null")})
+			return
+		}
+		out := make([]rpcResponse, 0, len(batch))
+		for _, item := range batch {
+			if resp, ok := handleRPC(item, p); ok {
+				out = append(out, resp)
+			}
+		}
+		if len(out) == 0 {
+			w.WriteHeader(http.StatusNoContent) // yalnız bildirimler
+			return
+		}
+		j(w, http.StatusOK, out)
+		return
+	}
+
+	resp, ok := handleRPC(body, p)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	j(w, http.StatusOK, resp)
+}
+
+// handleRPC: tek isteği işler; bildirimse ok=false (yanıt yok)
+func handleRPC(raw json.RawMessage, p *Principal) (rpcResponse, bool) {
+	resp := rpcResponse{JSONRPC: rpcProtocolVersion, ID: json.RawMessage("null")}
+	if !json.Valid(raw) {
+		resp.Error = rpcErrorf(rpcParseError, "invalid json")
+		return resp, true
+	}
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		resp.Error = rpcErrorf(rpcInvalidRequest, "%v", err)
+		return resp, true
+	}
+	notify := len(req.ID) == 0
+	if !notify {
+		resp.ID = req.ID
+	}
+	if req.JSONRPC != rpcProtocolVersion || req.Method == "" {
+		resp.Error = rpcErrorf(rpcInvalidRequest, `jsonrpc must be "2.0" and method set`)
+		return resp, true
+	}
+	res, rerr := CallRPCMethod(req.Method, req.Params, p)
+	if rerr != nil {
+		resp.Error = rerr
+		return resp, !notify
+	}
+	var err error
+	if resp.Result, err = json.Marshal(res); err != nil {
+		resp.Result, resp.Error = nil, rpcErrorf(rpcInternalError, "%v", err)
+	}
+	return resp, !notify
+}
+
+// CallRPCMethod: yöntemi p kimliğiyle çalıştırır; p yöntemin kapsamına sahip
+// olmalıdır (p nil ise yalnız read yöntemleri)
+func CallRPCMethod(method string, params json.RawMessage, p *Principal) (any, *RPCError) {
+	m, ok := rpcMethods[method]
+	if !ok {
+		return nil, rpcErrorf(rpcMethodNotFound, "method %q not found", method)
+	}
+	if scope := m.scope(); (p == nil && scope != ScopeRead) || (p != nil && !p.Has(scope)) {
+		return nil, rpcErrorf(rpcForbidden, "%s requires %s scope", method, scope)
+	}
+	args, err := parseRPCParams(params, m.Params)
+	if err != nil {
+		return nil, rpcErrorf(rpcInvalidParams, "%v", err)
+	}
+	if bc == nil {
+		return nil, rpcErrorf(rpcInternalError, "blockchain not ready")
+	}
+	res, err := m.Call(args)
+	if err != nil {
+		if re, ok := err.(*RPCError); ok {
+			return nil, re
+		}
+		return nil, rpcErrorf(rpcMiscError, "%v", err)
+	}
+	return res, nil
+}
+
+// rpcPrincipal: Protect'in iliştirdiği kimlik; Protect dışında yalnız sunulan
+// kimlik bilgisi değerlendirilir (loopback bağlantısına kimliksiz kapsam verilmez)
+func rpcPrincipal(r *http.Request) *Principal {
+	if p := PrincipalFrom(r); p != nil {
+		return p
+	}
+	if p, ok := authenticate(r, authConfig(), false); ok {
+		return p
+	}
+	return &Principal{Name: "anonymous", Kind: KindAnonymous}
+}
+
+// rpcAuthorized: istemcinin admin kapsamı var mı (anahtar, cookie, rpc_user/
+// rpc_password ya da api_loopback_scopes'ta admin verilmiş loopback kimliği)
+func rpcAuthorized(r *http.Request) bool {
+	return rpcPrincipal(r).Has(ScopeAdmin)
+}
+
+// RPCHelp: yöntem adı boşsa tüm yöntemlerin listesi, değilse kullanım satırı
+func RPCHelp(method string) (string, error) {
+	if method != "" {
+		m, ok := rpcMethods[method]
+		if !ok {
+			return "", rpcErrorf(rpcMethodNotFound, "method %q not found", method)
+		}
+		return rpcUsage(method, m), nil
+	}
+	names := make([]string, 0, len(rpcMethods))
+	for n := range rpcMethods {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, n := range names {
+		b.WriteString(rpcUsage(n, rpcMethods[n]))
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+func rpcUsage(name string, m rpcMethod) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, p := range m.Params {
+		if strings.HasSuffix(p, "?") {
+			b.WriteString(" [" + strings.TrimSuffix(p, "?") + "]")
+		} else {
+			b.WriteString(" <" + p + ">")
+		}
+	}
+	b.WriteString(" — " + m.Summary)
+	if s := m.scope(); s != ScopeRead {
+		b.WriteString(" (" + s + ")")
+	}
+	return b.String()
+}
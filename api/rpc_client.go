@@ -0,0 +1,60 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RPCClient: JSON-RPC uç noktası için basit istemci (CLI "rpc" komutu)
+type RPCClient struct {
+	URL      string
+	User     string
+	Password string
+	HTTP     *http.Client
+}
+
+// NewRPCClient: url için istemci (password boşsa kimlik gönderilmez)
+func NewRPCClient(url, user, password string) *RPCClient {
+	return &RPCClient{URL: url, User: user, Password: password, HTTP: &http.Client{Timeout: 60 * time.Second}}
+}
+
+// Call: method'u params (dizi ya da nesne) ile çağırır; RPC hatası *RPCError olarak döner
+func (c *RPCClient) Call(method string, params any) (json.RawMessage, error) {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(map[string]any{"jsonrpc": rpcProtocolVersion, "method": method, "params": params, "id": 1})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Password != "" {
+		req.SetBasicAuth(c.User, c.Password)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rpc: http %s", resp.Status)
+	}
+	var out struct {
+		Result json.RawMessage `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("rpc: bad response: %w", err)
+	}
+	if out.Error != nil {
+		return nil, out.Error
+	}
+	return out.Result, nil
+}
@@ -0,0 +1,534 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+	"quantumcoin/logging"
+	"quantumcoin/wallet"
+)
+
+// rpcMethods: yöntem tablosu (init'te kurulur; help tabloyu okuduğu için)
+var rpcMethods map[string]rpcMethod
+
+func init() {
+	rpcMethods = map[string]rpcMethod{
+		// --- zincir ---
+		"getblockchaininfo": {Summary: "network, height, best block hash, difficulty and supply", Call: rpcBlockchainInfo},
+		"getblockcount":     {Summary: "height of the best block", Call: func(rpcParams) (any, error) { return bc.GetBestHeight(), nil }},
+		"getbestblockhash":  {Summary: "hash of the best block", Call: rpcBestBlockHash},
+		"getblockhash":      {Params: []string{"height"}, Summary: "hash of the block at height", Call: rpcBlockHash},
+		"getblock": {Params: []string{"blockhash", "verbosity?"},
+			Summary: "block by hash (or height); verbosity 0 = hex, 1 = txids (default), 2 = full transactions", Call: rpcGetBlock},
+		"getrawtransaction": {Params: []string{"txid", "verbose?"},
+			Summary: "transaction from chain or mempool; hex unless verbose", Call: rpcRawTransaction},
+		"getsupply": {Summary: "minted, burned and circulating supply", Call: func(rpcParams) (any, error) { return bc.Supply(), nil }},
+		"getburns":  {Params: []string{"limit?"}, Summary: "burn outputs, newest first (default 100)", Call: rpcBurns},
+
+		// --- mempool ---
+		"getmempoolinfo":     {Summary: "mempool size", Call: rpcMempoolInfo},
+		"getrawmempool":      {Params: []string{"verbose?"}, Summary: "txids in the mempool (details if verbose)", Call: rpcRawMempool},
+		"sendrawtransaction": {Params: []string{"tx"}, Summary: "submit a signed transaction (hex or /api/tx JSON) and broadcast it", Call: rpcSendRaw},
+
+		// --- cüzdan ---
+		"getbalance":      {Params: []string{"address?"}, Summary: "balance and spendable balance (default: node wallet)", Call: rpcGetBalance},
+		"listunspent":     {Params: []string{"address?"}, Summary: "unspent outputs of address (default: node wallet)", Call: rpcListUnspent},
+		"validateaddress": {Params: []string{"address"}, Summary: "address validity and type", Call: rpcValidateAddress},
+		"getnewaddress":   {Summary: "new key in the node wallet store", Scope: ScopeWallet, Call: rpcNewAddress},
+		"sendtoaddress": {Params: []string{"address", "amount", "from?"},
+			Summary: "pay amount from a node wallet address (default wallet) and broadcast", Scope: ScopeWallet, Call: rpcSendToAddress},
+		"getstakeinfo": {Params: []string{"address"}, Summary: "stakes, totals and stake pool status", Call: rpcStakeInfo},
+
+		// --- madencilik ---
+		"getmininginfo":    {Summary: "height, difficulty, reward and mempool size", Call: rpcMiningInfo},
+		"getblocktemplate": {Params: []string{"address", "bits?"}, Summary: "block template paying address", Scope: ScopeMining, Call: rpcBlockTemplate},
+		"submitblock":      {Params: []string{"block"}, Summary: "submit a solved block (hex or submission object)", Scope: ScopeMining, Call: rpcSubmitBlock},
+		"generate": {Params: []string{"count", "address"},
+			Summary: "mine count blocks to address instantly (regtest only)", Scope: ScopeAdmin, Call: rpcGenerate},
+		"setgenerate": {Params: []string{"generate", "address?"},
+			Summary: "start (true) or stop (false) the in-process miner; address defaults to the node wallet", Scope: ScopeAdmin, Call: rpcSetGenerate},
+
+		// --- düğüm ---
+		"logging": {Params: []string{"subsystem?", "level?"},
+			Summary: "log levels per subsystem; with level, change subsystem (or \"all\") first", Scope: ScopeAdmin, Call: rpcLogging},
+
+		"help": {Params: []string{"method?"}, Summary: "list methods or show one method's usage", Call: rpcHelp},
+	}
+}
+
+// --- yardımcılar ---
+
+func rpcHexParam(p rpcParams, i int) ([]byte, error) {
+	s, err := p.str(i)
+	if err != nil {
+		return nil, err
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(b) == 0 {
+		return nil, rpcErrorf(rpcInvalidParams, "bad hex %q", s)
+	}
+	return b, nil
+}
+
+// rpcAddressParam: i. parametre ya da düğüm cüzdanının varsayılan adresi
+func rpcAddressParam(p rpcParams, i int) (string, error) {
+	addr, err := p.str(i)
+	if err != nil {
+		return "", err
+	}
+	if addr == "" {
+		if AddressProvider != nil {
+			addr = AddressProvider()
+		} else {
+			addr = wallet.LoadWalletFromFile().GetAddress()
+		}
+	}
+	if _, _, err := wallet.DecodeAddress(addr); err != nil {
+		return "", rpcErrorf(rpcInvalidAddress, "invalid address %q: %v", addr, err)
+	}
+	return addr, nil
+}
+
+// rpcChainError: zincir kural hatalarını -26'ya eşler
+func rpcChainError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return rpcErrorf(rpcVerifyRejected, "%v", err)
+}
+
+type rpcBlock = wire.Block
+
+func rpcBlockView(b *blockchain.Block, full bool) rpcBlock {
+	v := rpcBlock{
+		Hash:          hex.EncodeToString(b.Hash),
+		Height:        b.Index,
+		Version:       b.Version,
+		PreviousHash:  hex.EncodeToString(b.PrevHash),
+		Time:          b.Timestamp,
+		Bits:          b.Difficulty,
+		Nonce:         b.Nonce,
+		Miner:         b.Miner,
+		Confirmations: bc.GetBestHeight() - b.Index + 1,
+		Metadata:      b.Metadata,
+	}
+	for _, tx := range b.Transactions {
+		if full {
+			v.Txs = append(v.Txs, mapTxToDTO(tx))
+		} else {
+			v.TxIDs = append(v.TxIDs, hex.EncodeToString(tx.ID))
+		}
+	}
+	return v
+}
+
+// --- zincir ---
+
+func rpcBlockchainInfo(rpcParams) (any, error) {
+	last := bc.GetLastBlock()
+	best, bits := "", 0
+	if last != nil {
+		best, bits = hex.EncodeToString(last.Hash), last.Difficulty
+	}
+	return map[string]any{
+		"chain":         cfg.Params().Name,
+		"blocks":        bc.GetBestHeight(),
+		"bestblockhash": best,
+		"bits":          bits,
+		"genesis":       hex.EncodeToString(bc.GenesisHash()),
+		"supply":        bc.Supply(),
+	}, nil
+}
+
+func rpcBestBlockHash(rpcParams) (any, error) {
+	last := bc.GetLastBlock()
+	if last == nil {
+		return nil, rpcErrorf(rpcMiscError, "%v", blockchain.ErrNoBlocks)
+	}
+	return hex.EncodeToString(last.Hash), nil
+}
+
+func rpcBlockHash(p rpcParams) (any, error) {
+	h, err := p.num(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	b := bc.GetBlockByIndex(h)
+	if b == nil {
+		return nil, rpcErrorf(rpcInvalidAddress, "block height %d out of range", h)
+	}
+	return hex.EncodeToString(b.Hash), nil
+}
+
+func rpcGetBlock(p rpcParams) (any, error) {
+	var ref json.RawMessage
+	if err := p.decode(0, &ref); err != nil {
+		return nil, err
+	}
+	var blk *blockchain.Block
+	var height int
+	if json.Unmarshal(ref, &height) == nil {
+		blk = bc.GetBlockByIndex(height)
+	} else {
+		hash, err := rpcHexParam(p, 0)
+		if err != nil {
+			return nil, err
+		}
+		blk = bc.GetBlockByHash(hash)
+	}
+	if blk == nil {
+		return nil, rpcErrorf(rpcInvalidAddress, "block not found")
+	}
+	verbosity, err := p.num(1, 1)
+	if err != nil {
+		return nil, err
+	}
+	switch verbosity {
+	case 0:
+		return hex.EncodeToString(blk.Serialize()), nil
+	case 1, 2:
+		return rpcBlockView(blk, verbosity == 2), nil
+	}
+	return nil, rpcErrorf(rpcInvalidParams, "verbosity must be 0, 1 or 2")
+}
+
+func rpcRawTransaction(p rpcParams) (any, error) {
+	id, err := rpcHexParam(p, 0)
+	if err != nil {
+		return nil, err
+	}
+	verbose := false
+	if err := p.decode(1, &verbose); err != nil {
+		return nil, err
+	}
+	tx, height, ok := bc.FindTransaction(id)
+	if !ok {
+		for _, ptx := range bc.PendingTxs() {
+			if hex.EncodeToString(ptx.ID) == hex.EncodeToString(id) {
+				tx, ok = ptx, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, rpcErrorf(rpcInvalidAddress, "transaction not found")
+	}
+	if !verbose {
+		return hex.EncodeToString(tx.Serialize()), nil
+	}
+	res := map[string]any{"tx": mapTxToDTO(tx), "confirmations": 0}
+	if blk := bc.GetBlockByIndex(height); blk != nil {
+		res["blockhash"] = hex.EncodeToString(blk.Hash)
+		res["height"] = height
+		res["confirmations"] = bc.GetBestHeight() - height + 1
+	}
+	return res, nil
+}
+
+func rpcBurns(p rpcParams) (any, error) {
+	limit, err := p.num(0, 100)
+	if err != nil {
+		return nil, err
+	}
+	burns := bc.Burns(limit)
+	if burns == nil {
+		burns = []blockchain.BurnRecord{}
+	}
+	return burns, nil
+}
+
+// --- mempool ---
+
+func rpcMempoolInfo(rpcParams) (any, error) {
+	pending := bc.PendingTxs()
+	size := 0
+	for _, tx := range pending {
+		size += len(tx.Serialize())
+	}
+	return map[string]any{"size": len(pending), "bytes": size}, nil
+}
+
+func rpcRawMempool(p rpcParams) (any, error) {
+	verbose := false
+	if err := p.decode(0, &verbose); err != nil {
+		return nil, err
+	}
+	pending := bc.PendingTxs()
+	if verbose {
+		out := make(map[string]txDTO, len(pending))
+		for _, tx := range pending {
+			out[hex.EncodeToString(tx.ID)] = mapTxToDTO(tx)
+		}
+		return out, nil
+	}
+	ids := make([]string, 0, len(pending))
+	for _, tx := range pending {
+		ids = append(ids, hex.EncodeToString(tx.ID))
+	}
+	return ids, nil
+}
+
+func rpcSendRaw(p rpcParams) (any, error) {
+	var raw json.RawMessage
+	if err := p.decode(0, &raw); err != nil {
+		return nil, err
+	}
+	var tx *blockchain.Transaction
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		b, err := hex.DecodeString(strings.TrimSpace(s))
+		if err != nil {
+			return nil, rpcErrorf(rpcInvalidParams, "bad tx hex")
+		}
+		if tx = blockchain.DeserializeTransaction(b); tx == nil {
+			return nil, rpcErrorf(rpcInvalidParams, "tx decode failed")
+		}
+	} else {
+		var dto txDTO
+		if err := json.Unmarshal(raw, &dto); err != nil {
+			return nil, rpcErrorf(rpcInvalidParams, "tx: %v", err)
+		}
+		var err error
+		if tx, err = mapDTOToTx(dto); err != nil {
+			return nil, rpcErrorf(rpcInvalidParams, "tx: %v", err)
+		}
+	}
+	if err := bc.AddTransaction(tx); err != nil {
+		return nil, rpcChainError(err)
+	}
+	if TxBroadcaster != nil {
+		TxBroadcaster(tx)
+	}
+	return hex.EncodeToString(tx.ID), nil
+}
+
+// --- cüzdan ---
+
+func rpcGetBalance(p rpcParams) (any, error) {
+	addr, err := rpcAddressParam(p, 0)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"address":   addr,
+		"balance":   bc.GetBalance(addr),
+		"spendable": bc.GetSpendableBalance(addr),
+	}, nil
+}
+
+func rpcListUnspent(p rpcParams) (any, error) {
+	addr, err := rpcAddressParam(p, 0)
+	if err != nil {
+		return nil, err
+	}
+	utxos := bc.UnspentOutputs(addr)
+	if utxos == nil {
+		utxos = []blockchain.UnspentOutput{}
+	}
+	return utxos, nil
+}
+
+func rpcValidateAddress(p rpcParams) (any, error) {
+	addr, err := p.str(0)
+	if err != nil {
+		return nil, err
+	}
+	version, _, derr := wallet.DecodeAddress(addr)
+	res := map[string]any{"address": addr, "isvalid": derr == nil}
+	switch {
+	case derr != nil:
+		res["error"] = derr.Error()
+	case version == wallet.AddrVersionMultisig:
+		res["type"] = "multisig"
+	default:
+		res["type"] = "p2pkh"
+	}
+	return res, nil
+}
+
+func rpcNewAddress(rpcParams) (any, error) {
+	w := wallet.NewWallet()
+	if err := wallet.SaveWallet(w); err != nil {
+		return nil, rpcErrorf(rpcWalletError, "%v", err)
+	}
+	return w.GetAddress(), nil
+}
+
+func rpcSendToAddress(p rpcParams) (any, error) {
+	to, err := p.str(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := wallet.DecodeAddress(to); err != nil {
+		return nil, rpcErrorf(rpcInvalidAddress, "invalid address %q: %v", to, err)
+	}
+	amount, err := p.num(1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, rpcErrorf(rpcInvalidParams, "amount must be positive")
+	}
+	from, err := rpcAddressParam(p, 2)
+	if err != nil {
+		return nil, err
+	}
+	w, ok := wallet.LoadWalletByAddress(from)
+	if !ok {
+		return nil, rpcErrorf(rpcWalletError, "no key for %s in wallet store", from)
+	}
+	tx, err := blockchain.NewTransaction(from, to, amount, bc)
+	if err != nil {
+		return nil, rpcChainError(err)
+	}
+	if err := bc.SignTransaction(tx, w.PrivateKey); err != nil {
+		return nil, rpcErrorf(rpcWalletError, "%v", err)
+	}
+	if err := bc.AddTransaction(tx); err != nil {
+		return nil, rpcChainError(err)
+	}
+	if TxBroadcaster != nil {
+		TxBroadcaster(tx)
+	}
+	return hex.EncodeToString(tx.ID), nil
+}
+
+func rpcStakeInfo(p rpcParams) (any, error) {
+	addr, err := p.str(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := wallet.DecodeAddress(addr); err != nil {
+		return nil, rpcErrorf(rpcInvalidAddress, "invalid address %q: %v", addr, err)
+	}
+	return StakeSummary(addr), nil
+}
+
+// --- madencilik ---
+
+func rpcMiningInfo(rpcParams) (any, error) {
+	return map[string]any{
+		"chain":      cfg.Params().Name,
+		"blocks":     bc.GetBestHeight(),
+		"bits":       cfg.DefaultDifficultyBits,
+		"reward":     blockchain.RewardAt(time.Now().Unix()),
+		"pooledtx":   len(bc.PendingTxs()),
+		"generate":   cfg.Params().Generate,
+		"minbits":    cfg.Params().MinDifficulty,
+		"poolmining": Pool != nil,
+	}, nil
+}
+
+func rpcBlockTemplate(p rpcParams) (any, error) {
+	addr, err := p.str(0)
+	if err != nil {
+		return nil, err
+	}
+	bits, err := p.num(1, 0)
+	if err != nil {
+		return nil, err
+	}
+	t, err := BlockTemplateFor(addr, bits)
+	if err != nil {
+		return nil, rpcErrorf(rpcInvalidAddress, "%v", err)
+	}
+	return t, nil
+}
+
+func rpcSubmitBlock(p rpcParams) (any, error) {
+	var raw json.RawMessage
+	if err := p.decode(0, &raw); err != nil {
+		return nil, err
+	}
+	var sub blockchain.BlockSubmission
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		sub.Block = strings.TrimSpace(s)
+	} else if err := json.Unmarshal(raw, &sub); err != nil {
+		return nil, rpcErrorf(rpcInvalidParams, "block: %v", err)
+	}
+	blk, err := SubmitBlock(&sub)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrBadBlock) {
+			return nil, rpcErrorf(rpcInvalidParams, "%v", err)
+		}
+		return nil, rpcChainError(err)
+	}
+	return map[string]any{"hash": hex.EncodeToString(blk.Hash), "height": blk.Index}, nil
+}
+
+func rpcGenerate(p rpcParams) (any, error) {
+	count, err := p.num(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := p.str(1)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := Generate(count, addr)
+	if err != nil && len(blocks) == 0 {
+		if errors.Is(err, ErrGenerateDisabled) {
+			return nil, rpcErrorf(rpcForbidden, "%v", err)
+		}
+		return nil, rpcErrorf(rpcInvalidParams, "%v", err)
+	}
+	hashes := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		hashes = append(hashes, hex.EncodeToString(b.Hash))
+	}
+	return hashes, nil
+}
+
+func rpcSetGenerate(p rpcParams) (any, error) {
+	var on bool
+	if err := p.decode(0, &on); err != nil {
+		return nil, err
+	}
+	addr, err := p.str(1)
+	if err != nil {
+		return nil, err
+	}
+	if MinerControl == nil {
+		return nil, rpcErrorf(rpcMiscError, "in-process miner not available")
+	}
+	st, err := MinerControl(on, addr)
+	if err != nil {
+		return nil, rpcErrorf(rpcInvalidParams, "%v", err)
+	}
+	return st, nil
+}
+
+func rpcLogging(p rpcParams) (any, error) {
+	sub, err := p.str(0)
+	if err != nil {
+		return nil, err
+	}
+	level, err := p.str(1)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case level != "":
+		if err := logging.SetLevel(sub, level); err != nil {
+			return nil, rpcErrorf(rpcInvalidParams, "%v", err)
+		}
+		logger.Info("log level changed", "subsystem", sub, "new_level", level, "by", "rpc")
+	case sub != "":
+		return nil, rpcErrorf(rpcInvalidParams, "param \"level\" required with subsystem")
+	}
+	return logging.Levels(), nil
+}
+
+func rpcHelp(p rpcParams) (any, error) {
+	m, err := p.str(0)
+	if err != nil {
+		return nil, err
+	}
+	return RPCHelp(m)
+}
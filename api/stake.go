@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
+	"quantumcoin/stake"
+	"quantumcoin/wallet"
+)
+
+// StakePool: ağın Reward.StakePool adresi ayarlıysa main tarafından atanır (nil => dağıtım kapalı)
+var StakePool *stake.Distributor
+
+// RegisterStakeRoutes, zincir üstü staking uçlarını mux'a ekler. Anahtarı
+// düğüm cüzdanında olan adreslerin işlemi imzalanıp yayınlanır; diğerleri
+// için imzasız tx + signingHashes döner (imzalayıp /api/tx/send'e gönderin).
+//
+//	POST /api/stake/start    { address, amount, lockBlocks } -> { txid } | { tx, signingHashes }
+//	POST /api/stake/unstake  { address, txid, index }        -> aynı
+//	POST /api/stake/withdraw { address }                     -> aynı
+//	GET  /api/stake/status?address=                          -> stake'ler, toplamlar, kazanç
+//	GET  /api/stake/history[?address=]                       -> epoch dağıtımları
+func RegisterStakeRoutes(mux Router) {
+	mux.HandleFunc("/api/stake/start", stakeStart)
+	mux.HandleFunc("/api/stake/unstake", stakeUnstake)
+	mux.HandleFunc("/api/stake/withdraw", stakeWithdraw)
+	mux.HandleFunc("/api/stake/status", stakeStatus)
+	mux.HandleFunc("/api/stake/history", stakeHistory)
+}
+
+type ownerTxReq = wire.OwnerTxRequest
+
+// ownerTx: POST gövdesini çözer, build ile adresin işlemini kurar ve submitOwnerTx'e
+// verir (stake ve burn uçları ortak)
+func ownerTx(build func(req ownerTxReq) (*blockchain.Transaction, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		var req ownerTxReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+			return
+		}
+		req.Address = strings.TrimSpace(req.Address)
+		if _, _, err := wallet.DecodeAddress(req.Address); err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": "address: " + err.Error()})
+			return
+		}
+		tx, err := build(req)
+		if err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		submitOwnerTx(w, req, tx)
+	}
+}
+
+var (
+	stakeStart = ownerTx(func(req ownerTxReq) (*blockchain.Transaction, error) {
+		return blockchain.NewStakeTransaction(req.Address, req.Amount, req.LockBlocks, bc)
+	})
+	stakeUnstake = ownerTx(func(req ownerTxReq) (*blockchain.Transaction, error) {
+		return blockchain.NewUnstakeTransaction(req.Address, strings.TrimSpace(req.TxID), req.Index, bc)
+	})
+	stakeWithdraw = ownerTx(func(req ownerTxReq) (*blockchain.Transaction, error) {
+		return blockchain.NewWithdrawTransaction(req.Address, bc)
+	})
+)
+
+// submitOwnerTx: cüzdanda anahtar varsa imzala + mempool + yayın; yoksa imzasız döndür
+func submitOwnerTx(w http.ResponseWriter, req ownerTxReq, tx *blockchain.Transaction) {
+	wl, ok := wallet.LoadWalletByAddress(req.Address)
+	if !ok {
+		if req.Version != 0 {
+			if err := tx.SetVersion(req.Version); err != nil {
+				j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		hashes, err := signingHashes(tx)
+		if err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		dto := mapTxToDTO(tx)
+		j(w, http.StatusOK, wire.OwnerTxResult{Tx: &dto, SigningHashes: hashes})
+		return
+	}
+	if err := bc.SignTransaction(tx, wl.PrivateKey); err != nil {
+		j(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := bc.AddTransaction(tx); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if TxBroadcaster != nil {
+		TxBroadcaster(tx)
+	}
+	j(w, http.StatusOK, wire.OwnerTxResult{TxID: hex.EncodeToString(tx.ID)})
+}
+
+// StakeInfo: adresin stake durumu (HTTP ve CLI ortak)
+type StakeInfo struct {
+	Address      string             `json:"address"`
+	Height       int                `json:"height"`
+	Active       int                `json:"active"`
+	Unbonding    int                `json:"unbonding"`
+	Withdrawable int                `json:"withdrawable"`
+	Earned       int                `json:"earned"`
+	Stakes       []blockchain.Stake `json:"stakes"`
+	Pool         *stake.Status      `json:"pool,omitempty"`
+	Params       map[string]int     `json:"params"`
+}
+
+// StakeSummary: address'in stake'leri ve toplamları
+func StakeSummary(address string) StakeInfo {
+	s := StakeInfo{
+		Address: address,
+		Height:  bc.GetBestHeight(),
+		Stakes:  bc.Stakes(address),
+		Params: map[string]int{
+			"minAmount":      cfg.Params().Stake.MinAmount,
+			"minLockBlocks":  cfg.Params().Stake.MinLockBlocks,
+			"cooldownBlocks": cfg.Params().Stake.CooldownBlocks,
+			"epochBlocks":    cfg.StakeEpochBlocks,
+		},
+	}
+	if s.Stakes == nil {
+		s.Stakes = []blockchain.Stake{}
+	}
+	next := s.Height + 1 // bir sonraki blokta harcanabilir mi
+	for _, st := range s.Stakes {
+		switch {
+		case st.Status == blockchain.StakeActive:
+			s.Active += st.Amount
+		case next >= st.UnlockHeight:
+			s.Withdrawable += st.Amount
+		default:
+			s.Unbonding += st.Amount
+		}
+	}
+	if StakePool != nil {
+		ps := StakePool.Status()
+		s.Pool = &ps
+		s.Earned = StakePool.Earned(address)
+	}
+	return s
+}
+
+func stakeStatus(w http.ResponseWriter, r *http.Request) {
+	addr := strings.TrimSpace(r.URL.Query().Get("address"))
+	if _, _, err := wallet.DecodeAddress(addr); err != nil {
+		j(w, http.StatusBadRequest, map[string]string{"error": "address: " + err.Error()})
+		return
+	}
+	j(w, http.StatusOK, StakeSummary(addr))
+}
+
+func stakeHistory(w http.ResponseWriter, r *http.Request) {
+	if StakePool == nil {
+		j(w, http.StatusOK, []stake.Distribution{})
+		return
+	}
+	j(w, http.StatusOK, StakePool.History(strings.TrimSpace(r.URL.Query().Get("address"))))
+}
@@ -11,7 +11,7 @@ import (
 var AddressProvider func() string
 
 // RegisterWalletRoutes, /api/wallet/address endpoint'ini mux'a ekler.
-func RegisterWalletRoutes(mux *http.ServeMux) {
+func RegisterWalletRoutes(mux Router) {
 	mux.HandleFunc("/api/wallet/address", func(w http.ResponseWriter, r *http.Request) {
 		addr := ""
 		if AddressProvider != nil {
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"quantumcoin/webhook"
+)
+
+// Webhooks: main tarafından atanır (nil => webhook uçları 503 döner)
+var Webhooks *webhook.Service
+
+// RegisterWebhookRoutes, ödeme webhook'larının uçlarını mux'a ekler. Kanca
+// ekleme/silme ve yeniden gönderim RPC kimliği (ya da RPC parolası yoksa
+// loopback istemci) ister. Alıcı, X-QC-Signature başlığını kayıtta dönen
+// secret ile doğrular (bkz. webhook.Verify).
+//
+//	POST /api/webhooks                { url, addresses, txids, confirmations, secret? } -> kanca (+secret)
+//	GET  /api/webhooks                                                                  -> kancalar
+//	POST /api/webhooks/remove         { id }
+//	POST /api/webhooks/ping           { id }                                            -> teslim
+//	GET  /api/webhooks/deliveries?hook=&status=&limit=                                  -> teslimler (yeniden eskiye)
+//	POST /api/webhooks/redeliver      { id }                                            -> teslim
+func RegisterWebhookRoutes(mux Router) {
+	mux.HandleFunc("/api/webhooks", webhookHooks)
+	mux.HandleFunc("/api/webhooks/remove", webhookByID(func(id string) (any, error) {
+		return map[string]string{"removed": id}, Webhooks.Remove(id)
+	}))
+	mux.HandleFunc("/api/webhooks/ping", webhookByID(func(id string) (any, error) { return Webhooks.Ping(id) }))
+	mux.HandleFunc("/api/webhooks/redeliver", webhookByID(func(id string) (any, error) { return Webhooks.Redeliver(id) }))
+	mux.HandleFunc("/api/webhooks/deliveries", webhookDeliveries)
+}
+
+// webhookReady: servis yoksa 503 yazar
+func webhookReady(w http.ResponseWriter) bool {
+	if Webhooks == nil {
+		j(w, http.StatusServiceUnavailable, map[string]string{"error": "webhooks disabled"})
+		return false
+	}
+	return true
+}
+
+func webhookHooks(w http.ResponseWriter, r *http.Request) {
+	if !webhookReady(w) {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		j(w, http.StatusOK, Webhooks.Hooks())
+	case http.MethodPost:
+		if !rpcAuthorized(r) {
+			j(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		var h webhook.Hook
+		if err := json.NewDecoder(r.Body).Decode(&h); err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+			return
+		}
+		created, err := Webhooks.Register(h)
+		if err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		j(w, http.StatusCreated, created)
+	default:
+		j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// webhookByID: { id } gövdeli yetkili POST uçları
+func webhookByID(fn func(id string) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !webhookReady(w) {
+			return
+		}
+		if r.Method != http.MethodPost {
+			j(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		if !rpcAuthorized(r) {
+			j(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		var req struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			j(w, http.StatusBadRequest, map[string]string{"error": "bad json: " + err.Error()})
+			return
+		}
+		out, err := fn(strings.TrimSpace(req.ID))
+		switch {
+		case errors.Is(err, webhook.ErrNotFound):
+			j(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		case err != nil:
+			j(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			j(w, http.StatusOK, out)
+		}
+	}
+}
+
+func webhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if !webhookReady(w) {
+		return
+	}
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	j(w, http.StatusOK, Webhooks.Deliveries(strings.TrimSpace(q.Get("hook")), strings.TrimSpace(q.Get("status")), limit))
+}
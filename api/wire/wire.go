@@ -0,0 +1,409 @@
+// Package wire: HTTP API'nin istek/yanıt gövdeleri. Uçlar (api ve düğüm
+// main.go), OpenAPI belgesi (api/openapi.go) ve Go istemcisi (api/client) aynı
+// tipleri kullanır; alan eklenip çıkarıldığında üçü birlikte değişir.
+//
+// Paket yalnız blockchain'e bağımlıdır; madenci ve GUI gibi istemciler api
+// paketini (ve bağımlılıklarını) çekmeden kullanabilir.
+package wire
+
+import "quantumcoin/blockchain"
+
+// Error: hata gövdesi. api uçları "error", düğüm uçları (main.go)
+// { success: false, message } döner.
+type Error struct {
+	Error   string `json:"error,omitempty"`
+	Success *bool  `json:"success,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Health: GET /health ve /api/health
+type Health struct {
+	OK       bool   `json:"ok"`
+	Version  string `json:"version,omitempty"`
+	Time     string `json:"time"` // RFC3339
+	Height   int    `json:"height"`
+	HTTPPort string `json:"httpPort,omitempty"` // yalnız düğüm
+}
+
+// ----- zincir -----
+
+// BlockSummary: GET /api/blocks (api sunucusu) satırı, yeniden eskiye
+type BlockSummary struct {
+	Index    int    `json:"index"`
+	Hash     string `json:"hash"`
+	PrevHash string `json:"prevHash"`
+	Miner    string `json:"miner"`
+	TxCount  int    `json:"txCount"`
+}
+
+// NodeBlockSummary: GET /api/blocks?limit= (düğüm) satırı, eskiden yeniye
+type NodeBlockSummary struct {
+	Index      int    `json:"index"`
+	Hash       string `json:"hash"`
+	PrevHash   string `json:"prev_hash"`
+	Timestamp  int64  `json:"timestamp"`
+	Miner      string `json:"miner"`
+	Difficulty int    `json:"difficulty"`
+	TxCount    int    `json:"tx_count"`
+
+	Reward *blockchain.RewardBreakdown `json:"reward,omitempty"` // v2+ coinbase bölüşümü
+}
+
+// Block: blok görünümü (GET /api/block?id=, RPC getblock)
+type Block struct {
+	Hash          string            `json:"hash"`
+	Height        int               `json:"height"`
+	Version       int               `json:"version"`
+	PreviousHash  string            `json:"previousblockhash"`
+	Time          int64             `json:"time"`
+	Bits          int               `json:"bits"`
+	Nonce         int               `json:"nonce"`
+	Miner         string            `json:"miner"`
+	Confirmations int               `json:"confirmations"`
+	TxIDs         []string          `json:"tx,omitempty"`
+	Txs           []Tx              `json:"txs,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// MempoolTx: GET /api/mempool satırı
+type MempoolTx struct {
+	ID       string  `json:"id"`
+	Sender   string  `json:"sender"`
+	Amount   float64 `json:"amount"`
+	InCount  int     `json:"inputs"`
+	OutCount int     `json:"outputs"`
+	Time     string  `json:"time"`
+	Verifies bool    `json:"verifies"`
+	Coinbase bool    `json:"coinbase"`
+}
+
+// ----- işlemler (hex-string ile konuşmak için) -----
+
+// TxInput: işlem girdisi
+type TxInput struct {
+	TxID      string   `json:"txid"` // hex
+	OutIndex  int      `json:"n"`
+	Signature string   `json:"signature,omitempty"` // hex(v0/v1: DER low-S, v2: 64B Schnorr) — v1+: + 1 bayt sighash tipi
+	PubKey    string   `json:"pubKey,omitempty"`    // hex(v0/v1: 65B, v2: 33B)
+	Redeem    string   `json:"redeem,omitempty"`    // hex, multisig descriptor
+	Sigs      []string `json:"sigs,omitempty"`      // hex, multisig imzaları (descriptor sırası)
+}
+
+// Tx: işlem
+type Tx struct {
+	ID        string                         `json:"id,omitempty"` // hex
+	Inputs    []TxInput                      `json:"inputs"`
+	Outputs   []blockchain.TransactionOutput `json:"outputs"`
+	Timestamp string                         `json:"timestamp"` // RFC3339
+	Sender    string                         `json:"sender"`
+	Amount    float64                        `json:"amount"`
+	Version   int                            `json:"version"`
+}
+
+// BuildTxRequest: POST /api/tx/build
+type BuildTxRequest struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int    `json:"amount"`
+	// İmza şeması: 1 = ECDSA/DER (varsayılan, web cüzdan), 2 = Schnorr + compressed pubkey
+	Version int `json:"version,omitempty"`
+}
+
+// UnsignedTx: imzalanacak işlem ve girdi başına imza hash'leri
+type UnsignedTx struct {
+	Tx            Tx       `json:"tx"`
+	SigningHashes []string `json:"signingHashes"`
+}
+
+// BroadcastResult: POST /api/tx/send (imzalı işlem) yanıtı
+type BroadcastResult struct {
+	Accepted bool   `json:"accepted"`
+	ID       string `json:"id"`
+}
+
+// TxStatus: GET /api/tx/status?id=
+type TxStatus struct {
+	ID            string `json:"id"`
+	InBlock       bool   `json:"inBlock"`
+	InMempool     bool   `json:"inMempool"`
+	Height        *int   `json:"height,omitempty"`
+	Confirmations int    `json:"confirmations,omitempty"`
+}
+
+// TxView: GET /api/tx/{txid}
+type TxView struct {
+	Tx            Tx       `json:"tx"`
+	Status        string   `json:"status"` // "confirmed" | "mempool"
+	Height        int      `json:"height"` // -1 => mempool
+	Position      int      `json:"position"`
+	BlockHash     string   `json:"blockhash,omitempty"`
+	Confirmations int      `json:"confirmations"`
+	SpentBy       []string `json:"spentBy,omitempty"` // çıktı başına "txid:vin" ya da "" (indeks gerekir)
+}
+
+// OwnerTxRequest: stake/burn uçları (adresin kendi işlemi)
+type OwnerTxRequest struct {
+	Address    string `json:"address"`
+	Amount     int    `json:"amount,omitempty"`
+	LockBlocks int    `json:"lockBlocks,omitempty"`
+	TxID       string `json:"txid,omitempty"`
+	Index      int    `json:"index,omitempty"`
+	// Harici imza şeması (yalnız cüzdanda anahtar yoksa): 1 = ECDSA/DER, 2 = Schnorr
+	Version int `json:"version,omitempty"`
+}
+
+// OwnerTxResult: cüzdanda anahtar varsa { txid }, yoksa imzasız { tx, signingHashes }
+type OwnerTxResult struct {
+	TxID          string   `json:"txid,omitempty"`
+	Tx            *Tx      `json:"tx,omitempty"`
+	SigningHashes []string `json:"signingHashes,omitempty"`
+}
+
+// ----- adresler / explorer -----
+
+// AddressBalance: GET /api/address/balance?addr=
+type AddressBalance struct {
+	Address   string `json:"address"`
+	Balance   int    `json:"balance"`
+	Spendable int    `json:"spendable"`
+}
+
+// UTXO: GET /api/address/utxos?addr= satırı
+type UTXO struct {
+	TxID   string `json:"txid"`
+	N      int    `json:"n"`
+	Amount int    `json:"amount"`
+}
+
+// AddressTxs: GET /api/address/{addr}/txs sayfası
+type AddressTxs struct {
+	Address string                 `json:"address"`
+	Total   int                    `json:"total"`
+	Offset  int                    `json:"offset"`
+	Limit   int                    `json:"limit"`
+	Txs     []blockchain.AddressTx `json:"txs"`
+}
+
+// SearchResult: GET /api/search?q=; Path kaynağın API yolu
+type SearchResult struct {
+	Query   string `json:"query"`
+	Type    string `json:"type"` // "block" | "tx" | "address"
+	Height  *int   `json:"height,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+	TxID    string `json:"txid,omitempty"`
+	Address string `json:"address,omitempty"`
+	Path    string `json:"path"`
+}
+
+// ChainStats: GET /api/stats
+type ChainStats struct {
+	blockchain.ChainTotals
+	Difficulty int                    `json:"difficulty"`
+	Mempool    int                    `json:"mempool"`
+	Supply     blockchain.SupplyStats `json:"supply"`
+}
+
+// BurnHistory: GET /api/burns
+type BurnHistory struct {
+	Supply blockchain.SupplyStats  `json:"supply"`
+	Burns  []blockchain.BurnRecord `json:"burns"`
+}
+
+// ----- PSBT / multisig -----
+
+// PSBTCreateRequest: POST /api/psbt/create
+type PSBTCreateRequest struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int    `json:"amount"`
+}
+
+// PSBTRequest: inspect/sign/combine/finalize gövdesi
+type PSBTRequest struct {
+	PSBT      string   `json:"psbt"`
+	PSBTs     []string `json:"psbts,omitempty"`
+	PrivHex   string   `json:"priv_hex,omitempty"`
+	Broadcast bool     `json:"broadcast,omitempty"`
+}
+
+// PSBTResponse: PSBT ve özeti
+type PSBTResponse struct {
+	PSBT    string                  `json:"psbt"`
+	Signed  int                     `json:"signed,omitempty"`
+	Summary *blockchain.PSBTSummary `json:"summary"`
+}
+
+// PSBTFinalized: POST /api/psbt/finalize
+type PSBTFinalized struct {
+	ID       string `json:"id"`
+	Tx       Tx     `json:"tx"`
+	Accepted bool   `json:"accepted"`
+}
+
+// MultisigRequest: POST /api/multisig/new
+type MultisigRequest struct {
+	M       int      `json:"m"`
+	PubKeys []string `json:"pubkeys"` // hex
+}
+
+// Multisig: m-of-n adres ve descriptor'ı
+type Multisig struct {
+	Address    string `json:"address"`
+	Descriptor string `json:"descriptor"`
+	M          int    `json:"m"`
+	N          int    `json:"n"`
+}
+
+// ----- madencilik -----
+
+// WebJob: web madenci işi (GET /api/mine, solo). İstemci
+// sha256(left || nonce(8 bayt big-endian) || right) < 2^(256-bits) arar.
+type WebJob struct {
+	ID          string `json:"jobId"`
+	Miner       string `json:"miner"`
+	Left        string `json:"left"` // hex
+	Right       string `json:"right"`
+	NonceFormat string `json:"nonceFormat"`
+	Bits        int    `json:"bits"`
+	Height      int    `json:"height"`
+	Expires     int64  `json:"expires"`
+}
+
+// WebResult: çözüm gönderimi sonucu (reddedilen çözümler de 200 döner)
+type WebResult struct {
+	Accepted bool   `json:"accepted"`
+	Hash     string `json:"hash"`
+	Message  string `json:"message,omitempty"`
+}
+
+// WorkSubmit: POST /api/mine, /api/pool/submit, /api/mine/submit
+type WorkSubmit struct {
+	JobID string `json:"jobId"`
+	Nonce uint64 `json:"nonce"`
+}
+
+// MineJob: GET /api/mine/job (düğüm): sha256(challenge || nonce(8B big-endian) || suffix)
+// < 2^(256-difficulty). challenge kanonik blok başlığının ilk 72 baytıdır; solo
+// modda difficulty = blockBits, havuz modunda işçinin pay zorluğu.
+type MineJob struct {
+	Challenge  string `json:"challenge"`
+	Difficulty int    `json:"difficulty"`
+	Miner      string `json:"miner"`
+	Height     int    `json:"height"`
+	Expires    int64  `json:"expires"`
+
+	JobID       string `json:"jobId"`
+	Suffix      string `json:"suffix"`
+	NonceFormat string `json:"nonceFormat"`
+	BlockBits   int    `json:"blockBits"`
+}
+
+// MineSubmitResult: POST /api/mine/submit (düğüm)
+type MineSubmitResult struct {
+	Accepted bool   `json:"accepted"`
+	Hash     string `json:"hash"`
+	Message  string `json:"message,omitempty"`
+	Block    bool   `json:"block,omitempty"` // çözüm bloğu da çözdü (solo: her kabul)
+}
+
+// GenerateRequest: POST /api/regtest/generate
+type GenerateRequest struct {
+	Count   int    `json:"count"`
+	Address string `json:"address"`
+}
+
+// GenerateResult: üretilen blokların hash'leri; kısmi başarıda Error dolu
+type GenerateResult struct {
+	Hashes []string `json:"hashes"`
+	Height int      `json:"height"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// SubmitBlockResult: POST /api/mining/submitblock
+type SubmitBlockResult struct {
+	Accepted bool   `json:"accepted"`
+	Hash     string `json:"hash"`
+	Height   int    `json:"height"`
+}
+
+// ----- yönetim -----
+
+// LogLevels: GET/POST /api/admin/loglevel — alt sistem -> seviye
+type LogLevels struct {
+	Levels map[string]string `json:"levels"`
+}
+
+// SetLogLevelRequest: POST /api/admin/loglevel (subsystem "all" ya da boş => hepsi)
+type SetLogLevelRequest struct {
+	Subsystem string `json:"subsystem"`
+	Level     string `json:"level"`
+}
+
+// ----- düğüm (main.go) -----
+
+// AddressRequest: { address } gövdeli uçlar (/api/mine, /api/miner/start)
+type AddressRequest struct {
+	Address string `json:"address"`
+}
+
+// WalletResponse: /api/wallet/new ve /api/wallet/address
+type WalletResponse struct {
+	Address string `json:"address"`
+}
+
+// BalanceResponse: GET /api/wallet/balance/{addr}
+type BalanceResponse struct {
+	Balance   float64 `json:"balance"`
+	Spendable float64 `json:"spendable"`
+	Height    int     `json:"height"`
+}
+
+// SendRequest: POST /api/tx/send (düğüm cüzdanı; priv_hex verilirse onunla imzalanır)
+type SendRequest struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Amount  int    `json:"amount"`
+	PrivHex string `json:"priv_hex,omitempty"`
+}
+
+// SendResponse: POST /api/tx/send (düğüm)
+type SendResponse struct {
+	Success bool   `json:"success"`
+	TxID    string `json:"txid"`
+	Message string `json:"message,omitempty"`
+}
+
+// BurnRequest: POST /api/tx/burn (düğüm)
+type BurnRequest struct {
+	From   string `json:"from"`
+	Amount int    `json:"amount"`
+}
+
+// MineResult: POST /api/mine (düğüm, tek blok)
+type MineResult struct {
+	Success   bool   `json:"success"`
+	Reward    int    `json:"reward"`
+	Height    int    `json:"height"`
+	BlockHash string `json:"block_hash"`
+}
+
+// MinerState: /api/miner/start ve /api/miner/stop
+type MinerState struct {
+	Running    bool   `json:"running"`
+	Message    string `json:"message,omitempty"`
+	Address    string `json:"address,omitempty"`
+	Difficulty int    `json:"difficulty,omitempty"`
+	Height     int    `json:"height"`
+}
+
+// MinerStatus: GET /api/miner/status
+type MinerStatus struct {
+	Running  bool    `json:"running"`
+	Height   int     `json:"height"`
+	Bits     int     `json:"bits"`
+	HTTPPort string  `json:"httpPort"`
+	Hashrate float64 `json:"hashrate"`
+	Hashes   uint64  `json:"hashes"`
+	Restarts uint64  `json:"restarts"`
+	Threads  int     `json:"threads"`
+}
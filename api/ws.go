@@ -0,0 +1,411 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/events"
+	"quantumcoin/wallet"
+)
+
+// WebSocket abonelikleri (GET /ws). İstemci JSON komutları gönderir:
+//
+//	{ "op":"subscribe",   "topics":["tip","reorg","mempool","miner"],
+//	  "txids":[...], "addresses":[...], "confirmations":6 }
+//	{ "op":"unsubscribe", aynı alanlar }
+//	{ "op":"ping" }
+//
+// Sunucu { "type", "data" } mesajları yollar:
+//
+//	tip           yeni zincir ucu (her bağlanan blok)
+//	reorg         zincir değişti: ayrılma yüksekliği ve düşen blok hash'leri
+//	              (yeni dalın blokları ardından tip olarak gelir)
+//	mempool       mempool'a giren işlem
+//	miner         düğüm madencisinin durumu
+//	confirmation  izlenen txid'nin onay sayısı (hedefe ulaşınca izleme biter;
+//	              reorg'da "reorged", mempool'dan düşünce "dropped" alanı)
+//	address       izlenen adresi içeren işlem (mempool'da ya da blokta)
+//	subscribed / pong / error
+//
+// Olaylar events.Default'tan gelir; yavaş istemciye yetişemeyen olaylar düşer.
+const (
+	maxWSAddresses     = 1000
+	maxWSTxIDs         = 1000
+	maxWSMessage       = 64 << 10
+	wsWriteTimeout     = 10 * time.Second
+	wsEventBuffer      = 256
+	defaultWSConfirmed = 6
+)
+
+// RegisterWSRoutes, WebSocket abonelik uç noktasını mux'a ekler.
+//
+//	GET /ws  (WebSocket)
+func RegisterWSRoutes(mux Router) {
+	// Handshake nil: Origin denetlenmez (uç yalnız okuma yapar)
+	mux.Handle("/ws", websocket.Server{Handler: serveWS})
+}
+
+type wsRequest struct {
+	Op            string   `json:"op"`
+	Topics        []string `json:"topics"`
+	TxIDs         []string `json:"txids"`
+	Addresses     []string `json:"addresses"`
+	Confirmations int      `json:"confirmations"`
+}
+
+type wsMessage struct {
+	Type  string `json:"type"`
+	Data  any    `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// wsTx: izlenen işlemin durumu
+type wsTx struct {
+	height   int // bulunduğu blok; -1 => zincirde değil
+	reported int // son bildirilen onay sayısı; -1 => hiç
+}
+
+type wsClient struct {
+	mu      sync.Mutex
+	topics  map[string]bool
+	txids   map[string]*wsTx
+	addrs   map[string]bool
+	confirm int
+
+	disconnected []string // süren reorg'da düşen blok hash'leri
+}
+
+var wsTopics = map[string]bool{"tip": true, "reorg": true, "mempool": true, "miner": true}
+
+func serveWS(conn *websocket.Conn) {
+	defer conn.Close()
+	// http.Server'ın okuma/yazma süre sınırları ele geçirilen bağlantıda kalır
+	_ = conn.SetDeadline(time.Time{})
+	conn.MaxPayloadBytes = maxWSMessage
+
+	c := &wsClient{topics: map[string]bool{}, txids: map[string]*wsTx{}, addrs: map[string]bool{}, confirm: defaultWSConfirmed}
+	sub := events.Default.Subscribe("ws", wsEventBuffer,
+		events.BlockConnected{}, events.BlockDisconnected{}, events.TxAccepted{}, events.TxRemoved{}, events.MinerStatus{})
+	defer sub.Close()
+
+	replies := make(chan []wsMessage, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var req wsRequest
+			if err := websocket.JSON.Receive(conn, &req); err != nil {
+				return
+			}
+			select {
+			case replies <- c.command(req):
+			default: // istemci yanıtları okumuyor
+			}
+		}
+	}()
+
+	send := func(msgs []wsMessage) bool {
+		for _, m := range msgs {
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := websocket.JSON.Send(conn, m); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+	for {
+		select {
+		case <-done:
+			return
+		case msgs := <-replies:
+			if !send(msgs) {
+				return
+			}
+		case ev, ok := <-sub.C:
+			if !ok || !send(c.handle(ev)) {
+				return
+			}
+		}
+	}
+}
+
+// command: istemci komutunu uygular, yanıt mesajlarını döner
+func (c *wsClient) command(req wsRequest) []wsMessage {
+	switch req.Op {
+	case "ping":
+		return []wsMessage{{Type: "pong"}}
+	case "subscribe", "unsubscribe":
+	default:
+		return []wsMessage{{Type: "error", Error: "unknown op " + req.Op}}
+	}
+	add := req.Op == "subscribe"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []wsMessage
+	for _, t := range req.Topics {
+		if !wsTopics[t] {
+			return []wsMessage{{Type: "error", Error: "unknown topic " + t}}
+		}
+	}
+	for _, a := range req.Addresses {
+		if _, _, err := wallet.DecodeAddress(strings.TrimSpace(a)); err != nil {
+			return []wsMessage{{Type: "error", Error: "address " + a + ": " + err.Error()}}
+		}
+	}
+	for _, id := range req.TxIDs {
+		if b, err := hex.DecodeString(strings.TrimSpace(id)); err != nil || len(b) == 0 {
+			return []wsMessage{{Type: "error", Error: "bad txid " + id}}
+		}
+	}
+	if add && (len(c.addrs)+len(req.Addresses) > maxWSAddresses || len(c.txids)+len(req.TxIDs) > maxWSTxIDs) {
+		return []wsMessage{{Type: "error", Error: "too many subscriptions"}}
+	}
+
+	for _, t := range req.Topics {
+		if add {
+			c.topics[t] = true
+		} else {
+			delete(c.topics, t)
+		}
+	}
+	for _, a := range req.Addresses {
+		if a = strings.TrimSpace(a); add {
+			c.addrs[a] = true
+		} else {
+			delete(c.addrs, a)
+		}
+	}
+	if add && req.Confirmations > 0 {
+		c.confirm = req.Confirmations
+	}
+	for _, id := range req.TxIDs {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if !add {
+			delete(c.txids, id)
+			continue
+		}
+		st := &wsTx{height: -1, reported: -1}
+		c.txids[id] = st
+		if m, ok := c.confirmation(id, st, bc.GetBestHeight()); ok {
+			out = append(out, m)
+		}
+	}
+
+	topics := []string{}
+	for t := range c.topics {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+	return append([]wsMessage{{Type: "subscribed", Data: map[string]any{
+		"topics": topics, "txids": len(c.txids), "addresses": len(c.addrs), "confirmations": c.confirm,
+	}}}, out...)
+}
+
+// confirmation: txid'nin güncel onay sayısı değiştiyse bildirim (c.mu tutulur)
+func (c *wsClient) confirmation(id string, st *wsTx, tip int) (wsMessage, bool) {
+	if st.height < 0 {
+		if raw, err := hex.DecodeString(id); err == nil {
+			if _, h, ok := bc.FindTransaction(raw); ok {
+				st.height = h
+			}
+		}
+	}
+	data := map[string]any{"txid": id, "confirmations": 0}
+	confs := 0
+	if st.height >= 0 {
+		confs = tip - st.height + 1
+		data["confirmations"] = confs
+		data["height"] = st.height
+		if blk := bc.GetBlockByIndex(st.height); blk != nil {
+			data["blockhash"] = hex.EncodeToString(blk.Hash)
+		}
+	} else {
+		data["inMempool"] = wsInMempool(id)
+	}
+	if confs >= c.confirm {
+		delete(c.txids, id)
+	}
+	if confs == st.reported {
+		return wsMessage{}, false
+	}
+	st.reported = confs
+	return wsMessage{Type: "confirmation", Data: data}, true
+}
+
+// handle: veri yolu olayını istemcinin aboneliklerine göre mesajlara çevirir
+func (c *wsClient) handle(ev any) []wsMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []wsMessage
+	switch e := ev.(type) {
+	case events.BlockConnected:
+		blk := e.Block
+		if c.topics["tip"] {
+			out = append(out, wsMessage{Type: "tip", Data: wsBlockView(blk)})
+		}
+		for _, tx := range blk.Transactions {
+			out = append(out, c.addressActivity(tx, blk)...)
+			id := hex.EncodeToString(tx.ID)
+			if st, ok := c.txids[id]; ok && st.height < 0 {
+				st.height = blk.Index
+			}
+		}
+		for id, st := range c.txids {
+			if st.height < 0 {
+				continue
+			}
+			if m, ok := c.confirmation(id, st, blk.Index); ok {
+				out = append(out, m)
+			}
+		}
+
+	case events.BlockDisconnected:
+		// uçtan geriye gelir; son düşen (ForkHeight+1) reorg mesajını tamamlar
+		c.disconnected = append([]string{hex.EncodeToString(e.Block.Hash)}, c.disconnected...)
+		for id, st := range c.txids {
+			if st.height == e.Block.Index {
+				st.height, st.reported = -1, 0
+				out = append(out, wsMessage{Type: "confirmation", Data: map[string]any{
+					"txid": id, "confirmations": 0, "reorged": true,
+				}})
+			}
+		}
+		if e.Block.Index == e.ForkHeight+1 {
+			if c.topics["reorg"] {
+				out = append(out, wsMessage{Type: "reorg", Data: map[string]any{
+					"forkHeight":   e.ForkHeight,
+					"disconnected": c.disconnected,
+				}})
+			}
+			c.disconnected = nil
+		}
+
+	case events.TxAccepted:
+		tx := e.Tx
+		if c.topics["mempool"] {
+			out = append(out, wsMessage{Type: "mempool", Data: mapTxToDTO(tx)})
+		}
+		out = append(out, c.addressActivity(tx, nil)...)
+		if st, ok := c.txids[hex.EncodeToString(tx.ID)]; ok {
+			if m, ok := c.confirmation(hex.EncodeToString(tx.ID), st, bc.GetBestHeight()); ok {
+				out = append(out, m)
+			}
+		}
+
+	case events.TxRemoved:
+		id := hex.EncodeToString(e.Tx.ID)
+		if _, ok := c.txids[id]; ok && e.Reason != events.ReasonMined {
+			out = append(out, wsMessage{Type: "confirmation", Data: map[string]any{
+				"txid": id, "confirmations": 0, "dropped": e.Reason,
+			}})
+		}
+
+	case events.MinerStatus:
+		if !c.topics["miner"] {
+			return nil
+		}
+		data := map[string]any{
+			"active":   e.Active,
+			"threads":  e.Threads,
+			"hashrate": e.Hashrate,
+			"hashes":   e.Hashes,
+		}
+		if e.Block != nil {
+			data["blockHeight"] = e.Block.Index
+			data["blockHash"] = hex.EncodeToString(e.Block.Hash)
+			data["reward"] = e.Reward
+		}
+		out = append(out, wsMessage{Type: "miner", Data: data})
+	}
+	return out
+}
+
+// addressActivity: tx izlenen adreslerden birini içeriyorsa adres başına bildirim
+// (blk nil => mempool)
+func (c *wsClient) addressActivity(tx *blockchain.Transaction, blk *blockchain.Block) []wsMessage {
+	if len(c.addrs) == 0 {
+		return nil
+	}
+	received := map[string]int{}
+	sent := map[string]int{}
+	for i := range tx.Outputs {
+		if a := tx.Outputs[i].Address(); c.addrs[a] {
+			received[a] += tx.Outputs[i].Amount
+		}
+	}
+	if !tx.IsCoinbase() {
+		for _, in := range tx.Inputs {
+			prev, ok := wsPrevOutput(in.TxID, in.OutIndex)
+			if !ok {
+				continue
+			}
+			if a := prev.Address(); c.addrs[a] {
+				sent[a] += prev.Amount
+			}
+		}
+	}
+	var out []wsMessage
+	for a := range c.addrs {
+		r, rok := received[a]
+		s, sok := sent[a]
+		if !rok && !sok {
+			continue
+		}
+		data := map[string]any{
+			"address":   a,
+			"txid":      hex.EncodeToString(tx.ID),
+			"received":  r,
+			"sent":      s,
+			"confirmed": blk != nil,
+		}
+		if blk != nil {
+			data["height"] = blk.Index
+			data["blockhash"] = hex.EncodeToString(blk.Hash)
+		}
+		out = append(out, wsMessage{Type: "address", Data: data})
+	}
+	return out
+}
+
+// wsPrevOutput: input'un harcadığı çıktı (zincirde ya da mempool'da)
+func wsPrevOutput(txid []byte, idx int) (*blockchain.TransactionOutput, bool) {
+	if out, ok := bc.FindOutput(txid, idx); ok {
+		return out, true
+	}
+	for _, ptx := range bc.PendingTxs() {
+		if bytes.Equal(ptx.ID, txid) && idx >= 0 && idx < len(ptx.Outputs) {
+			out := ptx.Outputs[idx]
+			return &out, true
+		}
+	}
+	return nil, false
+}
+
+func wsInMempool(id string) bool {
+	for _, ptx := range bc.PendingTxs() {
+		if hex.EncodeToString(ptx.ID) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func wsBlockView(b *blockchain.Block) map[string]any {
+	return map[string]any{
+		"height":   b.Index,
+		"hash":     hex.EncodeToString(b.Hash),
+		"prevHash": hex.EncodeToString(b.PrevHash),
+		"time":     b.Timestamp,
+		"bits":     b.Difficulty,
+		"miner":    b.Miner,
+		"txCount":  len(b.Transactions),
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/gob"
-	"time"
 )
 
 type Block struct {
@@ -17,29 +16,7 @@ type Block struct {
 	Miner        string
 	Difficulty   int
 	Metadata     map[string]string
-}
-
-func NewBlock(index int, txs []*Transaction, prevHash []byte, miner string, difficulty int) *Block {
-	b := &Block{
-		Index:        index,
-		Timestamp:    time.Now().Unix(),
-		Transactions: txs,
-		PrevHash:     prevHash,
-		Hash:         nil,
-		Nonce:        0,
-		Miner:        miner,
-		Difficulty:   difficulty,
-		Metadata:     map[string]string{},
-	}
-
-	pow := NewProofOfWork(b)
-
-	nonce, hash := pow.Run()
-
-	b.Nonce = nonce
-	b.Hash = hash
-
-	return b
+	Version      int // BlockVersionLegacy (0, eski) | BlockVersionHeader (80 bayt başlık) | BlockVersionRewardSplit | BlockVersionBurnOutput; bkz. header.go
 }
 
 func (b *Block) HashTransactions() []byte {
@@ -51,8 +28,9 @@ func (b *Block) HashTransactions() []byte {
 
 	// prealloc: lint uyarısını sıfırlamak için kapasiteyi önceden ayarladık.
 	joined := make([][]byte, 0, len(b.Transactions))
+	// WitnessHash: v1+ işlemlerde imzalar txid dışında kaldığı için blok onları ayrıca bağlar
 	for _, tx := range b.Transactions {
-		joined = append(joined, tx.Hash())
+		joined = append(joined, tx.WitnessHash())
 	}
 
 	// bytes.Join'da ikinci parametreyi nil vermek hem daha temiz hem de linter dostu.
@@ -2,24 +2,39 @@ package blockchain
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
 	"encoding/gob"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"strings"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"quantumcoin/config"
+	"quantumcoin/logging"
 	"quantumcoin/wallet"
 )
 
+var logger = logging.For(logging.SubChain)
+
+// Blockchain: zincir, UTXO ve mempool. Dışa açık metotlar mu'yu kendileri alır
+// (okuyucular RLock, değiştirenler Lock); küçük harfli yardımcılar kilidin
+// tutulduğunu varsayar. Çağıranların ayrıca kilitlemesi gerekmez.
 type Blockchain struct {
+	mu sync.RWMutex
+
 	Blocks           []*Block
 	UTXO             map[string][]TransactionOutput
 	TotalSupply      int
 	coinbaseMaturity int
 	pendingTxs       []*Transaction
+	observer         ChainObserver // nil => olay bildirilmez (bkz. events.go)
+	index            *Index        // nil => indeks kapalı (bkz. index.go)
 }
 
 // Varsayılanlar (config yoksa devreye girer)
@@ -30,9 +45,11 @@ const (
 	InitialRewardDefault   = 50
 )
 
-func GetCurrentReward() int {
+func GetCurrentReward() int { return RewardAt(time.Now().Unix()) }
+
+// RewardAt: now anındaki blok sübvansiyonu (v2+ bloklar blok zaman damgasıyla doğrulanır)
+func RewardAt(now int64) int {
 	p := config.Current()
-	now := time.Now().Unix()
 
 	genesis := p.GenesisUnix
 	if genesis <= 0 {
@@ -67,129 +84,155 @@ func GetCurrentReward() int {
 	return r
 }
 
-func NewBlockchain(initialReward, totalSupply int) *Blockchain {
-	var txs []*Transaction
-
-	genesisCoinbase := &Transaction{
-		ID:        nil,
-		Inputs:    []TransactionInput{},
-		Outputs:   []TransactionOutput{{Amount: initialReward, PubKeyHash: []byte("genesis-recipient")}},
-		Timestamp: time.Now(),
-		Sender:    "COINBASE",
-		Amount:    float64(initialReward),
-	}
-	genesisCoinbase.ID = genesisCoinbase.Hash()
-	txs = append(txs, genesisCoinbase)
-
-	if mainAddr := strings.TrimSpace(os.Getenv("QC_MAIN_ADDRESS")); mainAddr != "" && totalSupply > 0 {
-		premine := int(float64(totalSupply) * 0.12)
-		if premine > 0 {
-			pk := wallet.Base58DecodeAddress(mainAddr)
-			premTx := &Transaction{
-				ID:        nil,
-				Inputs:    []TransactionInput{},
-				Outputs:   []TransactionOutput{{Amount: premine, PubKeyHash: pk}},
-				Timestamp: time.Now(),
-				Sender:    "COINBASE",
-				Amount:    float64(premine),
-			}
-			premTx.ID = premTx.Hash()
-			txs = append(txs, premTx)
-		}
+// NewBlockchain: ağın sabit genesis'iyle yeni zincir (bkz. genesis.go)
+func NewBlockchain(network string) (*Blockchain, error) {
+	spec, err := GenesisFor(network)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := spec.Block()
+	if err != nil {
+		return nil, err
 	}
-
-	genesis := NewBlock(0, txs, []byte{}, "genesis", 1)
-
 	bc := &Blockchain{
 		Blocks:      []*Block{genesis},
 		UTXO:        map[string][]TransactionOutput{},
-		TotalSupply: totalSupply,
+		TotalSupply: spec.TotalSupply,
 		pendingTxs:  []*Transaction{},
 	}
-	bc.UpdateUTXOSet()
-	return bc
+	bc.updateUTXOSet()
+	bc.observeTip()
+	return bc, nil
 }
 
 func (bc *Blockchain) SetCoinbaseMaturity(n int) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
 	if n < 0 {
 		n = 0
 	}
 	bc.coinbaseMaturity = n
 }
 
-func (bc *Blockchain) AddBlock(txs []*Transaction, miner string, difficulty int) *Block {
-	// Blok içindeki işlemleri doğrula (coinbase hariç imza zorunlu)
-	if err := bc.validateBlockTxs(txs); err != nil {
-		log.Printf("rejecting block: %v", err)
-		return nil
-	}
-
-	prev := bc.Blocks[len(bc.Blocks)-1]
-	nb := NewBlock(prev.Index+1, txs, prev.Hash, miner, difficulty)
-	bc.Blocks = append(bc.Blocks, nb)
-	bc.UpdateUTXOSet()
-	return nb
+func (bc *Blockchain) AddBlockFromPeer(blk *Block) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	start := time.Now()
+	err := bc.addBlockFromPeer(blk)
+	observeBlock(start, err == nil)
+	return err
 }
 
-func (bc *Blockchain) AddBlockFromPeer(blk *Block) error {
-	if !blk.ValidatePoW() {
-		return ErrInvalidPoW
+func (bc *Blockchain) addBlockFromPeer(blk *Block) error {
+	if err := checkBlockHash(blk); err != nil {
+		return err
 	}
-	if len(bc.Blocks) > 0 {
-		last := bc.Blocks[len(bc.Blocks)-1]
-		if !bytes.Equal(blk.PrevHash, last.Hash) {
-			return ErrPrevHashMismatch
-		}
+	if len(bc.Blocks) == 0 {
+		return ErrChainNotInitialized
+	}
+	if err := checkBlockLink(blk, bc.Blocks[len(bc.Blocks)-1], time.Now()); err != nil {
+		return err
 	}
 	// Peer'den gelen bloğun işlemlerini doğrula
-	if err := bc.validateBlockTxs(blk.Transactions); err != nil {
+	fees, err := bc.validateBlockTxs(blk.Transactions)
+	if err != nil {
+		return err
+	}
+	if err := validateBlockReward(blk, fees); err != nil {
 		return err
 	}
 
 	bc.Blocks = append(bc.Blocks, blk)
-	bc.UpdateUTXOSet()
+	bc.index.connect(blk)
+	bc.updateUTXOSet()
+	mBlocksConnected.Inc()
+	bc.observeTip()
+	if bc.observer != nil {
+		bc.observer.BlockConnected(blk)
+	}
+	bc.prunePending([]*Block{blk})
 	return nil
 }
 
 func (bc *Blockchain) IsValidChain() bool {
-	for i := 1; i < len(bc.Blocks); i++ {
-		if !bc.Blocks[i].ValidatePoW() || !bytes.Equal(bc.Blocks[i].PrevHash, bc.Blocks[i-1].Hash) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	for i, b := range bc.Blocks {
+		if checkBlockHash(b) != nil || (i > 0 && !bytes.Equal(b.PrevHash, bc.Blocks[i-1].Hash)) {
 			return false
 		}
 	}
 	return true
 }
 
-func (bc *Blockchain) GetHeight() int { return len(bc.Blocks) - 1 }
+func (bc *Blockchain) GetHeight() int {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return len(bc.Blocks) - 1
+}
 
 func (bc *Blockchain) ReplaceChain(blocks []*Block) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
 	if len(blocks) <= len(bc.Blocks) {
 		return ErrIncomingChainNotLonger
 	}
+	if err := bc.sameGenesis(blocks); err != nil {
+		return err
+	}
+	now := time.Now()
 	for i := 1; i < len(blocks); i++ {
-		if !blocks[i].ValidatePoW() || !bytes.Equal(blocks[i].PrevHash, blocks[i-1].Hash) {
-			return ErrIncomingChainInvalid
+		if err := checkBlockHash(blocks[i]); err != nil {
+			return fmt.Errorf("%w: %w", ErrIncomingChainInvalid, err)
+		}
+		if err := checkBlockLink(blocks[i], blocks[i-1], now); err != nil {
+			return fmt.Errorf("%w: %w", ErrIncomingChainInvalid, err)
 		}
-		// Zincir değiştirmede her bloğun işlemlerini denetle
-		if err := bc.validateBlockTxs(blocks[i].Transactions); err != nil {
+		// Zincir değiştirmede her bloğun işlemlerini, gelen zincirin o ana kadarki
+		// durumuna göre denetle
+		prefix := &Blockchain{Blocks: blocks[:i]}
+		fees, err := prefix.validateBlockTxs(blocks[i].Transactions)
+		if err != nil {
 			return fmt.Errorf("incoming chain invalid tx: %w", err)
 		}
+		if err := validateBlockReward(blocks[i], fees); err != nil {
+			return fmt.Errorf("incoming chain: %w", err)
+		}
 	}
+	old := bc.Blocks
 	bc.Blocks = blocks
-	bc.UpdateUTXOSet()
+	bc.index.reorg(old, blocks)
+	bc.updateUTXOSet()
+	bc.notifyChainChange(old, blocks)
+	bc.prunePending(blocks[forkHeight(old, blocks)+1:])
 	return nil
 }
 
-func (bc *Blockchain) GetAllBlocks() []*Block { return bc.Blocks }
+// GetAllBlocks: blok listesinin kopyası (bloklar eklendikten sonra değişmez)
+func (bc *Blockchain) GetAllBlocks() []*Block {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return append([]*Block(nil), bc.Blocks...)
+}
 
+// Harcanabilir çıktıları gerçek output indeksleriyle döner
+// (UTXO haritası sıkıştırılmış tuttuğu için indeksler zincirden okunur).
 func (bc *Blockchain) FindSpendableOutputs(pubKeyHash []byte, amount int) (map[string][]int, int) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
 	acc := 0
 	unspent := make(map[string][]int)
-	for txID, outs := range bc.UTXO {
-		for idx, out := range outs {
-			if out.IsLockedWithKey(pubKeyHash) {
+	_, pendingSpent := bc.pendingOutpoints()
+	for _, block := range bc.Blocks {
+		for _, tx := range block.Transactions {
+			txID := hex.EncodeToString(tx.ID)
+			for outIdx, out := range tx.Outputs {
+				if !out.IsLockedWithKey(pubKeyHash) || bc.isOutputSpent(tx.ID, outIdx) ||
+					pendingSpent[outpointKey(tx.ID, outIdx)] {
+					continue
+				}
 				acc += out.Amount
-				unspent[txID] = append(unspent[txID], idx)
+				unspent[txID] = append(unspent[txID], outIdx)
 				if acc >= amount {
 					return unspent, acc
 				}
@@ -199,30 +242,103 @@ func (bc *Blockchain) FindSpendableOutputs(pubKeyHash []byte, amount int) (map[s
 	return unspent, acc
 }
 
+// FindOutput: (txid, index) ile zincirdeki çıktıyı bulur (harcanmış olsa da)
+func (bc *Blockchain) FindOutput(txid []byte, outIdx int) (*TransactionOutput, bool) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.findOutput(txid, outIdx)
+}
+
+func (bc *Blockchain) findOutput(txid []byte, outIdx int) (*TransactionOutput, bool) {
+	for _, block := range bc.Blocks {
+		for _, tx := range block.Transactions {
+			if !bytes.Equal(tx.ID, txid) {
+				continue
+			}
+			if outIdx < 0 || outIdx >= len(tx.Outputs) {
+				return nil, false
+			}
+			out := tx.Outputs[outIdx]
+			return &out, true
+		}
+	}
+	return nil, false
+}
+
+// FindTransaction: zincirdeki işlem ve bulunduğu blok yüksekliği
+func (bc *Blockchain) FindTransaction(txid []byte) (*Transaction, int, bool) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	if bc.index != nil {
+		loc, ok := bc.index.Tx(hex.EncodeToString(txid))
+		if !ok {
+			return nil, -1, false
+		}
+		return bc.Blocks[loc.Height].Transactions[loc.Position], loc.Height, true
+	}
+	for height, block := range bc.Blocks {
+		for _, tx := range block.Transactions {
+			if bytes.Equal(tx.ID, txid) {
+				return tx, height, true
+			}
+		}
+	}
+	return nil, -1, false
+}
+
+// UnspentOutput: adresin harcanmamış serbest çıktısı (gerçek output indeksiyle)
+type UnspentOutput struct {
+	TxID     string `json:"txid"`
+	Index    int    `json:"vout"`
+	Amount   int    `json:"amount"`
+	Height   int    `json:"height"`
+	Coinbase bool   `json:"coinbase"`
+}
+
+// UnspentOutputs: address'in serbest bakiyesini oluşturan çıktılar (stake/burn hariç)
+func (bc *Blockchain) UnspentOutputs(address string) []UnspentOutput {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	_, pkh, err := wallet.DecodeAddress(address)
+	if err != nil {
+		return nil
+	}
+	var out []UnspentOutput
+	for height, block := range bc.Blocks {
+		for _, tx := range block.Transactions {
+			for idx, o := range tx.Outputs {
+				if !o.IsLockedWithKey(pkh) || bc.isOutputSpent(tx.ID, idx) {
+					continue
+				}
+				out = append(out, UnspentOutput{
+					TxID:     hex.EncodeToString(tx.ID),
+					Index:    idx,
+					Amount:   o.Amount,
+					Height:   height,
+					Coinbase: tx.IsCoinbase(),
+				})
+			}
+		}
+	}
+	return out
+}
+
 func (bc *Blockchain) UpdateUTXOSet() {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	bc.updateUTXOSet()
+}
+
+func (bc *Blockchain) updateUTXOSet() {
 	utxo := make(map[string][]TransactionOutput)
 	for _, block := range bc.Blocks {
 		for _, tx := range block.Transactions {
 			txID := hex.EncodeToString(tx.ID)
 			for outIdx, out := range tx.Outputs {
-				spent := false
-				for _, ob := range bc.Blocks {
-					if spent {
-						break
-					}
-					for _, otx := range ob.Transactions {
-						if spent {
-							break
-						}
-						for _, in := range otx.Inputs {
-							if hex.EncodeToString(in.TxID) == txID && in.OutIndex == outIdx {
-								spent = true
-								break
-							}
-						}
-					}
+				if out.LockType == LockBurn {
+					continue // harcanamaz; UTXO setine girmez
 				}
-				if !spent {
+				if !bc.isOutputSpent(tx.ID, outIdx) {
 					utxo[txID] = append(utxo[txID], out)
 				}
 			}
@@ -233,13 +349,21 @@ func (bc *Blockchain) UpdateUTXOSet() {
 
 // --- İMZA ZORUNLULUĞU: mempool’a eklemeden önce doğrula ---
 func (bc *Blockchain) AddTransaction(tx *Transaction) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if err := bc.addTransaction(tx); err != nil {
+		mMempoolRejected.Inc()
+		return err
+	}
+	mMempoolAccepted.Inc()
+	mMempoolSize.Set(float64(len(bc.pendingTxs)))
+	return nil
+}
+
+func (bc *Blockchain) addTransaction(tx *Transaction) error {
 	if tx == nil {
 		return ErrNilTransaction
 	}
-	// coinbase dışındaki işlemler imzalı ve doğrulanmış olmalı
-	if !tx.IsCoinbase() && !tx.Verify() {
-		return fmt.Errorf("invalid tx signature")
-	}
 	// basit kurallar
 	if len(tx.Outputs) == 0 {
 		return fmt.Errorf("empty outputs")
@@ -247,14 +371,26 @@ func (bc *Blockchain) AddTransaction(tx *Transaction) error {
 	if !tx.IsCoinbase() && len(tx.Inputs) == 0 {
 		return fmt.Errorf("empty inputs")
 	}
+	// zincir + bekleyen işlemler bağlamında input kuralları ve imzalar
+	if !tx.IsCoinbase() {
+		created, spent := bc.pendingOutpoints()
+		if _, err := bc.checkTxInputs(tx, created, spent, nil); err != nil {
+			return err
+		}
+	}
 
 	bc.pendingTxs = append(bc.pendingTxs, tx)
+	if bc.observer != nil {
+		bc.observer.TxAccepted(tx)
+	}
 	return nil
 }
 
 func (bc *Blockchain) GetSpendableBalance(address string) int {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
 	pubKeyHash := wallet.Base58DecodeAddress(address)
-	best := bc.GetBestHeight()
+	best := bc.bestHeight()
 	spend := 0
 
 	for height, block := range bc.Blocks {
@@ -277,6 +413,11 @@ func (bc *Blockchain) GetSpendableBalance(address string) int {
 }
 
 func (bc *Blockchain) GetBalance(address string) int {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	if bc.index != nil {
+		return bc.index.Address(address).Balance
+	}
 	pubKeyHash := wallet.Base58DecodeAddress(address)
 	total := 0
 	for _, outs := range bc.UTXO {
@@ -290,6 +431,12 @@ func (bc *Blockchain) GetBalance(address string) int {
 }
 
 func (bc *Blockchain) TotalMinted() int {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.totalMinted()
+}
+
+func (bc *Blockchain) totalMinted() int {
 	total := 0
 	for _, b := range bc.Blocks {
 		for _, tx := range b.Transactions {
@@ -299,48 +446,99 @@ func (bc *Blockchain) TotalMinted() int {
 				}
 			}
 		}
+		if b.Version >= BlockVersionMinerFees {
+			total -= bc.blockFees(b) // coinbase'e giren ücretler yeni arz değildir
+		}
 	}
 	return total
 }
 
-func (bc *Blockchain) MineBlock(miner string, difficulty int) (*Block, error) {
-	if len(bc.Blocks) == 0 {
-		return nil, ErrChainNotInitialized
+// blockFees: bloktaki coinbase dışı işlemlerin ücret toplamı
+func (bc *Blockchain) blockFees(b *Block) int {
+	created := map[string]TransactionOutput{}
+	fees := 0
+	for _, tx := range b.Transactions {
+		if !tx.IsCoinbase() {
+			fees += bc.txFee(tx, created)
+		}
+		addCreated(created, tx)
 	}
+	return fees
+}
 
-	// >>> EKLENDİ: yalnız başarılı kazımda mined_balance.json yaz (defer ile güvenli)
-	var _minedOK bool
-	_minedReward := GetCurrentReward()
-	defer func() {
-		if _minedOK && _minedReward > 0 {
-			AddMinedBalance(miner, _minedReward)
-		}
-	}()
+// MineBlock: tüm çekirdeklerle, iptalsiz kazım (bkz. MineBlockContext)
+func (bc *Blockchain) MineBlock(miner string, difficulty int) (*Block, error) {
+	return bc.MineBlockContext(context.Background(), miner, difficulty, 0, nil)
+}
 
-	cbTx, err := newCoinbaseTx(miner)
+// MineBlockContext: aday bloğu threads goroutine ile kazar (0 => CPU sayısı) ve zincire ekler.
+// ctx iptal edilirse ctx.Err() döner; arama sürerken zincir ucu değiştiyse ErrStaleBlock.
+// hashes nil değilse yapılan deneme sayısı eklenir (hashrate ölçümü).
+func (bc *Blockchain) MineBlockContext(ctx context.Context, miner string, difficulty, threads int, hashes *atomic.Uint64) (*Block, error) {
+	nb, err := bc.NewCandidateBlock(miner, difficulty)
 	if err != nil {
-		return nil, fmt.Errorf("coinbase tx: %w", err) // wrapcheck
+		return nil, err
 	}
-
-	// pending kopyasını al, coinbase ile birleştir ve doğrula
-	txs := append([]*Transaction{cbTx}, bc.PendingTxs()...)
-	if err := bc.validateBlockTxs(txs); err != nil {
+	reward := nb.Transactions[0].Outputs[0].Amount // madencinin payı
+	nonce, hash, err := NewProofOfWork(nb).RunContext(ctx, threads, hashes)
+	if err != nil {
 		return nil, err
 	}
+	nb.Nonce, nb.Hash = nonce, hash
 
-	prev := bc.Blocks[len(bc.Blocks)-1]
-	nb := NewBlock(prev.Index+1, txs, prev.Hash, miner, difficulty)
+	// Zincire ekle; mempool'dan yalnız bloğa girenler (ve geçersizleşenler) düşer
+	if err := bc.AddBlockFromPeer(nb); err != nil {
+		if errors.Is(err, ErrPrevHashMismatch) {
+			return nil, ErrStaleBlock
+		}
+		return nil, err
+	}
+	// yalnız başarılı kazımda mined_balance.json yaz
+	if reward > 0 {
+		AddMinedBalance(miner, reward)
+	}
+	return nb, nil
+}
 
-	bc.Blocks = append(bc.Blocks, nb)
-	bc.UpdateUTXOSet()
-	bc.pendingTxs = []*Transaction{} // mempool’u boşalt
+// NewCandidateBlock: coinbase + mempool ile çözülmemiş blok (Nonce/Hash boş).
+// Harici madenciler HeaderPrefix üzerinde nonce arar; çözüm AddBlockFromPeer ile eklenir.
+func (bc *Blockchain) NewCandidateBlock(miner string, difficulty int) (*Block, error) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.newCandidateBlock(miner, difficulty)
+}
 
-	// >>> EKLENDİ: başarı bayrağı
-	_minedOK = true
-	return nb, nil
+func (bc *Blockchain) newCandidateBlock(miner string, difficulty int) (*Block, error) {
+	if len(bc.Blocks) == 0 {
+		return nil, ErrChainNotInitialized
+	}
+	now := time.Now().Unix()
+	// ücretler coinbase'e girdiği için önce bekleyen işlemler doğrulanır
+	fees, err := bc.validateBlockTxs(bc.pendingTxs)
+	if err != nil {
+		return nil, err
+	}
+	cbTx, err := newCoinbaseTx(miner, now, fees)
+	if err != nil {
+		return nil, fmt.Errorf("coinbase tx: %w", err)
+	}
+	txs := append([]*Transaction{cbTx}, bc.pendingTxs...)
+	prev := bc.Blocks[len(bc.Blocks)-1]
+	return &Block{
+		Index:        prev.Index + 1,
+		Timestamp:    now,
+		Transactions: txs,
+		PrevHash:     prev.Hash,
+		Miner:        miner,
+		Difficulty:   difficulty,
+		Metadata:     map[string]string{},
+		Version:      CurrentBlockVersion,
+	}, nil
 }
 
 func SerializeBlockchain(bc *Blockchain) []byte {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
 	var buf bytes.Buffer
 	if err := gob.NewEncoder(&buf).Encode(bc); err != nil {
 		log.Panicf("serialize error: %v", err)
@@ -368,26 +566,48 @@ func LoadBlockchainFromFile(filename string) (*Blockchain, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read blockchain file: %w", err) // wrapcheck
 	}
-	return DeserializeBlockchain(data), nil
+	bc := DeserializeBlockchain(data)
+	bc.observeTip()
+	return bc, nil
 }
 
 // Helpers
 
 func (bc *Blockchain) GetBestHeight() int {
-	if bc == nil || len(bc.Blocks) == 0 {
+	if bc == nil {
+		return -1
+	}
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.bestHeight()
+}
+
+func (bc *Blockchain) bestHeight() int {
+	if len(bc.Blocks) == 0 {
 		return -1
 	}
 	return bc.Blocks[len(bc.Blocks)-1].Index
 }
 
 func (bc *Blockchain) GetLastBlock() *Block {
-	if bc == nil || len(bc.Blocks) == 0 {
+	if bc == nil {
+		return nil
+	}
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.lastBlock()
+}
+
+func (bc *Blockchain) lastBlock() *Block {
+	if len(bc.Blocks) == 0 {
 		return nil
 	}
 	return bc.Blocks[len(bc.Blocks)-1]
 }
 
 func (bc *Blockchain) GetBlockByIndex(idx int) *Block {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
 	for _, b := range bc.Blocks {
 		if b.Index == idx {
 			return b
@@ -397,6 +617,12 @@ func (bc *Blockchain) GetBlockByIndex(idx int) *Block {
 }
 
 func (bc *Blockchain) GetBlockByHash(hash []byte) *Block {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.blockByHash(hash)
+}
+
+func (bc *Blockchain) blockByHash(hash []byte) *Block {
 	for _, b := range bc.Blocks {
 		if bytes.Equal(b.Hash, hash) {
 			return b
@@ -406,6 +632,9 @@ func (bc *Blockchain) GetBlockByHash(hash []byte) *Block {
 }
 
 func (bc *Blockchain) isOutputSpent(txid []byte, outIdx int) bool {
+	if spent, ok := bc.index.isSpent(txid, outIdx); ok {
+		return spent
+	}
 	for _, blk := range bc.Blocks {
 		for _, tx := range blk.Transactions {
 			for _, in := range tx.Inputs {
@@ -420,28 +649,258 @@ func (bc *Blockchain) isOutputSpent(txid []byte, outIdx int) bool {
 
 // ---- Eklenen yardımcılar ----
 
-// Blok içindeki tüm işlemleri doğrula (coinbase için sadece output kuralı)
-func (bc *Blockchain) validateBlockTxs(txs []*Transaction) error {
+// Blok içindeki tüm işlemleri doğrula (coinbase için sadece output kuralı);
+// coinbase dışı işlemlerin ücret toplamını döner
+func (bc *Blockchain) validateBlockTxs(txs []*Transaction) (int, error) {
+	fees := 0
+	created := map[string]TransactionOutput{}
+	spent := map[string]bool{}
+	// v2 Schnorr imzaları blok sonunda tek seferde doğrulanır
+	batch := &schnorrBatch{}
 	for _, tx := range txs {
 		if tx == nil {
-			return fmt.Errorf("nil tx")
+			return 0, fmt.Errorf("nil tx")
 		}
 		if tx.IsCoinbase() {
 			if len(tx.Outputs) == 0 {
-				return fmt.Errorf("invalid coinbase (no outputs)")
+				return 0, fmt.Errorf("invalid coinbase (no outputs)")
 			}
+			addCreated(created, tx)
 			continue
 		}
-		if !tx.Verify() {
-			return fmt.Errorf("invalid tx signature")
+		fee, err := bc.checkTxInputs(tx, created, spent, batch)
+		if err != nil {
+			return 0, fmt.Errorf("tx %x: %w", tx.ID, err)
 		}
+		fees += fee
+	}
+	if !batch.verify() {
+		return 0, fmt.Errorf("invalid tx signature (schnorr batch)")
+	}
+	return fees, nil
+}
+
+// checkBlockVersion: bilinmeyen sürüm ya da önceki bloktan düşük sürüm reddedilir
+// (kanonik başlığa geçen zincir eski PoW düzenine dönemez).
+func checkBlockVersion(blk, prev *Block) error {
+	if blk.Version < BlockVersionLegacy || blk.Version > CurrentBlockVersion || blk.Version < prev.Version {
+		return ErrBlockVersion
+	}
+	return nil
+}
+
+// checkBlockHash: PoW ve saklanan Hash. Hash başlıktan yeniden hesaplanır; sonraki
+// blokların PrevHash'i buna bağlandığı için başlıkla tutmayan hash reddedilir.
+func checkBlockHash(blk *Block) error {
+	if !blk.ValidatePoW() {
+		return ErrInvalidPoW
+	}
+	if !bytes.Equal(blk.Hash, blk.PoWHash()) {
+		return ErrBlockHash
 	}
 	return nil
 }
 
+// maxFutureBlockTime: blok zamanının düğüm saatini aşabileceği pay
+const maxFutureBlockTime = 2 * time.Hour
+
+// checkBlockLink: bloğun önceki bloğa bağlanma kuralları; zincire giren her yol
+// (AddBlockFromPeer: MineBlockContext/SubmitBlock/p2p, ReplaceChain) aynı denetimi uygular:
+// PrevHash, Index = prev.Index+1, sürüm, prev.Timestamp <= Timestamp <= now+maxFutureBlockTime
+// ve ağın en düşük zorluğu.
+func checkBlockLink(blk, prev *Block, now time.Time) error {
+	if !bytes.Equal(blk.PrevHash, prev.Hash) {
+		return ErrPrevHashMismatch
+	}
+	if blk.Index != prev.Index+1 {
+		return fmt.Errorf("%w: %d after %d", ErrBlockIndex, blk.Index, prev.Index)
+	}
+	if err := checkBlockVersion(blk, prev); err != nil {
+		return err
+	}
+	if blk.Timestamp < prev.Timestamp || blk.Timestamp > now.Add(maxFutureBlockTime).Unix() {
+		return ErrBlockTimeRange
+	}
+	if blk.Difficulty < config.Current().Params().MinDifficulty {
+		return ErrLowDifficulty
+	}
+	return nil
+}
+
+func outpointKey(txid []byte, n int) string {
+	return hex.EncodeToString(txid) + ":" + strconv.Itoa(n)
+}
+
+func addCreated(created map[string]TransactionOutput, tx *Transaction) {
+	for idx, out := range tx.Outputs {
+		created[outpointKey(tx.ID, idx)] = out
+	}
+}
+
+// checkTxInputs: zincir bağlamında input kuralları:
+// harcanan çıktı var (zincirde ya da created içinde), harcanmamış, kilidi input'la açılıyor,
+// imzalar harcanan çıktılarla geçerli (batch verilirse v2 imzaları ona eklenir),
+// staking kuralları (stake.go; işlem bir sonraki blok yüksekliğinde değerlendirilir)
+// ve input toplamı >= output toplamı. Başarılıysa spent/created güncellenir ve
+// ücret (input - output) döner.
+func (bc *Blockchain) checkTxInputs(tx *Transaction, created map[string]TransactionOutput, spent map[string]bool, batch *schnorrBatch) (int, error) {
+	inSum, outSum := 0, 0
+	ins := make([]TransactionOutput, 0, len(tx.Inputs))
+	for i := range tx.Inputs {
+		in := &tx.Inputs[i]
+		key := outpointKey(in.TxID, in.OutIndex)
+		if spent[key] {
+			return 0, fmt.Errorf("input %d: %w", i, ErrDoubleSpend)
+		}
+		out, ok := created[key]
+		if !ok {
+			found, inChain := bc.findOutput(in.TxID, in.OutIndex)
+			if !inChain {
+				return 0, fmt.Errorf("input %d: %w", i, ErrMissingInput)
+			}
+			if bc.isOutputSpent(in.TxID, in.OutIndex) {
+				return 0, fmt.Errorf("input %d: %w", i, ErrDoubleSpend)
+			}
+			out = *found
+		}
+		if out.LockType == LockBurn {
+			return 0, fmt.Errorf("input %d: %w", i, ErrUnspendable)
+		}
+		if !out.CanBeUnlockedBy(in) {
+			return 0, fmt.Errorf("input %d: %w", i, ErrLockMismatch)
+		}
+		inSum += out.Amount
+		ins = append(ins, out)
+	}
+	if !tx.verify(ins, batch) {
+		return 0, ErrInvalidSignature
+	}
+	if err := checkStakeRules(tx, ins, len(bc.Blocks)); err != nil {
+		return 0, err
+	}
+	for _, out := range tx.Outputs {
+		if out.Amount <= 0 {
+			return 0, ErrInvalidAmount
+		}
+		if err := checkOutputLock(out); err != nil {
+			return 0, err
+		}
+		outSum += out.Amount
+	}
+	if inSum < outSum {
+		return 0, ErrInputsBelowOutputs
+	}
+	for i := range tx.Inputs {
+		spent[outpointKey(tx.Inputs[i].TxID, tx.Inputs[i].OutIndex)] = true
+	}
+	addCreated(created, tx)
+	return inSum - outSum, nil
+}
+
+// SpentOutputs: tx input'larının harcadığı çıktılar, input sırasıyla (zincir ya da
+// bekleyen işlemler). İmzalama ve zincir dışı doğrulama için (bkz. Transaction.Sign).
+func (bc *Blockchain) SpentOutputs(tx *Transaction) ([]TransactionOutput, error) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	created, _ := bc.pendingOutpoints()
+	prevs := make([]TransactionOutput, len(tx.Inputs))
+	for i, in := range tx.Inputs {
+		if out, ok := created[outpointKey(in.TxID, in.OutIndex)]; ok {
+			prevs[i] = out
+			continue
+		}
+		out, ok := bc.findOutput(in.TxID, in.OutIndex)
+		if !ok {
+			return nil, fmt.Errorf("input %d: %w", i, ErrMissingInput)
+		}
+		prevs[i] = *out
+	}
+	return prevs, nil
+}
+
+// SignTransaction: harcanan çıktıları bulup tüm input'ları priv ile imzalar (SIGHASH_ALL)
+func (bc *Blockchain) SignTransaction(tx *Transaction, priv *ecdsa.PrivateKey) error {
+	prevs, err := bc.SpentOutputs(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Sign(priv, prevs)
+}
+
+// VerifyTransaction: imzaları zincirdeki harcanan çıktılarla doğrular
+// (çift harcama vb. kurallar AddTransaction'da)
+func (bc *Blockchain) VerifyTransaction(tx *Transaction) bool {
+	if tx.IsCoinbase() {
+		return tx.Verify(nil)
+	}
+	prevs, err := bc.SpentOutputs(tx)
+	return err == nil && tx.Verify(prevs)
+}
+
+// pendingOutpoints: bekleyen işlemlerin harcadığı ve ürettiği çıktılar
+// (onaysız zincirleme harcamaya izin verir, mempool içi çift harcamayı engeller)
+func (bc *Blockchain) pendingOutpoints() (map[string]TransactionOutput, map[string]bool) {
+	created := map[string]TransactionOutput{}
+	spent := map[string]bool{}
+	for _, ptx := range bc.pendingTxs {
+		for _, in := range ptx.Inputs {
+			spent[outpointKey(in.TxID, in.OutIndex)] = true
+		}
+		addCreated(created, ptx)
+	}
+	return created, spent
+}
+
+// prunePending: yeni bağlanan bloklara giren ve artık geçersiz olan bekleyen
+// işlemleri düşürür
+func (bc *Blockchain) prunePending(connected []*Block) {
+	mined := map[string]bool{}
+	for _, blk := range connected {
+		for _, tx := range blk.Transactions {
+			mined[hex.EncodeToString(tx.ID)] = true
+		}
+	}
+	created := map[string]TransactionOutput{}
+	spent := map[string]bool{}
+	kept := bc.pendingTxs[:0]
+	var removed []*Transaction
+	var reasons []string
+	for _, tx := range bc.pendingTxs {
+		if mined[hex.EncodeToString(tx.ID)] {
+			removed, reasons = append(removed, tx), append(reasons, TxRemovedMined)
+			continue
+		}
+		if _, err := bc.checkTxInputs(tx, created, spent, nil); err != nil {
+			logger.Info("pending tx dropped", "txid", hex.EncodeToString(tx.ID), "err", err)
+			removed, reasons = append(removed, tx), append(reasons, err.Error())
+			continue
+		}
+		kept = append(kept, tx)
+	}
+	bc.pendingTxs = kept
+	mMempoolSize.Set(float64(len(kept)))
+	for _, r := range reasons {
+		if r == TxRemovedMined {
+			mMempoolRemoved.With(TxRemovedMined).Inc()
+		} else {
+			mMempoolRemoved.With("invalid").Inc()
+		}
+	}
+	if bc.observer != nil {
+		for i, tx := range removed {
+			bc.observer.TxRemoved(tx, reasons[i])
+		}
+	}
+}
+
 // pendingTxs'in güvenli kopyası (API/mine kullanımı için)
 func (bc *Blockchain) PendingTxs() []*Transaction {
-	if bc == nil || bc.pendingTxs == nil {
+	if bc == nil {
+		return []*Transaction{}
+	}
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	if bc.pendingTxs == nil {
 		return []*Transaction{}
 	}
 	cp := make([]*Transaction, len(bc.pendingTxs))
@@ -0,0 +1,95 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"quantumcoin/config"
+	"quantumcoin/wallet"
+)
+
+// newRegtestChain: geçici dizinde (mined_balance.json vb. oraya yazılır) regtest zinciri
+func newRegtestChain(t *testing.T) *Blockchain {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	prev := config.Current()
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		config.Set(prev)
+		wallet.UseNetwork(prev.Params())
+	})
+	c := config.Default()
+	if err := c.UseNetwork(config.NetworkRegtest); err != nil {
+		t.Fatal(err)
+	}
+	config.Set(c)
+	wallet.UseNetwork(c.Params())
+	bc, err := NewBlockchain(NetworkRegtest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bc
+}
+
+// mineBlocks: n blok kazar, madenci adresini döner
+func mineBlocks(t *testing.T, bc *Blockchain, n int) string {
+	t.Helper()
+	miner := wallet.NewWallet().GetAddress()
+	for i := 0; i < n; i++ {
+		if _, err := bc.MineBlock(miner, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return miner
+}
+
+func TestPeerBlockStoredHashMustMatchHeader(t *testing.T) {
+	bc := newRegtestChain(t)
+	blk, err := bc.NewCandidateBlock(wallet.NewWallet().GetAddress(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, hash, err := NewProofOfWork(blk).RunContext(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk.Nonce, blk.Hash = nonce, make([]byte, 32) // PoW geçerli, saklanan hash uydurma
+	if err := bc.AddBlockFromPeer(blk); !errors.Is(err, ErrBlockHash) {
+		t.Fatalf("forged hash: got %v, want ErrBlockHash", err)
+	}
+	blk.Hash = hash
+	if err := bc.AddBlockFromPeer(blk); err != nil {
+		t.Fatalf("valid block rejected: %v", err)
+	}
+}
+
+func TestReplaceChainChecksBlockHashes(t *testing.T) {
+	local := newRegtestChain(t)
+	remote, err := NewBlockchain(NetworkRegtest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mineBlocks(t, remote, 3)
+	blocks := remote.GetAllBlocks()
+
+	forged := *blocks[2]
+	forged.Hash = append([]byte{0xff}, forged.Hash[1:]...)
+	bad := append(append([]*Block{}, blocks[:2]...), &forged, blocks[3])
+	if err := local.ReplaceChain(bad); !errors.Is(err, ErrIncomingChainInvalid) {
+		t.Fatalf("forged block 2: got %v, want ErrIncomingChainInvalid", err)
+	}
+	if err := local.ReplaceChain(blocks); err != nil {
+		t.Fatalf("valid chain rejected: %v", err)
+	}
+	if !local.IsValidChain() {
+		t.Fatal("replaced chain reported invalid")
+	}
+}
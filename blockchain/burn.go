@@ -0,0 +1,130 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// Yakım (burn): LockBurn çıktısı hiçbir input'la açılmaz (checkTxInputs
+// ErrUnspendable döner) ve UTXO setine hiç girmez; yani tutar ispatlanabilir
+// biçimde dolaşımdan çıkar. Çıktının PubKeyHash'i boştur. Kaynaklar:
+//
+//	coinbase  v3+ blokta ödül bölüşümünün burn payı (bkz. reward_split.go)
+//	işlem     NewBurnTransaction (serbest bakiyeden)
+
+// NewBurnOutput: amount tutarında yakım çıktısı
+func NewBurnOutput(amount int) TransactionOutput {
+	return TransactionOutput{Amount: amount, LockType: LockBurn}
+}
+
+// NewBurnTransaction: from'un serbest bakiyesinden amount yakan imzasız işlem
+func NewBurnTransaction(from string, amount int, bc *Blockchain) (*Transaction, error) {
+	if amount <= 0 {
+		return nil, ErrAmountMustBePositive
+	}
+	change, err := NewTxOutput(0, from)
+	if err != nil {
+		return nil, fmt.Errorf("sender: %w", err)
+	}
+	return fundTransaction(from, change, []TransactionOutput{NewBurnOutput(amount)}, bc)
+}
+
+// checkOutputLock: işlem çıktısının kilit türü biliniyor ve biçimi doğru mu
+func checkOutputLock(out TransactionOutput) error {
+	if out.LockType < LockP2PKH || out.LockType > LockBurn {
+		return fmt.Errorf("%w: %d", ErrUnknownLockType, out.LockType)
+	}
+	if out.LockType == LockBurn && len(out.PubKeyHash) > 0 {
+		return fmt.Errorf("%w: burn output carries a key hash", ErrUnknownLockType)
+	}
+	return nil
+}
+
+// BurnRecord: zincirdeki tek yakım çıktısı
+type BurnRecord struct {
+	TxID     string `json:"txid"`
+	Index    int    `json:"index"`
+	Height   int    `json:"height"`
+	Time     int64  `json:"time"` // blok zaman damgası (unix sn)
+	Amount   int    `json:"amount"`
+	Coinbase bool   `json:"coinbase"`       // ödül bölüşümünün burn payı
+	From     string `json:"from,omitempty"` // işlemin Sender alanı (bilgi amaçlı)
+}
+
+// Burns: yakım çıktıları, en yeniden eskiye (limit <= 0 => hepsi)
+func (bc *Blockchain) Burns(limit int) []BurnRecord {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	var out []BurnRecord
+	for h := len(bc.Blocks) - 1; h >= 0; h-- {
+		blk := bc.Blocks[h]
+		for _, tx := range blk.Transactions {
+			for idx, o := range tx.Outputs {
+				if o.LockType != LockBurn {
+					continue
+				}
+				rec := BurnRecord{
+					TxID:     hex.EncodeToString(tx.ID),
+					Index:    idx,
+					Height:   h,
+					Time:     blk.Timestamp,
+					Amount:   o.Amount,
+					Coinbase: tx.IsCoinbase(),
+				}
+				if !rec.Coinbase {
+					rec.From = tx.Sender
+				}
+				out = append(out, rec)
+				if limit > 0 && len(out) >= limit {
+					return out
+				}
+			}
+		}
+	}
+	return out
+}
+
+// SupplyStats: zincirden türetilen arz özeti
+type SupplyStats struct {
+	Height      int `json:"height"`
+	Minted      int `json:"minted"`      // coinbase çıktıları toplamı (yakılan pay dahil, v4+ ücretler hariç)
+	Burned      int `json:"burned"`      // LockBurn çıktıları toplamı
+	Circulating int `json:"circulating"` // harcanmamış, yakılmamış çıktılar
+	// FeesDestroyed: v4 öncesi bloklarda coinbase ücret toplamadığı için
+	// input-output farkı olarak kaybolan tutar (Minted - Burned - Circulating)
+	FeesDestroyed int `json:"feesDestroyed"`
+	Premine       int `json:"premine"`   // genesis premine çıktısı (Minted'e dahil)
+	MaxSupply     int `json:"maxSupply"` // ağın TotalSupply'ı
+}
+
+// Supply: basılan, yakılan ve dolaşımdaki arz
+// (indeks açıksa tarama yapılmaz).
+func (bc *Blockchain) Supply() SupplyStats {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	s := SupplyStats{Height: bc.bestHeight(), MaxSupply: bc.TotalSupply}
+	s.Premine, _ = bc.premine()
+	if bc.index != nil {
+		t := bc.index.Totals()
+		s.Minted, s.Burned, s.FeesDestroyed = t.Minted, t.Burned, t.FeesDestroyed
+		s.Circulating = t.Minted - t.Burned - t.FeesDestroyed
+		return s
+	}
+	s.Minted = bc.totalMinted()
+	for _, blk := range bc.Blocks {
+		for _, tx := range blk.Transactions {
+			for _, o := range tx.Outputs {
+				if o.LockType == LockBurn {
+					s.Burned += o.Amount
+				}
+			}
+		}
+	}
+	for _, outs := range bc.UTXO {
+		for _, o := range outs {
+			s.Circulating += o.Amount
+		}
+	}
+	s.FeesDestroyed = s.Minted - s.Burned - s.Circulating
+	return s
+}
@@ -1,26 +1,42 @@
 package blockchain
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
-	"quantumcoin/wallet"
+	"quantumcoin/config"
 )
 
-func newCoinbaseTx(miner string) (*Transaction, error) {
+// newCoinbaseTx: ts anındaki sübvansiyonu bölüşüme göre dağıtan coinbase
+// (Outputs[0] madenci, fees dahil; bkz. reward_split.go)
+func newCoinbaseTx(miner string, ts int64, fees int) (*Transaction, error) {
 	if strings.TrimSpace(miner) == "" {
 		return nil, ErrMinerAddressEmpty
 	}
-	reward := GetCurrentReward()
+	rb, split, err := computeRewardSplit(RewardAt(ts), fees, CurrentBlockVersion, config.Current().Params().Reward)
+	if err != nil {
+		return nil, err
+	}
+	minerOut, err := NewTxOutput(int(rb.ToMiner), strings.TrimSpace(miner))
+	if err != nil {
+		return nil, fmt.Errorf("miner address %q: %w", miner, err)
+	}
+	outs := make([]TransactionOutput, 0, 1+len(split))
+	outs = append(outs, minerOut)
+	outs = append(outs, split...)
+	minted := 0
+	for _, out := range outs {
+		minted += out.Amount
+	}
 	tx := &Transaction{
-		ID:     nil,
-		Inputs: []TransactionInput{},
-		Outputs: []TransactionOutput{
-			{Amount: reward, PubKeyHash: wallet.Base58DecodeAddress(miner)},
-		},
+		ID:        nil,
+		Inputs:    []TransactionInput{},
+		Outputs:   outs,
 		Timestamp: time.Now(),
 		Sender:    "COINBASE",
-		Amount:    float64(reward),
+		Amount:    float64(minted),
+		Version:   TxVersionSegregated,
 	}
 	tx.ID = tx.Hash()
 	return tx, nil
@@ -5,16 +5,20 @@ import "errors"
 // Genel
 var (
 	ErrInvalidPoW             = errors.New("invalid proof-of-work")
+	ErrBlockHash              = errors.New("block hash does not match header")
 	ErrPrevHashMismatch       = errors.New("prev hash mismatch")
 	ErrIncomingChainNotLonger = errors.New("incoming chain is not longer")
 	ErrIncomingChainInvalid   = errors.New("incoming chain is invalid")
 	ErrNilTransaction         = errors.New("nil transaction")
 	ErrChainNotInitialized    = errors.New("blockchain not initialized (no genesis)")
+	ErrGenesisMismatch        = errors.New("genesis block does not match network")
+	ErrUnknownNetwork         = errors.New("unknown network")
 )
 
 // Coinbase / madenci
 var (
 	ErrMinerAddressEmpty = errors.New("miner address empty")
+	ErrRewardSplit       = errors.New("coinbase does not match reward split")
 )
 
 // NFT / yardımcılar
@@ -29,4 +33,30 @@ var (
 	ErrInsufficientBalance  = errors.New("yetersiz bakiye") // mevcut metni korunur
 	ErrAmountMustBePositive = errors.New("amount must be positive")
 	ErrInvalidSpendableTxID = errors.New("invalid txid hex in spendable set")
+	ErrMissingInput         = errors.New("input references unknown output")
+	ErrDoubleSpend          = errors.New("output already spent")
+	ErrLockMismatch         = errors.New("input does not satisfy output lock")
+	ErrInputsBelowOutputs   = errors.New("outputs exceed inputs")
+	ErrUnspendable          = errors.New("burn outputs cannot be spent")
+	ErrUnknownLockType      = errors.New("unknown output lock type")
+	ErrInvalidSignature     = errors.New("invalid tx signature")
+)
+
+// Staking
+var (
+	ErrStakeLocked       = errors.New("stake output is still locked")
+	ErrStakeRules        = errors.New("stake transaction violates staking rules")
+	ErrNothingToWithdraw = errors.New("no unbonded stake ready to withdraw")
+)
+
+// Harici blok montajı (getblocktemplate / submitblock)
+var (
+	ErrBadBlock       = errors.New("malformed block")
+	ErrDuplicateBlock = errors.New("block already in chain")
+	ErrStaleBlock     = errors.New("block does not extend the current tip")
+	ErrLowDifficulty  = errors.New("block difficulty below required bits")
+	ErrBlockTimeRange = errors.New("block timestamp out of range")
+	ErrBlockIndex     = errors.New("block index does not follow previous block")
+	ErrBlockVersion   = errors.New("unsupported or downgraded block version")
+	ErrNonceSpace     = errors.New("nonce space exhausted (no coinbase for extra-nonce)")
 )
@@ -0,0 +1,68 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"log/slog"
+)
+
+// ChainObserver: zincir ve mempool değişikliklerini dinleyen (SetObserver ile
+// bağlanır; events.ChainObserver olayları veri yoluna yayınlar). Çağrılar
+// değişikliği yapan goroutine'de, değişiklik tamamlandıktan sonra yapılır.
+type ChainObserver interface {
+	BlockConnected(blk *Block)
+	BlockDisconnected(blk *Block, forkHeight int)
+	TxAccepted(tx *Transaction)
+	TxRemoved(tx *Transaction, reason string)
+}
+
+// TxRemovedMined: TxRemoved nedeni — işlem bağlanan bir bloğa girdi
+const TxRemovedMined = "mined"
+
+// SetObserver: zincir/mempool olaylarının bildirileceği gözlemci (nil => kapalı)
+func (bc *Blockchain) SetObserver(o ChainObserver) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	bc.observer = o
+}
+
+// forkHeight: old ve cur zincirlerinin ortak son bloğunun yüksekliği (-1 => yok)
+func forkHeight(old, cur []*Block) int {
+	h := -1
+	for i := 0; i < len(old) && i < len(cur); i++ {
+		if !bytes.Equal(old[i].Hash, cur[i].Hash) {
+			break
+		}
+		h = i
+	}
+	return h
+}
+
+// notifyChainChange: eski zincirden yeni zincire geçişi bildirir (düşen bloklar
+// uçtan geriye, sonra eklenenler artan sırayla)
+func (bc *Blockchain) notifyChainChange(old, cur []*Block) {
+	fork := forkHeight(old, cur)
+	if n := len(old) - 1 - fork; n > 0 {
+		mReorgs.Inc()
+		mBlocksDisconnected.Add(float64(n))
+		logger.Info("chain reorganized", "fork", fork, "disconnected", n, "connected", len(cur)-1-fork)
+	}
+	for _, blk := range cur[fork+1:] {
+		if !logger.Enabled(context.Background(), slog.LevelDebug) {
+			break
+		}
+		logger.Debug("block connected", "height", blk.Index, "hash", hex.EncodeToString(blk.Hash), "txs", len(blk.Transactions))
+	}
+	mBlocksConnected.Add(float64(len(cur) - 1 - fork))
+	bc.observeTip()
+	if bc.observer == nil {
+		return
+	}
+	for i := len(old) - 1; i > fork; i-- {
+		bc.observer.BlockDisconnected(old[i], fork)
+	}
+	for _, blk := range cur[fork+1:] {
+		bc.observer.BlockConnected(blk)
+	}
+}
@@ -0,0 +1,192 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"quantumcoin/config"
+)
+
+// Ağ adları (parametreler: config.NetworkParams)
+const (
+	NetworkMainnet = config.NetworkMainnet
+	NetworkTestnet = config.NetworkTestnet
+	NetworkRegtest = config.NetworkRegtest
+)
+
+// GenesisSpec: ağın sabit genesis tanımı. Blok yalnız bu alanlardan kurulur
+// (time.Now yok, çalışma anında kazım yok); iki bağımsız düğüm aynı hash'i üretir.
+// Alanlardan biri değişirse Hash de değişir: bu yeni bir ağ demektir.
+type GenesisSpec struct {
+	Network        string
+	Version        int
+	Timestamp      int64 // unix sn (coinbase zaman damgası da budur)
+	Bits           int
+	Nonce          int
+	Reward         int    // sahipsiz genesis çıktısı ("genesis-recipient")
+	TotalSupply    int    // premine bunun yüzdesidir
+	PremineAddress string // PreminePercent > 0 ise zorunlu
+	PreminePercent int
+	Hash           string // beklenen blok hash'i (hex)
+}
+
+// genesisRecipient: eski genesis'lerle aynı, anahtarı olmayan kilit
+var genesisRecipient = []byte("genesis-recipient")
+
+var genesisSpecs = map[string]GenesisSpec{
+	NetworkMainnet: {
+		Network:     NetworkMainnet,
+		Version:     BlockVersionRewardSplit,
+		Timestamp:   GenesisTimeDefault,
+		Bits:        16,
+		Nonce:       86636,
+		Reward:      InitialRewardDefault,
+		TotalSupply: 25_500_000,
+		// Premine yok: ana cüzdan adresi sabitlenmedi. Eklenirse Nonce/Hash yeniden
+		// kazılır (yeni ağ); config'teki premine_address genesis'i etkilemez.
+		Hash: "000042baf09b388120b8d6f46d4c54cc25ebf3c31113989957af37a8a07a17ca",
+	},
+	NetworkTestnet: {
+		Network:     NetworkTestnet,
+		Version:     BlockVersionRewardSplit,
+		Timestamp:   1756684800, // 2025-09-01 UTC (testnet/genesis.json)
+		Bits:        8,
+		Nonce:       326,
+		Reward:      InitialRewardDefault,
+		TotalSupply: 25_500_000,
+		Hash:        "00d07c2f705cb64a906521f72e13505d65cd79c4eda1b5d1380a362cf15fc3e7",
+	},
+	NetworkRegtest: {
+		Network:     NetworkRegtest,
+		Version:     BlockVersionRewardSplit,
+		Timestamp:   GenesisTimeDefault,
+		Bits:        1,
+		Nonce:       2,
+		Reward:      InitialRewardDefault,
+		TotalSupply: 25_500_000,
+		Hash:        "5796530e0e0f8267a0d34f33b26da1b565aa4e309cde1efda2c2c0b5a7c78d46",
+	},
+}
+
+// GenesisFor: ağın genesis tanımı
+func GenesisFor(network string) (GenesisSpec, error) {
+	s, ok := genesisSpecs[strings.ToLower(strings.TrimSpace(network))]
+	if !ok {
+		return GenesisSpec{}, fmt.Errorf("%w: %q", ErrUnknownNetwork, network)
+	}
+	return s, nil
+}
+
+// Block: tanımdan genesis bloğu. Hash PoW'dan hesaplanır ve s.Hash ile
+// karşılaştırılır; tutmazsa (tanım bozuk) ErrGenesisMismatch.
+func (s GenesisSpec) Block() (*Block, error) {
+	b, err := s.build()
+	if err != nil {
+		return nil, err
+	}
+	if !b.ValidatePoW() || hex.EncodeToString(b.Hash) != s.Hash {
+		return nil, fmt.Errorf("%w: %s spec yields %x", ErrGenesisMismatch, s.Network, b.Hash)
+	}
+	return b, nil
+}
+
+// build: tanımdaki Nonce ile blok (PoW/hash denetimi yok)
+func (s GenesisSpec) build() (*Block, error) {
+	outs := []TransactionOutput{{Amount: s.Reward, PubKeyHash: genesisRecipient}}
+	if s.PreminePercent > 0 {
+		out, err := NewTxOutput(s.TotalSupply*s.PreminePercent/100, strings.TrimSpace(s.PremineAddress))
+		if err != nil {
+			return nil, fmt.Errorf("genesis premine: %w", err)
+		}
+		outs = append(outs, out)
+	}
+	minted := 0
+	for _, out := range outs {
+		minted += out.Amount
+	}
+	cb := &Transaction{
+		Inputs:    []TransactionInput{},
+		Outputs:   outs,
+		Timestamp: time.Unix(s.Timestamp, 0).UTC(),
+		Sender:    "COINBASE",
+		Amount:    float64(minted),
+		Version:   TxVersionSegregated,
+	}
+	cb.ID = cb.Hash()
+	b := &Block{
+		Index:        0,
+		Timestamp:    s.Timestamp,
+		Transactions: []*Transaction{cb},
+		PrevHash:     []byte{},
+		Nonce:        s.Nonce,
+		Miner:        "genesis",
+		Difficulty:   s.Bits,
+		Metadata:     map[string]string{"network": s.Network},
+		Version:      s.Version,
+	}
+	b.Hash = b.PoWHash()
+	return b, nil
+}
+
+// CheckGenesis: zincirin ilk bloğu ağın genesis'i mi (zincir dosyası yüklenince)
+func (bc *Blockchain) CheckGenesis(network string) error {
+	s, err := GenesisFor(network)
+	if err != nil {
+		return err
+	}
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	if len(bc.Blocks) == 0 {
+		return ErrChainNotInitialized
+	}
+	want, err := s.Block()
+	if err != nil {
+		return err
+	}
+	return matchGenesis(bc.Blocks[0], want)
+}
+
+// GenesisHash: zincirin genesis hash'i (p2p el sıkışması)
+func (bc *Blockchain) GenesisHash() []byte {
+	if bc == nil {
+		return nil
+	}
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	if len(bc.Blocks) == 0 {
+		return nil
+	}
+	return bc.Blocks[0].Hash
+}
+
+// sameGenesis: gelen zincir bu zincirin genesis'inden mi başlıyor
+func (bc *Blockchain) sameGenesis(blocks []*Block) error {
+	if len(blocks) == 0 || len(bc.Blocks) == 0 {
+		return ErrGenesisMismatch
+	}
+	return matchGenesis(blocks[0], bc.Blocks[0])
+}
+
+// matchGenesis: b, want ile aynı genesis mi. b'nin saklanan alanlarına güvenilmez:
+// hash başlıktan, txid'ler işlemlerden yeniden hesaplanır. Başlık işlem kökünü
+// (WitnessHash) bağladığı için başlık hash'inin tutması coinbase'in de tuttuğunu gösterir.
+func matchGenesis(b, want *Block) error {
+	if b == nil || b.Index != 0 || len(b.PrevHash) != 0 {
+		return fmt.Errorf("%w: not a genesis block", ErrGenesisMismatch)
+	}
+	if got := b.PoWHash(); !bytes.Equal(got, want.Hash) || !bytes.Equal(b.Hash, want.Hash) {
+		return fmt.Errorf("%w: header hash %x (stored %x), expected %x", ErrGenesisMismatch, got, b.Hash, want.Hash)
+	}
+	if len(b.Transactions) != len(want.Transactions) {
+		return fmt.Errorf("%w: %d transactions, expected %d", ErrGenesisMismatch, len(b.Transactions), len(want.Transactions))
+	}
+	for i, tx := range b.Transactions {
+		if tx == nil || !bytes.Equal(tx.ID, tx.Hash()) || !bytes.Equal(tx.ID, want.Transactions[i].ID) {
+			return fmt.Errorf("%w: transaction %d", ErrGenesisMismatch, i)
+		}
+	}
+	return nil
+}
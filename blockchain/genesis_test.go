@@ -0,0 +1,65 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenesisSpecsBuild(t *testing.T) {
+	for _, network := range []string{NetworkMainnet, NetworkTestnet, NetworkRegtest} {
+		s, err := GenesisFor(network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Block(); err != nil {
+			t.Errorf("%s: %v", network, err)
+		}
+		if s.PreminePercent > 0 && s.PremineAddress == "" {
+			t.Errorf("%s: premine percent without address", network)
+		}
+	}
+}
+
+// forgeGenesis: coinbase'i değiştirilmiş, Hash'i gerçek genesis'ten kopyalanmış blok
+func forgeGenesis(t *testing.T, real *Block) *Block {
+	t.Helper()
+	cb := *real.Transactions[0]
+	cb.Outputs = append([]TransactionOutput{}, cb.Outputs...)
+	cb.Outputs[0].Amount += 1_000_000
+	cb.ID = cb.Hash()
+	forged := *real
+	forged.Transactions = []*Transaction{&cb}
+	return &forged
+}
+
+func TestCheckGenesisRejectsForgedCoinbase(t *testing.T) {
+	for _, network := range []string{NetworkMainnet, NetworkTestnet, NetworkRegtest} {
+		s, _ := GenesisFor(network)
+		real, err := s.Block()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bc := &Blockchain{Blocks: []*Block{forgeGenesis(t, real)}}
+		if err := bc.CheckGenesis(network); !errors.Is(err, ErrGenesisMismatch) {
+			t.Errorf("%s: forged genesis: got %v, want ErrGenesisMismatch", network, err)
+		}
+		bc.Blocks[0] = real
+		if err := bc.CheckGenesis(network); err != nil {
+			t.Errorf("%s: real genesis rejected: %v", network, err)
+		}
+	}
+}
+
+func TestReplaceChainRejectsForgedGenesis(t *testing.T) {
+	local := newRegtestChain(t)
+	remote, err := NewBlockchain(NetworkRegtest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mineBlocks(t, remote, 2)
+	blocks := remote.GetAllBlocks()
+	blocks[0] = forgeGenesis(t, blocks[0]) // sonraki bloklar kopyalanan hash'e bağlı
+	if err := local.ReplaceChain(blocks); !errors.Is(err, ErrGenesisMismatch) {
+		t.Fatalf("got %v, want ErrGenesisMismatch", err)
+	}
+}
@@ -0,0 +1,159 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"hash"
+	"math"
+)
+
+// Kanonik blok başlığı (Block.Version >= BlockVersionHeader), sabit 80 bayt, big-endian:
+//
+//	[0:2)   version   uint16
+//	[2:4)   bits      uint16 (Difficulty)
+//	[4:36)  prevHash  32 bayt
+//	[36:68) txRoot    32 bayt (HashTransactions)
+//	[68:72) timestamp uint32 (unix sn)
+//	[72:80) nonce     uint64
+//
+// PoW = sha256(header) < 2^(256-bits). İlk 64 bayt tek bir SHA-256 bloğudur;
+// nonce döngüsü bu bloğun ara durumunu (midstate) bir kez hesaplar, her denemede
+// yalnızca son 16 baytı özetler. 64 bit nonce tükenirse coinbase ExtraNonce'u
+// artırılır (txRoot değişir) ve nonce sıfırdan başlar.
+//
+// Version 0 (eski bloklar): PrevHash || HashTransactions || ts(8) || diff(8) || nonce(8).
+// Her iki sürümde de PoW girdisi HeaderPrefix() || NonceBytes(nonce) biçimindedir.
+//
+// Version 2 başlık düzenini değiştirmez; coinbase ödül bölüşümünü zorunlu kılar
+// (bkz. reward_split.go). Version 3'te bölüşümün yakım payı LockBurn çıktısı
+// olarak basılır (bkz. burn.go). Version 4'te bloktaki işlem ücretleri madenci
+// çıktısına eklenir; öncekilerde input-output farkı yok olur.
+const (
+	BlockVersionLegacy      = 0
+	BlockVersionHeader      = 1
+	BlockVersionRewardSplit = 2
+	BlockVersionBurnOutput  = 3
+	BlockVersionMinerFees   = 4
+	CurrentBlockVersion     = BlockVersionMinerFees
+
+	HeaderSize       = 80
+	HeaderPrefixSize = HeaderSize - 8
+	midstateSize     = 64
+)
+
+// headerFieldsValid: v1 başlığında alanlar sabit genişliğe sığıyor mu
+func (b *Block) headerFieldsValid() bool {
+	return b.Version >= 0 && b.Version <= math.MaxUint16 &&
+		b.Difficulty >= 0 && b.Difficulty <= math.MaxUint16 &&
+		b.Timestamp >= 0 && b.Timestamp <= math.MaxUint32 &&
+		len(b.PrevHash) <= 32 && b.Nonce >= 0
+}
+
+// HeaderPrefix: PoW girdisinin nonce'tan önceki kısmı. v1: başlığın ilk 72 baytı;
+// v0: PrevHash || HashTransactions || Timestamp || Difficulty. Harici madenciler
+// sha256(prefix || NonceBytes(nonce)) hesaplar.
+func (b *Block) HeaderPrefix() []byte {
+	if b.Version < BlockVersionHeader {
+		out := make([]byte, 0, len(b.PrevHash)+32+16)
+		out = append(out, b.PrevHash...)
+		out = append(out, b.HashTransactions()...)
+		out = append(out, intToHex(b.Timestamp)...)
+		return append(out, intToHex(int64(b.Difficulty))...)
+	}
+	h := make([]byte, HeaderPrefixSize)
+	binary.BigEndian.PutUint16(h[0:2], uint16(b.Version))
+	binary.BigEndian.PutUint16(h[2:4], uint16(b.Difficulty))
+	copy(h[4+32-len(b.PrevHash):36], b.PrevHash) // genesis PrevHash boş: sıfır
+	copy(h[36:68], b.HashTransactions())
+	binary.BigEndian.PutUint32(h[68:72], uint32(b.Timestamp))
+	return h
+}
+
+// Header: nonce dahil PoW girdisi (v1'de 80 bayt)
+func (b *Block) Header() []byte {
+	return append(b.HeaderPrefix(), NonceBytes(b.Nonce)...)
+}
+
+// NonceBytes: PoW girdisindeki nonce kodlaması (8 bayt big-endian)
+func NonceBytes(nonce int) []byte { return intToHex(int64(nonce)) }
+
+// PoWHash: sha256(Header())
+func (b *Block) PoWHash() []byte {
+	sum := sha256.Sum256(b.Header())
+	return sum[:]
+}
+
+// SetExtraNonce: coinbase (ilk işlem) ExtraNonce'unu yazar ve txid'yi yeniler.
+// Başlık kökü değiştiği için HeaderPrefix yeniden hesaplanmalıdır.
+func (b *Block) SetExtraNonce(n uint64) bool {
+	if len(b.Transactions) == 0 || !b.Transactions[0].IsCoinbase() {
+		return false
+	}
+	cb := *b.Transactions[0]
+	cb.ExtraNonce = n
+	cb.ID = cb.Hash()
+	txs := append([]*Transaction{&cb}, b.Transactions[1:]...)
+	b.Transactions = txs
+	return true
+}
+
+// ExtraNonce: coinbase ExtraNonce (yoksa 0)
+func (b *Block) ExtraNonce() uint64 {
+	if len(b.Transactions) == 0 || !b.Transactions[0].IsCoinbase() {
+		return 0
+	}
+	return b.Transactions[0].ExtraNonce
+}
+
+// Midstate: sabit öneki (HeaderPrefixSize bayt) önceden işlenmiş PoW özetleyici.
+// Önek 64 bayttan uzunsa ilk 64 bayt için SHA-256 ara durumu saklanır.
+// Midstate değişmez; her goroutine kendi NonceHasher'ını kullanır.
+type Midstate struct {
+	state []byte // sha256 iç durumu (encoding.BinaryMarshaler)
+	tail  []byte // prefix[64:]
+	full  []byte // ara durum yoksa tüm önek
+}
+
+// NewMidstate: prefix = HeaderPrefix()
+func NewMidstate(prefix []byte) *Midstate {
+	m := &Midstate{}
+	if len(prefix) >= midstateSize {
+		h := sha256.New()
+		h.Write(prefix[:midstateSize])
+		if st, err := h.(encoding.BinaryMarshaler).MarshalBinary(); err == nil {
+			m.state = st
+			m.tail = append([]byte(nil), prefix[midstateSize:]...)
+			return m
+		}
+	}
+	m.full = append([]byte(nil), prefix...)
+	return m
+}
+
+// NonceHasher: tek goroutine için nonce özetleyici
+type NonceHasher struct {
+	m   *Midstate
+	h   hash.Hash
+	buf []byte
+}
+
+func (m *Midstate) NewHasher() *NonceHasher {
+	return &NonceHasher{m: m, h: sha256.New(), buf: make([]byte, 0, 80)}
+}
+
+// Sum: sha256(prefix || nonce(8 bayt big-endian))
+func (n *NonceHasher) Sum(nonce uint64) [32]byte {
+	var out [32]byte
+	if n.m.state == nil {
+		n.buf = append(append(n.buf[:0], n.m.full...), 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(n.buf[len(n.buf)-8:], nonce)
+		return sha256.Sum256(n.buf)
+	}
+	_ = n.h.(encoding.BinaryUnmarshaler).UnmarshalBinary(n.m.state)
+	n.buf = append(append(n.buf[:0], n.m.tail...), 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(n.buf[len(n.buf)-8:], nonce)
+	n.h.Write(n.buf)
+	n.h.Sum(out[:0])
+	return out
+}
@@ -0,0 +1,308 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"strconv"
+	"sync"
+)
+
+// Index: isteğe bağlı zincir indeksleri (EnableIndex ile açılır). Bloklar
+// bağlanıp düştükçe (AddBlock, AddBlockFromPeer, ReplaceChain) güncellenir:
+//
+//	txid           -> blok yüksekliği ve blok içindeki konum
+//	adres          -> adresi fonlayan ya da harcayan işlemler (+ bakiye özeti)
+//	harcanan çıktı -> harcayan işlem
+//	blok           -> günlük istatistik ve arz toplamlarına katkısı (bkz. stats.go)
+//
+// Açıkken FindTransaction, GetBalance, Supply ve harcanmışlık denetimleri
+// zinciri taramak yerine indeksi kullanır. Mempool indekslenmez.
+type Index struct {
+	bc *Blockchain
+
+	mu      sync.RWMutex
+	txs     map[string]TxLocation
+	txAddrs map[string][]string // txid -> dokunduğu adresler (düşürmede geri almak için)
+	addrs   map[string]*addressIndex
+	spent   map[string]SpendRef // outpointKey -> harcayan
+	blocks  map[string]blockStats
+	days    map[string]*dayAcc
+	totals  dayAcc
+}
+
+// TxLocation: işlemin zincirdeki yeri
+type TxLocation struct {
+	Height    int    `json:"height"`
+	Position  int    `json:"position"` // blok içindeki sıra (0 = coinbase)
+	BlockHash string `json:"blockhash"`
+}
+
+// SpendRef: bir çıktıyı harcayan işlem girdisi
+type SpendRef struct {
+	TxID   string `json:"txid"`
+	Input  int    `json:"vin"`
+	Height int    `json:"height"`
+}
+
+// AddressTx: adresin bir işlemdeki hareketi
+type AddressTx struct {
+	TxID     string `json:"txid"`
+	Height   int    `json:"height"`
+	Position int    `json:"position"`
+	Received int    `json:"received"` // adrese giden çıktılar (stake dahil, burn hariç)
+	Sent     int    `json:"sent"`     // adresin harcanan çıktıları
+	free     int    // serbest bakiyeye etkisi (stake/unbonding hariç)
+}
+
+// AddressSummary: adresin indekslenmiş özeti
+type AddressSummary struct {
+	Address  string `json:"address"`
+	Balance  int    `json:"balance"` // serbest bakiye (GetBalance)
+	Received int    `json:"received"`
+	Sent     int    `json:"sent"`
+	TxCount  int    `json:"txCount"`
+}
+
+type addressIndex struct {
+	txs                     []AddressTx // artan yükseklik/konum sırasıyla
+	received, sent, balance int
+}
+
+// EnableIndex: indeksleri mevcut zincirden kurar ve açar (yeniden çağrı yeniden kurar)
+func (bc *Blockchain) EnableIndex() *Index {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	ix := &Index{
+		bc:      bc,
+		txs:     map[string]TxLocation{},
+		txAddrs: map[string][]string{},
+		addrs:   map[string]*addressIndex{},
+		spent:   map[string]SpendRef{},
+		blocks:  map[string]blockStats{},
+		days:    map[string]*dayAcc{},
+	}
+	for _, b := range bc.Blocks {
+		ix.connect(b)
+	}
+	bc.index = ix
+	return ix
+}
+
+// Index: açık indeks (nil => kapalı)
+func (bc *Blockchain) Index() *Index {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.index
+}
+
+// Tx: işlemin zincirdeki yeri
+func (ix *Index) Tx(txid string) (TxLocation, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	loc, ok := ix.txs[txid]
+	return loc, ok
+}
+
+// Spender: txid:n çıktısını harcayan girdi
+func (ix *Index) Spender(txid string, n int) (SpendRef, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	s, ok := ix.spent[txid+":"+strconv.Itoa(n)]
+	return s, ok
+}
+
+// AddressTxs: adresin işlemleri, en yeniden eskiye; offset/limit sayfalama
+// (limit <= 0 => tümü). İkinci değer toplam işlem sayısıdır.
+func (ix *Index) AddressTxs(address string, offset, limit int) ([]AddressTx, int) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	a := ix.addrs[address]
+	if a == nil {
+		return []AddressTx{}, 0
+	}
+	total := len(a.txs)
+	offset = max(offset, 0)
+	if limit <= 0 {
+		limit = total
+	}
+	out := make([]AddressTx, 0, min(limit, max(total-offset, 0)))
+	for i := total - 1 - offset; i >= 0 && len(out) < limit; i-- {
+		out = append(out, a.txs[i])
+	}
+	return out, total
+}
+
+// Address: adresin bakiye ve işlem özeti
+func (ix *Index) Address(address string) AddressSummary {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	s := AddressSummary{Address: address}
+	if a := ix.addrs[address]; a != nil {
+		s.Balance, s.Received, s.Sent, s.TxCount = a.balance, a.received, a.sent, len(a.txs)
+	}
+	return s
+}
+
+// connect: bloğu indekslere ekler (blok bc.Blocks'a eklendikten sonra)
+func (ix *Index) connect(blk *Block) {
+	if ix == nil {
+		return
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	hash := hex.EncodeToString(blk.Hash)
+	st := blockStats{day: statsDay(blk.Timestamp), difficulty: blk.Difficulty}
+	if blk.Index > 0 && blk.Index <= len(ix.bc.Blocks) {
+		st.interval = blk.Timestamp - ix.bc.Blocks[blk.Index-1].Timestamp
+		st.hasInterval = true
+	}
+	for pos, tx := range blk.Transactions {
+		txid := hex.EncodeToString(tx.ID)
+		ix.txs[txid] = TxLocation{Height: blk.Index, Position: pos, BlockHash: hash}
+		if !tx.IsCoinbase() {
+			st.txs++
+		}
+
+		moves := map[string]*AddressTx{}
+		var order []string
+		move := func(addr string) *AddressTx {
+			m := moves[addr]
+			if m == nil {
+				m = &AddressTx{TxID: txid, Height: blk.Index, Position: pos}
+				moves[addr] = m
+				order = append(order, addr)
+			}
+			return m
+		}
+		for i, in := range tx.Inputs {
+			ix.spent[outpointKey(in.TxID, in.OutIndex)] = SpendRef{TxID: txid, Input: i, Height: blk.Index}
+			prev, ok := ix.outputLocked(in.TxID, in.OutIndex)
+			if !ok {
+				continue
+			}
+			st.fees += prev.Amount
+			m := move(prev.Address())
+			m.Sent += prev.Amount
+			if prev.LockType <= LockMultisig {
+				m.free -= prev.Amount
+			}
+		}
+		for _, out := range tx.Outputs {
+			if tx.IsCoinbase() {
+				st.minted += out.Amount
+			} else {
+				st.fees -= out.Amount
+			}
+			if out.LockType == LockBurn {
+				st.burned += out.Amount
+				continue
+			}
+			m := move(out.Address())
+			m.Received += out.Amount
+			if out.LockType <= LockMultisig {
+				m.free += out.Amount
+			}
+		}
+
+		for _, addr := range order {
+			m := moves[addr]
+			a := ix.addrs[addr]
+			if a == nil {
+				a = &addressIndex{}
+				ix.addrs[addr] = a
+			}
+			a.txs = append(a.txs, *m)
+			a.received += m.Received
+			a.sent += m.Sent
+			a.balance += m.free
+		}
+		ix.txAddrs[txid] = order
+	}
+	if blk.Version >= BlockVersionMinerFees {
+		st.minted -= st.fees // ücret madenciye geçti; yeni arz değil
+	} else {
+		st.feesDestroyed = st.fees
+	}
+	ix.blocks[hash] = st
+	ix.applyStatsLocked(st, 1)
+}
+
+// disconnect: bloğu indekslerden çıkarır (reorg'da uçtan geriye çağrılır)
+func (ix *Index) disconnect(blk *Block) {
+	if ix == nil {
+		return
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	hash := hex.EncodeToString(blk.Hash)
+	if st, ok := ix.blocks[hash]; ok {
+		ix.applyStatsLocked(st, -1)
+		delete(ix.blocks, hash)
+	}
+	for pos := len(blk.Transactions) - 1; pos >= 0; pos-- {
+		tx := blk.Transactions[pos]
+		txid := hex.EncodeToString(tx.ID)
+		for _, in := range tx.Inputs {
+			delete(ix.spent, outpointKey(in.TxID, in.OutIndex))
+		}
+		for _, addr := range ix.txAddrs[txid] {
+			a := ix.addrs[addr]
+			if a == nil {
+				continue
+			}
+			for i := len(a.txs) - 1; i >= 0; i-- {
+				if a.txs[i].TxID != txid {
+					continue
+				}
+				m := a.txs[i]
+				a.received -= m.Received
+				a.sent -= m.Sent
+				a.balance -= m.free
+				a.txs = append(a.txs[:i], a.txs[i+1:]...)
+				break
+			}
+			if len(a.txs) == 0 {
+				delete(ix.addrs, addr)
+			}
+		}
+		delete(ix.txAddrs, txid)
+		delete(ix.txs, txid)
+	}
+}
+
+// reorg: old zincirinden cur'a geçişi indekslere uygular
+func (ix *Index) reorg(old, cur []*Block) {
+	if ix == nil {
+		return
+	}
+	fork := forkHeight(old, cur)
+	for i := len(old) - 1; i > fork; i-- {
+		ix.disconnect(old[i])
+	}
+	for _, b := range cur[fork+1:] {
+		ix.connect(b)
+	}
+}
+
+// outputLocked: indekslenmiş işlemin n. çıktısı (ix.mu tutulurken)
+func (ix *Index) outputLocked(txid []byte, n int) (TransactionOutput, bool) {
+	loc, ok := ix.txs[hex.EncodeToString(txid)]
+	if !ok || loc.Height >= len(ix.bc.Blocks) {
+		return TransactionOutput{}, false
+	}
+	blk := ix.bc.Blocks[loc.Height]
+	if loc.Position >= len(blk.Transactions) || n < 0 || n >= len(blk.Transactions[loc.Position].Outputs) {
+		return TransactionOutput{}, false
+	}
+	return blk.Transactions[loc.Position].Outputs[n], true
+}
+
+// isSpent: indeks açıkken çıktı harcanmış mı (ikinci değer false => indeks kapalı)
+func (ix *Index) isSpent(txid []byte, n int) (spent, ok bool) {
+	if ix == nil {
+		return false, false
+	}
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	_, spent = ix.spent[outpointKey(txid, n)]
+	return spent, true
+}
@@ -1,23 +1,26 @@
 package blockchain
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 type RewardBreakdown struct {
-	Height        int64
-	Timestamp     int64
-	BaseSubsidy   int64
-	AnnualBonus   int64
-	FeesCollected int64
+	Height        int64 `json:"height"`
+	Timestamp     int64 `json:"timestamp"`
+	BaseSubsidy   int64 `json:"base_subsidy"`
+	AnnualBonus   int64 `json:"annual_bonus"`
+	FeesCollected int64 `json:"fees"`
 
-	ToMiner     int64
-	ToStakers   int64
-	ToDev       int64
-	ToBurn      int64
-	ToCommunity int64
+	ToMiner     int64 `json:"to_miner"`
+	ToStakers   int64 `json:"to_stakers"`
+	ToDev       int64 `json:"to_dev"`
+	ToBurn      int64 `json:"to_burn"`
+	ToCommunity int64 `json:"to_community"`
 
-	Total          int64
-	NFTAwarded     bool
-	NFTDeterminism string
+	Total          int64  `json:"total"`
+	NFTAwarded     bool   `json:"nft_awarded"`
+	NFTDeterminism string `json:"nft_det,omitempty"`
 }
 
 func RewardMetadataKV(rb RewardBreakdown) map[string]string {
@@ -40,3 +43,29 @@ func RewardMetadataKV(rb RewardBreakdown) map[string]string {
 	}
 	return m
 }
+
+// RewardFromMetadata: blok Metadata'sındaki ödül dökümü (v2 öncesi bloklarda yok)
+func (b *Block) RewardFromMetadata() (RewardBreakdown, bool) {
+	if _, ok := b.Metadata["reward.total"]; !ok {
+		return RewardBreakdown{}, false
+	}
+	get := func(k string) int64 {
+		v, _ := strconv.ParseInt(b.Metadata[k], 10, 64)
+		return v
+	}
+	return RewardBreakdown{
+		Height:         int64(b.Index),
+		Timestamp:      b.Timestamp,
+		BaseSubsidy:    get("reward.base_subsidy"),
+		AnnualBonus:    get("reward.annual_bonus"),
+		FeesCollected:  get("reward.fees"),
+		ToMiner:        get("reward.to_miner"),
+		ToStakers:      get("reward.to_stakers"),
+		ToDev:          get("reward.to_dev"),
+		ToBurn:         get("reward.to_burn"),
+		ToCommunity:    get("reward.to_community"),
+		Total:          get("reward.total"),
+		NFTAwarded:     b.Metadata["nft.drop"] == "1",
+		NFTDeterminism: b.Metadata["nft.det"],
+	}, true
+}
@@ -0,0 +1,40 @@
+package blockchain
+
+import (
+	"time"
+
+	"quantumcoin/metrics"
+)
+
+// Zincir ve mempool metrikleri (GET /metrics)
+var (
+	mChainHeight        = metrics.NewGauge("qc_chain_height", "Height of the best chain tip")
+	mChainTipTime       = metrics.NewGauge("qc_chain_tip_timestamp_seconds", "Timestamp of the best chain tip block")
+	mBlocksConnected    = metrics.NewCounter("qc_chain_blocks_connected_total", "Blocks connected to the best chain")
+	mBlocksDisconnected = metrics.NewCounter("qc_chain_blocks_disconnected_total", "Blocks disconnected by reorgs")
+	mReorgs             = metrics.NewCounter("qc_chain_reorgs_total", "Chain reorganizations that disconnected at least one block")
+	mBlockValidation    = metrics.NewHistogram("qc_block_validation_seconds", "Time to validate and connect a block", nil)
+	mBlocksRejected     = metrics.NewCounter("qc_blocks_rejected_total", "Blocks that failed validation")
+
+	mMempoolSize     = metrics.NewGauge("qc_mempool_transactions", "Transactions waiting in the chain mempool")
+	mMempoolAccepted = metrics.NewCounter("qc_mempool_accepted_total", "Transactions accepted into the chain mempool")
+	mMempoolRejected = metrics.NewCounter("qc_mempool_rejected_total", "Transactions rejected by the chain mempool")
+	mMempoolRemoved  = metrics.NewCounterVec("qc_mempool_removed_total", "Transactions removed from the chain mempool", "reason")
+)
+
+// observeBlock: doğrulama süresini ve sonucunu kaydeder
+func observeBlock(start time.Time, ok bool) {
+	if !ok {
+		mBlocksRejected.Inc()
+		return
+	}
+	mBlockValidation.ObserveSince(start)
+}
+
+// observeTip: zincir ucu göstergelerini günceller
+func (bc *Blockchain) observeTip() {
+	if n := len(bc.Blocks); n > 0 {
+		mChainHeight.Set(float64(n - 1))
+		mChainTipTime.Set(float64(bc.Blocks[n-1].Timestamp))
+	}
+}
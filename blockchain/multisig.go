@@ -0,0 +1,31 @@
+package blockchain
+
+import "quantumcoin/wallet"
+
+// verifyMultisigInput: Redeem descriptor'ını çözer, Sigs'i anahtar sırasına göre
+// doğrular; en az M geçerli imza olmalı. Boş olmayan her imza geçerli olmak zorunda
+// (geçersiz imza eklenerek tx'in şişirilmesi/değiştirilmesi engellenir).
+func verifyMultisigInput(tx *Transaction, idx int, prev *TransactionOutput, batch *schnorrBatch) bool {
+	in := &tx.Inputs[idx]
+	if len(in.Signature) != 0 || len(in.PubKey) != 0 {
+		return false
+	}
+	d, err := wallet.ParseMultisigDescriptor(in.Redeem)
+	if err != nil {
+		return false
+	}
+	if len(in.Sigs) != len(d.PubKeys) {
+		return false
+	}
+	valid := 0
+	for k, sig := range in.Sigs {
+		if len(sig) == 0 {
+			continue
+		}
+		if !verifyInputSig(tx, idx, d.PubKeys[k], sig, prev, batch) {
+			return false
+		}
+		valid++
+	}
+	return valid >= d.M
+}
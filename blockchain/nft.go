@@ -11,7 +11,9 @@ func (bc *Blockchain) MintNFT(toAddress, nftType string, meta map[string]string)
 	if bc == nil {
 		return "", fmt.Errorf("blockchain is nil")
 	}
-	last := bc.GetLastBlock()
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	last := bc.lastBlock()
 	if last == nil {
 		return "", fmt.Errorf("no blocks in chain")
 	}
@@ -1,9 +1,12 @@
 package blockchain
 
 import (
-	"bytes"
+	"context"
 	"crypto/sha256"
 	"math/big"
+	"runtime"
+	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -35,36 +38,80 @@ func NewProofOfWork(b *Block) *ProofOfWork {
 }
 
 func (pow *ProofOfWork) prepareData(nonce int) []byte {
-	return bytes.Join([][]byte{
-		pow.block.PrevHash,
-		pow.block.HashTransactions(),
-		intToHex(pow.block.Timestamp),
-		intToHex(int64(pow.block.Difficulty)),
-		intToHex(int64(nonce)),
-	}, []byte{})
+	return append(pow.block.HeaderPrefix(), NonceBytes(nonce)...)
 }
 
-func (pow *ProofOfWork) Run() (int, []byte) {
-	var (
-		hash    [32]byte
-		hashInt big.Int
-		nonce   = 0
-	)
+// hashCountBatch: sayaç ve iptal kontrolü bu kadar denemede bir yapılır
+const hashCountBatch = 1024
 
-	for nonce < maxNonce {
-		data := pow.prepareData(nonce)
-		hash = sha256.Sum256(data)
-		hashInt.SetBytes(hash[:])
-
-		if hashInt.Cmp(pow.target) == -1 {
-			break
+// RunContext: nonce aramasını threads goroutine'e böler (goroutine i: i, i+threads, ...).
+// İlk bulunan çözüm döner; ctx iptal edilirse ctx.Err() döner. hashes nil değilse
+// yapılan deneme sayısı eklenir. Nonce alanı tükenirse coinbase ExtraNonce'u
+// artırılır (blok işlemleri yerinde değişir) ve arama sıfırdan sürer.
+func (pow *ProofOfWork) RunContext(ctx context.Context, threads int, hashes *atomic.Uint64) (int, []byte, error) {
+	if threads <= 0 {
+		threads = runtime.NumCPU()
+	}
+	for {
+		mid := NewMidstate(pow.block.HeaderPrefix())
+		var (
+			wg        sync.WaitGroup
+			found     atomic.Bool
+			mu        sync.Mutex
+			bestNonce int
+			bestHash  []byte
+		)
+		for t := 0; t < threads; t++ {
+			wg.Add(1)
+			go func(start int) {
+				defer wg.Done()
+				hasher := mid.NewHasher()
+				var hashInt big.Int
+				n := uint64(0)
+				defer func() {
+					if hashes != nil {
+						hashes.Add(n % hashCountBatch)
+					}
+				}()
+				for nonce := start; nonce >= 0 && nonce < maxNonce; nonce += threads {
+					if n++; n%hashCountBatch == 0 {
+						if hashes != nil {
+							hashes.Add(hashCountBatch)
+						}
+						if found.Load() || ctx.Err() != nil {
+							return
+						}
+					}
+					sum := hasher.Sum(uint64(nonce))
+					hashInt.SetBytes(sum[:])
+					if hashInt.Cmp(pow.target) == -1 {
+						if found.CompareAndSwap(false, true) {
+							mu.Lock()
+							bestNonce, bestHash = nonce, append([]byte(nil), sum[:]...)
+							mu.Unlock()
+						}
+						return
+					}
+				}
+			}(t)
+		}
+		wg.Wait()
+		if found.Load() {
+			return bestNonce, bestHash, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, nil, err
+		}
+		if !pow.block.SetExtraNonce(pow.block.ExtraNonce() + 1) {
+			return maxNonce, nil, ErrNonceSpace
 		}
-		nonce++
 	}
-	return nonce, hash[:]
 }
 
 func (pow *ProofOfWork) Validate() bool {
+	if pow.block.Version >= BlockVersionHeader && !pow.block.headerFieldsValid() {
+		return false
+	}
 	var hashInt big.Int
 	data := pow.prepareData(pow.block.Nonce)
 	sum := sha256.Sum256(data)
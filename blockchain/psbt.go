@@ -0,0 +1,527 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"quantumcoin/wallet"
+)
+
+// PSBT (partially signed transaction): imzasız tx + harcanan çıktıların
+// tutar/kilit bilgisi + kısmi imzalar. Hava boşluklu (offline) cihaz zincire
+// erişmeden neyi imzaladığını görebilsin diye kendini tanımlar.
+// Dosya formatı: base64(JSON)
+
+const (
+	PSBTMagic   = "qcpsbt"
+	PSBTVersion = 1
+)
+
+type PSBTInput struct {
+	TxID        string            `json:"txid"`               // hex
+	OutIndex    int               `json:"n"`                  // harcanan çıktı
+	Amount      int               `json:"amount"`             // harcanan çıktının tutarı
+	PubKeyHash  string            `json:"pubKeyHash"`         // hex, çıktının kilidi (sahip anahtar / descriptor)
+	LockType    int               `json:"lockType,omitempty"` // LockP2PKH | LockMultisig
+	Redeem      string            `json:"redeem,omitempty"`   // hex, multisig descriptor
+	PartialSigs map[string]string `json:"partialSigs,omitempty"`
+	// PartialSigs: pubkey hex -> imza hex
+}
+
+type PSBT struct {
+	Magic      string      `json:"magic"`
+	Version    int         `json:"version"`
+	UnsignedTx []byte      `json:"unsignedTx"` // gob(Transaction), imzasız
+	Inputs     []PSBTInput `json:"inputs"`
+}
+
+// İnceleme çıktısı (CLI/API için)
+type PSBTSummary struct {
+	Version  int                 `json:"version"`
+	TxID     string              `json:"txid"`
+	Sender   string              `json:"sender"`
+	Inputs   []PSBTInputSummary  `json:"inputs"`
+	Outputs  []PSBTOutputSummary `json:"outputs"`
+	InTotal  int                 `json:"inTotal"`
+	OutTotal int                 `json:"outTotal"`
+	Fee      int                 `json:"fee"`
+	Complete bool                `json:"complete"`
+}
+
+type PSBTInputSummary struct {
+	TxID     string `json:"txid"`
+	N        int    `json:"n"`
+	Amount   int    `json:"amount"`
+	Owner    string `json:"owner"`
+	Sigs     int    `json:"sigs"`
+	Required int    `json:"required"` // gereken imza sayısı (multisig: m)
+	Signed   bool   `json:"signed"`
+}
+
+type PSBTOutputSummary struct {
+	Amount  int    `json:"amount"`
+	Address string `json:"address"`
+}
+
+// NewPSBT: from→to işlemini kurar ve her input için harcanan çıktının bilgisini ekler.
+func NewPSBT(from, to string, amount int, bc *Blockchain) (*PSBT, error) {
+	if bc == nil {
+		return nil, ErrNilBlockchain
+	}
+	tx, err := NewTransaction(from, to, amount, bc)
+	if err != nil {
+		return nil, err
+	}
+	return NewPSBTFromTx(tx, bc)
+}
+
+// NewPSBTFromTx: hazır (imzasız) bir tx'i PSBT kabına koyar.
+func NewPSBTFromTx(tx *Transaction, bc *Blockchain) (*PSBT, error) {
+	if tx == nil {
+		return nil, ErrNilTransaction
+	}
+	if tx.IsCoinbase() {
+		return nil, fmt.Errorf("psbt: coinbase tx cannot be wrapped")
+	}
+	unsigned := tx.TrimmedCopy()
+	p := &PSBT{
+		Magic:      PSBTMagic,
+		Version:    PSBTVersion,
+		UnsignedTx: unsigned.Serialize(),
+		Inputs:     make([]PSBTInput, len(tx.Inputs)),
+	}
+	for i, in := range tx.Inputs {
+		out, ok := bc.FindOutput(in.TxID, in.OutIndex)
+		if !ok {
+			return nil, fmt.Errorf("psbt: input %d references unknown output %x:%d", i, in.TxID, in.OutIndex)
+		}
+		p.Inputs[i] = PSBTInput{
+			TxID:        hex.EncodeToString(in.TxID),
+			OutIndex:    in.OutIndex,
+			Amount:      out.Amount,
+			PubKeyHash:  hex.EncodeToString(out.PubKeyHash),
+			LockType:    out.LockType,
+			PartialSigs: map[string]string{},
+		}
+		if out.LockType == LockMultisig {
+			// Descriptor zincirde yok; yerel cüzdan deposundan bulunur
+			d, ok := wallet.FindMultisigByHash(out.PubKeyHash)
+			if !ok {
+				return nil, fmt.Errorf("psbt: input %d: unknown multisig descriptor for %s (import it first)", i, out.Address())
+			}
+			p.Inputs[i].Redeem = hex.EncodeToString(d.Bytes())
+		}
+	}
+	return p, nil
+}
+
+// descriptor: multisig input'un redeem descriptor'ı (P2PKH için nil)
+func (in PSBTInput) descriptor() (*wallet.MultisigDescriptor, error) {
+	if in.LockType != LockMultisig {
+		return nil, nil
+	}
+	raw, err := hex.DecodeString(in.Redeem)
+	if err != nil {
+		return nil, fmt.Errorf("psbt: bad redeem hex: %w", err)
+	}
+	d, err := wallet.ParseMultisigDescriptor(raw)
+	if err != nil {
+		return nil, err
+	}
+	if hex.EncodeToString(d.Hash()) != in.PubKeyHash {
+		return nil, fmt.Errorf("psbt: redeem descriptor does not match locked hash")
+	}
+	return d, nil
+}
+
+// spentOutput: input kaydının tarif ettiği harcanan çıktı. v1+ imzalar bu
+// tutar ve kilidi kapsar (bkz. sigHash); kayıt zincirle uyuşmuyorsa imza geçersizdir.
+func (in PSBTInput) spentOutput() (TransactionOutput, error) {
+	pkh, err := hex.DecodeString(in.PubKeyHash)
+	if err != nil {
+		return TransactionOutput{}, fmt.Errorf("psbt: bad pubKeyHash hex: %w", err)
+	}
+	return TransactionOutput{Amount: in.Amount, PubKeyHash: pkh, LockType: in.LockType}, nil
+}
+
+// spentOutputs: tüm input'ların harcanan çıktıları (Check sonrası)
+func (p *PSBT) spentOutputs() ([]TransactionOutput, error) {
+	prevs := make([]TransactionOutput, len(p.Inputs))
+	for i, in := range p.Inputs {
+		out, err := in.spentOutput()
+		if err != nil {
+			return nil, fmt.Errorf("psbt: input %d: %w", i, err)
+		}
+		prevs[i] = out
+	}
+	return prevs, nil
+}
+
+// Tx: kaptaki imzasız işlemi çözer.
+func (p *PSBT) Tx() (*Transaction, error) {
+	tx := DeserializeTransaction(p.UnsignedTx)
+	if tx == nil {
+		return nil, fmt.Errorf("psbt: unsigned tx decode failed")
+	}
+	return tx, nil
+}
+
+// Check: yapısal tutarlılık (magic, sürüm, input eşleşmesi)
+func (p *PSBT) Check() error {
+	if p == nil {
+		return fmt.Errorf("psbt: nil")
+	}
+	if p.Magic != PSBTMagic {
+		return fmt.Errorf("psbt: bad magic %q", p.Magic)
+	}
+	if p.Version != PSBTVersion {
+		return fmt.Errorf("psbt: unsupported version %d", p.Version)
+	}
+	tx, err := p.Tx()
+	if err != nil {
+		return err
+	}
+	if len(tx.Inputs) != len(p.Inputs) {
+		return fmt.Errorf("psbt: input count mismatch (%d tx, %d psbt)", len(tx.Inputs), len(p.Inputs))
+	}
+	for i, in := range tx.Inputs {
+		if len(in.Signature) != 0 || len(in.PubKey) != 0 {
+			return fmt.Errorf("psbt: unsigned tx input %d carries signature data", i)
+		}
+		if hex.EncodeToString(in.TxID) != p.Inputs[i].TxID || in.OutIndex != p.Inputs[i].OutIndex {
+			return fmt.Errorf("psbt: input %d outpoint mismatch", i)
+		}
+		if p.Inputs[i].Amount <= 0 {
+			return fmt.Errorf("psbt: input %d: %w", i, ErrInvalidAmount)
+		}
+		switch p.Inputs[i].LockType {
+		case LockP2PKH:
+		case LockMultisig:
+			if _, err := p.Inputs[i].descriptor(); err != nil {
+				return fmt.Errorf("psbt: input %d: %w", i, err)
+			}
+		default:
+			return fmt.Errorf("psbt: input %d: unknown lock type %d", i, p.Inputs[i].LockType)
+		}
+	}
+	return nil
+}
+
+// Sign: anahtarın sahibi olduğu tüm input'ları imzalar; imzalanan input sayısını döner.
+func (p *PSBT) Sign(priv *ecdsa.PrivateKey) (int, error) {
+	if err := p.Check(); err != nil {
+		return 0, err
+	}
+	if priv == nil {
+		return 0, fmt.Errorf("psbt: nil private key")
+	}
+	tx, err := p.Tx()
+	if err != nil {
+		return 0, err
+	}
+	prevs, err := p.spentOutputs()
+	if err != nil {
+		return 0, err
+	}
+
+	pub := append([]byte{0x04}, pad32(priv.PublicKey.X.Bytes())...)
+	pub = append(pub, pad32(priv.PublicKey.Y.Bytes())...)
+	if tx.Version >= TxVersionSchnorr {
+		pub = wallet.CompressPubKey(pub)
+	}
+
+	signed := 0
+	for i := range p.Inputs {
+		// Kısmi imza, tx'e girecek anahtar kodlamasıyla saklanır
+		pubHex := hex.EncodeToString(pub)
+		if p.Inputs[i].LockType == LockMultisig {
+			d, _ := p.Inputs[i].descriptor() // Check() doğruladı
+			k := d.IndexOf(pub)
+			if k < 0 {
+				continue
+			}
+			pubHex = hex.EncodeToString(d.PubKeys[k])
+		} else if pkh, err := hex.DecodeString(p.Inputs[i].PubKeyHash); err != nil || !pubKeyHashMatches(pub, pkh) {
+			continue
+		}
+		sig, err := signInput(tx, i, priv, SigHashAll, &prevs[i])
+		if err != nil {
+			return signed, fmt.Errorf("psbt: sign input %d: %w", i, err)
+		}
+		if p.Inputs[i].PartialSigs == nil {
+			p.Inputs[i].PartialSigs = map[string]string{}
+		}
+		p.Inputs[i].PartialSigs[pubHex] = hex.EncodeToString(sig)
+		signed++
+	}
+	return signed, nil
+}
+
+// SignWithStore: yerel cüzdan deposunda anahtarı bulunan tüm input'ları imzalar
+// (multisig descriptor'ındaki bize ait anahtarlar dahil).
+func (p *PSBT) SignWithStore() (int, error) {
+	if err := p.Check(); err != nil {
+		return 0, err
+	}
+	keys := map[string]*ecdsa.PrivateKey{}
+	for _, in := range p.Inputs {
+		d, _ := in.descriptor()
+		if d == nil {
+			pkh, err := hex.DecodeString(in.PubKeyHash)
+			if err != nil {
+				continue
+			}
+			if w, ok := wallet.LoadWalletByAddress(wallet.AddressFromPubKeyHash(pkh)); ok {
+				keys[hex.EncodeToString(w.PublicKey)] = w.PrivateKey
+			}
+			continue
+		}
+		for _, pub := range d.PubKeys {
+			if w, ok := wallet.FindWalletByPubKey(pub); ok {
+				keys[hex.EncodeToString(pub)] = w.PrivateKey
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return 0, fmt.Errorf("psbt: no matching keys in wallet store")
+	}
+	total := 0
+	for _, priv := range keys {
+		n, err := p.Sign(priv)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// CombinePSBT: aynı işleme ait PSBT'lerin kısmi imzalarını birleştirir.
+func CombinePSBT(parts ...*PSBT) (*PSBT, error) {
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("psbt: nothing to combine")
+	}
+	for i, p := range parts {
+		if err := p.Check(); err != nil {
+			return nil, fmt.Errorf("psbt #%d: %w", i, err)
+		}
+		if !bytes.Equal(p.UnsignedTx, parts[0].UnsignedTx) {
+			return nil, fmt.Errorf("psbt #%d: different unsigned transaction", i)
+		}
+	}
+
+	base := parts[0]
+	out := &PSBT{
+		Magic:      base.Magic,
+		Version:    base.Version,
+		UnsignedTx: append([]byte(nil), base.UnsignedTx...),
+		Inputs:     make([]PSBTInput, len(base.Inputs)),
+	}
+	for i, in := range base.Inputs {
+		in.PartialSigs = map[string]string{}
+		out.Inputs[i] = in
+	}
+	for _, p := range parts {
+		for i, in := range p.Inputs {
+			for pub, sig := range in.PartialSigs {
+				out.Inputs[i].PartialSigs[pub] = sig
+			}
+		}
+	}
+	return out, nil
+}
+
+// inputSig: P2PKH input'un kilidini açan (pubkey, imza) çiftini döner.
+func (in PSBTInput) inputSig() (pub, sig []byte, ok bool) {
+	pkh, err := hex.DecodeString(in.PubKeyHash)
+	if err != nil {
+		return nil, nil, false
+	}
+	for pubHex, sigHex := range in.PartialSigs {
+		pb, err1 := hex.DecodeString(pubHex)
+		sb, err2 := hex.DecodeString(sigHex)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if pubKeyHashMatches(pb, pkh) {
+			return pb, sb, true
+		}
+	}
+	return nil, nil, false
+}
+
+// multisigSigs: descriptor sırasına hizalı imzalar ve kaç tanesinin dolu olduğu.
+// Eşik aşıldıktan sonraki imzalar eklenmez (tx gereksiz büyümesin).
+func (in PSBTInput) multisigSigs(d *wallet.MultisigDescriptor) ([][]byte, int) {
+	sigs := make([][]byte, len(d.PubKeys))
+	have := 0
+	for k, pub := range d.PubKeys {
+		if have >= d.M {
+			break
+		}
+		sigHex, ok := in.PartialSigs[hex.EncodeToString(pub)]
+		if !ok {
+			continue
+		}
+		sb, err := hex.DecodeString(sigHex)
+		if err != nil {
+			continue
+		}
+		sigs[k] = sb
+		have++
+	}
+	return sigs, have
+}
+
+// status: input için (mevcut, gereken) imza sayısı
+func (in PSBTInput) status() (have, required int) {
+	d, err := in.descriptor()
+	if err != nil {
+		return 0, 1
+	}
+	if d != nil {
+		_, have = in.multisigSigs(d)
+		return have, d.M
+	}
+	if _, _, ok := in.inputSig(); ok {
+		return 1, 1
+	}
+	return 0, 1
+}
+
+// Finalize: tüm input'lar imzalıysa yayınlanabilir işlemi üretir.
+func (p *PSBT) Finalize() (*Transaction, error) {
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+	tx, err := p.Tx()
+	if err != nil {
+		return nil, err
+	}
+	for i, in := range p.Inputs {
+		d, err := in.descriptor()
+		if err != nil {
+			return nil, err
+		}
+		if d != nil {
+			sigs, have := in.multisigSigs(d)
+			if have < d.M {
+				return nil, fmt.Errorf("psbt: input %d has %d of %d required signatures", i, have, d.M)
+			}
+			tx.Inputs[i].Redeem = d.Bytes()
+			tx.Inputs[i].Sigs = sigs
+			continue
+		}
+		pub, sig, ok := in.inputSig()
+		if !ok {
+			return nil, fmt.Errorf("psbt: input %d is not signed", i)
+		}
+		tx.Inputs[i].Signature = sig
+		tx.Inputs[i].PubKey = pub
+	}
+	prevs, err := p.spentOutputs()
+	if err != nil {
+		return nil, err
+	}
+	if !tx.Verify(prevs) {
+		return nil, fmt.Errorf("psbt: finalized tx failed verification")
+	}
+	return tx, nil
+}
+
+// Inspect: insan-okunur özet
+func (p *PSBT) Inspect() (*PSBTSummary, error) {
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+	tx, err := p.Tx()
+	if err != nil {
+		return nil, err
+	}
+	sum := &PSBTSummary{
+		Version:  p.Version,
+		TxID:     hex.EncodeToString(tx.ID),
+		Sender:   tx.Sender,
+		Inputs:   make([]PSBTInputSummary, 0, len(p.Inputs)),
+		Outputs:  make([]PSBTOutputSummary, 0, len(tx.Outputs)),
+		Complete: true,
+	}
+	for _, in := range p.Inputs {
+		have, required := in.status()
+		ok := have >= required
+		owner := ""
+		if pkh, err := hex.DecodeString(in.PubKeyHash); err == nil {
+			lock := TransactionOutput{PubKeyHash: pkh, LockType: in.LockType}
+			owner = lock.Address()
+		}
+		sum.Inputs = append(sum.Inputs, PSBTInputSummary{
+			TxID:     in.TxID,
+			N:        in.OutIndex,
+			Amount:   in.Amount,
+			Owner:    owner,
+			Sigs:     len(in.PartialSigs),
+			Required: required,
+			Signed:   ok,
+		})
+		sum.InTotal += in.Amount
+		if !ok {
+			sum.Complete = false
+		}
+	}
+	for _, out := range tx.Outputs {
+		sum.Outputs = append(sum.Outputs, PSBTOutputSummary{
+			Amount:  out.Amount,
+			Address: out.Address(),
+		})
+		sum.OutTotal += out.Amount
+	}
+	sum.Fee = sum.InTotal - sum.OutTotal
+	return sum, nil
+}
+
+// Encode: base64(JSON)
+func (p *PSBT) Encode() (string, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("psbt encode: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// DecodePSBT: base64(JSON) -> PSBT (+ yapısal kontrol)
+func DecodePSBT(s string) (*PSBT, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("psbt: bad base64: %w", err)
+	}
+	var p PSBT
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("psbt: bad json: %w", err)
+	}
+	if err := p.Check(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *PSBT) SaveToFile(filename string) error {
+	s, err := p.Encode()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, []byte(s+"\n"), 0o600)
+}
+
+func LoadPSBTFromFile(filename string) (*PSBT, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read psbt file: %w", err)
+	}
+	return DecodePSBT(string(data))
+}
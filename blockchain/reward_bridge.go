@@ -4,6 +4,8 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"time"
+
+	"quantumcoin/config"
 )
 
 func nftDropEligible(height int64, blockHash []byte) (bool, string) {
@@ -25,34 +27,33 @@ func hexString(b []byte) string {
 	return string(out)
 }
 
+// ComputeRewardBridge: now anındaki sübvansiyonun coinbase bölüşümüyle aynı dökümü.
+// cfg *config.Config değilse config.Current() kullanılır. fees (CurrentBlockVersion
+// >= BlockVersionMinerFees) madenci payına eklenir.
 func ComputeRewardBridge(
 	height int64,
 	now int64,
 	totalMintedQC int64,
 	blockHash []byte,
 	fees int64,
-	_ /* cfg placeholder */ any,
+	cfg any,
 ) RewardBreakdown {
 	if now == 0 {
 		now = time.Now().Unix()
 	}
-	base := int64(GetCurrentReward()) // QC → atom 1:1
-
-	// Tamamını madenci alsın (basit profil)
-	toMiner := base + fees
-	rb := RewardBreakdown{
-		Height:        height,
-		Timestamp:     now,
-		BaseSubsidy:   base,
-		AnnualBonus:   0,
-		FeesCollected: fees,
-		ToMiner:       toMiner,
-		ToStakers:     0,
-		ToDev:         0,
-		ToBurn:        0,
-		ToCommunity:   0,
-		Total:         toMiner,
+	c, ok := cfg.(*config.Config)
+	if !ok || c == nil {
+		c = config.Current()
+	}
+	base := RewardAt(now) // QC → atom 1:1
+	rb, _, err := computeRewardSplit(base, int(fees), CurrentBlockVersion, c.Params().Reward)
+	if err != nil {
+		// geçersiz hedef adresi: blok üretilemez; döküm tamamını madenciye yazar
+		rb = RewardBreakdown{BaseSubsidy: int64(base), ToMiner: int64(base), Total: int64(base)}
 	}
+	rb.Height = height
+	rb.Timestamp = now
+	rb.FeesCollected = fees
 	ok, det := nftDropEligible(height, blockHash)
 	rb.NFTAwarded = ok
 	rb.NFTDeterminism = det
@@ -0,0 +1,138 @@
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"quantumcoin/config"
+)
+
+// Coinbase ödül bölüşümü (Block.Version >= BlockVersionRewardSplit):
+//
+//	Outputs[0]  miner      (kalan: PctMiner + yuvarlama; v4+ bloktaki ücretler dahil)
+//	Outputs[1:] stake, dev, burn, community (sırayla; tutarı 0 olan atlanır)
+//
+// Miner dışındaki her hedef subsidy*pct/100 (aşağı yuvarlanmış) alır.
+// Payı olan stake/dev/community hedefinin adresi boşsa bölüşüm hatalıdır (bkz.
+// config.RewardSplit.Validate); pay madenciye kaydırılmaz. Burn payı v3+
+// blokta LockBurn çıktısıdır; v2'de burn adresine gider, adres çözülemiyorsa
+// (varsayılan QC_BURN_SINK) hiç basılmaz.
+// Yüzdeler ve adresler ağ parametresidir (config.NetworkParams.Reward); kullanıcı
+// yapılandırması değiştiremez. Doğrulayan düğüm bölüşümü blok zaman damgası ve
+// ağ parametreleriyle yeniden hesaplar; coinbase birebir tutmazsa blok
+// ErrRewardSplit ile reddedilir.
+
+// computeRewardSplit: sübvansiyonun dökümü ve miner dışındaki coinbase çıktıları
+func computeRewardSplit(subsidy, fees, version int, r config.RewardSplit) (RewardBreakdown, []TransactionOutput, error) {
+	rb := RewardBreakdown{BaseSubsidy: int64(subsidy)}
+	var outs []TransactionOutput
+	left := subsidy
+	share := func(pct int) int {
+		if pct <= 0 || subsidy <= 0 {
+			return 0
+		}
+		return min(subsidy*pct/100, left)
+	}
+	pay := func(pct int, address string, dst *int64) error {
+		amt := share(pct)
+		address = strings.TrimSpace(address)
+		if amt == 0 {
+			return nil
+		}
+		if address == "" {
+			return fmt.Errorf("%w: %d%% share has no address", ErrRewardSplit, pct)
+		}
+		out, err := NewTxOutput(amt, address)
+		if err != nil {
+			return fmt.Errorf("%w: address %q: %v", ErrRewardSplit, address, err)
+		}
+		outs = append(outs, out)
+		left -= amt
+		*dst = int64(amt)
+		return nil
+	}
+
+	if err := pay(r.PctStake, r.StakePool, &rb.ToStakers); err != nil {
+		return RewardBreakdown{}, nil, err
+	}
+	if err := pay(r.PctDev, r.DevFund, &rb.ToDev); err != nil {
+		return RewardBreakdown{}, nil, err
+	}
+	if amt := share(r.PctBurn); amt > 0 {
+		if version >= BlockVersionBurnOutput {
+			outs = append(outs, NewBurnOutput(amt))
+		} else if out, err := NewTxOutput(amt, strings.TrimSpace(r.BurnSink)); err == nil {
+			outs = append(outs, out)
+		}
+		left -= amt
+		rb.ToBurn = int64(amt)
+	}
+	if err := pay(r.PctCommunity(), r.Community, &rb.ToCommunity); err != nil {
+		return RewardBreakdown{}, nil, err
+	}
+
+	rb.ToMiner = int64(left)
+	if version >= BlockVersionMinerFees && fees > 0 {
+		rb.ToMiner += int64(fees)
+		rb.FeesCollected = int64(fees)
+	}
+	rb.Total = rb.ToMiner + rb.ToStakers + rb.ToDev + rb.ToBurn + rb.ToCommunity
+	return rb, outs, nil
+}
+
+// checkCoinbaseSplit: coinbase ilk ve tek, çıktıları bölüşümle birebir aynı mı
+// (fees: bloktaki coinbase dışı işlemlerin ücret toplamı)
+func checkCoinbaseSplit(blk *Block, fees int) (RewardBreakdown, error) {
+	if len(blk.Transactions) == 0 || blk.Transactions[0] == nil || !blk.Transactions[0].IsCoinbase() {
+		return RewardBreakdown{}, fmt.Errorf("%w: first transaction must be coinbase", ErrRewardSplit)
+	}
+	for _, tx := range blk.Transactions[1:] {
+		if tx != nil && tx.IsCoinbase() {
+			return RewardBreakdown{}, fmt.Errorf("%w: extra coinbase", ErrRewardSplit)
+		}
+	}
+	rb, want, err := computeRewardSplit(RewardAt(blk.Timestamp), fees, blk.Version, config.Current().Params().Reward)
+	if err != nil {
+		return RewardBreakdown{}, err
+	}
+	got := blk.Transactions[0].Outputs
+	if len(got) != 1+len(want) {
+		return RewardBreakdown{}, fmt.Errorf("%w: %d outputs, want %d", ErrRewardSplit, len(got), 1+len(want))
+	}
+	if int64(got[0].Amount) != rb.ToMiner {
+		return RewardBreakdown{}, fmt.Errorf("%w: miner output %d, want %d", ErrRewardSplit, got[0].Amount, rb.ToMiner)
+	}
+	if blk.Version >= BlockVersionMinerFees && got[0].LockType != LockP2PKH && got[0].LockType != LockMultisig {
+		return RewardBreakdown{}, fmt.Errorf("%w: miner output lock %d", ErrRewardSplit, got[0].LockType)
+	}
+	for i, w := range want {
+		g := got[i+1]
+		if g.Amount != w.Amount || g.LockType != w.LockType || !bytes.Equal(g.PubKeyHash, w.PubKeyHash) {
+			return RewardBreakdown{}, fmt.Errorf("%w: output %d", ErrRewardSplit, i+1)
+		}
+	}
+	rb.Height = int64(blk.Index)
+	rb.Timestamp = blk.Timestamp
+	rb.NFTAwarded, rb.NFTDeterminism = nftDropEligible(rb.Height, blk.Hash)
+	return rb, nil
+}
+
+// validateBlockReward: v2+ blokta bölüşümü denetler ve dökümü Metadata'ya yazar
+// (Metadata hash'e girmez; her düğüm kabul sırasında aynı değerleri üretir).
+func validateBlockReward(blk *Block, fees int) error {
+	if blk.Version < BlockVersionRewardSplit {
+		return nil
+	}
+	rb, err := checkCoinbaseSplit(blk, fees)
+	if err != nil {
+		return err
+	}
+	if blk.Metadata == nil {
+		blk.Metadata = map[string]string{}
+	}
+	for k, v := range rb.MetadataKV() {
+		blk.Metadata[k] = v
+	}
+	return nil
+}
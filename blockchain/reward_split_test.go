@@ -0,0 +1,32 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"quantumcoin/config"
+)
+
+func TestCoinbasePaysEveryDestination(t *testing.T) {
+	bc := newRegtestChain(t)
+	miner := mineBlocks(t, bc, 1)
+	r := config.Current().Params().Reward
+	cb := bc.GetLastBlock().Transactions[0]
+
+	want := []string{miner, r.StakePool, r.DevFund, BurnOutputLabel, r.Community}
+	if len(cb.Outputs) != len(want) {
+		t.Fatalf("coinbase has %d outputs, want %d", len(cb.Outputs), len(want))
+	}
+	for i, addr := range want {
+		if got := cb.Outputs[i].Address(); got != addr {
+			t.Errorf("output %d: %s, want %s", i, got, addr)
+		}
+	}
+}
+
+func TestRewardSplitRejectsUnaddressedShare(t *testing.T) {
+	r := config.RewardSplit{PctMiner: 90, PctStake: 10}
+	if _, _, err := computeRewardSplit(50, 0, CurrentBlockVersion, r); !errors.Is(err, ErrRewardSplit) {
+		t.Fatalf("got %v, want ErrRewardSplit", err)
+	}
+}
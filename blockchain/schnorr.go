@@ -0,0 +1,268 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// BIP340 tarzı Schnorr imzaları (secp256k1, x-only anahtar, 64 bayt imza).
+// dcrec/secp256k1/schnorr paketi Decred'in kendi (BIP340 olmayan) şemasıdır;
+// burada yalnız alt seviye eğri/skalar primitifleri kullanılır.
+//
+//	sig = R.x (32) || s (32),  s = k + e·d,  e = H_challenge(R.x || P.x || m)
+//
+// Nonce deterministiktir (aux = 32 sıfır bayt): aynı anahtar + mesaj => aynı imza.
+
+const SchnorrSigSize = 64
+
+var ErrSchnorrSig = errors.New("invalid schnorr signature")
+
+// taggedHash: sha256(sha256(tag) || sha256(tag) || msgs...)
+func taggedHash(tag string, msgs ...[]byte) [32]byte {
+	th := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(th[:])
+	h.Write(th[:])
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// liftX: x koordinatından çift Y'li noktayı kurar (BIP340 lift_x)
+func liftX(xb []byte) (*secp256k1.JacobianPoint, bool) {
+	var x, y secp256k1.FieldVal
+	if len(xb) != 32 || x.SetByteSlice(xb) {
+		return nil, false // x >= p
+	}
+	if !secp256k1.DecompressY(&x, false, &y) {
+		return nil, false
+	}
+	y.Normalize()
+	var one secp256k1.FieldVal
+	one.SetInt(1)
+	p := secp256k1.MakeJacobianPoint(&x, &y, &one)
+	return &p, true
+}
+
+// xOnly: 33 ya da 65 baytlık anahtardan 32 baytlık x koordinatı
+func xOnly(pub []byte) ([]byte, bool) {
+	key, err := secp256k1.ParsePubKey(pub)
+	if err != nil {
+		return nil, false
+	}
+	c := key.SerializeCompressed()
+	return c[1:], true
+}
+
+func isInfinity(p *secp256k1.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+// schnorrChallenge: e = H_challenge(r || px || m) mod n
+func schnorrChallenge(r, px, msg []byte) secp256k1.ModNScalar {
+	h := taggedHash("BIP0340/challenge", r, px, msg)
+	var e secp256k1.ModNScalar
+	e.SetBytes(&h)
+	return e
+}
+
+// schnorrSign: 64 baytlık imza
+func schnorrSign(priv *ecdsa.PrivateKey, msg []byte) ([]byte, error) {
+	if priv == nil || priv.D == nil {
+		return nil, errors.New("nil private key")
+	}
+	key := toSecpPriv(priv)
+	defer key.Zero()
+
+	d := key.Key
+	if d.IsZero() {
+		return nil, errors.New("invalid private key")
+	}
+	pubC := key.PubKey().SerializeCompressed()
+	if pubC[0] == 0x03 { // P.y tek => d = n - d
+		d.Negate()
+	}
+	px := pubC[1:]
+
+	var aux [32]byte
+	auxH := taggedHash("BIP0340/aux", aux[:])
+	db := d.Bytes()
+	var t [32]byte
+	for i := range t {
+		t[i] = db[i] ^ auxH[i]
+	}
+	kh := taggedHash("BIP0340/nonce", t[:], px, msg)
+	var k secp256k1.ModNScalar
+	k.SetBytes(&kh)
+	if k.IsZero() {
+		return nil, errors.New("schnorr: zero nonce")
+	}
+
+	var R secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&k, &R)
+	R.ToAffine()
+	if R.Y.IsOdd() {
+		k.Negate()
+	}
+	rx := R.X.Bytes()
+
+	e := schnorrChallenge(rx[:], px, msg)
+	s := new(secp256k1.ModNScalar).Mul2(&e, &d).Add(&k)
+
+	sig := make([]byte, 0, SchnorrSigSize)
+	sig = append(sig, rx[:]...)
+	sb := s.Bytes()
+	sig = append(sig, sb[:]...)
+
+	d.Zero()
+	k.Zero()
+	return sig, nil
+}
+
+// schnorrItem: tek imza doğrulaması için ayrıştırılmış girdiler
+type schnorrItem struct {
+	P   *secp256k1.JacobianPoint
+	r   []byte
+	s   secp256k1.ModNScalar
+	e   secp256k1.ModNScalar
+	sig []byte
+}
+
+// parseSchnorr: kodlama kuralları (64 bayt, r < p, s < n, geçerli pubkey)
+func parseSchnorr(pub, msg, sig []byte) (*schnorrItem, error) {
+	if len(sig) != SchnorrSigSize {
+		return nil, ErrSchnorrSig
+	}
+	px, ok := xOnly(pub)
+	if !ok {
+		return nil, ErrSigPubKey
+	}
+	P, ok := liftX(px)
+	if !ok {
+		return nil, ErrSigPubKey
+	}
+	var rf secp256k1.FieldVal
+	if rf.SetByteSlice(sig[:32]) {
+		return nil, ErrSchnorrSig // r >= p
+	}
+	it := &schnorrItem{P: P, r: sig[:32], sig: sig}
+	if it.s.SetByteSlice(sig[32:]) {
+		return nil, ErrSchnorrSig // s >= n
+	}
+	it.e = schnorrChallenge(sig[:32], px, msg)
+	return it, nil
+}
+
+// schnorrVerify: tek imza doğrulaması (bkz. schnorrVerifyItem)
+func schnorrVerify(pub, msg, sig []byte) bool {
+	it, err := parseSchnorr(pub, msg, sig)
+	if err != nil {
+		return false
+	}
+	return schnorrVerifyItem(it)
+}
+
+// schnorrVerifyItem: R = s·G - e·P; R sonsuz değil, R.y çift, R.x == r
+func schnorrVerifyItem(it *schnorrItem) bool {
+	var sG, eP, R secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&it.s, &sG)
+	negE := it.e
+	negE.Negate()
+	secp256k1.ScalarMultNonConst(&negE, it.P, &eP)
+	secp256k1.AddNonConst(&sG, &eP, &R)
+	if isInfinity(&R) {
+		return false
+	}
+	R.ToAffine()
+	if R.Y.IsOdd() {
+		return false
+	}
+	rx := R.X.Bytes()
+	return string(rx[:]) == string(it.r)
+}
+
+// schnorrBatch: blok doğrulamasında Schnorr imzalarını toplu doğrular.
+//
+//	(Σ aᵢ·sᵢ)·G == Σ aᵢ·Rᵢ + Σ (aᵢ·eᵢ)·Pᵢ     (a₀ = 1, diğerleri rastgele)
+//
+// Rastgele ağırlıklar, geçersiz imzaların birbirini götürecek şekilde seçilmesini engeller.
+type schnorrBatch struct {
+	items []*schnorrItem
+}
+
+// add: imzayı kuyruğa ekler; kodlama hatası hemen reddedilir.
+func (b *schnorrBatch) add(pub, msg, sig []byte) bool {
+	it, err := parseSchnorr(pub, msg, sig)
+	if err != nil {
+		return false
+	}
+	b.items = append(b.items, it)
+	return true
+}
+
+func (b *schnorrBatch) verify() bool {
+	if b == nil || len(b.items) == 0 {
+		return true
+	}
+	if len(b.items) == 1 {
+		return schnorrVerifyItem(b.items[0])
+	}
+
+	var sum secp256k1.ModNScalar
+	var rhs secp256k1.JacobianPoint
+	for i, it := range b.items {
+		var a secp256k1.ModNScalar
+		if i == 0 {
+			a.SetInt(1)
+		} else if !randScalar(&a) {
+			return false
+		}
+		R, ok := liftX(it.r)
+		if !ok {
+			return false
+		}
+
+		// sum += a·s
+		as := new(secp256k1.ModNScalar).Mul2(&a, &it.s)
+		sum.Add(as)
+
+		// rhs += a·R + (a·e)·P
+		var aR, aeP, tmp secp256k1.JacobianPoint
+		secp256k1.ScalarMultNonConst(&a, R, &aR)
+		ae := new(secp256k1.ModNScalar).Mul2(&a, &it.e)
+		secp256k1.ScalarMultNonConst(ae, it.P, &aeP)
+		secp256k1.AddNonConst(&aR, &aeP, &tmp)
+		var acc secp256k1.JacobianPoint
+		secp256k1.AddNonConst(&rhs, &tmp, &acc)
+		rhs.Set(&acc)
+	}
+
+	var lhs secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&sum, &lhs)
+	if isInfinity(&lhs) || isInfinity(&rhs) {
+		return isInfinity(&lhs) && isInfinity(&rhs)
+	}
+	lhs.ToAffine()
+	rhs.ToAffine()
+	return lhs.X.Equals(&rhs.X) && lhs.Y.Equals(&rhs.Y)
+}
+
+func randScalar(a *secp256k1.ModNScalar) bool {
+	var buf [32]byte
+	for tries := 0; tries < 8; tries++ {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return false
+		}
+		if overflow := a.SetBytes(&buf); overflow == 0 && !a.IsZero() {
+			return true
+		}
+	}
+	return false
+}
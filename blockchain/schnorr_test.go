@@ -0,0 +1,200 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestSchnorrBIP340Vector(t *testing.T) {
+	// BIP340 test vektörü 0 (aux_rand = 0, ki burada da sabittir)
+	priv, _ := testKey(t, "0000000000000000000000000000000000000000000000000000000000000003")
+	pub := mustHex(t, "02f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9")
+	msg := make([]byte, 32)
+	want := mustHex(t, "e907831f80848d1069a5371b402410364bdf1c5f8307b0084c55f1ce2dca8215"+
+		"25f66a4a85ea8b71e482a74f382d2ce5ebeee8fdb2172f477df4900d310536c0")
+
+	sig, err := schnorrSign(priv, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, want) {
+		t.Fatalf("sig = %x, want %x", sig, want)
+	}
+	if !schnorrVerify(pub, msg, sig) {
+		t.Fatal("vector signature does not verify")
+	}
+}
+
+func TestSchnorrSignVerify(t *testing.T) {
+	// tek ve çift Y'li anahtarlar (d ya da n-d ile imzalanır)
+	for _, d := range []string{
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+	} {
+		priv, pub := testKey(t, d)
+		msg := sha256.Sum256([]byte("schnorr " + d))
+
+		sig, err := schnorrSign(priv, msg[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := schnorrSign(priv, msg[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig, again) {
+			t.Fatalf("%s: nonce is not deterministic", d)
+		}
+
+		key, err := secp256k1.ParsePubKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !schnorrVerify(key.SerializeCompressed(), msg[:], sig) {
+			t.Fatalf("%s: signature does not verify", d)
+		}
+
+		other := sha256.Sum256([]byte("other"))
+		if schnorrVerify(key.SerializeCompressed(), other[:], sig) {
+			t.Fatalf("%s: verified against another message", d)
+		}
+		bad := append([]byte{}, sig...)
+		bad[40] ^= 0x01
+		if schnorrVerify(key.SerializeCompressed(), msg[:], bad) {
+			t.Fatalf("%s: tampered s verified", d)
+		}
+		if schnorrVerify(key.SerializeCompressed(), msg[:], sig[:63]) {
+			t.Fatalf("%s: short signature verified", d)
+		}
+	}
+}
+
+// batchFixture: n farklı anahtarla imzalanmış (pub, msg, sig) üçlüleri
+func batchFixture(t *testing.T, n int) (pubs, msgs, sigs [][]byte) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		priv, pub := testKey(t, fmt.Sprintf("%064x", i+11))
+		key, err := secp256k1.ParsePubKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := sha256.Sum256([]byte(fmt.Sprintf("batch %d", i)))
+		sig, err := schnorrSign(priv, msg[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubs = append(pubs, key.SerializeCompressed())
+		msgs = append(msgs, msg[:])
+		sigs = append(sigs, sig)
+	}
+	return pubs, msgs, sigs
+}
+
+func TestSchnorrBatch(t *testing.T) {
+	const n = 5
+	pubs, msgs, sigs := batchFixture(t, n)
+
+	var ok schnorrBatch
+	for i := 0; i < n; i++ {
+		if !ok.add(pubs[i], msgs[i], sigs[i]) {
+			t.Fatalf("add %d rejected", i)
+		}
+	}
+	if !ok.verify() {
+		t.Fatal("valid batch rejected")
+	}
+
+	// kodlaması geçerli ama yanlış olan tek imza tüm batch'i düşürmeli
+	for bad := 0; bad < n; bad++ {
+		var b schnorrBatch
+		for i := 0; i < n; i++ {
+			sig := sigs[i]
+			if i == bad {
+				sig = append([]byte{}, sig...)
+				sig[63] ^= 0x01
+			}
+			if !b.add(pubs[i], msgs[i], sig) {
+				t.Fatalf("tampered %d: add %d rejected", bad, i)
+			}
+		}
+		if b.verify() {
+			t.Fatalf("batch with tampered signature %d accepted", bad)
+		}
+	}
+
+	// başka mesajın imzası (r/s geçerli, bağlam yanlış)
+	var swapped schnorrBatch
+	for i := 0; i < n; i++ {
+		swapped.add(pubs[i], msgs[(i+1)%n], sigs[i])
+	}
+	if swapped.verify() {
+		t.Fatal("batch with swapped messages accepted")
+	}
+
+	var enc schnorrBatch
+	if enc.add(pubs[0], msgs[0], sigs[0][:63]) {
+		t.Fatal("short signature added to batch")
+	}
+	if !enc.verify() {
+		t.Fatal("empty batch should verify")
+	}
+}
+
+func TestSchnorrTxRequiresCompressedKey(t *testing.T) {
+	priv, pub := testKey(t, "0000000000000000000000000000000000000000000000000000000000000007")
+	tx, prevs := testSpend(t, priv, TxVersionSchnorr)
+	if err := tx.Sign(priv, prevs); err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.Inputs[0].PubKey) != 33 {
+		t.Fatalf("v2 signed with %d-byte key", len(tx.Inputs[0].PubKey))
+	}
+	if !tx.Verify(prevs) {
+		t.Fatal("v2 tx does not verify")
+	}
+
+	key, err := secp256k1.ParsePubKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed := key.SerializeCompressed()
+	tests := []struct {
+		name string
+		pub  []byte
+		ok   bool
+	}{
+		{"compressed", compressed, true},
+		{"uncompressed", pub, false},
+		{"x only", compressed[1:], false},
+		{"hybrid", append([]byte{0x06 | pub[64]&1}, pub[1:]...), false},
+		{"bad prefix", append([]byte{0x04}, compressed[1:]...), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := validPubKeyForm(TxVersionSchnorr, tc.pub); got != tc.ok {
+				t.Fatalf("validPubKeyForm = %v, want %v", got, tc.ok)
+			}
+			cp := *tx
+			cp.Inputs = append([]TransactionInput{}, tx.Inputs...)
+			for i := range cp.Inputs {
+				cp.Inputs[i].PubKey = tc.pub
+			}
+			if got := cp.Verify(prevs); got != tc.ok {
+				t.Fatalf("Verify = %v, want %v", got, tc.ok)
+			}
+		})
+	}
+
+	// v1 ise yalnız uncompressed kabul edilir
+	if validPubKeyForm(TxVersionSegregated, compressed) || !validPubKeyForm(TxVersionSegregated, pub) {
+		t.Fatal("v1 key form rules changed")
+	}
+	if err := checkSigEncoding(TxVersionSchnorr, tx.Inputs[0].Signature); err == nil {
+		t.Fatal("65-byte (sig || type) accepted as raw schnorr signature")
+	}
+}
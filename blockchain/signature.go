@@ -0,0 +1,66 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// İmza kuralları (konsensüs):
+//   - nonce RFC6979 ile deterministik (aynı anahtar + mesaj => aynı imza)
+//   - S <= N/2 (low-S); high-S imzalar reddedilir
+//   - kodlama katı DER; fazladan/eksik bayt, gereksiz sıfır dolgu vb. reddedilir
+// Böylece geçerli bir imzanın üçüncü kişilerce başka bir geçerli biçime
+// dönüştürülmesi (malleability) mümkün olmaz.
+
+var (
+	ErrSigEncoding = errors.New("signature is not strict DER")
+	ErrSigHighS    = errors.New("signature S value is not low-S")
+	ErrSigPubKey   = errors.New("invalid public key")
+)
+
+// toSecpPriv: crypto/ecdsa anahtarını secp256k1 anahtarına çevirir.
+func toSecpPriv(priv *ecdsa.PrivateKey) *secp256k1.PrivateKey {
+	return secp256k1.PrivKeyFromBytes(pad32(priv.D.Bytes()))
+}
+
+// signDigest: RFC6979 + low-S, DER kodlu imza
+func signDigest(priv *ecdsa.PrivateKey, digest []byte) ([]byte, error) {
+	if priv == nil || priv.D == nil {
+		return nil, errors.New("nil private key")
+	}
+	key := toSecpPriv(priv)
+	defer key.Zero()
+	return secpecdsa.Sign(key, digest).Serialize(), nil
+}
+
+// parseSig: katı DER + low-S kontrolü
+func parseSig(sig []byte) (*secpecdsa.Signature, error) {
+	parsed, err := secpecdsa.ParseDERSignature(sig)
+	if err != nil {
+		return nil, ErrSigEncoding
+	}
+	s := parsed.S()
+	if s.IsOverHalfOrder() {
+		return nil, ErrSigHighS
+	}
+	return parsed, nil
+}
+
+// verifySig: pubkey (65B uncompressed ya da 33B compressed) + digest + DER imza
+func verifySig(pub, digest, sig []byte) bool {
+	if !(len(pub) == 65 && pub[0] == 0x04) && !(len(pub) == 33 && (pub[0] == 0x02 || pub[0] == 0x03)) {
+		return false
+	}
+	key, err := secp256k1.ParsePubKey(pub)
+	if err != nil {
+		return false
+	}
+	parsed, err := parseSig(sig)
+	if err != nil {
+		return false
+	}
+	return parsed.Verify(digest, key)
+}
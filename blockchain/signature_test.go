@@ -0,0 +1,173 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// testKey: sabit skalerden secp256k1 anahtarı
+func testKey(t *testing.T, d string) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+	raw, err := hex.DecodeString(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := secp256k1.PrivKeyFromBytes(raw)
+	return key.ToECDSA(), key.PubKey().SerializeUncompressed()
+}
+
+// derInt: DER INTEGER gövdesi (en kısa biçim, gerekirse 0x00 işaret baytı)
+func derInt(b []byte) []byte {
+	b = bytes.TrimLeft(b, "\x00")
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		b = append([]byte{0x00}, b...)
+	}
+	return b
+}
+
+// derSig: r ve s gövdelerinden DER imza
+func derSig(r, s []byte) []byte {
+	body := append([]byte{0x02, byte(len(r))}, r...)
+	body = append(body, 0x02, byte(len(s)))
+	body = append(body, s...)
+	return append([]byte{0x30, byte(len(body))}, body...)
+}
+
+// splitSig: DER imzadan r ve s gövdeleri (test girdisi geçerli varsayılır)
+func splitSig(sig []byte) (r, s []byte) {
+	rl := int(sig[3])
+	r = sig[4 : 4+rl]
+	s = sig[6+rl:]
+	return r, s
+}
+
+func TestSignDigestRFC6979(t *testing.T) {
+	// RFC6979 test vektörü: d=1, SHA256("Satoshi Nakamoto")
+	priv, pub := testKey(t, "0000000000000000000000000000000000000000000000000000000000000001")
+	digest := sha256.Sum256([]byte("Satoshi Nakamoto"))
+	want := derSig(
+		derInt(mustHex(t, "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8")),
+		derInt(mustHex(t, "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5")),
+	)
+
+	for i := 0; i < 2; i++ {
+		sig, err := signDigest(priv, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig, want) {
+			t.Fatalf("sign #%d = %x, want %x", i, sig, want)
+		}
+		if !verifySig(pub, digest[:], sig) {
+			t.Fatalf("sign #%d does not verify", i)
+		}
+	}
+
+	other := sha256.Sum256([]byte("Satoshi Nakamoto!"))
+	sig, err := signDigest(priv, other[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sig, want) {
+		t.Fatal("different digests produced the same signature")
+	}
+	if verifySig(pub, digest[:], sig) {
+		t.Fatal("signature verified against the wrong digest")
+	}
+}
+
+func TestParseSigStrictDER(t *testing.T) {
+	priv, pub := testKey(t, "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	digest := sha256.Sum256([]byte("sample"))
+	sig, err := signDigest(priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s := splitSig(sig)
+
+	// highS: N - s; matematiksel olarak geçerli ama low-S kuralını çiğner
+	var sc secp256k1.ModNScalar
+	sc.SetByteSlice(s)
+	sc.Negate()
+	highS := sc.Bytes()
+
+	// uzun biçim uzunluk: 0x30 0x81 <len> ... (len < 128 için en kısa değil)
+	longLen := append([]byte{0x30, 0x81}, sig[1:]...)
+	// r önünde gereksiz sıfır dolgu
+	padR := derSig(append([]byte{0x00}, r...), s)
+
+	tests := []struct {
+		name string
+		sig  []byte
+		err  error
+	}{
+		{"valid", sig, nil},
+		{"high S", derSig(r, derInt(highS[:])), ErrSigHighS},
+		{"trailing byte", append(append([]byte{}, sig...), 0x00), ErrSigEncoding},
+		{"non-minimal length", longLen, ErrSigEncoding},
+		{"padded R", padR, ErrSigEncoding},
+		{"short outer length", append([]byte{0x30, sig[1] - 1}, sig[2:]...), ErrSigEncoding},
+		{"wrong sequence tag", append([]byte{0x31}, sig[1:]...), ErrSigEncoding},
+		{"truncated", sig[:len(sig)-1], ErrSigEncoding},
+		{"empty", nil, ErrSigEncoding},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseSig(tc.sig)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("parseSig err = %v, want %v", err, tc.err)
+			}
+			if got := verifySig(pub, digest[:], tc.sig); got != (tc.err == nil) {
+				t.Fatalf("verifySig = %v, want %v", got, tc.err == nil)
+			}
+		})
+	}
+}
+
+func TestVerifySigPubKeyForms(t *testing.T) {
+	priv, pub := testKey(t, "0000000000000000000000000000000000000000000000000000000000000003")
+	digest := sha256.Sum256([]byte("pubkey forms"))
+	sig, err := signDigest(priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := secp256k1.ParsePubKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hybrid := append([]byte{0x06 | pub[64]&1}, pub[1:]...)
+
+	tests := []struct {
+		name string
+		pub  []byte
+		ok   bool
+	}{
+		{"uncompressed", pub, true},
+		{"compressed", key.SerializeCompressed(), true},
+		{"hybrid", hybrid, false},
+		{"x only", pub[1:33], false},
+		{"empty", nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := verifySig(tc.pub, digest[:], sig); got != tc.ok {
+				t.Fatalf("verifySig = %v, want %v", got, tc.ok)
+			}
+		})
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
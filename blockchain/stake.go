@@ -0,0 +1,261 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"quantumcoin/config"
+	"quantumcoin/wallet"
+)
+
+// Zincir üstü staking:
+//
+//	stake     serbest çıktılar -> LockStake{owner, UnlockHeight >= h+Stake.MinLockBlocks}
+//	unstake   LockStake (UnlockHeight dolmuş) -> tek LockUnbonding{owner, UnlockHeight >= h+Stake.CooldownBlocks}
+//	withdraw  LockUnbonding (UnlockHeight dolmuş) -> serbest çıktı
+//
+// Stake, ağın config.NetworkParams.Stake kurallarıdır (konsensüs; config değiştiremez).
+// h, işlemin gireceği bloğun yüksekliğidir. Stake/unbonding çıktıları
+// bakiyeye (IsLockedWithKey) sayılmaz; stake ağırlığı = harcanmamış LockStake tutarı.
+
+// stakeMargin: oluşturucuların UnlockHeight'a eklediği pay (işlem birkaç blok
+// mempool'da bekleyip alt sınırın altına düşmesin diye)
+const stakeMargin = 6
+
+// Stake durumları
+const (
+	StakeActive    = "active"    // ağırlığa sayılır
+	StakeUnbonding = "unbonding" // bekleme süresinde; UnlockHeight'tan sonra çekilebilir
+)
+
+// Stake: harcanmamış stake/unbonding çıktısı
+type Stake struct {
+	TxID         string `json:"txid"`
+	Index        int    `json:"index"`
+	Owner        string `json:"owner"`
+	Amount       int    `json:"amount"`
+	Height       int    `json:"height"` // çıktının girdiği blok
+	UnlockHeight int    `json:"unlockHeight"`
+	Status       string `json:"status"`
+}
+
+// checkStakeRules: checkTxInputs'un staking kuralları (ins: harcanan çıktılar)
+func checkStakeRules(tx *Transaction, ins []TransactionOutput, height int) error {
+	r := config.Current().Params().Stake
+	staked, owner := 0, []byte(nil)
+	for i, out := range ins {
+		if out.LockType < LockStake {
+			continue
+		}
+		if height < out.UnlockHeight {
+			return fmt.Errorf("input %d: %w (until height %d)", i, ErrStakeLocked, out.UnlockHeight)
+		}
+		if out.LockType != LockStake {
+			continue
+		}
+		if owner != nil && !bytes.Equal(owner, out.PubKeyHash) {
+			return fmt.Errorf("%w: unstake mixes owners", ErrStakeRules)
+		}
+		owner = out.PubKeyHash
+		staked++
+	}
+
+	if staked > 0 {
+		// unstake: yalnız aynı sahibin stake'leri -> tek unbonding çıktısı
+		if staked != len(ins) {
+			return fmt.Errorf("%w: unstake cannot spend other outputs", ErrStakeRules)
+		}
+		inSum := 0
+		for _, out := range ins {
+			inSum += out.Amount
+		}
+		if len(tx.Outputs) != 1 {
+			return fmt.Errorf("%w: unstake must have exactly one output", ErrStakeRules)
+		}
+		out := tx.Outputs[0]
+		if out.LockType != LockUnbonding || !bytes.Equal(out.PubKeyHash, owner) || out.Amount != inSum {
+			return fmt.Errorf("%w: unstake output must return the full stake to its owner as unbonding", ErrStakeRules)
+		}
+		if out.UnlockHeight < height+r.CooldownBlocks {
+			return fmt.Errorf("%w: cooldown ends before height %d", ErrStakeRules, height+r.CooldownBlocks)
+		}
+		return nil
+	}
+
+	for i, out := range tx.Outputs {
+		switch out.LockType {
+		case LockUnbonding:
+			return fmt.Errorf("%w: output %d: unbonding outputs come only from unstake", ErrStakeRules, i)
+		case LockStake:
+			if len(out.PubKeyHash) != 20 {
+				return fmt.Errorf("%w: output %d: bad owner", ErrStakeRules, i)
+			}
+			if out.Amount < r.MinAmount {
+				return fmt.Errorf("%w: output %d: stake below minimum %d", ErrStakeRules, i, r.MinAmount)
+			}
+			if out.UnlockHeight < height+r.MinLockBlocks {
+				return fmt.Errorf("%w: output %d: lock ends before height %d", ErrStakeRules, i, height+r.MinLockBlocks)
+			}
+		}
+	}
+	return nil
+}
+
+// Stakes: harcanmamış stake ve unbonding çıktıları (address boşsa hepsi)
+func (bc *Blockchain) Stakes(address string) []Stake {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.stakes(address)
+}
+
+func (bc *Blockchain) stakes(address string) []Stake {
+	var pkh []byte
+	if address != "" {
+		_, h, err := wallet.DecodeAddress(address)
+		if err != nil {
+			return nil
+		}
+		pkh = h
+	}
+	var out []Stake
+	for height, blk := range bc.Blocks {
+		for _, tx := range blk.Transactions {
+			for idx, o := range tx.Outputs {
+				if (o.LockType != LockStake && o.LockType != LockUnbonding) || (pkh != nil && !bytes.Equal(o.PubKeyHash, pkh)) ||
+					bc.isOutputSpent(tx.ID, idx) {
+					continue
+				}
+				st := Stake{
+					TxID:         hex.EncodeToString(tx.ID),
+					Index:        idx,
+					Owner:        wallet.AddressFromPubKeyHash(o.PubKeyHash),
+					Amount:       o.Amount,
+					Height:       height,
+					UnlockHeight: o.UnlockHeight,
+					Status:       StakeActive,
+				}
+				if o.LockType == LockUnbonding {
+					st.Status = StakeUnbonding
+				}
+				out = append(out, st)
+			}
+		}
+	}
+	return out
+}
+
+// StakeWeights: sahip adresi -> aktif stake toplamı
+func (bc *Blockchain) StakeWeights() map[string]int {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	w := map[string]int{}
+	for _, st := range bc.stakes("") {
+		if st.Status == StakeActive {
+			w[st.Owner] += st.Amount
+		}
+	}
+	return w
+}
+
+// NewStakeTransaction: from'un serbest bakiyesinden amount'u lockBlocks blok
+// kilitli stake'e çevirir (lockBlocks < Stake.MinLockBlocks ise en azı kullanılır)
+func NewStakeTransaction(from string, amount, lockBlocks int, bc *Blockchain) (*Transaction, error) {
+	r := config.Current().Params().Stake
+	if amount < r.MinAmount {
+		return nil, fmt.Errorf("%w: stake below minimum %d", ErrStakeRules, r.MinAmount)
+	}
+	change, err := NewTxOutput(0, from)
+	if err != nil {
+		return nil, fmt.Errorf("sender: %w", err)
+	}
+	if change.LockType != LockP2PKH {
+		return nil, fmt.Errorf("%w: only single-key addresses can stake", ErrStakeRules)
+	}
+	lockBlocks = max(lockBlocks, r.MinLockBlocks)
+	stake := TransactionOutput{
+		Amount:       amount,
+		PubKeyHash:   change.PubKeyHash,
+		LockType:     LockStake,
+		UnlockHeight: bc.GetHeight() + 1 + lockBlocks + stakeMargin,
+	}
+	return fundTransaction(from, change, []TransactionOutput{stake}, bc)
+}
+
+// NewUnstakeTransaction: kilidi dolmuş stake'i bekleme süresine (unbonding) alır
+func NewUnstakeTransaction(owner, txid string, index int, bc *Blockchain) (*Transaction, error) {
+	id, err := hex.DecodeString(txid)
+	if err != nil {
+		return nil, ErrInvalidSpendableTxID
+	}
+	_, pkh, err := wallet.DecodeAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := bc.FindOutput(id, index)
+	if !ok || out.LockType != LockStake || !bytes.Equal(out.PubKeyHash, pkh) {
+		return nil, fmt.Errorf("%w: %s:%d is not a stake of %s", ErrStakeRules, txid, index, owner)
+	}
+	bc.mu.RLock()
+	_, pendingSpent := bc.pendingOutpoints()
+	spent := pendingSpent[outpointKey(id, index)] || bc.isOutputSpent(id, index)
+	height := len(bc.Blocks)
+	bc.mu.RUnlock()
+	if spent {
+		return nil, ErrDoubleSpend
+	}
+	if height < out.UnlockHeight {
+		return nil, fmt.Errorf("%w (until height %d)", ErrStakeLocked, out.UnlockHeight)
+	}
+	return newStakeSpend(owner, []TransactionInput{{TxID: id, OutIndex: index}}, TransactionOutput{
+		Amount:       out.Amount,
+		PubKeyHash:   out.PubKeyHash,
+		LockType:     LockUnbonding,
+		UnlockHeight: height + config.Current().Params().Stake.CooldownBlocks + stakeMargin,
+	}), nil
+}
+
+// NewWithdrawTransaction: bekleme süresi dolmuş tüm unbonding çıktılarını
+// sahibin serbest bakiyesine aktarır
+func NewWithdrawTransaction(owner string, bc *Blockchain) (*Transaction, error) {
+	to, err := NewTxOutput(0, owner)
+	if err != nil {
+		return nil, err
+	}
+	bc.mu.RLock()
+	_, pendingSpent := bc.pendingOutpoints()
+	height := len(bc.Blocks)
+	stakes := bc.stakes(owner)
+	bc.mu.RUnlock()
+	var ins []TransactionInput
+	for _, st := range stakes {
+		if st.Status != StakeUnbonding || height < st.UnlockHeight {
+			continue
+		}
+		id, _ := hex.DecodeString(st.TxID)
+		if pendingSpent[outpointKey(id, st.Index)] {
+			continue
+		}
+		ins = append(ins, TransactionInput{TxID: id, OutIndex: st.Index})
+		to.Amount += st.Amount
+	}
+	if len(ins) == 0 {
+		return nil, ErrNothingToWithdraw
+	}
+	return newStakeSpend(owner, ins, to), nil
+}
+
+// newStakeSpend: ücretsiz tek çıktılı imzasız işlem
+func newStakeSpend(owner string, ins []TransactionInput, out TransactionOutput) *Transaction {
+	tx := &Transaction{
+		Inputs:    ins,
+		Outputs:   []TransactionOutput{out},
+		Timestamp: time.Now(),
+		Sender:    owner,
+		Amount:    float64(out.Amount),
+		Version:   TxVersionSchnorr,
+	}
+	tx.ID = tx.Hash()
+	return tx
+}
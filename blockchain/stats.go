@@ -0,0 +1,193 @@
+package blockchain
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"quantumcoin/utils"
+)
+
+// DayStats: bir UTC gününün zincir istatistikleri (bkz. Index.Daily)
+type DayStats struct {
+	Day           string  `json:"day"` // YYYY-MM-DD
+	Blocks        int     `json:"blocks"`
+	Txs           int     `json:"txs"` // coinbase hariç
+	Fees          int     `json:"fees"`
+	Minted        int     `json:"minted"`
+	Burned        int     `json:"burned"`
+	AvgInterval   float64 `json:"avgInterval"`   // önceki bloğa göre ortalama süre (sn)
+	AvgDifficulty float64 `json:"avgDifficulty"` // ortalama zorluk (bit)
+}
+
+// ChainTotals: indeksin tuttuğu zincir geneli toplamlar
+type ChainTotals struct {
+	Height int `json:"height"`
+	Txs    int `json:"txs"` // coinbase hariç
+	Fees   int `json:"fees"`
+	Minted int `json:"minted"`
+	Burned int `json:"burned"`
+	// FeesDestroyed: coinbase'e girmeyen (v4 öncesi bloklardaki) ücretler
+	FeesDestroyed int     `json:"feesDestroyed"`
+	Addresses     int     `json:"addresses"`
+	AvgInterval   float64 `json:"avgInterval"` // genesis sonrası tüm bloklar (sn)
+}
+
+// RichEntry: zengin listesi satırı
+type RichEntry struct {
+	Rank    int     `json:"rank"`
+	Address string  `json:"address"`
+	Balance int     `json:"balance"` // serbest
+	Staked  int     `json:"staked"`  // stake + unbonding
+	Total   int     `json:"total"`
+	Share   float64 `json:"share"` // dolaşımdaki arzın yüzdesi
+}
+
+// blockStats: bir bloğun istatistiklere katkısı (düşürmede aynen geri alınır)
+type blockStats struct {
+	day            string
+	interval       int64 // önceki bloğa göre sn (genesis'te yok)
+	hasInterval    bool
+	difficulty     int
+	txs, fees      int
+	feesDestroyed  int // v4 öncesi: ücret coinbase'e girmez, arzdan düşer
+	minted, burned int
+}
+
+// dayAcc: günlük toplamlar (ortalamalar okurken hesaplanır)
+type dayAcc struct {
+	blocks, txs, fees, minted, burned int
+	feesDestroyed                     int
+	intervalSum, intervals            int64
+	difficultySum                     int64
+}
+
+func (a *dayAcc) apply(s blockStats, sign int) {
+	a.blocks += sign
+	a.txs += sign * s.txs
+	a.fees += sign * s.fees
+	a.feesDestroyed += sign * s.feesDestroyed
+	a.minted += sign * s.minted
+	a.burned += sign * s.burned
+	a.difficultySum += int64(sign * s.difficulty)
+	if s.hasInterval {
+		a.intervalSum += int64(sign) * s.interval
+		a.intervals += int64(sign)
+	}
+}
+
+// applyStatsLocked: bloğun katkısını günlük ve genel toplamlara ekler (sign=1) ya da çıkarır (sign=-1)
+func (ix *Index) applyStatsLocked(s blockStats, sign int) {
+	d := ix.days[s.day]
+	if d == nil {
+		d = &dayAcc{}
+		ix.days[s.day] = d
+	}
+	d.apply(s, sign)
+	if d.blocks == 0 {
+		delete(ix.days, s.day)
+	}
+	ix.totals.apply(s, sign)
+}
+
+// Daily: son days günün istatistikleri, eskiden yeniye (days <= 0 => tümü)
+func (ix *Index) Daily(days int) []DayStats {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	keys := utils.SortedKeys(ix.days)
+	if days > 0 && len(keys) > days {
+		keys = keys[len(keys)-days:]
+	}
+	out := make([]DayStats, 0, len(keys))
+	for _, k := range keys {
+		d := ix.days[k]
+		s := DayStats{Day: k, Blocks: d.blocks, Txs: d.txs, Fees: d.fees, Minted: d.minted, Burned: d.burned}
+		if d.intervals > 0 {
+			s.AvgInterval = float64(d.intervalSum) / float64(d.intervals)
+		}
+		if d.blocks > 0 {
+			s.AvgDifficulty = float64(d.difficultySum) / float64(d.blocks)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// Totals: zincir geneli toplamlar
+func (ix *Index) Totals() ChainTotals {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	t := ix.totals
+	c := ChainTotals{
+		Height: t.blocks - 1, Txs: t.txs, Fees: t.fees, Minted: t.minted, Burned: t.burned,
+		FeesDestroyed: t.feesDestroyed, Addresses: len(ix.addrs),
+	}
+	if t.intervals > 0 {
+		c.AvgInterval = float64(t.intervalSum) / float64(t.intervals)
+	}
+	return c
+}
+
+// RichList: toplam varlığa (serbest + stake) göre ilk n adres (n <= 0 => 100).
+// Sahipsiz genesis çıktısı listelenmez.
+func (ix *Index) RichList(n int) []RichEntry {
+	if n <= 0 {
+		n = 100
+	}
+	unowned := (&TransactionOutput{PubKeyHash: genesisRecipient}).Address()
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	circulating := ix.totals.minted - ix.totals.burned - ix.totals.feesDestroyed
+	out := make([]RichEntry, 0, len(ix.addrs))
+	for addr, a := range ix.addrs {
+		total := a.received - a.sent
+		if total <= 0 || addr == unowned {
+			continue
+		}
+		out = append(out, RichEntry{Address: addr, Balance: a.balance, Staked: total - a.balance, Total: total})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Address < out[j].Address
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	for i := range out {
+		out[i].Rank = i + 1
+		if circulating > 0 {
+			out[i].Share = float64(out[i].Total) * 100 / float64(circulating)
+		}
+	}
+	return out
+}
+
+// Premine: genesis'teki premine çıktısı (sahipsiz genesis ödülü hariç)
+func (bc *Blockchain) Premine() (amount int, address string) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.premine()
+}
+
+func (bc *Blockchain) premine() (amount int, address string) {
+	if len(bc.Blocks) == 0 {
+		return 0, ""
+	}
+	for _, tx := range bc.Blocks[0].Transactions {
+		for i := range tx.Outputs {
+			if bytes.Equal(tx.Outputs[i].PubKeyHash, genesisRecipient) {
+				continue
+			}
+			amount += tx.Outputs[i].Amount
+			address = tx.Outputs[i].Address()
+		}
+	}
+	return amount, address
+}
+
+// statsDay: blok zaman damgasının UTC günü
+func statsDay(ts int64) string {
+	return time.Unix(ts, 0).UTC().Format("2006-01-02")
+}
@@ -0,0 +1,222 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+)
+
+// Harici blok montajı: getblocktemplate / submitblock.
+//
+// Şablon, düğümün kazacağı bloğun bütün girdilerini verir (coinbase + seçilen
+// mempool işlemleri, başlık alanları, hedef). Madenci yazılımı bloğu kendisi
+// kurar, sha256(HeaderPrefix || nonce(8 bayt big-endian)) < Target olacak nonce'u
+// bulur ve SubmitBlock ile geri yollar. İşlemler hex(gob) olarak taşınır.
+// Başlık düzeni için bkz. header.go.
+
+const NonceFormatBE64 = "be64"
+
+// TemplateTx: şablondaki işlem
+type TemplateTx struct {
+	Data    string `json:"data"` // hex(gob Transaction)
+	TxID    string `json:"txid"`
+	Hash    string `json:"hash"` // WitnessHash (blok köküne giren değer)
+	Fee     int    `json:"fee"`
+	Size    int    `json:"size"`
+	Depends []int  `json:"depends,omitempty"` // bu işlemin harcadığı şablon işlemleri (1 tabanlı)
+}
+
+// BlockTemplate: getblocktemplate yanıtı
+type BlockTemplate struct {
+	Version       int          `json:"version"`
+	Height        int          `json:"height"`
+	PreviousHash  string       `json:"previousHash"`
+	CurTime       int64        `json:"curTime"`
+	MinTime       int64        `json:"minTime"`
+	Bits          int          `json:"bits"`
+	Target        string       `json:"target"` // 64 hex
+	Miner         string       `json:"miner"`
+	CoinbaseValue int          `json:"coinbaseValue"`
+	Fees          int          `json:"fees"` // CoinbaseValue'ya dahil (v4+)
+	Coinbase      TemplateTx   `json:"coinbaseTxn"`
+	Transactions  []TemplateTx `json:"transactions"`
+	MerkleRoot    string       `json:"merkleRoot"`   // HashTransactions(coinbase + transactions)
+	HeaderPrefix  string       `json:"headerPrefix"` // kanonik başlığın nonce öncesi 72 baytı
+	NonceFormat   string       `json:"nonceFormat"`
+}
+
+// GetBlockTemplate: miner adresine coinbase'li, çözülmemiş blok şablonu
+func (bc *Blockchain) GetBlockTemplate(miner string, difficulty int) (*BlockTemplate, error) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	cand, err := bc.newCandidateBlock(miner, difficulty)
+	if err != nil {
+		return nil, err
+	}
+	prev := bc.lastBlock()
+	pow := NewProofOfWork(cand)
+	cb := cand.Transactions[0]
+	t := &BlockTemplate{
+		Version:       cand.Version,
+		Height:        cand.Index,
+		PreviousHash:  hex.EncodeToString(cand.PrevHash),
+		CurTime:       cand.Timestamp,
+		MinTime:       prev.Timestamp,
+		Bits:          cand.Difficulty,
+		Target:        fmt.Sprintf("%064x", pow.target),
+		Miner:         miner,
+		CoinbaseValue: cb.Outputs[0].Amount,
+		Coinbase:      templateTx(cb, 0, nil),
+		Transactions:  []TemplateTx{},
+		MerkleRoot:    hex.EncodeToString(cand.HashTransactions()),
+		HeaderPrefix:  hex.EncodeToString(cand.HeaderPrefix()),
+		NonceFormat:   NonceFormatBE64,
+	}
+	created := map[string]TransactionOutput{}
+	pos := map[string]int{}
+	for i, tx := range cand.Transactions[1:] {
+		fee := bc.txFee(tx, created)
+		var deps []int
+		for _, in := range tx.Inputs {
+			if n, ok := pos[hex.EncodeToString(in.TxID)]; ok {
+				deps = append(deps, n)
+			}
+		}
+		t.Transactions = append(t.Transactions, templateTx(tx, fee, deps))
+		t.Fees += fee
+		pos[hex.EncodeToString(tx.ID)] = i + 1
+		addCreated(created, tx)
+	}
+	return t, nil
+}
+
+func templateTx(tx *Transaction, fee int, deps []int) TemplateTx {
+	raw := tx.Serialize()
+	return TemplateTx{
+		Data:    hex.EncodeToString(raw),
+		TxID:    hex.EncodeToString(tx.ID),
+		Hash:    hex.EncodeToString(tx.WitnessHash()),
+		Fee:     fee,
+		Size:    len(raw),
+		Depends: deps,
+	}
+}
+
+// txFee: input toplamı - output toplamı (girdiler created ya da zincirde aranır)
+func (bc *Blockchain) txFee(tx *Transaction, created map[string]TransactionOutput) int {
+	in := 0
+	for _, vin := range tx.Inputs {
+		if out, ok := created[outpointKey(vin.TxID, vin.OutIndex)]; ok {
+			in += out.Amount
+		} else if out, ok := bc.findOutput(vin.TxID, vin.OutIndex); ok {
+			in += out.Amount
+		}
+	}
+	out := 0
+	for _, o := range tx.Outputs {
+		out += o.Amount
+	}
+	if in < out {
+		return 0
+	}
+	return in - out
+}
+
+// BlockSubmission: submitblock girdisi. Ya Block (hex(gob Block)) ya da
+// şablondan kurulmuş başlık + işlemler (coinbase ilk sırada) verilir.
+type BlockSubmission struct {
+	Block string `json:"block,omitempty"`
+
+	Version      int      `json:"version,omitempty"`
+	Height       int      `json:"height,omitempty"`
+	PreviousHash string   `json:"previousHash,omitempty"`
+	Timestamp    int64    `json:"timestamp,omitempty"`
+	Bits         int      `json:"bits,omitempty"`
+	Nonce        uint64   `json:"nonce,omitempty"`
+	Miner        string   `json:"miner,omitempty"`
+	Transactions []string `json:"transactions,omitempty"` // hex(gob Transaction)
+}
+
+// ToBlock: gönderimi Block'a çevirir; Hash PoW girdisinden hesaplanır.
+func (s *BlockSubmission) ToBlock() (*Block, error) {
+	if s.Block != "" {
+		raw, err := hex.DecodeString(s.Block)
+		if err != nil {
+			return nil, fmt.Errorf("%w: block hex: %v", ErrBadBlock, err)
+		}
+		var blk Block
+		if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&blk); err != nil {
+			return nil, fmt.Errorf("%w: block decode: %v", ErrBadBlock, err)
+		}
+		if blk.Metadata == nil {
+			blk.Metadata = map[string]string{}
+		}
+		blk.Hash = blk.PoWHash()
+		return &blk, nil
+	}
+
+	prev, err := hex.DecodeString(s.PreviousHash)
+	if err != nil || len(prev) == 0 {
+		return nil, fmt.Errorf("%w: previousHash", ErrBadBlock)
+	}
+	if s.Nonce > uint64(maxNonce) {
+		return nil, fmt.Errorf("%w: nonce out of range", ErrBadBlock)
+	}
+	txs := make([]*Transaction, 0, len(s.Transactions))
+	for i, h := range s.Transactions {
+		raw, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, fmt.Errorf("%w: tx %d hex", ErrBadBlock, i)
+		}
+		tx := DeserializeTransaction(raw)
+		if tx == nil {
+			return nil, fmt.Errorf("%w: tx %d decode", ErrBadBlock, i)
+		}
+		txs = append(txs, tx)
+	}
+	blk := &Block{
+		Index:        s.Height,
+		Timestamp:    s.Timestamp,
+		Transactions: txs,
+		PrevHash:     prev,
+		Nonce:        int(s.Nonce),
+		Miner:        s.Miner,
+		Difficulty:   s.Bits,
+		Metadata:     map[string]string{},
+		Version:      s.Version,
+	}
+	blk.Hash = blk.PoWHash()
+	return blk, nil
+}
+
+// SubmitBlock: harici olarak çözülmüş bloğu denetleyip zincire ekler.
+// minBits, düğümün istediği en düşük zorluktur (şablondaki Bits).
+func (bc *Blockchain) SubmitBlock(blk *Block, minBits int) error {
+	if blk == nil || len(blk.Transactions) == 0 {
+		return ErrBadBlock
+	}
+	if !blk.Transactions[0].IsCoinbase() {
+		return fmt.Errorf("%w: first transaction must be coinbase", ErrBadBlock)
+	}
+	for _, tx := range blk.Transactions[1:] {
+		if tx == nil || tx.IsCoinbase() {
+			return fmt.Errorf("%w: extra coinbase", ErrBadBlock)
+		}
+	}
+	if bc.GetBlockByHash(blk.Hash) != nil {
+		return ErrDuplicateBlock
+	}
+	tip := bc.GetLastBlock()
+	if tip == nil {
+		return ErrChainNotInitialized
+	}
+	if !bytes.Equal(blk.PrevHash, tip.Hash) || blk.Index != tip.Index+1 {
+		return ErrStaleBlock
+	}
+	if blk.Difficulty < minBits {
+		return ErrLowDifficulty
+	}
+	// zaman damgası ve diğer bağ kuralları: checkBlockLink
+	return bc.AddBlockFromPeer(blk)
+}
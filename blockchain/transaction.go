@@ -3,19 +3,15 @@ package blockchain
 import (
 	"bytes"
 	"crypto/ecdsa"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
 	"encoding/gob"
 	"encoding/hex"
 	"fmt"
 	"log"
-	"math/big"
 	"time"
 
 	"quantumcoin/wallet"
-
-	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
 
 // --------- TX Yapıları ---------
@@ -23,13 +19,21 @@ import (
 type TransactionInput struct {
 	TxID      []byte // Harcanan çıktının TxID'si
 	OutIndex  int    // Hangi output
-	Signature []byte // encodeSig(r,s)
-	PubKey    []byte // Uncompressed (0x04||X||Y)
+	Signature []byte // v0/v1: DER, low-S (bkz. signature.go); v2: Schnorr (bkz. schnorr.go)
+	PubKey    []byte // v0/v1: uncompressed (0x04||X||Y); v2: compressed (0x02/0x03||X)
+
+	// Multisig harcama (LockMultisig): Signature/PubKey boş kalır
+	Redeem []byte   // wallet.MultisigDescriptor.Bytes()
+	Sigs   [][]byte // descriptor anahtar sırasına hizalı; imzalamayan anahtar için boş
 }
 
 type TransactionOutput struct {
 	Amount     int
-	PubKeyHash []byte // Hash160(pubkey)
+	PubKeyHash []byte // Hash160(pubkey) ya da Hash160(descriptor)
+	LockType   int    // LockP2PKH (0, eski çıktılar) | LockMultisig | LockStake | LockUnbonding | LockBurn
+
+	// UnlockHeight: LockStake/LockUnbonding çıktısı bu yükseklikten önce harcanamaz (bkz. stake.go)
+	UnlockHeight int
 }
 
 type Transaction struct {
@@ -37,23 +41,97 @@ type Transaction struct {
 	Inputs    []TransactionInput
 	Outputs   []TransactionOutput
 	Timestamp time.Time
-	Sender    string  // kolaylık alanı (adres string)
-	Amount    float64 // kolaylık alanı
+	Sender    string  // kolaylık alanı (adres string); v1+'da hiçbir özete girmez
+	Amount    float64 // kolaylık alanı; v1+'da hiçbir özete girmez
+	Version   int     // TxVersionLegacy (0, eski) | TxVersionSegregated | TxVersionSchnorr (bkz. txhash.go)
+
+	// ExtraNonce: yalnız coinbase; başlık nonce'u tükenince madenci artırır (bkz. header.go)
+	ExtraNonce uint64
 }
 
 // --------- Yardımcılar ---------
 
+// Çıktı kilit türleri (sıfır değer = eski tek-anahtar kilidi; gob'da yer tutmaz)
+const (
+	LockP2PKH     = 0
+	LockMultisig  = 1
+	LockStake     = 2 // sahibin anahtarı; yalnız UnlockHeight'tan sonra LockUnbonding'e çözülür
+	LockUnbonding = 3 // sahibin anahtarı; bekleme süresi (UnlockHeight) dolunca serbest
+	LockBurn      = 4 // hiçbir anahtarla açılmaz; UTXO setine girmez (bkz. burn.go)
+)
+
+// BurnOutputLabel: yakım çıktısının adres yerine gösterilen etiketi
+const BurnOutputLabel = "burn"
+
+// NewTxOutput: adresin sürüm baytına göre kilit türünü seçer.
+func NewTxOutput(amount int, address string) (TransactionOutput, error) {
+	version, hash, err := wallet.DecodeAddress(address)
+	if err != nil {
+		return TransactionOutput{}, err
+	}
+	out := TransactionOutput{Amount: amount, PubKeyHash: hash}
+	switch version {
+	case wallet.AddrVersionP2PKH:
+		out.LockType = LockP2PKH
+	case wallet.AddrVersionMultisig:
+		out.LockType = LockMultisig
+	default:
+		return TransactionOutput{}, fmt.Errorf("unsupported address version 0x%02x", version)
+	}
+	return out, nil
+}
+
+// Address: çıktının kilitlendiği adres
+func (out *TransactionOutput) Address() string {
+	switch out.LockType {
+	case LockMultisig:
+		return wallet.AddressFromMultisigHash(out.PubKeyHash)
+	case LockBurn:
+		return BurnOutputLabel
+	}
+	return wallet.AddressFromPubKeyHash(out.PubKeyHash)
+}
+
+// CanBeUnlockedBy: input'un sunduğu anahtar/descriptor bu kilide uyuyor mu?
+// (imzaların geçerliliği Verify'da denetlenir)
+func (out *TransactionOutput) CanBeUnlockedBy(in *TransactionInput) bool {
+	switch out.LockType {
+	case LockP2PKH, LockStake, LockUnbonding:
+		return len(in.Redeem) == 0 && len(in.PubKey) > 0 && pubKeyHashMatches(in.PubKey, out.PubKeyHash)
+	case LockMultisig:
+		return len(in.Redeem) > 0 && bytes.Equal(wallet.HashPubKey(in.Redeem), out.PubKeyHash)
+	}
+	return false
+}
+
+// IsLockedWithKey: çıktı pubKeyHash'in serbest bakiyesine mi ait (stake/unbonding hariç)
 func (out *TransactionOutput) IsLockedWithKey(pubKeyHash []byte) bool {
-	return bytes.Equal(out.PubKeyHash, pubKeyHash)
+	return out.LockType <= LockMultisig && bytes.Equal(out.PubKeyHash, pubKeyHash)
 }
 
 func (in *TransactionInput) UsesKey(pubKeyHash []byte) bool {
+	if len(in.Redeem) > 0 {
+		return bytes.Equal(wallet.HashPubKey(in.Redeem), pubKeyHash)
+	}
 	// PubKey boşsa true (eski davranış) — asıl doğrulama Verify()'da
 	if len(in.PubKey) == 0 {
 		return true
 	}
-	lockingHash := wallet.HashPubKey(in.PubKey)
-	return bytes.Equal(lockingHash, pubKeyHash)
+	return pubKeyHashMatches(in.PubKey, pubKeyHash)
+}
+
+// pubKeyHashMatches: anahtarın compressed ya da uncompressed kodlaması pkh'ye uyuyor mu?
+// (eski adresler uncompressed anahtardan türetilmiştir)
+func pubKeyHashMatches(pub, pkh []byte) bool {
+	if bytes.Equal(wallet.HashPubKey(pub), pkh) {
+		return true
+	}
+	for _, h := range wallet.PubKeyHashes(pub) {
+		if bytes.Equal(h, pkh) {
+			return true
+		}
+	}
+	return false
 }
 
 func (tx *Transaction) IsCoinbase() bool { return len(tx.Inputs) == 0 }
@@ -66,11 +144,20 @@ func (tx *Transaction) Serialize() []byte {
 	return buff.Bytes()
 }
 
+func DeserializeTransaction(data []byte) *Transaction {
+	var tx Transaction
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&tx); err != nil {
+		return nil
+	}
+	return &tx
+}
+
+// Hash: txid. v1+'da imzalardan bağımsızdır (imzalamadan önce ve sonra aynı).
 func (tx *Transaction) Hash() []byte {
-	var h [32]byte
-	copyTx := *tx
-	copyTx.ID = nil
-	h = sha256.Sum256(copyTx.Serialize())
+	if tx.Version < TxVersionSegregated {
+		return tx.legacyHash()
+	}
+	h := sha256.Sum256(tx.unsignedBytes())
 	return h[:]
 }
 
@@ -93,6 +180,7 @@ func (tx *Transaction) TrimmedCopy() Transaction {
 		Timestamp: tx.Timestamp,
 		Sender:    tx.Sender,
 		Amount:    tx.Amount,
+		Version:   tx.Version,
 	}
 }
 
@@ -105,53 +193,8 @@ func pad32(b []byte) []byte {
 	return out
 }
 
-// encode/decode ECDSA imzası (r,s)
-func encodeSig(r, s *big.Int) []byte {
-	rb := r.Bytes()
-	sb := s.Bytes()
-	out := make([]byte, 0, len(rb)+len(sb)+2)
-	out = append(out, byte(len(rb)))
-	out = append(out, rb...)
-	out = append(out, byte(len(sb)))
-	out = append(out, sb...)
-	return out
-}
-func decodeSig(sig []byte) (r, s *big.Int) {
-	if len(sig) < 2 {
-		return new(big.Int), new(big.Int)
-	}
-	rl := int(sig[0])
-	if 1+rl >= len(sig) {
-		return new(big.Int), new(big.Int)
-	}
-	r = new(big.Int).SetBytes(sig[1 : 1+rl])
-	sb := sig[1+rl:]
-	if len(sb) < 1 {
-		return r, new(big.Int)
-	}
-	sl := int(sb[0])
-	if 1+sl > len(sb) {
-		return r, new(big.Int)
-	}
-	s = new(big.Int).SetBytes(sb[1 : 1+sl])
-	return r, s
-}
-
-// Uncompressed (0x04||X||Y) -> *ecdsa.PublicKey (secp256k1)
-func pubKeyToECDSA(pub []byte) *ecdsa.PublicKey {
-	if len(pub) != 65 || pub[0] != 0x04 {
-		return nil
-	}
-	curve := secp256k1.S256()
-	x := new(big.Int).SetBytes(pub[1:33])
-	y := new(big.Int).SetBytes(pub[33:65])
-	if !curve.IsOnCurve(x, y) {
-		return nil
-	}
-	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
-}
-
-// İmza mesajı (deterministik): TrimmedCopy.Serialize + input.TxID + input.OutIndex
+// v0 imza mesajı (deterministik): TrimmedCopy.Serialize + input.TxID + input.OutIndex
+// (v1+ için sigHash kullanılır)
 func signMessageBytes(tx *Transaction, inputIdx int) []byte {
 	txCopy := tx.TrimmedCopy()
 	var buf bytes.Buffer
@@ -171,12 +214,46 @@ func signMessageBytes(tx *Transaction, inputIdx int) []byte {
 // --------- İşlem oluşturma / imzalama / doğrulama ---------
 
 func NewTransaction(from string, to string, amount int, bc *Blockchain) (*Transaction, error) {
-	if amount <= 0 {
-		return nil, fmt.Errorf("invalid amount")
+	return NewPaymentTransaction(from, []Payment{{Address: to, Amount: amount}}, bc)
+}
+
+// Payment: çok alıcılı işlemde tek bir çıktı
+type Payment struct {
+	Address string
+	Amount  int
+}
+
+// NewPaymentTransaction: from'dan birden çok alıcıya tek işlem (havuz ödemeleri vb.)
+func NewPaymentTransaction(from string, pays []Payment, bc *Blockchain) (*Transaction, error) {
+	if len(pays) == 0 {
+		return nil, fmt.Errorf("no recipients")
+	}
+	change, err := NewTxOutput(0, from)
+	if err != nil {
+		return nil, fmt.Errorf("sender: %w", err)
+	}
+	outputs := make([]TransactionOutput, 0, len(pays)+1)
+	for _, p := range pays {
+		if p.Amount <= 0 {
+			return nil, fmt.Errorf("invalid amount")
+		}
+		out, err := NewTxOutput(p.Amount, p.Address)
+		if err != nil {
+			return nil, fmt.Errorf("recipient: %w", err)
+		}
+		outputs = append(outputs, out)
 	}
-	pubFrom := wallet.Base58DecodeAddress(from)
+	return fundTransaction(from, change, outputs, bc)
+}
 
-	utxos, acc := bc.FindSpendableOutputs(pubFrom, amount)
+// fundTransaction: outputs'u from'un serbest bakiyesinden karşılar; artan
+// change kilidiyle para üstü olarak eklenir
+func fundTransaction(from string, change TransactionOutput, outputs []TransactionOutput, bc *Blockchain) (*Transaction, error) {
+	amount := 0
+	for _, out := range outputs {
+		amount += out.Amount
+	}
+	utxos, acc := bc.FindSpendableOutputs(change.PubKeyHash, amount)
 	if acc < amount {
 		return nil, fmt.Errorf("yetersiz bakiye")
 	}
@@ -195,18 +272,10 @@ func NewTransaction(from string, to string, amount int, bc *Blockchain) (*Transa
 		}
 	}
 
-	var outputs []TransactionOutput
-	// Alıcı
-	outputs = append(outputs, TransactionOutput{
-		Amount:     amount,
-		PubKeyHash: wallet.Base58DecodeAddress(to),
-	})
-	// Para üstü
+	// Alıcılar + para üstü (göndericinin kilit türüyle)
 	if acc > amount {
-		outputs = append(outputs, TransactionOutput{
-			Amount:     acc - amount,
-			PubKeyHash: pubFrom,
-		})
+		change.Amount = acc - amount
+		outputs = append(outputs, change)
 	}
 
 	tx := &Transaction{
@@ -216,44 +285,88 @@ func NewTransaction(from string, to string, amount int, bc *Blockchain) (*Transa
 		Timestamp: time.Now(),
 		Sender:    from,
 		Amount:    float64(amount),
+		Version:   TxVersionSchnorr,
 	}
 	tx.ID = tx.Hash()
 	return tx, nil
 }
 
-// Tüm input'ları imzala (secp256k1)
-func (tx *Transaction) Sign(priv *ecdsa.PrivateKey) error {
+// SetVersion: imzasız tx'in sürümünü değiştirir ve ID'yi yeniden hesaplar
+// (ör. yalnız ECDSA imzalayabilen harici cüzdanlar için v1).
+func (tx *Transaction) SetVersion(v int) error {
+	if v < TxVersionSegregated || v > TxVersionMax {
+		return fmt.Errorf("unsupported tx version %d", v)
+	}
+	for _, in := range tx.Inputs {
+		if len(in.Signature) > 0 || len(in.Sigs) > 0 {
+			return fmt.Errorf("tx already signed")
+		}
+	}
+	tx.Version = v
+	tx.ID = tx.Hash()
+	return nil
+}
+
+// Tüm input'ları imzala (secp256k1, SIGHASH_ALL). prevs: input'ların harcadığı
+// çıktılar, input sırasıyla (bkz. Blockchain.SpentOutputs; v0'da kullanılmaz)
+func (tx *Transaction) Sign(priv *ecdsa.PrivateKey, prevs []TransactionOutput) error {
+	return tx.SignWithType(priv, prevs, SigHashAll)
+}
+
+// SignWithType: tüm input'ları verilen sighash tipiyle imzalar (v1+).
+func (tx *Transaction) SignWithType(priv *ecdsa.PrivateKey, prevs []TransactionOutput, flag SigHashType) error {
 	if tx == nil {
 		return fmt.Errorf("nil tx")
 	}
 	if tx.IsCoinbase() {
 		return nil
 	}
+	if tx.Version >= TxVersionSegregated && len(prevs) != len(tx.Inputs) {
+		return fmt.Errorf("sign: %d spent outputs for %d inputs", len(prevs), len(tx.Inputs))
+	}
 
-	fromPKH := wallet.Base58DecodeAddress(tx.Sender)
-	if len(fromPKH) == 0 {
+	version, fromPKH, err := wallet.DecodeAddress(tx.Sender)
+	if err != nil || len(fromPKH) == 0 {
 		return fmt.Errorf("invalid sender address")
 	}
+	if version == wallet.AddrVersionMultisig {
+		return fmt.Errorf("multisig sender: sign with PSBT (psbt-sign) by each co-signer")
+	}
 
-	// Uncompressed pubkey (65B)
+	// Uncompressed pubkey (65B); v2'de compressed (33B)
 	pub := append([]byte{0x04}, pad32(priv.PublicKey.X.Bytes())...)
 	pub = append(pub, pad32(priv.PublicKey.Y.Bytes())...)
+	if tx.Version >= TxVersionSchnorr {
+		pub = wallet.CompressPubKey(pub)
+	}
 
 	for i := range tx.Inputs {
-		msg := signMessageBytes(tx, i)
-
-		r, s, err := ecdsa.Sign(rand.Reader, priv, msg)
+		sig, err := signInput(tx, i, priv, flag, prevOut(prevs, i))
 		if err != nil {
-			return fmt.Errorf("ecdsa sign failed: %w", err)
+			return fmt.Errorf("sign failed: %w", err)
 		}
-		tx.Inputs[i].Signature = encodeSig(r, s)
+		tx.Inputs[i].Signature = sig
 		tx.Inputs[i].PubKey = pub
 	}
 	return nil
 }
 
-// İmzaları doğrula (coinbase hariç)
-func (tx *Transaction) Verify() bool {
+// prevOut: i. input'un harcadığı çıktı (bilinmiyorsa nil)
+func prevOut(prevs []TransactionOutput, i int) *TransactionOutput {
+	if i < 0 || i >= len(prevs) {
+		return nil
+	}
+	return &prevs[i]
+}
+
+// İmzaları doğrula (coinbase hariç). prevs: harcanan çıktılar, input sırasıyla
+// (v1+ imzaları tutar ve kilidi kapsar; bkz. sigHash). Zincir bağlamında
+// Blockchain.VerifyTransaction çıktıları kendisi bulur.
+func (tx *Transaction) Verify(prevs []TransactionOutput) bool { return tx.verify(prevs, nil) }
+
+// verify: batch nil değilse v2 Schnorr imzaları toplu doğrulamaya ertelenir;
+// bu durumda true dönüşü batch.verify() başarılı olursa geçerlidir.
+func (tx *Transaction) verify(prevs []TransactionOutput, batch *schnorrBatch) bool {
 	if tx == nil {
 		return false
 	}
@@ -264,29 +377,43 @@ func (tx *Transaction) Verify() bool {
 		return false
 	}
 
-	fromPKH := wallet.Base58DecodeAddress(tx.Sender)
-	if len(fromPKH) == 0 {
+	if tx.Version > TxVersionMax {
 		return false
 	}
+	// v1+: txid imzasız alanlardan türetilir; taşınan ID birebir tutmalı.
+	// Sender yalnız kolaylık alanıdır — kilit eşleşmesi zincir bağlamında
+	// (checkTxInputs / CanBeUnlockedBy) denetlenir.
+	segregated := tx.Version >= TxVersionSegregated
+	if segregated && (!bytes.Equal(tx.ID, tx.Hash()) || len(prevs) != len(tx.Inputs)) {
+		return false
+	}
+	var fromPKH []byte
+	if !segregated {
+		_, pkh, err := wallet.DecodeAddress(tx.Sender)
+		if err != nil || len(pkh) == 0 {
+			return false
+		}
+		fromPKH = pkh
+	}
 
 	for i := range tx.Inputs {
 		in := tx.Inputs[i]
-		if len(in.Signature) == 0 || len(in.PubKey) != 65 || in.PubKey[0] != 0x04 {
-			return false
+		if len(in.Redeem) > 0 {
+			if (!segregated && !in.UsesKey(fromPKH)) || !verifyMultisigInput(tx, i, prevOut(prevs, i), batch) {
+				return false
+			}
+			continue
 		}
-		if !in.UsesKey(fromPKH) {
+		if len(in.Sigs) > 0 {
 			return false
 		}
-		pub := pubKeyToECDSA(in.PubKey)
-		if pub == nil {
+		if len(in.Signature) == 0 || !validPubKeyForm(tx.Version, in.PubKey) {
 			return false
 		}
-		r, s := decodeSig(in.Signature)
-		if r == nil || s == nil {
+		if !segregated && !in.UsesKey(fromPKH) {
 			return false
 		}
-		msg := signMessageBytes(tx, i)
-		if !ecdsa.Verify(pub, msg, r, s) {
+		if !verifyInputSig(tx, i, in.PubKey, in.Signature, prevOut(prevs, i), batch) {
 			return false
 		}
 	}
@@ -294,11 +421,20 @@ func (tx *Transaction) Verify() bool {
 }
 
 // ---- Web cüzdan için: her input’un imzalanacak mesajının HEX’i ----
-func SigningHashes(tx *Transaction) []string {
+// v1+: SIGHASH_ALL özeti (prevs: harcanan çıktılar); istemci imzanın sonuna
+// 0x01 baytını eklemeli (v1: DER ECDSA + uncompressed pubkey, v2: 64B Schnorr +
+// compressed pubkey).
+func SigningHashes(tx *Transaction, prevs []TransactionOutput) ([]string, error) {
 	out := make([]string, 0, len(tx.Inputs))
 	for i := range tx.Inputs {
 		h := signMessageBytes(tx, i)
+		if tx.Version >= TxVersionSegregated {
+			var err error
+			if h, err = sigHash(tx, i, SigHashAll, prevOut(prevs, i)); err != nil {
+				return nil, err
+			}
+		}
 		out = append(out, hex.EncodeToString(h))
 	}
-	return out
+	return out, nil
 }
@@ -0,0 +1,297 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// İşlem sürümleri
+//
+//	TxVersionLegacy (0):     txid = sha256(gob(tx)) — imzalar ve Sender/Amount dahil (eski işlemler)
+//	TxVersionSegregated (1): txid = sha256(imzasız alanların sabit ikili kodlaması);
+//	                         imzalar yalnız WitnessHash'e girer, Sender/Amount hiçbir özete girmez
+//	TxVersionSchnorr (2):    v1 kuralları + BIP340 Schnorr imzaları (64 bayt || sighash tipi)
+//	                         ve 33 baytlık compressed anahtarlar (bkz. schnorr.go)
+const (
+	TxVersionLegacy     = 0
+	TxVersionSegregated = 1
+	TxVersionSchnorr    = 2
+	TxVersionMax        = TxVersionSchnorr
+)
+
+// SigHashType: imzanın tx'in hangi kısımlarını kapsadığı (v1+: imzanın son baytı)
+type SigHashType byte
+
+const (
+	SigHashAll          SigHashType = 0x01 // tüm input'lar + tüm output'lar
+	SigHashNone         SigHashType = 0x02 // tüm input'lar, output yok
+	SigHashSingle       SigHashType = 0x03 // tüm input'lar + aynı indeksli output
+	SigHashAnyoneCanPay SigHashType = 0x80 // (bayrak) yalnız imzalanan input
+)
+
+func (t SigHashType) valid() bool {
+	base := t &^ SigHashAnyoneCanPay
+	return base >= SigHashAll && base <= SigHashSingle
+}
+
+// hashWriter: sabit, dil-bağımsız kodlama (big-endian, uzunluk önekli baytlar)
+type hashWriter struct{ bytes.Buffer }
+
+func (w *hashWriter) u32(v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	w.Write(b[:])
+}
+
+func (w *hashWriter) u64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	w.Write(b[:])
+}
+
+func (w *hashWriter) bytes(b []byte) {
+	w.u32(uint32(len(b)))
+	w.Write(b)
+}
+
+func (w *hashWriter) outpoint(in *TransactionInput) {
+	w.bytes(in.TxID)
+	w.u32(uint32(in.OutIndex))
+}
+
+func (w *hashWriter) output(out *TransactionOutput) {
+	w.u64(uint64(out.Amount))
+	w.u32(uint32(out.LockType))
+	w.bytes(out.PubKeyHash)
+	if out.LockType == LockStake || out.LockType == LockUnbonding {
+		w.u64(uint64(out.UnlockHeight))
+	}
+}
+
+// unsignedBytes: txid'nin kapsadığı alanlar (imza, pubkey, redeem, Sender, Amount hariç)
+func (tx *Transaction) unsignedBytes() []byte {
+	var w hashWriter
+	w.u32(uint32(tx.Version))
+	w.u32(uint32(len(tx.Inputs)))
+	for i := range tx.Inputs {
+		w.outpoint(&tx.Inputs[i])
+	}
+	w.u32(uint32(len(tx.Outputs)))
+	for i := range tx.Outputs {
+		w.output(&tx.Outputs[i])
+	}
+	w.u64(uint64(tx.Timestamp.UnixNano()))
+	if tx.ExtraNonce != 0 { // sıfırken eski txid'ler değişmez
+		w.u64(tx.ExtraNonce)
+	}
+	return w.Bytes()
+}
+
+// witnessBytes: input başına kilit açma verisi
+func (tx *Transaction) witnessBytes() []byte {
+	var w hashWriter
+	for _, in := range tx.Inputs {
+		w.bytes(in.Signature)
+		w.bytes(in.PubKey)
+		w.bytes(in.Redeem)
+		w.u32(uint32(len(in.Sigs)))
+		for _, s := range in.Sigs {
+			w.bytes(s)
+		}
+	}
+	return w.Bytes()
+}
+
+// legacyHash: v0 txid (gob, ID hariç)
+func (tx *Transaction) legacyHash() []byte {
+	copyTx := *tx
+	copyTx.ID = nil
+	h := sha256.Sum256(copyTx.Serialize())
+	return h[:]
+}
+
+// WitnessHash: imzalar dahil tüm işlemin özeti (blok tx kökü bunu kullanır).
+// Eski işlemlerde txid zaten imzaları kapsadığı için Hash() ile aynıdır.
+func (tx *Transaction) WitnessHash() []byte {
+	if tx.Version < TxVersionSegregated {
+		return tx.Hash()
+	}
+	h := sha256.New()
+	h.Write(tx.Hash())
+	h.Write(tx.witnessBytes())
+	return h.Sum(nil)
+}
+
+// sigHash: v1+ input imzası için özet.
+// Kapsam SigHashType'a göre daralır; imzalanan input'un kendisi ve harcadığı
+// çıktı (tutar, kilit; BIP143 gibi) her zaman kapsanır. Böylece zincire erişmeyen
+// imzalayan, kendisine bildirilen input tutarını da imzalamış olur: tutar yanlış
+// bildirilmişse (gizli ücret) imza zincirdeki çıktıyla doğrulanmaz.
+func sigHash(tx *Transaction, idx int, flag SigHashType, prev *TransactionOutput) ([]byte, error) {
+	if idx < 0 || idx >= len(tx.Inputs) {
+		return nil, fmt.Errorf("sighash: input %d out of range", idx)
+	}
+	if !flag.valid() {
+		return nil, fmt.Errorf("sighash: unknown type 0x%02x", byte(flag))
+	}
+	if prev == nil {
+		return nil, fmt.Errorf("sighash: spent output of input %d unknown", idx)
+	}
+	base := flag &^ SigHashAnyoneCanPay
+
+	var w hashWriter
+	w.u32(uint32(tx.Version))
+	w.u32(uint32(flag))
+
+	if flag&SigHashAnyoneCanPay != 0 {
+		w.u32(1)
+		w.outpoint(&tx.Inputs[idx])
+	} else {
+		w.u32(uint32(len(tx.Inputs)))
+		for i := range tx.Inputs {
+			w.outpoint(&tx.Inputs[i])
+		}
+	}
+
+	switch base {
+	case SigHashAll:
+		w.u32(uint32(len(tx.Outputs)))
+		for i := range tx.Outputs {
+			w.output(&tx.Outputs[i])
+		}
+	case SigHashNone:
+		w.u32(0)
+	case SigHashSingle:
+		if idx >= len(tx.Outputs) {
+			return nil, fmt.Errorf("sighash: SINGLE without matching output %d", idx)
+		}
+		w.u32(1)
+		w.output(&tx.Outputs[idx])
+	}
+
+	w.u64(uint64(tx.Timestamp.UnixNano()))
+	w.u32(uint32(idx))
+	w.outpoint(&tx.Inputs[idx])
+	w.output(prev)
+
+	sum := sha256.Sum256(w.Bytes())
+	return sum[:], nil
+}
+
+// signInput: input için imza baytları (v1: DER || sighash tipi, v2: Schnorr || sighash tipi).
+// prev: input'un harcadığı çıktı (v0'da kullanılmaz)
+func signInput(tx *Transaction, idx int, priv *ecdsa.PrivateKey, flag SigHashType, prev *TransactionOutput) ([]byte, error) {
+	if tx.Version < TxVersionSegregated {
+		if flag != SigHashAll {
+			return nil, fmt.Errorf("legacy tx supports only SIGHASH_ALL")
+		}
+		return signDigest(priv, signMessageBytes(tx, idx))
+	}
+	digest, err := sigHash(tx, idx, flag, prev)
+	if err != nil {
+		return nil, err
+	}
+	var sig []byte
+	if tx.Version >= TxVersionSchnorr {
+		sig, err = schnorrSign(priv, digest)
+	} else {
+		sig, err = signDigest(priv, digest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(sig, byte(flag)), nil
+}
+
+// inputDigest: doğrulama için (özet, ham imza) — v1+'da sighash tipi imzadan okunur
+func inputDigest(tx *Transaction, idx int, sig []byte, prev *TransactionOutput) (digest, raw []byte, err error) {
+	if tx.Version < TxVersionSegregated {
+		return signMessageBytes(tx, idx), sig, nil
+	}
+	if len(sig) < 2 {
+		return nil, nil, ErrSigEncoding
+	}
+	flag := SigHashType(sig[len(sig)-1])
+	digest, err = sigHash(tx, idx, flag, prev)
+	if err != nil {
+		return nil, nil, err
+	}
+	return digest, sig[:len(sig)-1], nil
+}
+
+// verifyInputSig: pubkey + imza, input idx için geçerli mi?
+// batch verilirse v2 Schnorr imzaları yalnız kodlama açısından denetlenip
+// toplu doğrulamaya eklenir; nihai sonuç batch.verify() ile alınır.
+func verifyInputSig(tx *Transaction, idx int, pub, sig []byte, prev *TransactionOutput, batch *schnorrBatch) bool {
+	digest, raw, err := inputDigest(tx, idx, sig, prev)
+	if err != nil {
+		return false
+	}
+	if tx.Version >= TxVersionSchnorr {
+		if batch != nil {
+			return batch.add(pub, digest, raw)
+		}
+		return schnorrVerify(pub, digest, raw)
+	}
+	return verifySig(pub, digest, raw)
+}
+
+// validPubKeyForm: v2'de yalnız 33B compressed, öncesinde yalnız 65B uncompressed anahtar
+func validPubKeyForm(version int, pub []byte) bool {
+	if version >= TxVersionSchnorr {
+		return len(pub) == 33 && (pub[0] == 0x02 || pub[0] == 0x03)
+	}
+	return len(pub) == 65 && pub[0] == 0x04
+}
+
+// checkSigEncoding: ham imzanın sürüme göre kodlama kuralları
+func checkSigEncoding(version int, raw []byte) error {
+	if version >= TxVersionSchnorr {
+		if len(raw) != SchnorrSigSize {
+			return ErrSchnorrSig
+		}
+		var r secp256k1.FieldVal
+		var s secp256k1.ModNScalar
+		if r.SetByteSlice(raw[:32]) || s.SetByteSlice(raw[32:]) {
+			return ErrSchnorrSig
+		}
+		return nil
+	}
+	_, err := parseSig(raw)
+	return err
+}
+
+// CheckSignatureEncodings: tüm input imzalarının kodlamasını (DER/low-S ya da Schnorr, sighash tipi) denetler.
+// API/mempool girişinde anlaşılır hata vermek için; Verify zaten aynı kuralları uygular.
+func (tx *Transaction) CheckSignatureEncodings() error {
+	check := func(i int, sig []byte) error {
+		if len(sig) == 0 {
+			return nil
+		}
+		// özet kullanılmaz (harcanan çıktı gerekmez); yalnız sighash tipi denetlenir
+		_, raw, err := inputDigest(tx, i, sig, &TransactionOutput{})
+		if err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+		if err := checkSigEncoding(tx.Version, raw); err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+		return nil
+	}
+	for i, in := range tx.Inputs {
+		if err := check(i, in.Signature); err != nil {
+			return err
+		}
+		for _, s := range in.Sigs {
+			if err := check(i, s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
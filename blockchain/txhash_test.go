@@ -0,0 +1,226 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"quantumcoin/wallet"
+)
+
+// testSpend: key'e ait iki çıktıyı harcayan imzasız tx ve harcanan çıktılar
+func testSpend(t *testing.T, priv *ecdsa.PrivateKey, version int) (*Transaction, []TransactionOutput) {
+	t.Helper()
+	w := wallet.WalletFromKey(priv)
+	from := w.GetAddress()
+	to := wallet.NewWallet().GetAddress()
+
+	prev0, err := NewTxOutput(30, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev1, err := NewTxOutput(20, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pay, err := NewTxOutput(40, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change, err := NewTxOutput(9, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &Transaction{
+		Inputs: []TransactionInput{
+			{TxID: bytes.Repeat([]byte{0x11}, 32), OutIndex: 0},
+			{TxID: bytes.Repeat([]byte{0x22}, 32), OutIndex: 1},
+		},
+		Outputs:   []TransactionOutput{pay, change},
+		Timestamp: time.Unix(1700000000, 0),
+		Sender:    from,
+		Amount:    40,
+		Version:   version,
+	}
+	tx.ID = tx.Hash()
+	return tx, []TransactionOutput{prev0, prev1}
+}
+
+func TestTxIDIndependentOfSignatures(t *testing.T) {
+	priv, _ := testKey(t, "0000000000000000000000000000000000000000000000000000000000000007")
+	for _, version := range []int{TxVersionSegregated, TxVersionSchnorr} {
+		tx, prevs := testSpend(t, priv, version)
+		id := append([]byte{}, tx.ID...)
+		unsignedWitness := tx.WitnessHash()
+
+		if err := tx.Sign(priv, prevs); err != nil {
+			t.Fatalf("v%d: %v", version, err)
+		}
+		if !bytes.Equal(tx.Hash(), id) {
+			t.Fatalf("v%d: txid changed by signing", version)
+		}
+		if !tx.Verify(prevs) {
+			t.Fatalf("v%d: signed tx does not verify", version)
+		}
+		signedWitness := tx.WitnessHash()
+		if bytes.Equal(signedWitness, unsignedWitness) {
+			t.Fatalf("v%d: witness hash ignores signatures", version)
+		}
+
+		// imza baytı değişince yalnız WitnessHash değişir
+		tx.Inputs[1].Signature = append([]byte{}, tx.Inputs[1].Signature...)
+		tx.Inputs[1].Signature[0] ^= 0x01
+		if !bytes.Equal(tx.Hash(), id) {
+			t.Fatalf("v%d: txid changed with witness", version)
+		}
+		if bytes.Equal(tx.WitnessHash(), signedWitness) {
+			t.Fatalf("v%d: witness hash unchanged after witness change", version)
+		}
+		if tx.Verify(prevs) {
+			t.Fatalf("v%d: tampered signature verified", version)
+		}
+
+		// kolaylık alanları hiçbir özete girmez
+		tx.Sender, tx.Amount = "", 0
+		if !bytes.Equal(tx.Hash(), id) {
+			t.Fatalf("v%d: txid commits to Sender/Amount", version)
+		}
+	}
+}
+
+func TestLegacyTxIDCommitsToSignatures(t *testing.T) {
+	priv, _ := testKey(t, "0000000000000000000000000000000000000000000000000000000000000007")
+	tx, prevs := testSpend(t, priv, TxVersionLegacy)
+	id := append([]byte{}, tx.ID...)
+	if err := tx.Sign(priv, prevs); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(tx.Hash(), id) {
+		t.Fatal("legacy txid should cover signatures")
+	}
+	if !bytes.Equal(tx.WitnessHash(), tx.Hash()) {
+		t.Fatal("legacy witness hash should equal txid")
+	}
+}
+
+func TestSigHashCommitments(t *testing.T) {
+	priv, _ := testKey(t, "0000000000000000000000000000000000000000000000000000000000000007")
+
+	// her değişiklik input 0'ın özetini etkiler mi? (All, None, Single) x (ACP yok, ACP)
+	mutations := []struct {
+		name   string
+		mutate func(tx *Transaction, prev *TransactionOutput)
+		want   map[SigHashType]bool
+	}{
+		{"signed outpoint", func(tx *Transaction, _ *TransactionOutput) { tx.Inputs[0].OutIndex++ },
+			commitsAll(true)},
+		{"other outpoint", func(tx *Transaction, _ *TransactionOutput) { tx.Inputs[1].OutIndex++ },
+			commitsUnlessACP()},
+		{"matching output", func(tx *Transaction, _ *TransactionOutput) { tx.Outputs[0].Amount++ },
+			commitsBase(SigHashAll, SigHashSingle)},
+		{"other output", func(tx *Transaction, _ *TransactionOutput) { tx.Outputs[1].Amount++ },
+			commitsBase(SigHashAll)},
+		{"timestamp", func(tx *Transaction, _ *TransactionOutput) { tx.Timestamp = tx.Timestamp.Add(time.Second) },
+			commitsAll(true)},
+		{"prev amount", func(_ *Transaction, prev *TransactionOutput) { prev.Amount++ },
+			commitsAll(true)},
+		{"prev lock", func(_ *Transaction, prev *TransactionOutput) {
+			prev.LockType = LockStake
+			prev.UnlockHeight = 10
+		}, commitsAll(true)},
+		{"prev owner", func(_ *Transaction, prev *TransactionOutput) {
+			prev.PubKeyHash = bytes.Repeat([]byte{0x33}, len(prev.PubKeyHash))
+		}, commitsAll(true)},
+		{"sender and amount", func(tx *Transaction, _ *TransactionOutput) { tx.Sender, tx.Amount = "", 0 },
+			commitsAll(false)},
+	}
+
+	for _, m := range mutations {
+		for flag, want := range m.want {
+			tx, prevs := testSpend(t, priv, TxVersionSegregated)
+			before, err := sigHash(tx, 0, flag, &prevs[0])
+			if err != nil {
+				t.Fatalf("%s/0x%02x: %v", m.name, byte(flag), err)
+			}
+			m.mutate(tx, &prevs[0])
+			after, err := sigHash(tx, 0, flag, &prevs[0])
+			if err != nil {
+				t.Fatalf("%s/0x%02x: %v", m.name, byte(flag), err)
+			}
+			if got := !bytes.Equal(before, after); got != want {
+				t.Errorf("%s/0x%02x: digest changed = %v, want %v", m.name, byte(flag), got, want)
+			}
+		}
+	}
+}
+
+func TestSigHashRejects(t *testing.T) {
+	priv, _ := testKey(t, "0000000000000000000000000000000000000000000000000000000000000007")
+	tx, prevs := testSpend(t, priv, TxVersionSegregated)
+
+	if _, err := sigHash(tx, 0, 0x00, &prevs[0]); err == nil {
+		t.Error("unknown sighash type accepted")
+	}
+	if _, err := sigHash(tx, 0, 0x04|SigHashAnyoneCanPay, &prevs[0]); err == nil {
+		t.Error("unknown sighash type with ANYONECANPAY accepted")
+	}
+	if _, err := sigHash(tx, 0, SigHashAll, nil); err == nil {
+		t.Error("missing spent output accepted")
+	}
+	if _, err := sigHash(tx, 2, SigHashAll, &prevs[0]); err == nil {
+		t.Error("out-of-range input accepted")
+	}
+	tx.Outputs = tx.Outputs[:1]
+	if _, err := sigHash(tx, 1, SigHashSingle, &prevs[1]); err == nil {
+		t.Error("SINGLE without matching output accepted")
+	}
+}
+
+func TestSignatureCommitsToSpentOutput(t *testing.T) {
+	priv, _ := testKey(t, "0000000000000000000000000000000000000000000000000000000000000007")
+	for _, version := range []int{TxVersionSegregated, TxVersionSchnorr} {
+		tx, prevs := testSpend(t, priv, version)
+		if err := tx.Sign(priv, prevs); err != nil {
+			t.Fatal(err)
+		}
+		// imzalayana bildirilen tutar zincirdekinden farklıysa imza tutmamalı
+		lied := append([]TransactionOutput{}, prevs...)
+		lied[0].Amount += 5
+		if tx.Verify(lied) {
+			t.Fatalf("v%d: signature verified against a different spent amount", version)
+		}
+		if !tx.Verify(prevs) {
+			t.Fatalf("v%d: signature does not verify", version)
+		}
+	}
+}
+
+var sigHashBases = []SigHashType{SigHashAll, SigHashNone, SigHashSingle}
+
+// commitsAll: tüm sighash tipleri için aynı beklenti
+func commitsAll(v bool) map[SigHashType]bool {
+	m := map[SigHashType]bool{}
+	for _, b := range sigHashBases {
+		m[b], m[b|SigHashAnyoneCanPay] = v, v
+	}
+	return m
+}
+
+// commitsUnlessACP: ANYONECANPAY yoksa kapsanır
+func commitsUnlessACP() map[SigHashType]bool {
+	m := map[SigHashType]bool{}
+	for _, b := range sigHashBases {
+		m[b], m[b|SigHashAnyoneCanPay] = true, false
+	}
+	return m
+}
+
+// commitsBase: yalnız verilen temel tipler (ACP'den bağımsız) kapsar
+func commitsBase(bases ...SigHashType) map[SigHashType]bool {
+	m := commitsAll(false)
+	for _, b := range bases {
+		m[b], m[b|SigHashAnyoneCanPay] = true, true
+	}
+	return m
+}
@@ -2,8 +2,13 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"time"
+
 	"quantumcoin/api"
+	"quantumcoin/api/client"
 	"quantumcoin/blockchain"
 	"quantumcoin/config"
 )
@@ -17,7 +22,38 @@ func main() {
 
 	api.Init(bc, nil, cfg)
 
-	if err := api.StartHTTP(""); err != nil {
-		log.Fatal(err)
+	// belge ile rotalar uyuşuyor mu
+	problems := api.CheckOpenAPI(api.NewMux(), api.ServerOpenAPIOperations())
+	for _, p := range problems {
+		log.Printf("openapi: %s", p)
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- api.StartHTTP("") }()
+
+	// sunucuya tipli istemciyle bağlan
+	c := client.New(cfg.HTTPPort)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	h, err := c.WaitReady(ctx, 200*time.Millisecond)
+	if err == nil {
+		var spec map[string]any
+		if spec, err = c.OpenAPI(ctx); err == nil {
+			paths, _ := spec["paths"].(map[string]any)
+			log.Printf("apitest: %s ok height=%d, openapi %d path", c.BaseURL, h.Height, len(paths))
+		}
 	}
+	cancel()
+	if err != nil {
+		select {
+		case serr := <-errc:
+			log.Fatal(serr)
+		default:
+			log.Fatal(err)
+		}
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+
+	log.Fatal(<-errc)
 }
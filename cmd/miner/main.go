@@ -9,26 +9,35 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
+	"quantumcoin/api/client"
 	"quantumcoin/miner"
 )
 
 var (
 	flagAddr    = flag.String("address", "", "Coinbase ödül adresi (zorunlu)")
 	flagThreads = flag.Int("threads", runtime.NumCPU(), "CPU iş parçacığı sayısı")
-	flagMode    = flag.String("mode", "local", "Çalışma modu: mock | local")
+	flagMode    = flag.String("mode", "local", "Çalışma modu: mock | local | stratum | http")
 	flagLog     = flag.String("log", "", "Log dosyası (örn: miner.log)")
 	flagConfig  = flag.String("config", "config.json", "Config dosyası yolu")
 	flagChain   = flag.String("chain", "chain_data.dat", "Chain dosyası yolu")
 	flagP2P     = flag.String("p2p", ":3001", "P2P dinleme portu (adapter broadcast için)")
+	flagPool    = flag.String("pool", "127.0.0.1:3333", "Stratum sunucusu (mode=stratum)")
+	flagWorker  = flag.String("worker", "", "İşçi adı (mode=stratum; adres.işçi olarak gönderilir)")
+	flagPass    = flag.String("password", "x", "Stratum parolası (mode=stratum)")
+	flagNode    = flag.String("node", "http://127.0.0.1:8081", "Düğüm HTTP API adresi (mode=http)")
+	flagAPIKey  = flag.String("apikey", "", "API anahtarı; mining kapsamı gerekir (mode=http, ya da QC_API_KEY)")
+	flagCookie  = flag.String("cookie", "", "Yerel düğümün cookie dosyası (api_cookie_file); anahtar yoksa kullanılır (mode=http)")
+	flagBits    = flag.Int("bits", 0, "Şablon zorluk biti; 0 = düğüm varsayılanı (mode=http)")
 )
 
 func main() {
 	flag.Parse()
 	if *flagAddr == "" {
-		fmt.Println("Kullanım: miner -address <QC_ADDRESS> [-threads N] [-mode mock|local] [-config config.json] [-chain chain_data.dat] [-p2p :3001] [-log miner.log]")
+		fmt.Println("Kullanım: miner -address <QC_ADDRESS> [-threads N] [-mode mock|local|stratum|http] [-config config.json] [-chain chain_data.dat] [-p2p :3001] [-pool host:3333] [-worker name] [-node http://127.0.0.1:8081] [-apikey KEY | -cookie api.cookie] [-log miner.log]")
 		os.Exit(2)
 	}
 
@@ -68,14 +77,48 @@ func main() {
 			log.Fatalf("adapter init: %v", err)
 		}
 		backend = ad
+	case "stratum":
+		user := *flagAddr
+		if *flagWorker != "" {
+			user += "." + *flagWorker
+		}
+		sb, err := miner.NewStratumBackend(miner.StratumOpts{
+			Addr:     *flagPool,
+			User:     user,
+			Password: *flagPass,
+		})
+		if err != nil {
+			log.Fatalf("stratum: %v", err)
+		}
+		backend = sb
+	case "http":
+		c := client.New(*flagNode)
+		c.APIKey = *flagAPIKey
+		if c.APIKey == "" {
+			c.APIKey = os.Getenv("QC_API_KEY")
+		}
+		if c.APIKey == "" && *flagCookie != "" {
+			b, err := os.ReadFile(*flagCookie)
+			if err != nil {
+				log.Fatalf("cookie: %v", err)
+			}
+			c.User, c.Password, _ = strings.Cut(strings.TrimSpace(string(b)), ":")
+		}
+		hctx, hcancel := context.WithTimeout(ctx, 10*time.Second)
+		h, err := c.Health(hctx)
+		hcancel()
+		if err != nil {
+			log.Fatalf("http: %v", err)
+		}
+		log.Printf("Düğüm: %s height=%d", c.BaseURL, h.Height)
+		backend = miner.NewHTTPBackend(c, *flagBits)
 	default:
 		log.Fatalf("bilinmeyen mode: %s", *flagMode)
 	}
 
 	w := miner.NewWorker(backend, miner.WorkerConfig{
-		Threads:      *flagThreads,
-		Address:      *flagAddr,
-		GreenOnFound: true,
+		Threads: *flagThreads,
+		Address: *flagAddr,
 	})
 	log.Printf("Miner başlıyor | addr=%s threads=%d mode=%s", *flagAddr, *flagThreads, *flagMode)
 
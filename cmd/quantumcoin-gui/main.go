@@ -3,13 +3,15 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"time"
+
+	"quantumcoin/api/client"
 )
 
 const defaultHTTPPort = "8081"
@@ -35,16 +37,12 @@ func startBackend(bin, port string) error {
 }
 
 func waitOK(port string, d time.Duration) error {
-	url := fmt.Sprintf("http://localhost:%s/api/health", port)
-	dead := time.Now().Add(d)
-	for time.Now().Before(dead) {
-		if resp, err := http.Get(url); err == nil && resp.StatusCode == 200 {
-			resp.Body.Close()
-			return nil
-		}
-		time.Sleep(300 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	if _, err := client.New("http://localhost:"+port).WaitReady(ctx, 300*time.Millisecond); err != nil {
+		return fmt.Errorf("API /api/health timeout: %w", err)
 	}
-	return errors.New("API /api/health timeout")
+	return nil
 }
 
 func openBrowser(port string) {
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"quantumcoin/logging"
 )
 
 //
@@ -54,42 +57,79 @@ type Config struct {
 	// --- Coinbase maturity (in blocks) ---
 	CoinbaseMaturity int `json:"coinbase_maturity"`
 
-	// --- Reward split percentages ---
-	RewardPctMiner int `json:"reward_pct_miner"`
-	RewardPctStake int `json:"reward_pct_stake"`
-	RewardPctDev   int `json:"reward_pct_dev"`
-	RewardPctBurn  int `json:"reward_pct_burn"`
-	// Community = 100 - (yukarıdakilerin toplamı)
-
-	// --- Kanonik adres alanları ---
-	DevFundAddress       string `json:"dev_fund_address"`
-	StakePoolAddress     string `json:"stake_pool_address"`
-	CommunityPoolAddress string `json:"community_pool_address"`
-	BurnAddress          string `json:"burn_address"`
-
-	// --- Eski/alternatif adres alanları (normalize edilecek) ---
-	RewardAddrMiner     string `json:"reward_addr_miner"`
-	RewardAddrStake     string `json:"reward_addr_stake"`
-	RewardAddrDev       string `json:"reward_addr_dev"`
-	RewardAddrBurn      string `json:"reward_addr_burn"`
-	RewardAddrCommunity string `json:"reward_addr_community"`
+	// Coinbase ödül bölüşümü (yüzdeler, dev/stake/community adresleri ve fonların
+	// multisig descriptor'ları) konsensüs kuralıdır: NetworkParams.Reward'dadır,
+	// config'ten değiştirilemez.
 
 	// --- Premine (ANA CÜZDAN) ---
 	PreminePercent int    `json:"premine_percent"` // varsayılan: 12
-	PremineAddress string `json:"premine_address"` // boşsa DevFundAddress kullanılır
+	PremineAddress string `json:"premine_address"` // bilgi amaçlı; genesis'i etkilemez (bkz. GenesisSpec)
 
 	// --- Networking ---
+	Network   string   `json:"network"` // mainnet | testnet | regtest (bkz. network.go)
 	HTTPPort  string   `json:"http_port"`
 	P2PPort   string   `json:"p2p_port"`
 	BootPeers []string `json:"boot_peers"`
 
+	// --- JSON-RPC (POST /rpc; bkz. api/rpc.go) ---
+	// Parola boşsa yalnız cüzdan/madencilik kontrol yöntemleri loopback'e açıktır.
+	RPCUser     string `json:"rpc_user"`
+	RPCPassword string `json:"rpc_password"`
+
+	// --- HTTP API erişimi (bkz. api/auth.go) ---
+	// Kapsamlar: read, wallet, mining, admin (admin hepsini içerir). Anahtarsız
+	// istemciler APIPublicScopes'u, başka kökenden gelmeyen loopback istemciler
+	// APILoopbackScopes'u alır; "none" => hiçbiri.
+	APIKeysFile       string   `json:"api_keys_file"`       // hash'lenmiş anahtarlar (apikey-new)
+	APICookieFile     string   `json:"api_cookie_file"`     // açılışta yazılan admin token'ı; "" => yok
+	APIPublicScopes   []string `json:"api_public_scopes"`   // varsayılan: read
+	APILoopbackScopes []string `json:"api_loopback_scopes"` // varsayılan: read (yerel yönetim cookie dosyasıyla)
+	APIRateLimit      int      `json:"api_rate_limit"`      // anahtar/IP başına istek/dk; 0 => sınırsız
+	APIRateBurst      int      `json:"api_rate_burst"`      // kova kapasitesi
+	CORSOrigins       []string `json:"cors_origins"`        // izinli kökenler; "*" => hepsi, boş => yalnız aynı köken
+	APIAuditLog       string   `json:"api_audit_log"`       // yetkili çağrılar (JSON satırları); "" => log
+
+	// --- İzleme (GET /metrics her zaman açık; bkz. metrics paketi) ---
+	PprofAddr string `json:"pprof_addr"` // net/http/pprof dinleyicisi, yalnız admin kapsamı; "" => kapalı (örn. "127.0.0.1:6060")
+
+	// --- Düğüm içi madenci ---
+	MinerThreads int `json:"miner_threads"` // PoW goroutine sayısı; 0 => CPU sayısı
+
+	// --- Stratum (harici madenciler) ---
+	StratumPort      string `json:"stratum_port"`       // boş: kapalı (örn. ":3333")
+	StratumShareBits int    `json:"stratum_share_bits"` // pay zorluğu; 0 => blok zorluğu
+
+	// --- Havuz (pool) modu ---
+	PoolEnabled   bool   `json:"pool_enabled"`
+	PoolAddress   string `json:"pool_address"`    // coinbase + ödeme cüzdanı (anahtarı wallet deposunda olmalı)
+	PoolScheme    string `json:"pool_scheme"`     // "pplns" | "pps"
+	PoolFeePct    int    `json:"pool_fee_pct"`    // havuz payı (%)
+	PoolWindow    int    `json:"pool_window"`     // PPLNS: son N pay
+	PoolMinPayout int    `json:"pool_min_payout"` // bu tutarın altındaki bakiyeler birikir
+	PoolStateFile string `json:"pool_state_file"`
+	PoolShareLog  string `json:"pool_share_log"`
+
+	// --- Staking (bkz. stake paketi; stake kuralları NetworkParams.Stake'tedir) ---
+	StakeEpochBlocks int    `json:"stake_epoch_blocks"` // stake havuzu dağıtım aralığı
+	StakeStateFile   string `json:"stake_state_file"`
+
+	// --- Webhook'lar (bkz. webhook paketi) ---
+	WebhookStateFile   string `json:"webhook_state_file"`   // kayıtlar + teslim kuyruğu
+	WebhookMaxAttempts int    `json:"webhook_max_attempts"` // ilk deneme dahil; sonra "failed"
+	WebhookTimeoutSecs int    `json:"webhook_timeout_secs"` // tek HTTP isteği süresi
+
 	// --- Storage ---
+	TxIndex    bool   `json:"tx_index"` // txid/adres/harcama indeksleri (explorer uçları)
 	ChainFile  string `json:"chain_file"`
 	BonusFile  string `json:"bonus_file"`
 	WalletFile string `json:"wallet_file"`
 
-	// --- Misc ---
-	LogLevel string `json:"log_level"`
+	// --- Log (bkz. logging paketi) ---
+	LogLevel      string `json:"log_level"`       // "info" ya da alt sistem bazında: "info,p2p=debug,miner=warn"
+	LogFormat     string `json:"log_format"`      // text | json
+	LogFile       string `json:"log_file"`        // "" => stderr
+	LogMaxSizeMB  int    `json:"log_max_size_mb"` // log_file bu boyutu aşınca döndürülür; 0 => döndürme yok
+	LogMaxBackups int    `json:"log_max_backups"` // saklanan eski dosya (log_file.1 .. .N)
 }
 
 // ---- Defaults ----
@@ -109,35 +149,46 @@ func Default() *Config {
 
 		CoinbaseMaturity: 10,
 
-		RewardPctMiner: 70,
-		RewardPctStake: 10,
-		RewardPctDev:   10,
-		RewardPctBurn:  5,
-
-		DevFundAddress:       "",
-		StakePoolAddress:     "",
-		CommunityPoolAddress: "",
-		BurnAddress:          "QC_BURN_SINK",
-
-		RewardAddrMiner:     "",
-		RewardAddrStake:     "",
-		RewardAddrDev:       "",
-		RewardAddrBurn:      "",
-		RewardAddrCommunity: "",
-
 		// Premine defaults
 		PreminePercent: 12,
 		PremineAddress: "",
 
+		Network:   NetworkMainnet,
 		HTTPPort:  ":8081",
 		P2PPort:   ":3001",
 		BootPeers: []string{},
 
+		APIKeysFile:       "api_keys.json",
+		APICookieFile:     "api.cookie",
+		APIPublicScopes:   []string{"read"},
+		APILoopbackScopes: []string{"read"},
+		APIRateLimit:      600,
+		APIRateBurst:      100,
+		CORSOrigins:       []string{},
+		APIAuditLog:       "api_audit.log",
+
+		PoolScheme:    "pplns",
+		PoolFeePct:    1,
+		PoolWindow:    1000,
+		PoolMinPayout: 1,
+		PoolStateFile: "pool_state.json",
+		PoolShareLog:  "pool_shares.log",
+
+		StakeEpochBlocks: 100,
+		StakeStateFile:   "stake_state.json",
+
+		WebhookStateFile:   "webhook_state.json",
+		WebhookMaxAttempts: 8,
+		WebhookTimeoutSecs: 10,
+
 		ChainFile:  "chain_data.dat",
 		BonusFile:  "bonus_store.json",
 		WalletFile: "wallet_data.json",
 
-		LogLevel: "info",
+		LogLevel:      "info",
+		LogFormat:     "text",
+		LogMaxSizeMB:  50,
+		LogMaxBackups: 5,
 	}
 }
 
@@ -149,6 +200,8 @@ var (
 	mu      sync.RWMutex
 )
 
+var logger = logging.For(logging.SubNode)
+
 // Load: ENV ve (varsa) dosyadan yükle; normalize et; doğrula; global ata.
 func Load(filePath string) (*Config, error) {
 	var err error
@@ -165,7 +218,13 @@ func Load(filePath string) (*Config, error) {
 			}
 		}
 
-		cfg.normalizeRewardAddresses()
+		// Default() mainnet profilidir; seçilen ağın varsayılanlarına oradan geçilir
+		network := cfg.Network
+		cfg.Network = NetworkMainnet
+		if nErr := cfg.UseNetwork(network); nErr != nil {
+			err = nErr
+			return
+		}
 
 		if vErr := cfg.Validate(); vErr != nil {
 			err = vErr
@@ -213,39 +272,6 @@ func Set(c *Config) {
 	mu.Unlock()
 }
 
-// ====== EK: Community yüzdesi ve “ana cüzdan” çözümleyici ======
-
-// CommunityPercent: kalan % (negatifse 0’a sıkıştırılır)
-func (c *Config) CommunityPercent() int {
-	sum := c.RewardPctMiner + c.RewardPctStake + c.RewardPctDev + c.RewardPctBurn
-	if sum >= 100 {
-		return 0
-	}
-	rem := 100 - sum
-	if rem < 0 {
-		return 0
-	}
-	return rem
-}
-
-// ResolveCommunityAddress:
-// community_pool_address boşsa öncelik sırası:
-// 1) PremineAddress (ANA CÜZDAN)
-// 2) DevFundAddress
-// 3) (boş)
-func (c *Config) ResolveCommunityAddress() string {
-	if strings.TrimSpace(c.CommunityPoolAddress) != "" {
-		return c.CommunityPoolAddress
-	}
-	if strings.TrimSpace(c.PremineAddress) != "" {
-		return c.PremineAddress
-	}
-	if strings.TrimSpace(c.DevFundAddress) != "" {
-		return c.DevFundAddress
-	}
-	return ""
-}
-
 // Validate: mantıksal doğrulama
 func (c *Config) Validate() error {
 	if c.InitialReward < 0 {
@@ -263,37 +289,70 @@ func (c *Config) Validate() error {
 	if c.DefaultDifficultyBits <= 0 || c.DefaultDifficultyBits > 255 {
 		return errors.New("default_difficulty_bits must be 1..255")
 	}
-	if c.CoinbaseMaturity < 0 {
-		return errors.New("coinbase_maturity cannot be negative")
+	if _, err := Network(c.Network); err != nil {
+		return err
 	}
-	if c.RewardPctMiner < 0 || c.RewardPctStake < 0 || c.RewardPctDev < 0 || c.RewardPctBurn < 0 {
-		return errors.New("reward percentages cannot be negative")
+	if err := c.Params().Reward.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", c.Network, err)
 	}
-
-	sum := c.RewardPctMiner + c.RewardPctStake + c.RewardPctDev + c.RewardPctBurn
-	if sum > 100 {
-		fmt.Println("[config] warning: reward percentages sum to >100")
+	if floor := c.Params().MinDifficulty; c.DefaultDifficultyBits < floor {
+		return fmt.Errorf("default_difficulty_bits must be >= %d on %s", floor, c.Network)
 	}
-	if sum < 100 {
-		rem := 100 - sum
-		fmt.Printf("[config] info: reward percentages sum to %d%%; remaining %d%% goes to community.\n", sum, rem)
-		// ADDED: community adresi boşsa otomatik “ana cüzdan”a bağla
-		if strings.TrimSpace(c.CommunityPoolAddress) == "" {
-			if addr := c.ResolveCommunityAddress(); addr == "" {
-				fmt.Println("[config] info: community address not set; will try premine/dev fund at runtime")
-			} else {
-				// Normalize içinde de set ediliyor; burada bilgi mesajı yeterli.
-				fmt.Printf("[config] info: community address resolved to %s\n", addr)
-			}
-		}
+	if c.CoinbaseMaturity < 0 {
+		return errors.New("coinbase_maturity cannot be negative")
 	}
-
 	if c.PreminePercent < 0 || c.PreminePercent > 100 {
 		return errors.New("premine_percent must be 0..100")
 	}
 	if c.HTTPPort == "" || c.P2PPort == "" {
 		return errors.New("ports cannot be empty")
 	}
+	if c.MinerThreads < 0 {
+		return errors.New("miner_threads cannot be negative")
+	}
+	if c.StakeEpochBlocks < 1 {
+		return errors.New("stake_epoch_blocks must be >= 1")
+	}
+	if c.APIRateLimit < 0 || c.APIRateBurst < 0 {
+		return errors.New("api_rate_limit and api_rate_burst cannot be negative")
+	}
+	for _, list := range [][]string{c.APIPublicScopes, c.APILoopbackScopes} {
+		for _, s := range list {
+			switch s {
+			case "read", "wallet", "mining", "admin", "none":
+			default:
+				return fmt.Errorf("unknown api scope %q (read, wallet, mining, admin, none)", s)
+			}
+		}
+	}
+	if c.PprofAddr != "" {
+		if _, _, err := net.SplitHostPort(c.PprofAddr); err != nil {
+			return fmt.Errorf("pprof_addr: %v", err)
+		}
+	}
+	if _, err := logging.ParseLevels(c.LogLevel); err != nil {
+		return fmt.Errorf("log_level: %v", err)
+	}
+	if c.LogFormat != "" && c.LogFormat != "text" && c.LogFormat != "json" {
+		return errors.New("log_format must be text or json")
+	}
+	if c.LogMaxSizeMB < 0 || c.LogMaxBackups < 0 {
+		return errors.New("log_max_size_mb and log_max_backups cannot be negative")
+	}
+	if c.WebhookMaxAttempts < 1 || c.WebhookTimeoutSecs < 1 {
+		return errors.New("webhook_max_attempts and webhook_timeout_secs must be >= 1")
+	}
+	if c.PoolEnabled {
+		if strings.TrimSpace(c.PoolAddress) == "" {
+			return errors.New("pool_address required when pool_enabled")
+		}
+		if c.PoolScheme != "pplns" && c.PoolScheme != "pps" {
+			return errors.New("pool_scheme must be pplns or pps")
+		}
+		if c.PoolFeePct < 0 || c.PoolFeePct > 100 {
+			return errors.New("pool_fee_pct must be 0..100")
+		}
+	}
 	return nil
 }
 
@@ -328,124 +387,36 @@ func (c *Config) MiningEndsAt() int64 {
 
 // ---- internal: ENV ve Dosya yükleyicileri ----
 
+// loadFromFile: dosyada bulunan anahtarları into'ya yazar; dosyada olmayan alanlar
+// (varsayılan/ENV değerleri) korunur. Açıkça yazılmış sıfır değerler de uygulanır
+// (ör. "pool_fee_pct": 0, "tx_index": false).
 func loadFromFile(path string, into *Config) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	var fileCfg Config
-	if err := json.Unmarshal(data, &fileCfg); err != nil {
+	if err := json.Unmarshal(data, into); err != nil {
 		return fmt.Errorf("parse %s failed: %w", path, err)
 	}
-	merge(into, &fileCfg)
+	var keys map[string]json.RawMessage
+	if json.Unmarshal(data, &keys) == nil {
+		for _, k := range ignoredConsensusKeys {
+			if _, ok := keys[k]; ok {
+				logger.Warn("config key ignored; consensus rules are fixed per network", "file", path, "key", k)
+			}
+		}
+	}
 	return nil
 }
 
-func merge(base, src *Config) {
-	// Basit merge (zero-value olmayanlar)
-	if src.Symbol != "" {
-		base.Symbol = src.Symbol
-	}
-	if src.InitialReward != 0 {
-		base.InitialReward = src.InitialReward
-	}
-	if src.TotalSupply != 0 {
-		base.TotalSupply = src.TotalSupply
-	}
-	if src.GenesisUnix != 0 {
-		base.GenesisUnix = src.GenesisUnix
-	}
-	if src.HalvingIntervalSecs != 0 {
-		base.HalvingIntervalSecs = src.HalvingIntervalSecs
-	}
-	if src.MiningPeriodSecs != 0 {
-		base.MiningPeriodSecs = src.MiningPeriodSecs
-	}
-	if src.TargetBlockTimeSecs != 0 {
-		base.TargetBlockTimeSecs = src.TargetBlockTimeSecs
-	}
-	if src.HalvingPeriodBlocks != 0 {
-		base.HalvingPeriodBlocks = src.HalvingPeriodBlocks
-	}
-	if src.DefaultDifficultyBits != 0 {
-		base.DefaultDifficultyBits = src.DefaultDifficultyBits
-	}
-	if src.CoinbaseMaturity != 0 {
-		base.CoinbaseMaturity = src.CoinbaseMaturity
-	}
-	if src.RewardPctMiner != 0 {
-		base.RewardPctMiner = src.RewardPctMiner
-	}
-	if src.RewardPctStake != 0 {
-		base.RewardPctStake = src.RewardPctStake
-	}
-	if src.RewardPctDev != 0 {
-		base.RewardPctDev = src.RewardPctDev
-	}
-	if src.RewardPctBurn != 0 {
-		base.RewardPctBurn = src.RewardPctBurn
-	}
-
-	// Kanonik adresler
-	if src.DevFundAddress != "" {
-		base.DevFundAddress = src.DevFundAddress
-	}
-	if src.StakePoolAddress != "" {
-		base.StakePoolAddress = src.StakePoolAddress
-	}
-	if src.CommunityPoolAddress != "" {
-		base.CommunityPoolAddress = src.CommunityPoolAddress
-	}
-	if src.BurnAddress != "" {
-		base.BurnAddress = src.BurnAddress
-	}
-
-	// Eski alanlar
-	if src.RewardAddrMiner != "" {
-		base.RewardAddrMiner = src.RewardAddrMiner
-	}
-	if src.RewardAddrStake != "" {
-		base.RewardAddrStake = src.RewardAddrStake
-	}
-	if src.RewardAddrDev != "" {
-		base.RewardAddrDev = src.RewardAddrDev
-	}
-	if src.RewardAddrBurn != "" {
-		base.RewardAddrBurn = src.RewardAddrBurn
-	}
-	if src.RewardAddrCommunity != "" {
-		base.RewardAddrCommunity = src.RewardAddrCommunity
-	}
-
-	// Premine
-	if src.PreminePercent != 0 {
-		base.PreminePercent = src.PreminePercent
-	}
-	if src.PremineAddress != "" {
-		base.PremineAddress = src.PremineAddress
-	}
-
-	if src.HTTPPort != "" {
-		base.HTTPPort = src.HTTPPort
-	}
-	if src.P2PPort != "" {
-		base.P2PPort = src.P2PPort
-	}
-	if len(src.BootPeers) > 0 {
-		base.BootPeers = append([]string(nil), src.BootPeers...)
-	}
-	if src.ChainFile != "" {
-		base.ChainFile = src.ChainFile
-	}
-	if src.BonusFile != "" {
-		base.BonusFile = src.BonusFile
-	}
-	if src.WalletFile != "" {
-		base.WalletFile = src.WalletFile
-	}
-	if src.LogLevel != "" {
-		base.LogLevel = src.LogLevel
-	}
+// ignoredConsensusKeys: eski konsensüs ayarları; artık NetworkParams'tadır
+// (bölüşüm ve fon descriptor'ları: Reward, stake kuralları: Stake)
+var ignoredConsensusKeys = []string{
+	"reward_pct_miner", "reward_pct_stake", "reward_pct_dev", "reward_pct_burn",
+	"dev_fund_address", "stake_pool_address", "community_pool_address", "burn_address",
+	"reward_addr_miner", "reward_addr_stake", "reward_addr_dev", "reward_addr_burn", "reward_addr_community",
+	"dev_fund_multisig", "community_pool_multisig",
+	"stake_min_amount", "stake_min_lock_blocks", "stake_cooldown_blocks",
 }
 
 func applyEnv(c *Config) {
@@ -472,6 +443,14 @@ func applyEnv(c *Config) {
 		}
 		return def
 	}
+	envBool := func(key string, def bool) bool {
+		if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		}
+		return def
+	}
 	envCSV := func(key string, def []string) []string {
 		if v := strings.TrimSpace(os.Getenv(key)); v != "" {
 			parts := strings.Split(v, ",")
@@ -500,37 +479,59 @@ func applyEnv(c *Config) {
 
 	c.CoinbaseMaturity = envInt("QC_COINBASE_MATURITY", c.CoinbaseMaturity)
 
-	c.RewardPctMiner = envInt("QC_REWARD_PCT_MINER", c.RewardPctMiner)
-	c.RewardPctStake = envInt("QC_REWARD_PCT_STAKE", c.RewardPctStake)
-	c.RewardPctDev = envInt("QC_REWARD_PCT_DEV", c.RewardPctDev)
-	c.RewardPctBurn = envInt("QC_REWARD_PCT_BURN", c.RewardPctBurn)
-
-	// Eski/alternatif adres alanları
-	c.RewardAddrMiner = envStr("QC_REWARD_ADDR_MINER", c.RewardAddrMiner)
-	c.RewardAddrStake = envStr("QC_REWARD_ADDR_STAKE", c.RewardAddrStake)
-	c.RewardAddrDev = envStr("QC_REWARD_ADDR_DEV", c.RewardAddrDev)
-	c.RewardAddrBurn = envStr("QC_REWARD_ADDR_BURN", c.RewardAddrBurn)
-	c.RewardAddrCommunity = envStr("QC_REWARD_ADDR_COMMUNITY", c.RewardAddrCommunity)
-
-	// Kanonik adresler
-	c.DevFundAddress = envStr("QC_DEV_FUND_ADDRESS", c.DevFundAddress)
-	c.StakePoolAddress = envStr("QC_STAKE_POOL_ADDRESS", c.StakePoolAddress)
-	c.CommunityPoolAddress = envStr("QC_COMMUNITY_POOL_ADDRESS", c.CommunityPoolAddress)
-	c.BurnAddress = envStr("QC_BURN_ADDRESS", c.BurnAddress)
+	// Bölüşüm ve stake kuralları konsensüs kuralıdır (NetworkParams); eski ayarlar yok sayılır
+	for _, k := range ignoredConsensusKeys {
+		if env := "QC_" + strings.ToUpper(k); strings.TrimSpace(os.Getenv(env)) != "" {
+			logger.Warn("environment variable ignored; consensus rules are fixed per network", "env", env)
+		}
+	}
 
 	// Premine
 	c.PreminePercent = envInt("QC_PREMINE_PERCENT", c.PreminePercent)
 	c.PremineAddress = envStr("QC_PREMINE_ADDRESS", c.PremineAddress)
 
+	c.Network = envStr("QC_NETWORK", c.Network)
 	c.HTTPPort = envStr("QC_HTTP_PORT", c.HTTPPort)
 	c.P2PPort = envStr("QC_P2P_PORT", c.P2PPort)
 	c.BootPeers = envCSV("QC_BOOT_PEERS", c.BootPeers)
-
+	c.RPCUser = envStr("QC_RPC_USER", c.RPCUser)
+	c.RPCPassword = envStr("QC_RPC_PASSWORD", c.RPCPassword)
+	c.APIKeysFile = envStr("QC_API_KEYS_FILE", c.APIKeysFile)
+	c.APICookieFile = envStr("QC_API_COOKIE_FILE", c.APICookieFile)
+	c.APIPublicScopes = envCSV("QC_API_PUBLIC_SCOPES", c.APIPublicScopes)
+	c.APILoopbackScopes = envCSV("QC_API_LOOPBACK_SCOPES", c.APILoopbackScopes)
+	c.APIRateLimit = envInt("QC_API_RATE_LIMIT", c.APIRateLimit)
+	c.APIRateBurst = envInt("QC_API_RATE_BURST", c.APIRateBurst)
+	c.CORSOrigins = envCSV("QC_CORS_ORIGINS", c.CORSOrigins)
+	c.APIAuditLog = envStr("QC_API_AUDIT_LOG", c.APIAuditLog)
+	c.PprofAddr = envStr("QC_PPROF_ADDR", c.PprofAddr)
+	c.MinerThreads = envInt("QC_MINER_THREADS", c.MinerThreads)
+	c.StratumPort = envStr("QC_STRATUM_PORT", c.StratumPort)
+	c.StratumShareBits = envInt("QC_STRATUM_SHARE_BITS", c.StratumShareBits)
+	c.PoolEnabled = envBool("QC_POOL_ENABLED", c.PoolEnabled)
+	c.PoolAddress = envStr("QC_POOL_ADDRESS", c.PoolAddress)
+	c.PoolScheme = envStr("QC_POOL_SCHEME", c.PoolScheme)
+	c.PoolFeePct = envInt("QC_POOL_FEE_PCT", c.PoolFeePct)
+	c.PoolWindow = envInt("QC_POOL_WINDOW", c.PoolWindow)
+	c.PoolMinPayout = envInt("QC_POOL_MIN_PAYOUT", c.PoolMinPayout)
+
+	c.StakeEpochBlocks = envInt("QC_STAKE_EPOCH_BLOCKS", c.StakeEpochBlocks)
+	c.StakeStateFile = envStr("QC_STAKE_STATE_FILE", c.StakeStateFile)
+
+	c.WebhookStateFile = envStr("QC_WEBHOOK_STATE_FILE", c.WebhookStateFile)
+	c.WebhookMaxAttempts = envInt("QC_WEBHOOK_MAX_ATTEMPTS", c.WebhookMaxAttempts)
+	c.WebhookTimeoutSecs = envInt("QC_WEBHOOK_TIMEOUT_SECS", c.WebhookTimeoutSecs)
+
+	c.TxIndex = envBool("QC_TX_INDEX", c.TxIndex)
 	c.ChainFile = envStr("QC_CHAIN_FILE", c.ChainFile)
 	c.BonusFile = envStr("QC_BONUS_FILE", c.BonusFile)
 	c.WalletFile = envStr("QC_WALLET_FILE", c.WalletFile)
 
 	c.LogLevel = envStr("QC_LOG_LEVEL", c.LogLevel)
+	c.LogFormat = envStr("QC_LOG_FORMAT", c.LogFormat)
+	c.LogFile = envStr("QC_LOG_FILE", c.LogFile)
+	c.LogMaxSizeMB = envInt("QC_LOG_MAX_SIZE_MB", c.LogMaxSizeMB)
+	c.LogMaxBackups = envInt("QC_LOG_MAX_BACKUPS", c.LogMaxBackups)
 
 	// Back-compat: yıllık bonus ENV override
 	if v := strings.TrimSpace(os.Getenv("QC_ANNUAL_BONUS_QC")); v != "" {
@@ -539,31 +540,3 @@ func applyEnv(c *Config) {
 		}
 	}
 }
-
-// Eski adres alanlarından kanoniğe taşıma + premine/community fallback
-func (c *Config) normalizeRewardAddresses() {
-	if c.DevFundAddress == "" && c.RewardAddrDev != "" {
-		c.DevFundAddress = c.RewardAddrDev
-	}
-	if c.StakePoolAddress == "" && c.RewardAddrStake != "" {
-		c.StakePoolAddress = c.RewardAddrStake
-	}
-	if c.CommunityPoolAddress == "" && c.RewardAddrCommunity != "" {
-		c.CommunityPoolAddress = c.RewardAddrCommunity
-	}
-	if c.BurnAddress == "" && c.RewardAddrBurn != "" {
-		c.BurnAddress = c.RewardAddrBurn
-	}
-	// Premine adresi boşsa DevFundAddress'tan devral
-	if c.PremineAddress == "" && c.DevFundAddress != "" {
-		c.PremineAddress = c.DevFundAddress
-	}
-	// ADDED: Community adresi yoksa ana cüzdana (premine) ya da dev fona bağla
-	if strings.TrimSpace(c.CommunityPoolAddress) == "" {
-		if strings.TrimSpace(c.PremineAddress) != "" {
-			c.CommunityPoolAddress = c.PremineAddress
-		} else if strings.TrimSpace(c.DevFundAddress) != "" {
-			c.CommunityPoolAddress = c.DevFundAddress
-		}
-	}
-}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Ağ adları
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+	NetworkRegtest = "regtest"
+)
+
+// NetworkParams: ağa özgü sabitler. Genesis tanımları blockchain/genesis.go'dadır.
+type NetworkParams struct {
+	Name            string
+	AddrVersion     byte    // P2PKH adres öneki (Base58Check)
+	MultisigVersion byte    // m-of-n adres öneki
+	Magic           [4]byte // p2p hello; farklıysa bağlantı reddedilir
+	HTTPPort        string
+	P2PPort         string
+	DifficultyBits  int    // varsayılan kazım zorluğu
+	MinDifficulty   int    // bundan düşük zorluklu blok kabul edilmez
+	DataDir         string // zincir/cüzdan/havuz dosyaları; "" => çalışma dizini
+	Generate        bool   // regtest: /api/regtest/generate ile anında blok
+	StakePoolKey    string // regtest: Reward.StakePool'un herkesçe bilinen özel anahtarı (hex); diğer ağlarda anahtar cüzdanda olmalı
+
+	Reward RewardSplit // coinbase bölüşümü (konsensüs kuralı)
+	Stake  StakeRules  // stake/unstake kuralları (konsensüs kuralı)
+}
+
+// RewardSplit: coinbase ödül bölüşümü (bkz. blockchain/reward_split.go). Konsensüs
+// kuralıdır: ağa sabittir, config dosyası ya da ENV değiştiremez (farklı bölüşüm
+// üreten düğüm zincirden ayrılır). Payı olan her hedefin adresi burada sabitlenir
+// (bkz. Validate); adresi olmayan hedefin payı 0'dır.
+type RewardSplit struct {
+	PctMiner int
+	PctStake int
+	PctDev   int
+	PctBurn  int // community = 100 - (yukarıdakilerin toplamı)
+
+	StakePool string // stake payı (stake dağıtıcısının adresi; bkz. stake paketi)
+	DevFund   string // m-of-n adres; descriptor'ı DevFundMultisig
+	Community string // m-of-n adres; descriptor'ı CommunityMultisig
+	BurnSink  string // yalnız v2 blokların burn payı; v3+ LockBurn çıktısı
+
+	// Fon descriptor'ları ("m:pubhex,pubhex,..."): adresleri DevFund/Community'dir
+	// (bkz. wallet.RegisterFundMultisigs); düğüm PSBT co-signing için cüzdana kaydeder.
+	DevFundMultisig   string
+	CommunityMultisig string
+}
+
+// PctCommunity: kalan yüzde (negatifse 0)
+func (r RewardSplit) PctCommunity() int {
+	return max(0, 100-(r.PctMiner+r.PctStake+r.PctDev+r.PctBurn))
+}
+
+// Validate: yüzdeler 0..100, toplamları 100'ü aşmaz, payı olan her hedefin
+// adresi vardır (adressiz pay sessizce madenciye kalırdı) ve fon adreslerinin
+// descriptor'ı sabitlenmiştir.
+func (r RewardSplit) Validate() error {
+	pcts := []int{r.PctMiner, r.PctStake, r.PctDev, r.PctBurn}
+	sum := 0
+	for _, p := range pcts {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("reward split: percentages must be 0..100")
+		}
+		sum += p
+	}
+	if sum > 100 {
+		return fmt.Errorf("reward split: percentages add up to %d", sum)
+	}
+	for _, d := range []struct {
+		name    string
+		pct     int
+		address string
+	}{
+		{"stake pool", r.PctStake, r.StakePool},
+		{"dev fund", r.PctDev, r.DevFund},
+		{"community", r.PctCommunity(), r.Community},
+	} {
+		if d.pct > 0 && strings.TrimSpace(d.address) == "" {
+			return fmt.Errorf("reward split: %s share is %d%% but has no address", d.name, d.pct)
+		}
+	}
+	if (r.DevFund != "" && r.DevFundMultisig == "") || (r.Community != "" && r.CommunityMultisig == "") {
+		return fmt.Errorf("reward split: dev fund and community addresses must be multisig descriptors")
+	}
+	return nil
+}
+
+// unpinnedRewardSplit: hedef adresleri sabitlenmemiş ağ (mainnet, testnet): stake,
+// dev ve community payları adresleri NetworkParams'a eklenene dek 0; %95 madenci,
+// %5 yakım. Adres eklemek bölüşümü değiştirir (konsensüs değişikliği).
+var unpinnedRewardSplit = RewardSplit{
+	PctMiner: 95,
+	PctBurn:  5,
+	BurnSink: "QC_BURN_SINK",
+}
+
+// regtestRewardSplit: %70 madenci, %10 stake, %10 dev, %5 yakım, %5 community.
+// Adreslerin özel anahtarları herkesçe bilinir, yalnız yerel denemeler içindir:
+// stake havuzu sha256("quantumcoin/regtest/stake-pool"); dev fonu ve community
+// 2-of-3 multisig, anahtar i = sha256("quantumcoin/regtest/<dev-fund|community>/<i>").
+var regtestRewardSplit = RewardSplit{
+	PctMiner:  70,
+	PctStake:  10,
+	PctDev:    10,
+	PctBurn:   5,
+	StakePool: "mpdbQfSMyXDzN1C4fkGEYjPx1ndojxM1XJ",
+	DevFund:   "2N9pdwoAp9WCgmFc6gyBx1FRYnUsN2F4d58",
+	Community: "2N4NhS2KdWXwZxkKxoCfPy4bprSBqRYawnk",
+	BurnSink:  "QC_BURN_SINK",
+
+	DevFundMultisig: "2:020b60ad172521b531a68b67ad5cfdba8b333666da337e4239b3e70c7aaad735ed," +
+		"022396fc95feca7cd231a914cc5c1a19d7b24cf01bcbe1ea43de279ff9dcadb722," +
+		"02621e489ed529ebf32b3d5b367552948afa7414815858a320116bd02510272488",
+	CommunityMultisig: "2:03155fb69d91a1749b3e85e8042ddd8e117e461e74a59c14c0f7234b05a31fb441," +
+		"03483a7517ce8163baafc460763d934e8335e90e14e74cfb19730c6764d8acdbbf," +
+		"03adc2c36f34283aa40b6288dbf88db4323dcc69b179504a6596865ddd5d727478",
+}
+
+// StakeRules: stake çıktılarının zincir kuralları (bkz. blockchain/stake.go).
+// Konsensüs kuralıdır; config dosyası ya da ENV değiştiremez.
+type StakeRules struct {
+	MinAmount      int // stake çıktısı en az bu kadar
+	MinLockBlocks  int // stake en az bu kadar blok kilitli
+	CooldownBlocks int // unstake sonrası çekim beklemesi
+}
+
+var defaultStakeRules = StakeRules{MinAmount: 10, MinLockBlocks: 1000, CooldownBlocks: 100}
+
+var networks = map[string]NetworkParams{
+	NetworkMainnet: {
+		Name:            NetworkMainnet,
+		AddrVersion:     0x00,
+		MultisigVersion: 0x05,
+		Magic:           [4]byte{'Q', 'C', 'M', 'N'},
+		HTTPPort:        ":8081",
+		P2PPort:         ":3001",
+		DifficultyBits:  16,
+		MinDifficulty:   16,
+		Reward:          unpinnedRewardSplit,
+		Stake:           defaultStakeRules,
+	},
+	NetworkTestnet: {
+		Name:            NetworkTestnet,
+		AddrVersion:     0x6f,
+		MultisigVersion: 0xc4,
+		Magic:           [4]byte{'Q', 'C', 'T', 'N'},
+		HTTPPort:        ":18081",
+		P2PPort:         ":13001",
+		DifficultyBits:  12,
+		MinDifficulty:   8,
+		DataDir:         "testnet",
+		Reward:          unpinnedRewardSplit,
+		Stake:           defaultStakeRules,
+	},
+	NetworkRegtest: {
+		Name:            NetworkRegtest,
+		AddrVersion:     0x6f,
+		MultisigVersion: 0xc4,
+		Magic:           [4]byte{'Q', 'C', 'R', 'T'},
+		HTTPPort:        ":28081",
+		P2PPort:         ":23001",
+		DifficultyBits:  1,
+		MinDifficulty:   1,
+		DataDir:         "regtest",
+		Generate:        true,
+		StakePoolKey:    "04c1e9f5a6dc895554731874e8f1a3054c8bdb30300af4109a630aadba552fec", // sha256("quantumcoin/regtest/stake-pool")
+		Reward:          regtestRewardSplit,
+		Stake:           defaultStakeRules,
+	},
+}
+
+// Network: ada göre ağ parametreleri
+func Network(name string) (NetworkParams, error) {
+	p, ok := networks[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return NetworkParams{}, fmt.Errorf("unknown network %q (mainnet|testnet|regtest)", name)
+	}
+	return p, nil
+}
+
+// Params: config'in ağı (boş/bilinmeyen => mainnet)
+func (c *Config) Params() NetworkParams {
+	if p, err := Network(c.Network); err == nil {
+		return p
+	}
+	return networks[NetworkMainnet]
+}
+
+// UseNetwork: ağı seçer ve profil varsayılanlarını uygular. Önceki ağın
+// varsayılanında kalan alanlar (port, zorluk, dosya yolları) yeni ağınkine
+// taşınır; kullanıcının değiştirdiği değerlere dokunulmaz.
+func (c *Config) UseNetwork(name string) error {
+	next, err := Network(name)
+	if err != nil {
+		return err
+	}
+	prev := c.Params()
+	c.Network = next.Name
+
+	swap := func(v *string, from, to string) {
+		if *v == from {
+			*v = to
+		}
+	}
+	swap(&c.HTTPPort, prev.HTTPPort, next.HTTPPort)
+	swap(&c.P2PPort, prev.P2PPort, next.P2PPort)
+	if c.DefaultDifficultyBits == prev.DifficultyBits {
+		c.DefaultDifficultyBits = next.DifficultyBits
+	}
+	def := Default()
+	for _, f := range []struct {
+		v   *string
+		def string
+	}{
+		{&c.ChainFile, def.ChainFile},
+		{&c.WalletFile, def.WalletFile},
+		{&c.BonusFile, def.BonusFile},
+		{&c.PoolStateFile, def.PoolStateFile},
+		{&c.PoolShareLog, def.PoolShareLog},
+		{&c.StakeStateFile, def.StakeStateFile},
+		{&c.WebhookStateFile, def.WebhookStateFile},
+		{&c.APIKeysFile, def.APIKeysFile},
+		{&c.APICookieFile, def.APICookieFile},
+		{&c.APIAuditLog, def.APIAuditLog},
+	} {
+		swap(f.v, prev.path(f.def), next.path(f.def))
+	}
+	return nil
+}
+
+// path: ağın veri dizinindeki dosya yolu
+func (p NetworkParams) path(name string) string {
+	if p.DataDir == "" {
+		return name
+	}
+	return filepath.Join(p.DataDir, name)
+}
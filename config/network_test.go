@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestNetworkRewardSplitsValid(t *testing.T) {
+	for name, p := range networks {
+		if err := p.Reward.Validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestRewardSplitRejectsShareWithoutAddress(t *testing.T) {
+	r := regtestRewardSplit
+	r.StakePool = ""
+	if r.Validate() == nil {
+		t.Error("stake share without address accepted")
+	}
+	r = regtestRewardSplit
+	r.PctMiner = 75 // community payı 0 olur
+	r.Community = ""
+	if err := r.Validate(); err != nil {
+		t.Errorf("zero community share: %v", err)
+	}
+	r.PctMiner = 90
+	if r.Validate() == nil {
+		t.Error("percentages over 100 accepted")
+	}
+}
@@ -0,0 +1,142 @@
+// Package events: zincir, mempool, p2p ve madenci olaylarının yayınlandığı iç
+// yayın/abone (pub/sub) veri yolu. Olaylar Go türüyle ayırt edilir (bkz.
+// types.go); abone yalnız istediği türleri alır. Yayıncılar bloklanmaz: abone
+// kanalı doluysa olay o abone için düşürülür ve sayacı artar.
+//
+// Aboneler iki biçimde bağlanır:
+//
+//	sub := bus.Subscribe("ws", 256, events.BlockConnected{}, events.TxAccepted{})
+//	for ev := range sub.C { switch e := ev.(type) { ... } }   // sıralı, çok türlü
+//
+//	events.Handle(bus, "autosave", 16, func(e events.BlockConnected) { ... })
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"quantumcoin/logging"
+)
+
+var logger = logging.For(logging.SubNode)
+
+// DefaultBuffer: Subscribe'a 0 verilirse kanal kapasitesi
+const DefaultBuffer = 64
+
+// Bus: tür bazlı, bloklamayan yayın/abone
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription: bir abonenin olay kanalı (olaylar yayın sırasıyla gelir)
+type Subscription struct {
+	Name    string
+	C       <-chan any
+	c       chan any
+	kinds   map[reflect.Type]bool // boş => tüm türler
+	bus     *Bus
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+// NewBus: boş veri yolu
+func NewBus() *Bus {
+	return &Bus{subs: map[*Subscription]struct{}{}}
+}
+
+// Default: düğüm genelindeki veri yolu
+var Default = NewBus()
+
+// Publish: olayı türüne abone olan herkese bloklamadan iletir
+func (b *Bus) Publish(ev any) {
+	t := reflect.TypeOf(ev)
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for s := range b.subs {
+		if len(s.kinds) > 0 && !s.kinds[t] {
+			continue
+		}
+		select {
+		case s.c <- ev:
+		default:
+			if n := s.dropped.Add(1); n == 1 || n%100 == 0 {
+				logger.Warn("slow subscriber, event dropped", "subscriber", s.Name, "event", fmt.Sprintf("%T", ev), "dropped", n)
+			}
+		}
+	}
+}
+
+// Subscribe: kinds türlerindeki (örnek değerlerle verilir; boşsa tümü) olaylar
+// için buffer kapasiteli abonelik
+func (b *Bus) Subscribe(name string, buffer int, kinds ...any) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	c := make(chan any, buffer)
+	s := &Subscription{Name: name, C: c, c: c, kinds: map[reflect.Type]bool{}, bus: b}
+	for _, k := range kinds {
+		s.kinds[reflect.TypeOf(k)] = true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(c)
+		return s
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// Handle: T türündeki olayları ayrı bir goroutine'de fn'e verir. fn'deki
+// panik yakalanıp loglanır; abonelik Close ile biter.
+func Handle[T any](b *Bus, name string, buffer int, fn func(T)) *Subscription {
+	var zero T
+	s := b.Subscribe(name, buffer, zero)
+	go func() {
+		for ev := range s.C {
+			if e, ok := ev.(T); ok {
+				s.call(func() { fn(e) })
+			}
+		}
+	}()
+	return s
+}
+
+func (s *Subscription) call(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("subscriber panicked", "subscriber", s.Name, "panic", r)
+		}
+	}()
+	fn()
+}
+
+// Close: aboneliği kaldırır ve kanalı kapatır (birden çok çağrı güvenli)
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		if _, ok := s.bus.subs[s]; ok {
+			delete(s.bus.subs, s)
+			close(s.c)
+		}
+	})
+}
+
+// Dropped: kanal dolu olduğu için bu aboneye iletilemeyen olay sayısı
+func (s *Subscription) Dropped() uint64 { return s.dropped.Load() }
+
+// Close: tüm abonelikleri kapatır; sonraki Subscribe'lar kapalı kanal alır
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for s := range b.subs {
+		delete(b.subs, s)
+		close(s.c)
+	}
+}
@@ -0,0 +1,67 @@
+package events
+
+import "quantumcoin/blockchain"
+
+// BlockConnected: blok zincirin ucuna bağlandı (reorg'da yeni daldaki her blok,
+// artan yükseklikle)
+type BlockConnected struct {
+	Block *blockchain.Block
+}
+
+// BlockDisconnected: reorg'da eski daldan düşen blok; uçtan başlayarak
+// ForkHeight+1'e kadar yayınlanır, ardından yeni dalın BlockConnected'ları gelir
+type BlockDisconnected struct {
+	Block      *blockchain.Block
+	ForkHeight int // iki dalın ortak son bloğu
+}
+
+// TxAccepted: işlem mempool'a kabul edildi
+type TxAccepted struct {
+	Tx *blockchain.Transaction
+}
+
+// TxRemoved: işlem mempool'dan çıktı
+type TxRemoved struct {
+	Tx     *blockchain.Transaction
+	Reason string // ReasonMined ya da geçersizleşme nedeni
+}
+
+// ReasonMined: TxRemoved.Reason — işlem bağlanan bir bloğa girdi
+const ReasonMined = blockchain.TxRemovedMined
+
+// PeerConnected: p2p el sıkışması (hello/genesis) tamamlandı
+type PeerConnected struct {
+	Addr string
+}
+
+// MinerStatus: düğüm madencisinin durumu; Block nil değilse yeni bulunan bloğu bildirir
+type MinerStatus struct {
+	Active   bool
+	Threads  int
+	Hashrate float64
+	Hashes   uint64
+	Block    *blockchain.Block
+	Reward   int // Block'un madenci payı
+}
+
+// chainObserver: blockchain.ChainObserver'ı veri yoluna bağlar
+type chainObserver struct{ b *Bus }
+
+// ChainObserver: bc.SetObserver'a verilecek, olayları b'ye yayınlayan gözlemci
+func ChainObserver(b *Bus) blockchain.ChainObserver { return chainObserver{b} }
+
+func (o chainObserver) BlockConnected(blk *blockchain.Block) {
+	o.b.Publish(BlockConnected{Block: blk})
+}
+
+func (o chainObserver) BlockDisconnected(blk *blockchain.Block, forkHeight int) {
+	o.b.Publish(BlockDisconnected{Block: blk, ForkHeight: forkHeight})
+}
+
+func (o chainObserver) TxAccepted(tx *blockchain.Transaction) {
+	o.b.Publish(TxAccepted{Tx: tx})
+}
+
+func (o chainObserver) TxRemoved(tx *blockchain.Transaction, reason string) {
+	o.b.Publish(TxRemoved{Tx: tx, Reason: reason})
+}
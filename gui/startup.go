@@ -21,12 +21,19 @@ func main() {
 	var bc *blockchain.Blockchain
 	if _, err := os.Stat(blockchainFile); err == nil {
 		bc, err = blockchain.LoadBlockchainFromFile(blockchainFile)
+		if err == nil {
+			err = bc.CheckGenesis(blockchain.NetworkMainnet)
+		}
 		if err != nil {
 			log.Println("Blockchain dosyası okunamadı, yeni başlatılıyor:", err)
-			bc = blockchain.NewBlockchain(50, 25500000)
+			bc = nil
+		}
+	}
+	if bc == nil {
+		var err error
+		if bc, err = blockchain.NewBlockchain(blockchain.NetworkMainnet); err != nil {
+			log.Fatalf("Genesis: %v", err)
 		}
-	} else {
-		bc = blockchain.NewBlockchain(50, 25500000)
 	}
 
 	// Ana GUI arayüzünü başlat (hem cüzdan hem zincir parametreli)
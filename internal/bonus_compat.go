@@ -3,7 +3,6 @@ package internal
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 	"sync"
 	"time"
@@ -41,11 +40,7 @@ func GiveBonus(address, bonusType string, amount int, reason, metadata string) {
 		Timestamp: time.Now(),
 	}
 
-	// Konsola yaz
-	fmt.Printf("[BONUS] %s → %d QC (%s)\n", address, amount, bonusType)
-	if reason != "" {
-		fmt.Println(" Reason:", reason)
-	}
+	logger.Info("bonus awarded", "address", address, "amount", amount, "type", bonusType, "reason", reason)
 
 	// Hafızaya ekle
 	memBonusMu.Lock()
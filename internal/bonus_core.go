@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"quantumcoin/logging"
 )
 
+var logger = logging.For(logging.SubMiner)
+
 var (
 	bonusLog     []string
 	bonusLogLock sync.Mutex
@@ -20,7 +24,7 @@ func GiveBonusCore(address, bonusType string, amount int, reason string, txID st
 		time.Now().Format(time.RFC3339), address, amount, bonusType, reason, txID)
 	bonusLog = append(bonusLog, entry)
 
-	fmt.Println("🎁 Bonus awarded:", entry)
+	logger.Info("bonus awarded", "address", address, "amount", amount, "type", bonusType, "reason", reason, "txid", txID)
 }
 
 // ListBonusLog — mevcut bonus loglarını döndürür
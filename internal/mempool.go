@@ -5,6 +5,16 @@ import (
 	"sync"
 
 	"quantumcoin/blockchain"
+	"quantumcoin/metrics"
+)
+
+// Mempool metrikleri: tüm Mempool örneklerinin toplamı (zincirin kendi
+// mempool'u qc_mempool_* adlarıyla blockchain paketindedir)
+var (
+	mPoolSize     = metrics.NewGauge("qc_txpool_transactions", "Transactions held in internal mempools")
+	mPoolAdded    = metrics.NewCounter("qc_txpool_added_total", "Transactions added to internal mempools")
+	mPoolRejected = metrics.NewCounterVec("qc_txpool_rejected_total", "Transactions refused by internal mempools", "reason")
+	mPoolRemoved  = metrics.NewCounter("qc_txpool_removed_total", "Transactions popped or removed from internal mempools")
 )
 
 // Mempool: Zincir dışı bekleyen işlemleri tutar
@@ -49,6 +59,7 @@ func (mp *Mempool) Has(txID []byte) bool {
 // Add: Yeni işlem ekle (tekrarları engeller, kapasiteyi uygular)
 func (mp *Mempool) Add(tx *blockchain.Transaction) bool {
 	if tx == nil || len(tx.ID) == 0 {
+		mPoolRejected.With("invalid").Inc()
 		return false
 	}
 	key := string(tx.ID)
@@ -58,15 +69,19 @@ func (mp *Mempool) Add(tx *blockchain.Transaction) bool {
 
 	// kapasite kontrolü
 	if mp.capacity > 0 && len(mp.transactions) >= mp.capacity {
+		mPoolRejected.With("full").Inc()
 		return false
 	}
 	// tekrar kontrolü
 	if _, ok := mp.index[key]; ok {
+		mPoolRejected.With("duplicate").Inc()
 		return false
 	}
 
 	mp.transactions = append(mp.transactions, tx)
 	mp.index[key] = struct{}{}
+	mPoolAdded.Inc()
+	mPoolSize.Inc()
 	return true
 }
 
@@ -99,6 +114,8 @@ func (mp *Mempool) PopBatch(n int) []*blockchain.Transaction {
 	rest := make([]*blockchain.Transaction, len(mp.transactions)-n)
 	copy(rest, mp.transactions[n:])
 	mp.transactions = rest
+	mPoolRemoved.Add(float64(n))
+	mPoolSize.Add(-float64(n))
 	return batch
 }
 
@@ -116,6 +133,8 @@ func (mp *Mempool) RemoveTx(txID []byte) bool {
 		if bytes.Equal(tx.ID, txID) {
 			mp.transactions = append(mp.transactions[:i], mp.transactions[i+1:]...)
 			delete(mp.index, key)
+			mPoolRemoved.Inc()
+			mPoolSize.Dec()
 			return true
 		}
 	}
@@ -127,6 +146,8 @@ func (mp *Mempool) RemoveTx(txID []byte) bool {
 // Clear: Tüm işlemleri sil (blok kazıldığında çağrılır)
 func (mp *Mempool) Clear() {
 	mp.mu.Lock()
+	mPoolRemoved.Add(float64(len(mp.transactions)))
+	mPoolSize.Add(-float64(len(mp.transactions)))
 	mp.transactions = []*blockchain.Transaction{}
 	mp.index = make(map[string]struct{})
 	mp.mu.Unlock()
@@ -21,7 +21,7 @@ func (set *UTXOSet) Reindex(chain *blockchain.Blockchain) {
 	set.UTXOs = make(map[string][]blockchain.TransactionOutput)
 
 	// 1) Tüm çıktıları ekle
-	for _, b := range chain.Blocks {
+	for _, b := range chain.GetAllBlocks() {
 		for _, tx := range b.Transactions {
 			txID := hex.EncodeToString(tx.ID)
 			if _, ok := set.UTXOs[txID]; !ok {
@@ -32,7 +32,7 @@ func (set *UTXOSet) Reindex(chain *blockchain.Blockchain) {
 	}
 
 	// 2) Non-coinbase işlemlerin harcadığı çıktıları düş
-	for _, b := range chain.Blocks {
+	for _, b := range chain.GetAllBlocks() {
 		for _, tx := range b.Transactions {
 			if tx.IsCoinbase() {
 				continue
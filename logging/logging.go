@@ -0,0 +1,291 @@
+// Package logging: log/slog tabanlı, alt sistem bazında seviyeli ortak logger.
+// Her paket kendi alt sistemi için bir logger alır; seviyeler çalışırken
+// değiştirilebilir (SetLevel; HTTP'de POST /api/admin/loglevel, RPC'de "logging").
+//
+//	var logger = logging.For(logging.SubP2P)
+//	logger.Warn("peer refused", "peer", addr, "err", err)
+//
+// Setup çağrılmadan önce çıktı stderr'e metin biçimindedir ve seviye info'dur.
+// Setup standart log paketini de bu handler'a bağlar: log.Printf satırları
+// "node" alt sisteminde info seviyesinde yazılır.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Alt sistemler
+const (
+	SubNode    = "node" // main, komut satırı, olay yolu (events) ve standart log paketi
+	SubP2P     = "p2p"
+	SubChain   = "chain"
+	SubMiner   = "miner"
+	SubAPI     = "api"
+	SubWallet  = "wallet"
+	SubPool    = "pool"
+	SubStake   = "stake"
+	SubWebhook = "webhook"
+)
+
+// Subsystems: seviyesi ayarlanabilen alt sistemler
+var Subsystems = []string{SubNode, SubP2P, SubChain, SubMiner, SubAPI, SubWallet, SubPool, SubStake, SubWebhook}
+
+// AllSubsystems: SetLevel'de tüm alt sistemleri seçer
+const AllSubsystems = "all"
+
+// Options: Setup ayarları (config'teki log_* alanlarından doldurulur)
+type Options struct {
+	Level      string // "info" ya da "info,p2p=debug,miner=warn" (bkz. ParseLevels)
+	Format     string // text | json
+	File       string // "" => stderr
+	MaxSizeMB  int    // dosya bu boyutu aşınca döndürülür; 0 => döndürme yok
+	MaxBackups int    // saklanan eski dosya sayısı (file.1 .. file.N)
+}
+
+var (
+	levels = map[string]*slog.LevelVar{}
+
+	mu      sync.Mutex // out, closer
+	out     io.Writer  = os.Stderr
+	closer  io.Closer
+	base    atomic.Pointer[baseHandler] // biçim + çıktı; Setup değiştirir
+	loggers sync.Map                    // alt sistem -> *slog.Logger
+)
+
+type baseHandler struct {
+	h   slog.Handler
+	gen uint64
+}
+
+func init() {
+	for _, s := range Subsystems {
+		levels[s] = new(slog.LevelVar)
+	}
+	base.Store(&baseHandler{h: slog.NewTextHandler(lockedWriter{}, nil), gen: 1})
+}
+
+// Setup: biçimi, çıktıyı ve seviyeleri uygular; standart log paketini yönlendirir.
+// Tekrar çağrılabilir (önceki log dosyası kapatılır).
+func Setup(o Options) error {
+	lv, err := ParseLevels(o.Level)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stderr
+	var c io.Closer
+	if o.File != "" {
+		rf, err := openRotating(o.File, int64(o.MaxSizeMB)<<20, o.MaxBackups)
+		if err != nil {
+			return err
+		}
+		w, c = rf, rf
+	}
+	var h slog.Handler
+	switch strings.ToLower(o.Format) {
+	case "", "text":
+		h = slog.NewTextHandler(lockedWriter{}, &slog.HandlerOptions{Level: slog.LevelDebug})
+	case "json":
+		h = slog.NewJSONHandler(lockedWriter{}, &slog.HandlerOptions{Level: slog.LevelDebug})
+	default:
+		if c != nil {
+			c.Close()
+		}
+		return fmt.Errorf("logging: unknown format %q (text, json)", o.Format)
+	}
+
+	mu.Lock()
+	old := closer
+	out, closer = w, c
+	for s, l := range lv {
+		levels[s].Set(l)
+	}
+	base.Store(&baseHandler{h: h, gen: base.Load().gen + 1})
+	mu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	slog.SetDefault(For(SubNode))
+	return nil
+}
+
+// lockedWriter: o anki çıktıya yazar (Setup çıktıyı değiştirebilir)
+type lockedWriter struct{}
+
+func (lockedWriter) Write(p []byte) (int, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return out.Write(p)
+}
+
+// For: alt sistemin logger'ı (kayıtlara sub=<ad> eklenir). Bilinmeyen ad
+// "node" seviyesini kullanır.
+func For(sub string) *slog.Logger {
+	if l, ok := loggers.Load(sub); ok {
+		return l.(*slog.Logger)
+	}
+	lv := levels[sub]
+	if lv == nil {
+		lv = levels[SubNode]
+	}
+	l, _ := loggers.LoadOrStore(sub, slog.New(&subHandler{level: lv, attrs: []slog.Attr{slog.String("sub", sub)}}))
+	return l.(*slog.Logger)
+}
+
+// ----- seviyeler -----
+
+// ParseLevel: debug | info | warn | error
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "warning":
+		s = "warn"
+	case "":
+		return slog.LevelInfo, nil
+	}
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("logging: unknown level %q (debug, info, warn, error)", s)
+	}
+	return l, nil
+}
+
+// ParseLevels: "info" ya da "warn,p2p=debug,api=error" -> alt sistem başına seviye.
+// Çıplak seviye tüm alt sistemlere, "ad=seviye" yalnız o alt sisteme uygulanır.
+func ParseLevels(spec string) (map[string]slog.Level, error) {
+	out := map[string]slog.Level{}
+	for _, s := range Subsystems {
+		out[s] = slog.LevelInfo
+	}
+	var overrides [][2]string
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if sub, lvl, ok := strings.Cut(part, "="); ok {
+			overrides = append(overrides, [2]string{strings.TrimSpace(sub), lvl})
+			continue
+		}
+		l, err := ParseLevel(part)
+		if err != nil {
+			return nil, err
+		}
+		for s := range out {
+			out[s] = l
+		}
+	}
+	for _, o := range overrides {
+		if _, ok := out[o[0]]; !ok {
+			return nil, fmt.Errorf("logging: unknown subsystem %q (%s)", o[0], strings.Join(Subsystems, ", "))
+		}
+		l, err := ParseLevel(o[1])
+		if err != nil {
+			return nil, err
+		}
+		out[o[0]] = l
+	}
+	return out, nil
+}
+
+// SetLevel: alt sistemin (ya da AllSubsystems ile hepsinin) seviyesini değiştirir
+func SetLevel(sub, level string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	if sub == AllSubsystems || sub == "" {
+		for _, lv := range levels {
+			lv.Set(l)
+		}
+		return nil
+	}
+	lv := levels[sub]
+	if lv == nil {
+		return fmt.Errorf("logging: unknown subsystem %q (%s)", sub, strings.Join(Subsystems, ", "))
+	}
+	lv.Set(l)
+	return nil
+}
+
+// Levels: alt sistem -> seviye adı ("debug", "info", ...)
+func Levels() map[string]string {
+	m := make(map[string]string, len(levels))
+	for s, lv := range levels {
+		m[s] = strings.ToLower(lv.Level().String())
+	}
+	return m
+}
+
+// LevelsString: Levels'in ParseLevels biçimi (sıralı)
+func LevelsString() string {
+	m := Levels()
+	parts := make([]string, 0, len(m))
+	for s, l := range m {
+		parts = append(parts, s+"="+l)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// ----- handler -----
+
+// subHandler: alt sistem seviyesini uygular, kaydı o anki temel handler'a iletir.
+// WithAttrs/WithGroup işlemleri saklanır ve temel handler değişince yeniden kurulur.
+type subHandler struct {
+	level *slog.LevelVar
+	attrs []slog.Attr
+	ops   []func(slog.Handler) slog.Handler
+
+	cache atomic.Pointer[derived]
+}
+
+type derived struct {
+	gen uint64
+	h   slog.Handler
+}
+
+func (h *subHandler) Enabled(_ context.Context, l slog.Level) bool { return l >= h.level.Level() }
+
+func (h *subHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(ctx, r)
+}
+
+func (h *subHandler) handler() slog.Handler {
+	b := base.Load()
+	if d := h.cache.Load(); d != nil && d.gen == b.gen {
+		return d.h
+	}
+	out := b.h.WithAttrs(h.attrs)
+	for _, op := range h.ops {
+		out = op(out)
+	}
+	h.cache.Store(&derived{gen: b.gen, h: out})
+	return out
+}
+
+func (h *subHandler) with(op func(slog.Handler) slog.Handler) *subHandler {
+	ops := append(append([]func(slog.Handler) slog.Handler{}, h.ops...), op)
+	return &subHandler{level: h.level, attrs: h.attrs, ops: ops}
+}
+
+func (h *subHandler) WithAttrs(as []slog.Attr) slog.Handler {
+	if len(as) == 0 {
+		return h
+	}
+	return h.with(func(x slog.Handler) slog.Handler { return x.WithAttrs(as) })
+}
+
+func (h *subHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(func(x slog.Handler) slog.Handler { return x.WithGroup(name) })
+}
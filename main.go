@@ -1,35 +1,41 @@
 package main
 
 import (
+	"bytes"
 	"context"
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
-	"math/bits"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	// not: runtime is unnecessary here
-
 	"quantumcoin/ai"
+	"quantumcoin/api"
+	"quantumcoin/api/wire"
 	"quantumcoin/blockchain"
 	"quantumcoin/config"
+	"quantumcoin/events"
 	"quantumcoin/game"
 	"quantumcoin/internal"
+	"quantumcoin/logging"
+	"quantumcoin/miner"
 	"quantumcoin/p2p"
+	"quantumcoin/pool"
+	"quantumcoin/stake"
+	"quantumcoin/telegram_game"
 	"quantumcoin/wallet"
+	"quantumcoin/webhook"
 	"quantumcoin/webui"
 )
 
@@ -42,51 +48,18 @@ const (
 
 /* ---------- API types ---------- */
 
-type WalletResponse struct {
-	Address string `json:"address"`
-}
-type BalanceResponse struct {
-	Balance   float64 `json:"balance"`
-	Spendable float64 `json:"spendable"`
-	Height    int     `json:"height"`
-}
-type MineRequest struct {
-	Address string `json:"address"`
-}
-type WebMineJobResp struct {
-	Challenge  string `json:"challenge"`
-	Difficulty int    `json:"difficulty"`
-	Miner      string `json:"miner"`
-	Height     int    `json:"height"`
-	Expires    int64  `json:"expires"`
-}
-type WebMineSubmitReq struct {
-	Address   string `json:"address"`
-	Challenge string `json:"challenge"`
-	Nonce     uint64 `json:"nonce"`
-}
-type WebMineSubmitResp struct {
-	Accepted bool   `json:"accepted"`
-	Hash     string `json:"hash"`
-	Message  string `json:"message,omitempty"`
-}
-
-/* 🟢 GÜNCEL: imzalı gönderim için priv_hex eklendi */
-type SendRequest struct {
-	From    string `json:"from"`
-	To      string `json:"to"`
-	Amount  int    `json:"amount"`
-	PrivHex string `json:"priv_hex,omitempty"`
-}
-type SendResponse struct {
-	Success bool   `json:"success"`
-	TxID    string `json:"txid"`
-	Message string `json:"message,omitempty"`
-}
-type BurnRequest struct {
-	From   string `json:"from"`
-	Amount int    `json:"amount"`
-}
+// Gövde tipleri api/wire'da (OpenAPI belgesi ve api/client ile ortak)
+type (
+	WalletResponse    = wire.WalletResponse
+	BalanceResponse   = wire.BalanceResponse
+	MineRequest       = wire.AddressRequest
+	WebMineJobResp    = wire.MineJob
+	WebMineSubmitReq  = wire.WorkSubmit
+	WebMineSubmitResp = wire.MineSubmitResult
+	SendRequest       = wire.SendRequest
+	SendResponse      = wire.SendResponse
+	BurnRequest       = wire.BurnRequest
+)
 
 /* ---------- Globals ---------- */
 
@@ -95,22 +68,33 @@ var (
 	gameState  = game.NewGameState()
 	cfg        *config.Config
 	httpServer *http.Server
-)
+	saveMu     sync.Mutex // autosave ve kapanış kaydı aynı dosyaya yazar
 
-/* web miner job state */
+	// alt sistem logger'ları (komut satırı çıktısı fmt/log ile kalır)
+	nodeLog  = logging.For(logging.SubNode)
+	chainLog = logging.For(logging.SubChain)
+	minerLog = logging.For(logging.SubMiner)
+	apiLog   = logging.For(logging.SubAPI)
+)
 
-type webJob struct {
-	Challenge  []byte
-	Difficulty int
-	Miner      string
-	Height     int
-	ExpiresAt  time.Time
+// saveChain: zinciri cfg.ChainFile'a yazar (eşzamanlı kayıtlar sıralanır)
+func saveChain() error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	return bc.SaveToFile(cfg.ChainFile)
 }
 
+/* web miner / stratum state */
+
 var (
-	jobMu     sync.Mutex
-	curJob    *webJob
-	minerStop chan struct{}
+	minerMu      sync.Mutex // minerStop'u HTTP/RPC kontrolü ve kapanışta korur
+	minerStop    chan struct{}
+	mineMeter    miner.HashMeter // düğüm içi madencinin ölçülen hashrate'i
+	mineRestarts atomic.Uint64   // bayatlayıp yeniden başlatılan aramalar
+
+	soloBackend miner.Backend  // canlı zincir üzerinde solo işler (web + stratum)
+	webJobs     *miner.WebJobs // /api/mine/job|submit (havuz kapalıyken)
+	minePool    *pool.Pool     // havuz modu (cfg.PoolEnabled); nil => solo web madenci
 )
 
 /* ---------- helpers ---------- */
@@ -127,6 +111,28 @@ func printUsage() {
 	fmt.Println("  newaddr                  - Generate wallet address")
 	fmt.Println("  newaddr-priv             - Generate wallet + print private key (hex)")
 	fmt.Println("  api                      - Start HTTP API")
+	fmt.Println("  psbt-create [from] [to] [amt] [file]   - Create unsigned PSBT file")
+	fmt.Println("  psbt-inspect [file]                    - Show PSBT inputs/outputs/signatures")
+	fmt.Println("  psbt-sign [file] [privhex]             - Sign own inputs (offline safe; no key = wallet store)")
+	fmt.Println("  psbt-combine [out] [file1] [file2...]  - Merge partial signatures")
+	fmt.Println("  psbt-finalize [file]                   - Finalize, submit and broadcast")
+	fmt.Println("  multisig-new [m] [pubhex...]           - Create m-of-n address and save descriptor")
+	fmt.Println("  multisig-import [m:pubhex,...]         - Register a co-signer's descriptor")
+	fmt.Println("  multisig-list                          - List known multisig addresses")
+	fmt.Println("  stake [addr] [amt] [lockBlocks]        - Lock coins as stake (weight for stake pool rewards)")
+	fmt.Println("  unstake [addr] [txid] [index]          - Start cooldown for an unlocked stake")
+	fmt.Println("  withdraw [addr]                        - Release stakes whose cooldown has ended")
+	fmt.Println("  stakes [addr]                          - List stakes and totals")
+	fmt.Println("  burn [addr] [amt]                      - Burn coins (provably unspendable output)")
+	fmt.Println("  supply                                 - Minted / burned / circulating supply and recent burns")
+	fmt.Println("  rpc [-url U] <method> [params...]      - Call a running node's JSON-RPC (rpc help = method list)")
+	fmt.Println("  webhook-listen [addr] [secret]         - Local webhook receiver: verify signatures and print notifications")
+	fmt.Println("  openapi [file]                         - Write the node's OpenAPI 3 document (default: stdout)")
+	fmt.Println("  apikey-new [name] [scopes] [ratePerMin] - Create HTTP API key (scopes: read,wallet,mining,admin)")
+	fmt.Println("  apikey-list                            - List HTTP API keys")
+	fmt.Println("  apikey-revoke [name]                   - Revoke HTTP API key")
+	fmt.Println("Flags:")
+	fmt.Println("  -network mainnet|testnet|regtest       - Network profile (ports, addresses, genesis, data dir)")
 }
 
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
@@ -241,6 +247,28 @@ func ensureMinerAddress() (string, error) {
 
 /* ---------- main ---------- */
 
+// takeNetworkFlag: "-network X" / "--network=X" bayrağını os.Args'tan çıkarır
+// (alt komutlar konumsal olduğu için flag paketi kullanılmaz)
+func takeNetworkFlag() string {
+	network := ""
+	args := os.Args[:1]
+	for i := 1; i < len(os.Args); i++ {
+		a := os.Args[i]
+		name, val, hasVal := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		if !strings.HasPrefix(a, "-") || name != "network" {
+			args = append(args, a)
+			continue
+		}
+		if !hasVal && i+1 < len(os.Args) {
+			i++
+			val = os.Args[i]
+		}
+		network = val
+	}
+	os.Args = args
+	return network
+}
+
 func main() {
 	var err error
 
@@ -248,10 +276,67 @@ func main() {
 		_ = os.Chdir(filepath.Dir(exe))
 	}
 
+	network := takeNetworkFlag()
 	cfg, err = config.Load("config.json")
 	if err != nil {
 		log.Fatalf("Config yüklenemedi: %v", err)
 	}
+	if network != "" {
+		if err = cfg.UseNetwork(network); err == nil {
+			err = cfg.Validate()
+		}
+		if err != nil {
+			log.Fatalf("Ağ seçilemedi: %v", err)
+		}
+		config.Set(cfg)
+	}
+	params := cfg.Params()
+	wallet.UseNetwork(params)
+	if params.DataDir != "" {
+		if err = os.MkdirAll(params.DataDir, 0o700); err != nil {
+			log.Fatalf("Veri dizini: %v", err)
+		}
+	}
+	if err = logging.Setup(logging.Options{
+		Level:      cfg.LogLevel,
+		Format:     cfg.LogFormat,
+		File:       cfg.LogFile,
+		MaxSizeMB:  cfg.LogMaxSizeMB,
+		MaxBackups: cfg.LogMaxBackups,
+	}); err != nil {
+		log.Fatalf("Log ayarları: %v", err)
+	}
+	nodeLog.Info("network selected", "network", params.Name, "http", cfg.HTTPPort, "p2p", cfg.P2PPort, "bits", cfg.DefaultDifficultyBits)
+
+	// Multisig dev fonu / community: ağın descriptor'ları cüzdana (co-signing)
+	if err := wallet.RegisterFundMultisigs(params.Reward); err != nil {
+		log.Fatalf("Ağın fon descriptor'ı hatalı: %v", err)
+	}
+
+	// RPC istemcisi çalışan düğüme bağlanır; yerel zinciri yüklemez
+	if len(os.Args) >= 2 && os.Args[1] == "rpc" {
+		runRPCClient(os.Args[2:])
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "webhook-listen" {
+		runWebhookListener(os.Args[2:])
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "openapi" {
+		b, _ := json.MarshalIndent(api.OpenAPI(nodeOpenAPI()), "", "  ")
+		if len(os.Args) >= 3 {
+			if err := os.WriteFile(os.Args[2], b, 0o644); err != nil {
+				log.Fatalf("openapi: %v", err)
+			}
+			return
+		}
+		fmt.Println(string(b))
+		return
+	}
+	if len(os.Args) >= 2 && strings.HasPrefix(os.Args[1], "apikey-") {
+		runAPIKeyCommand(os.Args[1], os.Args[2:])
+		return
+	}
 
 	internal.SetBonusFile(cfg.BonusFile)
 
@@ -260,20 +345,76 @@ func main() {
 		if err != nil {
 			log.Fatalf("Blockchain yüklenemedi: %v", err)
 		}
-	} else {
-		bc = blockchain.NewBlockchain(cfg.InitialReward, cfg.TotalSupply)
+		if err = bc.CheckGenesis(params.Name); err != nil {
+			log.Fatalf("Zincir dosyası %s bu ağa ait değil: %v", cfg.ChainFile, err)
+		}
+	} else if bc, err = blockchain.NewBlockchain(params.Name); err != nil {
+		log.Fatalf("Genesis: %v", err)
 	}
 
 	bc.SetCoinbaseMaturity(cfg.CoinbaseMaturity)
+	if cfg.TxIndex {
+		start := time.Now()
+		bc.EnableIndex()
+		chainLog.Info("tx index built", "blocks", bc.GetHeight()+1, "took", time.Since(start).Round(time.Millisecond))
+	}
+	bc.SetObserver(events.ChainObserver(events.Default))
+	subscribeNodeEvents()
+
+	api.Init(bc, nil, cfg)
+	api.TxBroadcaster = func(tx *blockchain.Transaction) { p2p.BroadcastMessage(p2p.TxMessage(tx)) }
+	api.BlockSubmitted = func(blk *blockchain.Block) { p2p.BroadcastMessage(p2p.BlockMessage(blk)) }
+	api.MinerControl = minerControl
+	soloBackend, err = miner.NewChainBackend(bc, miner.ChainBackendOpts{
+		Difficulty: func() int { return cfg.DefaultDifficultyBits },
+		OnBlock:    func(blk *blockchain.Block) { p2p.BroadcastMessage(p2p.BlockMessage(blk)) },
+	})
+	if err != nil {
+		log.Fatalf("miner backend: %v", err)
+	}
+	webJobs = miner.NewWebJobs(soloBackend)
+	api.WebJobs = webJobs
+
+	if cfg.PoolEnabled {
+		opt := pool.OptionsFromConfig(cfg)
+		opt.Broadcast = api.TxBroadcaster
+		opt.OnBlock = func(blk *blockchain.Block) { p2p.BroadcastMessage(p2p.BlockMessage(blk)) }
+		minePool, err = pool.New(bc, opt)
+		if err != nil {
+			log.Fatalf("Havuz başlatılamadı: %v", err)
+		}
+		api.Pool = minePool
+		go minePool.Run(context.Background(), 30*time.Second)
+		nodeLog.Info("pool mode enabled", "address", cfg.PoolAddress, "scheme", cfg.PoolScheme, "fee_pct", cfg.PoolFeePct)
+	}
+
+	// Stake havuzu dağıtımı (coinbase stake payı -> stake sahipleri)
+	if strings.TrimSpace(cfg.Params().Reward.StakePool) != "" {
+		opt := stake.OptionsFromConfig(cfg)
+		opt.Broadcast = api.TxBroadcaster
+		dist, err := stake.New(bc, opt)
+		if err != nil {
+			log.Fatalf("Stake havuzu başlatılamadı: %v", err)
+		}
+		api.StakePool = dist
+		go dist.Run(context.Background(), 30*time.Second)
+	}
+
+	// Ödeme webhook'ları (kayıtlar ve teslim kuyruğu cfg.WebhookStateFile'da)
+	hooks, err := webhook.New(bc, webhook.OptionsFromConfig(cfg))
+	if err != nil {
+		log.Fatalf("Webhook servisi başlatılamadı: %v", err)
+	}
+	api.Webhooks = hooks
+	go hooks.Run(context.Background())
 
 	/* auto mode: no args -> node + api + mining */
 	if len(os.Args) < 2 {
 		minerAddr := getDefaultAddress()
-		fmt.Printf("⛏️  Auto mode: node+api+mining -> %s (difficulty=%d)\n", minerAddr, cfg.DefaultDifficultyBits)
+		nodeLog.Info("auto mode: node+api+mining", "miner", minerAddr, "bits", cfg.DefaultDifficultyBits)
 		minerStop = make(chan struct{})
 		go startHTTPAPI()
-		go autosaveLoop()
-		go startContinuousMining(minerAddr)
+		go startContinuousMining(minerAddr, minerStop)
 		go trapAndShutdown()
 		p := strings.TrimPrefix(cfg.P2PPort, ":")
 		p2p.RunNode(p, bc)
@@ -285,12 +426,10 @@ func main() {
 		if len(os.Args) >= 3 {
 			port := os.Args[2]
 			go startHTTPAPI()
-			go autosaveLoop()
 			go trapAndShutdown()
 			p2p.RunNode(port, bc)
 		} else {
 			go startHTTPAPI()
-			go autosaveLoop()
 			go trapAndShutdown()
 			p := strings.TrimPrefix(cfg.P2PPort, ":")
 			p2p.RunNode(p, bc)
@@ -304,14 +443,12 @@ func main() {
 		miner := os.Args[2]
 		minerStop = make(chan struct{})
 		go startHTTPAPI()
-		go autosaveLoop()
-		go startContinuousMining(miner)
+		go startContinuousMining(miner, minerStop)
 		go trapAndShutdown()
 		p := strings.TrimPrefix(cfg.P2PPort, ":")
 		p2p.RunNode(p, bc)
 
 	case "api":
-		go autosaveLoop()
 		go trapAndShutdown()
 		startHTTPAPI()
 
@@ -323,7 +460,6 @@ func main() {
 		port := os.Args[2]
 		address := os.Args[3]
 		go startHTTPAPI()
-		go autosaveLoop()
 		go trapAndShutdown()
 		p2p.ConnectToPeer(port, address, bc)
 
@@ -351,6 +487,89 @@ func main() {
 		p2p.BroadcastMessage(p2p.TxMessage(tx))
 		fmt.Printf("✓ Transaction accepted and broadcasted (txid=%s)\n", hex.EncodeToString(tx.ID))
 
+	case "stake":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: stake [address] [amount] [lockBlocks]")
+			return
+		}
+		amount, err := strconv.Atoi(os.Args[3])
+		if err != nil || amount <= 0 {
+			fmt.Println("Invalid amount")
+			return
+		}
+		lock := 0
+		if len(os.Args) >= 5 {
+			if lock, err = strconv.Atoi(os.Args[4]); err != nil || lock < 0 {
+				fmt.Println("Invalid lockBlocks")
+				return
+			}
+		}
+		tx, err := blockchain.NewStakeTransaction(os.Args[2], amount, lock, bc)
+		submitSigned(tx, err, os.Args[2])
+
+	case "unstake":
+		if len(os.Args) < 5 {
+			fmt.Println("Usage: unstake [address] [txid] [index]")
+			return
+		}
+		idx, err := strconv.Atoi(os.Args[4])
+		if err != nil || idx < 0 {
+			fmt.Println("Invalid index")
+			return
+		}
+		tx, err := blockchain.NewUnstakeTransaction(os.Args[2], os.Args[3], idx, bc)
+		submitSigned(tx, err, os.Args[2])
+
+	case "withdraw":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: withdraw [address]")
+			return
+		}
+		tx, err := blockchain.NewWithdrawTransaction(os.Args[2], bc)
+		submitSigned(tx, err, os.Args[2])
+
+	case "burn":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: burn [address] [amount]")
+			return
+		}
+		amount, err := strconv.Atoi(os.Args[3])
+		if err != nil || amount <= 0 {
+			fmt.Println("Invalid amount")
+			return
+		}
+		tx, err := blockchain.NewBurnTransaction(os.Args[2], amount, bc)
+		submitSigned(tx, err, os.Args[2])
+
+	case "supply":
+		s := bc.Supply()
+		fmt.Printf("Height %d  minted %d  burned %d  circulating %d  fees destroyed %d QC\n",
+			s.Height, s.Minted, s.Burned, s.Circulating, s.FeesDestroyed)
+		for _, b := range bc.Burns(20) {
+			src := b.From
+			if b.Coinbase {
+				src = "coinbase"
+			}
+			fmt.Printf("  🔥 #%d  %s:%d  %d QC  (%s)\n", b.Height, b.TxID, b.Index, b.Amount, src)
+		}
+		return
+
+	case "stakes":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: stakes [address]")
+			return
+		}
+		if _, _, err := wallet.DecodeAddress(os.Args[2]); err != nil {
+			fmt.Println("Invalid address:", err)
+			return
+		}
+		s := api.StakeSummary(os.Args[2])
+		fmt.Printf("Height %d  active %d  unbonding %d  withdrawable %d QC\n", s.Height, s.Active, s.Unbonding, s.Withdrawable)
+		for _, st := range s.Stakes {
+			fmt.Printf("  %s:%d  %-9s %d QC  unlock @%d\n", st.TxID, st.Index, st.Status, st.Amount, st.UnlockHeight)
+		}
+		return
+
 	case "mine":
 		if len(os.Args) < 3 {
 			fmt.Println("Usage: mine [miner]")
@@ -368,7 +587,7 @@ func main() {
 		fmt.Printf("   Hash:   %s%s%s\n", ansiCyan, hex.EncodeToString(block.Hash), ansiReset)
 		fmt.Printf("   Height: %d  Reward: %d QC\n", bc.GetBestHeight(), blockchain.GetCurrentReward())
 		processAIBonus()
-		_ = bc.SaveToFile(cfg.ChainFile)
+		_ = saveChain()
 
 	case "mine-forever":
 		if len(os.Args) < 3 {
@@ -377,12 +596,11 @@ func main() {
 		}
 		miner := os.Args[2]
 		minerStop = make(chan struct{})
-		go autosaveLoop()
 		go trapAndShutdown()
-		startContinuousMining(miner)
+		startContinuousMining(miner, minerStop)
 
 	case "print":
-		for _, block := range bc.Blocks {
+		for _, block := range bc.GetAllBlocks() {
 			fmt.Printf("📦 Block #%d\n", block.Index)
 			fmt.Printf("⛏️  Miner     : %s\n", block.Miner)
 			fmt.Printf("🧱 Hash       : %s\n", hex.EncodeToString(block.Hash))
@@ -391,21 +609,190 @@ func main() {
 			for _, tx := range block.Transactions {
 				fmt.Printf("  TxID: %s\n", hex.EncodeToString(tx.ID))
 				for _, out := range tx.Outputs {
+					if out.LockType == blockchain.LockBurn {
+						fmt.Printf("    🔥 Burned: %d QC\n", out.Amount)
+						continue
+					}
 					fmt.Printf("    🔸 Amount: %d QC\n", out.Amount)
 				}
 			}
 			fmt.Println("-------------------------------")
 		}
 
+	case "psbt-create":
+		if len(os.Args) < 6 {
+			fmt.Println("Usage: psbt-create [from] [to] [amount] [file]")
+			return
+		}
+		amount, err := strconv.Atoi(os.Args[4])
+		if err != nil || amount <= 0 {
+			fmt.Println("Invalid amount")
+			return
+		}
+		p, err := blockchain.NewPSBT(os.Args[2], os.Args[3], amount, bc)
+		if err != nil {
+			log.Println("psbt create failed:", err)
+			return
+		}
+		if err := p.SaveToFile(os.Args[5]); err != nil {
+			log.Println("psbt save failed:", err)
+			return
+		}
+		fmt.Printf("✓ PSBT written to %s\n", os.Args[5])
+		return
+
+	case "psbt-inspect":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: psbt-inspect [file]")
+			return
+		}
+		p, err := blockchain.LoadPSBTFromFile(os.Args[2])
+		if err != nil {
+			log.Println("psbt load failed:", err)
+			return
+		}
+		sum, err := p.Inspect()
+		if err != nil {
+			log.Println("psbt inspect failed:", err)
+			return
+		}
+		fmt.Printf("PSBT v%d  txid=%s\n", sum.Version, sum.TxID)
+		for i, in := range sum.Inputs {
+			fmt.Printf("  in  #%d %s:%d  %d QC  owner=%s  sigs=%d signed=%v\n", i, in.TxID, in.N, in.Amount, in.Owner, in.Sigs, in.Signed)
+		}
+		for i, out := range sum.Outputs {
+			fmt.Printf("  out #%d %d QC -> %s\n", i, out.Amount, out.Address)
+		}
+		fmt.Printf("  in=%d out=%d fee=%d complete=%v\n", sum.InTotal, sum.OutTotal, sum.Fee, sum.Complete)
+		return
+
+	case "psbt-sign":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: psbt-sign [file] [privhex]")
+			return
+		}
+		p, err := blockchain.LoadPSBTFromFile(os.Args[2])
+		if err != nil {
+			log.Println("psbt load failed:", err)
+			return
+		}
+		var n int
+		if len(os.Args) >= 4 {
+			priv, err := wallet.ImportPrivateKeyHex(os.Args[3])
+			if err != nil {
+				log.Println("invalid private key:", err)
+				return
+			}
+			n, err = p.Sign(priv)
+		} else {
+			n, err = p.SignWithStore()
+		}
+		if err != nil {
+			log.Println("psbt sign failed:", err)
+			return
+		}
+		if err := p.SaveToFile(os.Args[2]); err != nil {
+			log.Println("psbt save failed:", err)
+			return
+		}
+		fmt.Printf("✓ Signed %d input(s) in %s\n", n, os.Args[2])
+		return
+
+	case "psbt-combine":
+		if len(os.Args) < 5 {
+			fmt.Println("Usage: psbt-combine [out] [file1] [file2...]")
+			return
+		}
+		var parts []*blockchain.PSBT
+		for _, f := range os.Args[3:] {
+			p, err := blockchain.LoadPSBTFromFile(f)
+			if err != nil {
+				log.Printf("psbt load failed (%s): %v", f, err)
+				return
+			}
+			parts = append(parts, p)
+		}
+		p, err := blockchain.CombinePSBT(parts...)
+		if err != nil {
+			log.Println("psbt combine failed:", err)
+			return
+		}
+		if err := p.SaveToFile(os.Args[2]); err != nil {
+			log.Println("psbt save failed:", err)
+			return
+		}
+		fmt.Printf("✓ Combined %d PSBT(s) into %s\n", len(parts), os.Args[2])
+		return
+
+	case "psbt-finalize":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: psbt-finalize [file]")
+			return
+		}
+		p, err := blockchain.LoadPSBTFromFile(os.Args[2])
+		if err != nil {
+			log.Println("psbt load failed:", err)
+			return
+		}
+		tx, err := p.Finalize()
+		if err != nil {
+			log.Println("psbt finalize failed:", err)
+			return
+		}
+		if err := bc.AddTransaction(tx); err != nil {
+			log.Println("tx submit failed:", err)
+			return
+		}
+		p2p.BroadcastMessage(p2p.TxMessage(tx))
+		fmt.Printf("✓ Transaction accepted and broadcasted (txid=%s)\n", hex.EncodeToString(tx.ID))
+
+	case "multisig-new", "multisig-import":
+		var d *wallet.MultisigDescriptor
+		if os.Args[1] == "multisig-import" && len(os.Args) == 3 {
+			d, err = wallet.ParseMultisigSpec(os.Args[2])
+		} else if len(os.Args) >= 4 {
+			d, err = wallet.ParseMultisigSpec(os.Args[2] + ":" + strings.Join(os.Args[3:], ","))
+		} else {
+			fmt.Println("Usage: multisig-new [m] [pubhex1] [pubhex2...] | multisig-import [m:pubhex,...]")
+			return
+		}
+		if err != nil {
+			log.Println("invalid multisig descriptor:", err)
+			return
+		}
+		if err := wallet.SaveMultisig(d); err != nil {
+			log.Println("multisig save failed:", err)
+			return
+		}
+		fmt.Printf("Multisig Address (%d-of-%d): %s\n", d.M, len(d.PubKeys), d.Address())
+		fmt.Println("Descriptor:", d.String())
+		return
+
+	case "multisig-list":
+		for _, addr := range wallet.ListMultisig() {
+			if d, ok := wallet.LookupMultisig(addr); ok {
+				fmt.Printf("%s  %d-of-%d\n", addr, d.M, len(d.PubKeys))
+			}
+		}
+		return
+
 	case "newaddr":
 		w := wallet.NewWallet()
 		address := w.GetAddress()
+		if err := wallet.SaveWallet(w); err != nil {
+			log.Println("wallet save failed:", err)
+		}
 		fmt.Println("New Wallet Address:", address)
+		fmt.Println("PublicKey (hex):", hex.EncodeToString(w.PublicKey))
 
 	case "newaddr-priv":
 		w := wallet.NewWallet()
 		address := w.GetAddress()
+		if err := wallet.SaveWallet(w); err != nil {
+			log.Println("wallet save failed:", err)
+		}
 		fmt.Println("New Wallet Address:", address)
+		fmt.Println("PublicKey (hex):", hex.EncodeToString(w.PublicKey))
 		fmt.Println("PrivateKey (hex):", w.ExportPrivateKeyHex())
 		return
 
@@ -413,63 +800,145 @@ func main() {
 		printUsage()
 	}
 
-	if err := bc.SaveToFile(cfg.ChainFile); err != nil {
+	if err := saveChain(); err != nil {
 		log.Fatalf("Blockchain kaydedilemedi: %v", err)
 	}
 }
 
 /* ---------- mining loops ---------- */
 
-func startContinuousMining(miner string) {
-	fmt.Printf("⛏️  Continuous mining started for %s (difficulty=%d)\n", miner, cfg.DefaultDifficultyBits)
-	for {
+// startContinuousMining: stop (başlatan minerStop) kapanana dek cfg.MinerThreads goroutine ile kazar.
+// Peer'den yeni blok ya da yeni mempool işlemi gelince arama baştan başlar.
+func startContinuousMining(addr string, stop chan struct{}) {
+	threads := minerThreads()
+	minerLog.Info("continuous mining started", "address", addr, "bits", cfg.DefaultDifficultyBits, "threads", threads)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
 		select {
-		case <-minerStop:
-			fmt.Println("🛑 Miner stopped.")
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	publishMinerStatus(true, nil)
+	for {
+		blk, err := miner.MineContext(ctx, bc, addr, cfg.DefaultDifficultyBits, miner.MineConfig{
+			Threads:   threads,
+			Meter:     &mineMeter,
+			OnRestart: func() { mineRestarts.Add(1) },
+		})
+		if ctx.Err() != nil {
+			minerLog.Info("continuous mining stopped", "address", addr)
+			publishMinerStatus(false, nil)
 			return
-		default:
-			blk, err := bc.MineBlock(miner, cfg.DefaultDifficultyBits)
-			if err != nil {
-				log.Printf("mine error: %v", err)
-				time.Sleep(500 * time.Millisecond)
-				continue
-			}
-			p2p.BroadcastMessage(p2p.BlockMessage(blk))
-			fmt.Printf(ansiGreen+"✅ Block #%d mined"+ansiReset+"  Hash: %s%s%s\n",
-				blk.Index, ansiCyan, hex.EncodeToString(blk.Hash), ansiReset)
-			processAIBonus()
-			_ = bc.SaveToFile(cfg.ChainFile)
-			time.Sleep(50 * time.Millisecond)
 		}
+		if err != nil {
+			minerLog.Warn("mine failed", "address", addr, "err", err)
+			time.Sleep(500 * time.Millisecond)
+			continue
+		}
+		p2p.BroadcastMessage(p2p.BlockMessage(blk))
+		minerLog.Info("block mined", "height", blk.Index, "hash", hex.EncodeToString(blk.Hash), "hashrate", int(mineMeter.Rate()))
+		publishMinerStatus(true, blk)
 	}
 }
 
-func autosaveLoop() {
-	t := time.NewTicker(10 * time.Second)
-	defer t.Stop()
-	for range t.C {
-		if err := bc.SaveToFile(cfg.ChainFile); err != nil {
-			log.Println("autosave error:", err)
-		}
+// publishMinerStatus: düğüm içi madencinin durumunu olay veri yoluna yayınlar
+func publishMinerStatus(active bool, blk *blockchain.Block) {
+	st := events.MinerStatus{
+		Active:   active,
+		Threads:  minerThreads(),
+		Hashrate: mineMeter.Rate(),
+		Hashes:   mineMeter.Total(),
+		Block:    blk,
+	}
+	if blk != nil {
+		st.Reward = blk.Transactions[0].Outputs[0].Amount
 	}
+	events.Default.Publish(st)
+}
+
+// minerThreads: cfg.MinerThreads (0 => CPU sayısı)
+func minerThreads() int {
+	if cfg.MinerThreads > 0 {
+		return cfg.MinerThreads
+	}
+	return runtime.NumCPU()
+}
+
+// subscribeNodeEvents: düğüm yan etkileri olay veri yoluna abone olur
+// (kazım döngüleri ve blok geri çağrıları bunları ayrıca çağırmaz)
+func subscribeNodeEvents() {
+	bus := events.Default
+
+	// autosave: zincir değişince kaydet; birikmiş bildirimler tek kayıtta toplanır
+	dirty := make(chan struct{}, 1)
+	events.Handle(bus, "autosave", 0, func(events.BlockConnected) {
+		select {
+		case dirty <- struct{}{}:
+		default:
+		}
+	})
+	go func() {
+		for range dirty {
+			if err := saveChain(); err != nil {
+				chainLog.Error("autosave failed", "file", cfg.ChainFile, "err", err)
+			}
+		}
+	}()
+
+	// AI bonusu: yalnız bu düğümün kazdığı bloklarda
+	events.Handle(bus, "ai-bonus", 0, func(e events.MinerStatus) {
+		if e.Block != nil {
+			processAIBonus()
+		}
+	})
+
+	telegram_game.Subscribe(bus)
 }
 
 /* ---------- HTTP API ---------- */
 
-func withCORS(next http.Handler) http.Handler {
+// withRequestLog: istek satırını api logger'ına yazar (CORS/kimlik/hız sınırı api.Protect'te)
+func withRequestLog(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
-		}
-		log.Printf("%s %s", r.Method, r.URL.Path)
+		apiLog.Info("request", "method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
 		next.ServeHTTP(w, r)
 	})
 }
 
+// nodeOperations: düğümün kendi rotalarının OpenAPI kayıtları (ortak rotalar api.Operations'ta)
+var nodeOperations = []api.Operation{
+	{Method: "GET", Path: "/api/health", Tag: "chain", Summary: "Liveness, height and HTTP port", Response: wire.Health{}},
+	{Method: "GET", Path: "/api/blocks", Tag: "chain", Summary: "Latest blocks, oldest first", Query: []api.Param{{Name: "limit", Type: "integer", Description: "default 20"}}, Response: []wire.NodeBlockSummary{}},
+	{Method: "GET", Path: "/api/block", Tag: "chain", Summary: "Raw block by index or hash",
+		Query: []api.Param{{Name: "index", Type: "integer"}, {Name: "hash", Type: "string"}}, Response: blockchain.Block{}},
+	{Method: "POST", Path: "/api/wallet/new", Tag: "wallet", Summary: "Create wallet in the node's store", Response: wire.WalletResponse{}},
+	{Method: "GET", Path: "/api/wallet/address", Tag: "wallet", Summary: "Node's default miner address", Response: wire.WalletResponse{}},
+	{Method: "GET", Path: "/api/wallet/balance/{address}", Tag: "wallet", Summary: "Balance and spendable balance", Response: wire.BalanceResponse{}},
+	{Method: "POST", Path: "/api/tx/send", Tag: "wallet", Summary: "Send from a node wallet (or with priv_hex)", Request: wire.SendRequest{}, Response: wire.SendResponse{}},
+	{Method: "POST", Path: "/api/tx/burn", Tag: "wallet", Summary: "Burn from a node wallet", Request: wire.BurnRequest{}, Response: wire.SendResponse{}},
+	{Method: "POST", Path: "/api/mine", Tag: "mining", Summary: "Mine one block on the node", Request: wire.AddressRequest{}, Response: wire.MineResult{}},
+	{Method: "GET", Path: "/api/mine/job", Tag: "mining", Summary: "Web miner job (pool share job when pool mode is on)", Query: []api.Param{{Name: "address", Type: "string", Required: true}}, Response: wire.MineJob{}},
+	{Method: "POST", Path: "/api/mine/submit", Tag: "mining", Summary: "Submit web miner solution", Request: wire.WorkSubmit{}, Response: wire.MineSubmitResult{}},
+	{Method: "POST", Path: "/api/miner/start", Tag: "miner", Summary: "Start in-process miner", Request: wire.AddressRequest{}, Response: wire.MinerState{}},
+	{Method: "POST", Path: "/api/miner/stop", Tag: "miner", Summary: "Stop in-process miner", Response: wire.MinerState{}},
+	{Method: "GET", Path: "/api/miner/status", Tag: "miner", Summary: "In-process miner status and hashrate", Response: wire.MinerStatus{}},
+	{Method: "POST", Path: "/api/dev/fastmine", Tag: "miner", Summary: "Mine n blocks immediately (development)",
+		Query: []api.Param{{Name: "address", Type: "string", Required: true}, {Name: "n", Type: "integer", Description: "default 5"}}, Response: map[string]any{}},
+	{Method: "GET", Path: "/api/ai/bonus", Tag: "ai", Summary: "AI bonuses", Query: []api.Param{{Name: "address", Type: "string"}}, Response: []internal.Bonus{}},
+	{Method: "GET", Path: "/api/ai/analysis", Tag: "ai", Summary: "Anomalies, recommendations and reward suggestions", Query: []api.Param{{Name: "address", Type: "string", Required: true}}, Response: map[string]any{}},
+	{Method: "POST", Path: "/api/game/score", Tag: "game", Summary: "Record game score",
+		Query: []api.Param{{Name: "player", Type: "string", Required: true}, {Name: "score", Type: "integer", Required: true}}, Response: map[string]any{}},
+	{Method: "GET", Path: "/api/game/leaderboard", Tag: "game", Summary: "Top players", Response: []game.PlayerScore{}},
+}
+
+// nodeOpenAPI: düğüm sunucusunun tam uç listesi
+func nodeOpenAPI() []api.Operation {
+	return append(append([]api.Operation{}, api.Operations...), nodeOperations...)
+}
+
 func startHTTPAPI() {
 	mux := http.NewServeMux()
 
@@ -491,8 +960,6 @@ func startHTTPAPI() {
 	mux.HandleFunc("/api/block", handleBlockDetail)
 
 	mux.HandleFunc("/api/tx/burn", handleBurn)
-	mux.HandleFunc("/api/stake/start", handleStakeStart)
-	mux.HandleFunc("/api/stake/status", handleStakeStatus)
 
 	mux.HandleFunc("/api/mine/job", handleMineJob)
 	mux.HandleFunc("/api/mine/submit", handleMineSubmit)
@@ -502,6 +969,29 @@ func startHTTPAPI() {
 	mux.HandleFunc("/api/miner/stop", handleMinerStop)
 	mux.HandleFunc("/api/miner/status", handleMinerStatus)
 
+	// PSBT (offline / çok taraflı imza) + multisig adresler
+	api.RegisterPSBTRoutes(mux)
+	api.RegisterMultisigRoutes(mux)
+	api.RegisterPoolRoutes(mux)
+	api.RegisterStakeRoutes(mux)
+	api.RegisterBurnRoutes(mux)
+	api.RegisterRPCRoutes(mux)
+	api.RegisterWSRoutes(mux)
+	api.RegisterWebhookRoutes(mux)
+	api.RegisterExplorerRoutes(mux)
+	api.RegisterMetricsRoute(mux) // GET /metrics (Prometheus)
+	api.RegisterLogRoutes(mux)    // /api/admin/loglevel (çalışırken log seviyesi)
+
+	// Harici blok montajı (getblocktemplate / submitblock) + regtest generate
+	api.RegisterMiningRoutes(mux)
+	api.RegisterRegtestRoutes(mux)
+
+	// OpenAPI belgesi; belge ile rotalar ayrışmışsa açılışta uyar
+	api.RegisterOpenAPIRoute(mux, nodeOpenAPI())
+	for _, p := range api.CheckOpenAPI(mux, nodeOpenAPI()) {
+		apiLog.Warn("openapi mismatch", "problem", p)
+	}
+
 	// Gömülü web cüzdan (SPA)
 	if h, err := webui.Handler(); err == nil {
 		mux.Handle("/", h)
@@ -512,30 +1002,65 @@ func startHTTPAPI() {
 		})
 	}
 
+	if cfg.StratumPort != "" {
+		go startStratum()
+	}
+	if cfg.PprofAddr != "" {
+		go func() {
+			apiLog.Info("pprof listening", "url", "http://"+cfg.PprofAddr+"/debug/pprof/", "scope", api.ScopeAdmin)
+			if err := api.StartPprof(cfg.PprofAddr); err != nil {
+				apiLog.Error("pprof server failed", "addr", cfg.PprofAddr, "err", err)
+			}
+		}()
+	}
+
 	addr := getHTTPAddr()
 	httpServer = &http.Server{
 		Addr:              addr,
-		Handler:           withCORS(mux),
+		Handler:           withRequestLog(api.Protect(mux)),
 		ReadHeaderTimeout: 5 * time.Second,
 		ReadTimeout:       15 * time.Second,
 		WriteTimeout:      15 * time.Second,
 		IdleTimeout:       60 * time.Second,
 	}
 
-	fmt.Println("HTTP API starting at http://localhost" + addr)
+	apiLog.Info("http api starting", "url", "http://localhost"+addr)
 	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Printf("http server error: %v", err)
+		apiLog.Error("http server failed", "addr", addr, "err", err)
+	}
+}
+
+// startStratum: harici madenciler için Stratum sunucusu (canlı zincir üzerinde)
+// Havuz modunda coinbase havuza yazılır, paylar havuz muhasebesine gider.
+func startStratum() {
+	if minePool != nil {
+		srv := miner.NewStratumServer(minePool.Backend(), miner.StratumServerOpts{
+			ShareBits: minePool.ShareBits,
+			OnShare:   minePool.OnStratumShare,
+		})
+		minerLog.Info("stratum server listening", "addr", cfg.StratumPort, "pool", true)
+		if err := srv.ListenAndServe(cfg.StratumPort); err != nil {
+			minerLog.Error("stratum server failed", "addr", cfg.StratumPort, "pool", true, "err", err)
+		}
+		return
+	}
+	srv := miner.NewStratumServer(soloBackend, miner.StratumServerOpts{
+		ShareBits: func(string) int { return cfg.StratumShareBits },
+	})
+	minerLog.Info("stratum server listening", "addr", cfg.StratumPort)
+	if err := srv.ListenAndServe(cfg.StratumPort); err != nil {
+		minerLog.Error("stratum server failed", "addr", cfg.StratumPort, "err", err)
 	}
 }
 
 /* ---------- handlers ---------- */
 
 func handleHealth(w http.ResponseWriter, _ *http.Request) {
-	writeOK(w, map[string]any{
-		"ok":       true,
-		"height":   bc.GetBestHeight(),
-		"time":     time.Now().UTC().Format(time.RFC3339),
-		"httpPort": getHTTPPort(),
+	writeOK(w, wire.Health{
+		OK:       true,
+		Height:   bc.GetBestHeight(),
+		Time:     time.Now().UTC().Format(time.RFC3339),
+		HTTPPort: getHTTPPort(),
 	})
 }
 
@@ -595,14 +1120,13 @@ func handleMineBlock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	p2p.BroadcastMessage(p2p.BlockMessage(block))
-	writeOK(w, map[string]any{
-		"success":    true,
-		"reward":     blockchain.GetCurrentReward(),
-		"height":     bc.GetBestHeight(),
-		"block_hash": hex.EncodeToString(block.Hash),
+	miner.PublishStatus(block)
+	writeOK(w, wire.MineResult{
+		Success:   true,
+		Reward:    blockchain.GetCurrentReward(),
+		Height:    bc.GetBestHeight(),
+		BlockHash: hex.EncodeToString(block.Hash),
 	})
-	processAIBonus()
-	_ = bc.SaveToFile(cfg.ChainFile)
 }
 
 /* 🟢 GÜNCEL: /api/tx/send -> priv_hex ile imzalama */
@@ -641,11 +1165,11 @@ func handleSendTx(w http.ResponseWriter, r *http.Request) {
 		writeOK(w, SendResponse{Success: false, TxID: "", Message: "invalid priv_hex: " + err.Error()})
 		return
 	}
-	if err := tx.Sign(priv); err != nil {
+	if err := bc.SignTransaction(tx, priv); err != nil {
 		writeOK(w, SendResponse{Success: false, TxID: "", Message: "sign tx: " + err.Error()})
 		return
 	}
-	if !tx.Verify() {
+	if !bc.VerifyTransaction(tx) {
 		writeOK(w, SendResponse{Success: false, TxID: "", Message: "verify failed"})
 		return
 	}
@@ -674,8 +1198,7 @@ func handleFastMine(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	p2p.BroadcastMessage(p2p.BlockMessage(bc.Blocks[len(bc.Blocks)-1]))
-	_ = bc.SaveToFile(cfg.ChainFile)
+	p2p.BroadcastMessage(p2p.BlockMessage(bc.GetLastBlock()))
 	writeOK(w, map[string]any{"success": true, "mined": n, "height": bc.GetBestHeight()})
 }
 
@@ -684,7 +1207,7 @@ func handleFastMine(w http.ResponseWriter, r *http.Request) {
 func processAIBonus() {
 	var recentTxs []*blockchain.Transaction
 	now := time.Now()
-	for _, block := range bc.Blocks {
+	for _, block := range bc.GetAllBlocks() {
 		for _, tx := range block.Transactions {
 			if tx.Timestamp.After(now.Add(-24 * time.Hour)) {
 				recentTxs = append(recentTxs, tx)
@@ -702,12 +1225,27 @@ func handleAIBonus(w http.ResponseWriter, r *http.Request) {
 func handleAIAnalysis(w http.ResponseWriter, r *http.Request) {
 	address := r.URL.Query().Get("address")
 	var userTxs []*blockchain.Transaction
-	for _, block := range bc.Blocks {
-		for _, tx := range block.Transactions {
-			if tx.Sender == address {
+	if ix := bc.Index(); ix != nil {
+		// indeks: yalnız adresten harcayan işlemler (eskiden yeniye)
+		moves, _ := ix.AddressTxs(address, 0, 0)
+		for i := len(moves) - 1; i >= 0; i-- {
+			blk := bc.GetBlockByIndex(moves[i].Height)
+			if blk == nil || moves[i].Position >= len(blk.Transactions) {
+				continue
+			}
+			tx := blk.Transactions[moves[i].Position]
+			if moves[i].Sent > 0 && tx.Sender == address {
 				userTxs = append(userTxs, tx)
 			}
 		}
+	} else {
+		for _, block := range bc.GetAllBlocks() {
+			for _, tx := range block.Transactions {
+				if tx.Sender == address {
+					userTxs = append(userTxs, tx)
+				}
+			}
+		}
 	}
 	anomalies := ai.AnalyzeTransactions(userTxs, 5, 24)
 	recs := ai.GenerateRecommendations(userTxs, 14, 10)
@@ -740,24 +1278,16 @@ func handleBlocksList(w http.ResponseWriter, r *http.Request) {
 	if limit <= 0 {
 		limit = 20
 	}
-	total := len(bc.Blocks)
+	blocks := bc.GetAllBlocks()
+	total := len(blocks)
 	start := total - limit
 	if start < 0 {
 		start = 0
 	}
-	type bsum struct {
-		Index      int    `json:"index"`
-		Hash       string `json:"hash"`
-		PrevHash   string `json:"prev_hash"`
-		Timestamp  int64  `json:"timestamp"`
-		Miner      string `json:"miner"`
-		Difficulty int    `json:"difficulty"`
-		TxCount    int    `json:"tx_count"`
-	}
-	summaries := make([]bsum, 0, limit)
+	summaries := make([]wire.NodeBlockSummary, 0, limit)
 	for i := start; i < total; i++ {
-		b := bc.Blocks[i]
-		summaries = append(summaries, bsum{
+		b := blocks[i]
+		summaries = append(summaries, wire.NodeBlockSummary{
 			Index:      b.Index,
 			Hash:       hex.EncodeToString(b.Hash),
 			PrevHash:   hex.EncodeToString(b.PrevHash),
@@ -766,6 +1296,9 @@ func handleBlocksList(w http.ResponseWriter, r *http.Request) {
 			Difficulty: b.Difficulty,
 			TxCount:    len(b.Transactions),
 		})
+		if rb, ok := b.RewardFromMetadata(); ok {
+			summaries[len(summaries)-1].Reward = &rb
+		}
 	}
 	writeOK(w, summaries)
 }
@@ -800,17 +1333,165 @@ func handleBlockDetail(w http.ResponseWriter, r *http.Request) {
 	writeError(w, http.StatusBadRequest, "index or hash required")
 }
 
-/* burn & stake stubs */
+/* stake / burn CLI */
+
+// submitSigned: build sonucunu cüzdan deposundaki anahtarla imzalar, mempool'a ekler ve yayınlar
+func submitSigned(tx *blockchain.Transaction, err error, addr string) {
+	if err != nil {
+		log.Println("tx build failed:", err)
+		return
+	}
+	w, ok := wallet.LoadWalletByAddress(addr)
+	if !ok {
+		log.Println("no key for", addr, "in wallet store")
+		return
+	}
+	if err := bc.SignTransaction(tx, w.PrivateKey); err != nil {
+		log.Println("sign failed:", err)
+		return
+	}
+	if err := bc.AddTransaction(tx); err != nil {
+		log.Println("tx submit failed:", err)
+		return
+	}
+	p2p.BroadcastMessage(p2p.TxMessage(tx))
+	fmt.Printf("✓ Transaction accepted and broadcasted (txid=%s)\n", hex.EncodeToString(tx.ID))
+}
+
+/* rpc client */
+
+// runRPCClient: "rpc [-url U] <method> [params...]". Parametre geçerli JSON ise
+// olduğu gibi, değilse metin olarak gönderilir; tek parametre "{...}" ise
+// adlandırılmış parametre nesnesi sayılır.
+func runRPCClient(args []string) {
+	url := "http://127.0.0.1" + getHTTPAddr() + "/rpc"
+	if len(args) >= 2 && (args[0] == "-url" || args[0] == "--url") {
+		url, args = args[1], args[2:]
+	}
+	if len(args) < 1 {
+		fmt.Println("Usage: rpc [-url http://host:port/rpc] <method> [params...]   (rpc help = method list)")
+		return
+	}
+	var params any
+	if len(args) == 2 && strings.HasPrefix(strings.TrimSpace(args[1]), "{") && json.Valid([]byte(args[1])) {
+		params = json.RawMessage(args[1])
+	} else {
+		pos := make([]json.RawMessage, 0, len(args)-1)
+		for _, a := range args[1:] {
+			if json.Valid([]byte(a)) {
+				pos = append(pos, json.RawMessage(a))
+			} else {
+				b, _ := json.Marshal(a)
+				pos = append(pos, b)
+			}
+		}
+		params = pos
+	}
+	user, pass := cfg.RPCUser, cfg.RPCPassword
+	if pass == "" && cfg.APICookieFile != "" {
+		// parola yoksa çalışan düğümün cookie dosyası (aynı veri dizini)
+		if u, p, err := api.ReadCookie(cfg.APICookieFile); err == nil {
+			user, pass = u, p
+		}
+	}
+	res, err := api.NewRPCClient(url, user, pass).Call(args[0], params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var text string
+	if json.Unmarshal(res, &text) == nil {
+		fmt.Println(text)
+		return
+	}
+	var out bytes.Buffer
+	if json.Indent(&out, res, "", "  ") != nil {
+		out.Reset()
+		out.Write(res)
+	}
+	fmt.Println(out.String())
+}
+
+/* webhook receiver */
+
+// runWebhookListener: "webhook-listen [addr] [secret]" — webhook'ları yerelde
+// denemek için alıcı; imzayı doğrular, bildirimi yazdırır, 204 döner
+func runWebhookListener(args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: webhook-listen [addr, e.g. 127.0.0.1:9099] [secret]")
+		return
+	}
+	h := webhook.Receiver(args[1], func(n webhook.Notification) {
+		b, _ := json.Marshal(n)
+		fmt.Println(string(b))
+	})
+	fmt.Printf("🪝 Webhook receiver on http://%s/ (bad signatures -> 401)\n", args[0])
+	log.Fatal(http.ListenAndServe(args[0], h))
+}
+
+/* api keys */
+
+// runAPIKeyCommand: apikey-new / apikey-list / apikey-revoke (api_keys_file)
+func runAPIKeyCommand(cmd string, args []string) {
+	path := cfg.APIKeysFile
+	switch cmd {
+	case "apikey-new":
+		if len(args) < 2 {
+			fmt.Println("Usage: apikey-new [name] [scopes, e.g. read,wallet] [ratePerMin (optional)]")
+			return
+		}
+		rate := 0
+		if len(args) >= 3 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil {
+				log.Fatalf("ratePerMin: %v", err)
+			}
+			rate = n
+		}
+		token, err := api.CreateAPIKey(path, args[0], strings.Split(args[1], ","), rate)
+		if err != nil {
+			log.Fatalf("apikey-new: %v", err)
+		}
+		fmt.Printf("🔑 API key %q (%s) saved to %s\n", args[0], args[1], path)
+		fmt.Println("   Shown only once — send as \"Authorization: Bearer <key>\" or \"X-API-Key: <key>\":")
+		fmt.Println(token)
+	case "apikey-list":
+		keys, err := api.ListAPIKeys(path)
+		if err != nil {
+			log.Fatalf("apikey-list: %v", err)
+		}
+		if len(keys) == 0 {
+			fmt.Println("No API keys in", path)
+			return
+		}
+		for _, k := range keys {
+			rate := "default"
+			if k.RatePerMin > 0 {
+				rate = strconv.Itoa(k.RatePerMin) + "/min"
+			}
+			fmt.Printf("%-20s scopes=%-24s rate=%-10s created=%s\n", k.Name, strings.Join(k.Scopes, ","), rate, k.Created.Format(time.RFC3339))
+		}
+	case "apikey-revoke":
+		if len(args) < 1 {
+			fmt.Println("Usage: apikey-revoke [name]")
+			return
+		}
+		if err := api.RevokeAPIKey(path, args[0]); err != nil {
+			log.Fatalf("apikey-revoke: %v", err)
+		}
+		fmt.Printf("API key %q revoked\n", args[0])
+	default:
+		printUsage()
+	}
+}
+
+/* burn */
 
 func handleBurn(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
-	if cfg.BurnAddress == "" || cfg.BurnAddress == "QC_BURN_SINK" {
-		writeError(w, http.StatusBadRequest, "burn address not configured")
-		return
-	}
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -826,24 +1507,26 @@ func handleBurn(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "from and positive amount required")
 		return
 	}
-	tx, err := blockchain.NewTransaction(req.From, cfg.BurnAddress, req.Amount, bc)
+	tx, err := blockchain.NewBurnTransaction(req.From, req.Amount, bc)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "create tx: "+err.Error())
 		return
 	}
+	wl, ok := wallet.LoadWalletByAddress(req.From)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "no key for "+req.From+" in wallet store (use /api/burn for external signing)")
+		return
+	}
+	if err := bc.SignTransaction(tx, wl.PrivateKey); err != nil {
+		writeError(w, http.StatusInternalServerError, "sign tx: "+err.Error())
+		return
+	}
 	if err := bc.AddTransaction(tx); err != nil {
 		writeError(w, http.StatusBadRequest, "submit tx: "+err.Error())
 		return
 	}
 	p2p.BroadcastMessage(p2p.TxMessage(tx))
-	writeOK(w, map[string]any{"success": true, "txid": hex.EncodeToString(tx.ID)})
-}
-
-func handleStakeStart(w http.ResponseWriter, _ *http.Request) {
-	writeOK(w, map[string]any{"success": false, "message": "stake module coming soon"})
-}
-func handleStakeStatus(w http.ResponseWriter, _ *http.Request) {
-	writeOK(w, map[string]any{"success": false, "message": "stake status endpoint coming soon"})
+	writeOK(w, SendResponse{Success: true, TxID: hex.EncodeToString(tx.ID)})
 }
 
 /* graceful shutdown */
@@ -852,63 +1535,25 @@ func trapAndShutdown() {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	<-c
-	fmt.Println("\nShutting down...")
+	nodeLog.Info("shutting down")
+	minerMu.Lock()
 	if minerStop != nil {
 		close(minerStop)
+		minerStop = nil
 	}
+	minerMu.Unlock()
 	if httpServer != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		_ = httpServer.Shutdown(ctx)
 		cancel()
 	}
-	if err := bc.SaveToFile(cfg.ChainFile); err != nil {
-		log.Printf("save on shutdown error: %v", err)
+	if err := saveChain(); err != nil {
+		chainLog.Error("save on shutdown failed", "file", cfg.ChainFile, "err", err)
 	}
 	os.Exit(0)
 }
 
-/* ---------- Web miner PoW ---------- */
-
-func powHash(challenge []byte, nonce uint64) [32]byte {
-	var nb [8]byte
-	binary.LittleEndian.PutUint64(nb[:], nonce)
-	return sha256.Sum256(append(challenge, nb[:]...))
-}
-
-func hasLeadingZeroBits(h []byte, need int) bool {
-	if need <= 0 {
-		return true
-	}
-	for _, b := range h {
-		if need <= 0 {
-			return true
-		}
-		if b == 0 {
-			need -= 8
-			continue
-		}
-		z := bits.LeadingZeros8(b)
-		return z >= need
-	}
-	return need <= 0
-}
-
-func makeWebJob(addr string, diff int) *webJob {
-	last := bc.GetLastBlock()
-	base := append([]byte(addr), last.Hash...)
-	base = append(base, byte(last.Index))
-	rnd := make([]byte, 8)
-	_, _ = io.ReadFull(rand.Reader, rnd)
-	base = append(base, rnd...)
-	ch := sha256.Sum256(base)
-	return &webJob{
-		Challenge:  ch[:],
-		Difficulty: diff,
-		Miner:      addr,
-		Height:     bc.GetBestHeight(),
-		ExpiresAt:  time.Now().Add(60 * time.Second),
-	}
-}
+/* ---------- Web miner ---------- */
 
 func handleMineJob(w http.ResponseWriter, r *http.Request) {
 	addr := r.URL.Query().Get("address")
@@ -916,17 +1561,41 @@ func handleMineJob(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "address required")
 		return
 	}
-	diff := cfg.DefaultDifficultyBits
-	j := makeWebJob(addr, diff)
-	jobMu.Lock()
-	curJob = j
-	jobMu.Unlock()
+	if minePool != nil {
+		pj, err := minePool.NewJob(addr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeOK(w, WebMineJobResp{
+			Challenge:   pj.Left,
+			Difficulty:  pj.ShareBits,
+			Miner:       pj.Worker,
+			Height:      pj.Height,
+			Expires:     pj.Expires,
+			JobID:       pj.ID,
+			Suffix:      pj.Right,
+			NonceFormat: pj.NonceFormat,
+			BlockBits:   pj.BlockBits,
+		})
+		return
+	}
+	// Solo: iş gerçek blok adayıdır; çözüm bloğu doğrudan zincire ekler
+	j, err := webJobs.NewJob(addr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	writeOK(w, WebMineJobResp{
-		Challenge:  hex.EncodeToString(j.Challenge),
-		Difficulty: j.Difficulty,
-		Miner:      j.Miner,
-		Height:     j.Height,
-		Expires:    j.ExpiresAt.Unix(),
+		Challenge:   j.Left,
+		Difficulty:  j.Bits,
+		Miner:       j.Miner,
+		Height:      j.Height,
+		Expires:     j.Expires,
+		JobID:       j.ID,
+		Suffix:      j.Right,
+		NonceFormat: j.NonceFormat,
+		BlockBits:   j.Bits,
 	})
 }
 
@@ -946,121 +1615,97 @@ func handleMineSubmit(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if req.Address == "" || req.Challenge == "" {
-		writeError(w, http.StatusBadRequest, "address & challenge required")
-		return
-	}
-
-	jobMu.Lock()
-	j := curJob
-	jobMu.Unlock()
-
-	if j == nil || time.Now().After(j.ExpiresAt) {
-		writeOK(w, WebMineSubmitResp{Accepted: false, Hash: "", Message: "job expired"})
-		return
-	}
-	if req.Address != j.Miner {
-		writeOK(w, WebMineSubmitResp{Accepted: false, Hash: "", Message: "address mismatch"})
+	// Havuz: pay muhasebesi; blok bulunursa ödül PPLNS/PPS ile dağıtılır
+	if minePool != nil {
+		res, _ := minePool.Submit(req.JobID, req.Nonce)
+		writeOK(w, WebMineSubmitResp{Accepted: res.Accepted, Hash: res.Hash, Message: res.Message, Block: res.Block})
 		return
 	}
-	if !strings.EqualFold(req.Challenge, hex.EncodeToString(j.Challenge)) {
-		writeOK(w, WebMineSubmitResp{Accepted: false, Hash: "", Message: "challenge mismatch"})
+	if req.JobID == "" {
+		writeError(w, http.StatusBadRequest, "jobId required")
 		return
 	}
-
-	h := powHash(j.Challenge, req.Nonce)
-	if !hasLeadingZeroBits(h[:], j.Difficulty) {
-		writeOK(w, WebMineSubmitResp{Accepted: false, Hash: hex.EncodeToString(h[:]), Message: "below difficulty"})
-		return
-	}
-
-	// Kabul -> arka planda 1 blok kaz
-	go func(miner string) {
-		if blk, err := bc.MineBlock(miner, cfg.DefaultDifficultyBits); err == nil {
-			p2p.BroadcastMessage(p2p.BlockMessage(blk))
-			processAIBonus()
-			_ = bc.SaveToFile(cfg.ChainFile)
-		} else {
-			log.Printf("mine after accept failed: %v", err)
-		}
-	}(req.Address)
-
-	writeOK(w, WebMineSubmitResp{Accepted: true, Hash: hex.EncodeToString(h[:])})
+	res := webJobs.Submit(req.JobID, req.Nonce)
+	writeOK(w, WebMineSubmitResp{Accepted: res.Accepted, Hash: res.Hash, Message: res.Message, Block: res.Accepted})
 }
 
 /* ---- Miner control (start/stop/status) ---- */
 
-type minerStartReq struct {
-	Address string `json:"address"`
-}
-
 func handleMinerStart(w http.ResponseWriter, r *http.Request) {
 	// GET veya POST(JSON) kabul
 	var addr string
 	if r.Method == http.MethodPost {
 		defer r.Body.Close()
-		var req minerStartReq
+		var req wire.AddressRequest
 		_ = json.NewDecoder(r.Body).Decode(&req)
 		addr = strings.TrimSpace(req.Address)
 	}
 	if addr == "" {
 		addr = strings.TrimSpace(r.URL.Query().Get("address"))
 	}
+	st, err := minerControl(true, addr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeOK(w, st)
+}
+
+func handleMinerStop(w http.ResponseWriter, r *http.Request) {
+	st, _ := minerControl(false, "")
+	writeOK(w, st)
+}
+
+// minerControl: süreç içi madenciyi başlatır/durdurur (HTTP ve RPC setgenerate ortak;
+// bkz. api.MinerControl). address boşsa düğüm cüzdanı kullanılır.
+func minerControl(start bool, addr string) (wire.MinerState, error) {
+	minerMu.Lock()
+	defer minerMu.Unlock()
+	if !start {
+		if minerStop == nil {
+			return wire.MinerState{Running: false, Message: "miner not running", Height: bc.GetBestHeight()}, nil
+		}
+		close(minerStop)
+		minerStop = nil
+		return wire.MinerState{Running: false, Message: "miner stopped", Height: bc.GetBestHeight()}, nil
+	}
+
+	addr = strings.TrimSpace(addr)
 	if addr == "" {
 		if a, err := ensureMinerAddress(); err == nil {
 			addr = a
 		}
 	}
 	if addr == "" {
-		writeError(w, http.StatusBadRequest, "address required")
-		return
+		return wire.MinerState{}, errors.New("address required")
+	}
+	st := wire.MinerState{
+		Running:    true,
+		Address:    addr,
+		Difficulty: cfg.DefaultDifficultyBits,
+		Height:     bc.GetBestHeight(),
 	}
-
 	if minerStop != nil {
-		writeOK(w, map[string]any{
-			"running":    true,
-			"message":    "miner already running",
-			"address":    addr,
-			"difficulty": cfg.DefaultDifficultyBits,
-			"height":     bc.GetBestHeight(),
-		})
-		return
+		st.Message = "miner already running"
+		return st, nil
 	}
-
 	minerStop = make(chan struct{})
-	go startContinuousMining(addr)
-
-	writeOK(w, map[string]any{
-		"running":    true,
-		"address":    addr,
-		"difficulty": cfg.DefaultDifficultyBits,
-		"height":     bc.GetBestHeight(),
-	})
-}
-
-func handleMinerStop(w http.ResponseWriter, r *http.Request) {
-	if minerStop == nil {
-		writeOK(w, map[string]any{
-			"running": false,
-			"message": "miner not running",
-			"height":  bc.GetBestHeight(),
-		})
-		return
-	}
-	close(minerStop)
-	minerStop = nil
-	writeOK(w, map[string]any{
-		"running": false,
-		"message": "miner stopped",
-		"height":  bc.GetBestHeight(),
-	})
+	go startContinuousMining(addr, minerStop)
+	return st, nil
 }
 
 func handleMinerStatus(w http.ResponseWriter, _ *http.Request) {
-	writeOK(w, map[string]any{
-		"running":  minerStop != nil,
-		"height":   bc.GetBestHeight(),
-		"bits":     cfg.DefaultDifficultyBits,
-		"httpPort": getHTTPPort(),
+	minerMu.Lock()
+	running := minerStop != nil
+	minerMu.Unlock()
+	writeOK(w, wire.MinerStatus{
+		Running:  running,
+		Height:   bc.GetBestHeight(),
+		Bits:     cfg.DefaultDifficultyBits,
+		HTTPPort: getHTTPPort(),
+		Hashrate: mineMeter.Rate(),
+		Hashes:   mineMeter.Total(),
+		Restarts: mineRestarts.Load(),
+		Threads:  minerThreads(),
 	})
 }
@@ -0,0 +1,440 @@
+// Package metrics: Prometheus metin biçiminde (exposition format 0.0.4) sayaç,
+// gösterge ve histogramlar. Harici bağımlılık yoktur; paketler metriklerini
+// paket düzeyinde tanımlar ve Default kayıt defterine kaydeder, düğüm
+// GET /metrics ile yayınlar (bkz. api/metrics.go).
+//
+//	var blocks = metrics.NewCounter("qc_chain_blocks_connected_total", "Blocks connected to the best chain")
+//	var reqs = metrics.NewCounterVec("qc_http_requests_total", "HTTP requests", "route", "code")
+//	blocks.Inc(); reqs.With("/api/tx/send", "200").Inc()
+//
+// Aynı ad iki kez kaydedilirse New* panikler (program başlangıcında yakalanır).
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefBuckets: süre histogramları için varsayılan üst sınırlar (saniye)
+var DefBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// ExpBuckets: start'tan başlayıp factor ile büyüyen n sınır
+func ExpBuckets(start, factor float64, n int) []float64 {
+	b := make([]float64, n)
+	for i := range b {
+		b[i] = start
+		start *= factor
+	}
+	return b
+}
+
+// ----- kayıt defteri -----
+
+// collector: tek bir metrik ailesi (ad, yardım, tür ve örnekler)
+type collector interface {
+	desc() *desc
+	write(w *bufio.Writer)
+}
+
+type desc struct {
+	name   string
+	help   string
+	typ    string // counter | gauge | histogram
+	labels []string
+}
+
+// Registry: metrik aileleri; ServeHTTP ile metin biçiminde yayınlanır
+type Registry struct {
+	mu    sync.RWMutex
+	byKey map[string]collector
+}
+
+// NewRegistry: boş kayıt defteri
+func NewRegistry() *Registry { return &Registry{byKey: map[string]collector{}} }
+
+// Default: düğüm genelindeki kayıt defteri (New* buraya kaydeder)
+var Default = NewRegistry()
+
+func (r *Registry) register(c collector) {
+	d := c.desc()
+	if !validName(d.name) {
+		panic("metrics: invalid metric name " + strconv.Quote(d.name))
+	}
+	for _, l := range d.labels {
+		if !validName(l) || l == "le" {
+			panic("metrics: invalid label name " + strconv.Quote(l) + " for " + d.name)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.byKey[d.name]; dup {
+		panic("metrics: duplicate metric " + d.name)
+	}
+	r.byKey[d.name] = c
+}
+
+// WriteTo: tüm metrikleri ada göre sıralı, metin biçiminde yazar
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.byKey))
+	for n := range r.byKey {
+		names = append(names, n)
+	}
+	cs := make([]collector, len(names))
+	sort.Strings(names)
+	for i, n := range names {
+		cs[i] = r.byKey[n]
+	}
+	r.mu.RUnlock()
+
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, c := range cs {
+		d := c.desc()
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, d.typ)
+		c.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ServeHTTP: GET /metrics
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if req.Method == http.MethodHead {
+		return
+	}
+	_, _ = r.WriteTo(w)
+}
+
+// Handler: Default'u yayınlayan handler
+func Handler() http.Handler { return Default }
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ----- sayaç / gösterge -----
+
+// Counter: yalnız artan değer
+type Counter struct{ v atomicFloat }
+
+// Inc: 1 ekler
+func (c *Counter) Inc() { c.v.add(1) }
+
+// Add: d ekler (negatifse yok sayılır)
+func (c *Counter) Add(d float64) {
+	if d > 0 {
+		c.v.add(d)
+	}
+}
+
+// Value: anlık değer
+func (c *Counter) Value() float64 { return c.v.load() }
+
+// Gauge: artıp azalabilen değer
+type Gauge struct{ v atomicFloat }
+
+func (g *Gauge) Set(v float64)  { g.v.store(v) }
+func (g *Gauge) Add(d float64)  { g.v.add(d) }
+func (g *Gauge) Inc()           { g.v.add(1) }
+func (g *Gauge) Dec()           { g.v.add(-1) }
+func (g *Gauge) Value() float64 { return g.v.load() }
+
+// SetToCurrentTime: Unix saniyesi
+func (g *Gauge) SetToCurrentTime() { g.Set(float64(time.Now().UnixNano()) / 1e9) }
+
+type atomicFloat struct{ bits atomic.Uint64 }
+
+func (f *atomicFloat) load() float64   { return math.Float64frombits(f.bits.Load()) }
+func (f *atomicFloat) store(v float64) { f.bits.Store(math.Float64bits(v)) }
+func (f *atomicFloat) add(d float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+d)) {
+			return
+		}
+	}
+}
+
+// ----- histogram -----
+
+// Histogram: gözlemleri üst sınırlara (bucket) göre sayar
+type Histogram struct {
+	upper  []float64
+	counts []atomic.Uint64 // bucket başına (kümülatif değil); son eleman +Inf
+	sum    atomicFloat
+	count  atomic.Uint64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	up := append([]float64(nil), buckets...)
+	sort.Float64s(up)
+	return &Histogram{upper: up, counts: make([]atomic.Uint64, len(up)+1)}
+}
+
+// Observe: v değerini kaydeder
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.upper, v) // v <= upper[i] olan ilk sınır
+	h.counts[i].Add(1)
+	h.sum.add(v)
+	h.count.Add(1)
+}
+
+// ObserveSince: start'tan bu yana geçen süreyi saniye olarak kaydeder
+func (h *Histogram) ObserveSince(start time.Time) { h.Observe(time.Since(start).Seconds()) }
+
+// Count: toplam gözlem sayısı
+func (h *Histogram) Count() uint64 { return h.count.Load() }
+
+func (h *Histogram) writeSamples(w *bufio.Writer, name string, names, values []string) {
+	var cum uint64
+	for i, up := range h.upper {
+		cum += h.counts[i].Load()
+		writeSample(w, name+"_bucket", append(names, "le"), append(values, formatFloat(up)), float64(cum))
+	}
+	cum += h.counts[len(h.upper)].Load()
+	writeSample(w, name+"_bucket", append(names, "le"), append(values, "+Inf"), float64(cum))
+	writeSample(w, name+"_sum", names, values, h.sum.load())
+	writeSample(w, name+"_count", names, values, float64(cum))
+}
+
+// ----- aileler -----
+
+// family: etiketli ya da etiketsiz örnekleri tutan ortak yapı
+type family[T any] struct {
+	d        desc
+	mu       sync.RWMutex
+	children map[string]*child[T]
+	mk       func() *T
+}
+
+type child[T any] struct {
+	values []string
+	m      *T
+}
+
+func (f *family[T]) desc() *desc { return &f.d }
+
+// with: etiket değerlerine ait örnek (yoksa oluşturulur)
+func (f *family[T]) with(values []string) *T {
+	if len(values) != len(f.d.labels) {
+		panic(fmt.Sprintf("metrics: %s wants %d label values, got %d", f.d.name, len(f.d.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	f.mu.RLock()
+	c := f.children[key]
+	f.mu.RUnlock()
+	if c != nil {
+		return c.m
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c = f.children[key]; c == nil {
+		c = &child[T]{values: append([]string(nil), values...), m: f.mk()}
+		f.children[key] = c
+	}
+	return c.m
+}
+
+func (f *family[T]) sorted() []*child[T] {
+	f.mu.RLock()
+	out := make([]*child[T], 0, len(f.children))
+	for _, c := range f.children {
+		out = append(out, c)
+	}
+	f.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		return strings.Join(out[i].values, "\xff") < strings.Join(out[j].values, "\xff")
+	})
+	return out
+}
+
+func newFamily[T any](name, help, typ string, labels []string, mk func() *T) *family[T] {
+	return &family[T]{
+		d:        desc{name: name, help: help, typ: typ, labels: append([]string(nil), labels...)},
+		children: map[string]*child[T]{},
+		mk:       mk,
+	}
+}
+
+type counterFamily struct{ *family[Counter] }
+
+func (f counterFamily) write(w *bufio.Writer) {
+	for _, c := range f.sorted() {
+		writeSample(w, f.d.name, f.d.labels, c.values, c.m.Value())
+	}
+}
+
+type gaugeFamily struct{ *family[Gauge] }
+
+func (f gaugeFamily) write(w *bufio.Writer) {
+	for _, c := range f.sorted() {
+		writeSample(w, f.d.name, f.d.labels, c.values, c.m.Value())
+	}
+}
+
+type histogramFamily struct{ *family[Histogram] }
+
+func (f histogramFamily) write(w *bufio.Writer) {
+	for _, c := range f.sorted() {
+		c.m.writeSamples(w, f.d.name, f.d.labels, c.values)
+	}
+}
+
+// funcMetric: değeri okuma anında fn'den alınan sayaç ya da gösterge
+type funcMetric struct {
+	d  desc
+	fn func() float64
+}
+
+func (f *funcMetric) desc() *desc { return &f.d }
+func (f *funcMetric) write(w *bufio.Writer) {
+	writeSample(w, f.d.name, nil, nil, f.fn())
+}
+
+// CounterVec / GaugeVec / HistogramVec: etiketli aileler
+type CounterVec struct{ f counterFamily }
+type GaugeVec struct{ f gaugeFamily }
+type HistogramVec struct{ f histogramFamily }
+
+// With: etiket değerlerine (tanımdaki sırayla) ait örnek
+func (v *CounterVec) With(values ...string) *Counter     { return v.f.with(values) }
+func (v *GaugeVec) With(values ...string) *Gauge         { return v.f.with(values) }
+func (v *HistogramVec) With(values ...string) *Histogram { return v.f.with(values) }
+
+// ----- kurucular (Default'a kaydeder) -----
+
+// NewCounter: etiketsiz sayaç
+func NewCounter(name, help string) *Counter {
+	f := counterFamily{newFamily(name, help, "counter", nil, func() *Counter { return &Counter{} })}
+	Default.register(f)
+	return f.with(nil)
+}
+
+// NewCounterVec: labels etiketli sayaç ailesi
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	f := counterFamily{newFamily(name, help, "counter", labels, func() *Counter { return &Counter{} })}
+	Default.register(f)
+	return &CounterVec{f}
+}
+
+// NewGauge: etiketsiz gösterge
+func NewGauge(name, help string) *Gauge {
+	f := gaugeFamily{newFamily(name, help, "gauge", nil, func() *Gauge { return &Gauge{} })}
+	Default.register(f)
+	return f.with(nil)
+}
+
+// NewGaugeVec: labels etiketli gösterge ailesi
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	f := gaugeFamily{newFamily(name, help, "gauge", labels, func() *Gauge { return &Gauge{} })}
+	Default.register(f)
+	return &GaugeVec{f}
+}
+
+// NewHistogram: etiketsiz histogram (buckets nil => DefBuckets)
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	f := histogramFamily{newFamily(name, help, "histogram", nil, func() *Histogram { return newHistogram(buckets) })}
+	Default.register(f)
+	return f.with(nil)
+}
+
+// NewHistogramVec: labels etiketli histogram ailesi
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	f := histogramFamily{newFamily(name, help, "histogram", labels, func() *Histogram { return newHistogram(buckets) })}
+	Default.register(f)
+	return &HistogramVec{f}
+}
+
+// NewGaugeFunc: değeri her okumada fn'den alınan gösterge (fn hızlı ve eşzamanlılığa güvenli olmalı)
+func NewGaugeFunc(name, help string, fn func() float64) {
+	Default.register(&funcMetric{d: desc{name: name, help: help, typ: "gauge"}, fn: fn})
+}
+
+// NewCounterFunc: değeri fn'den alınan, yalnız artan sayaç (örn. mevcut bir atomik toplam)
+func NewCounterFunc(name, help string, fn func() float64) {
+	Default.register(&funcMetric{d: desc{name: name, help: help, typ: "counter"}, fn: fn})
+}
+
+// ----- biçim -----
+
+func writeSample(w *bufio.Writer, name string, names, values []string, v float64) {
+	w.WriteString(name)
+	if len(names) > 0 {
+		w.WriteByte('{')
+		for i, n := range names {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(n)
+			w.WriteString(`="`)
+			w.WriteString(escapeLabel(values[i]))
+			w.WriteByte('"')
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+// validName: [a-zA-Z_][a-zA-Z0-9_]* (metrik adlarında ':' de olur; burada kullanılmaz)
+func validName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Süreç ve Go çalışma zamanı metrikleri (Default'ta her zaman bulunur)
+func init() {
+	start := float64(time.Now().Unix())
+	NewGaugeFunc("process_start_time_seconds", "Start time of the process since unix epoch in seconds", func() float64 { return start })
+	NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist", func() float64 { return float64(runtime.NumGoroutine()) })
+	NewGaugeFunc("go_memstats_heap_alloc_bytes", "Bytes of allocated heap objects", func() float64 { return float64(memStats().HeapAlloc) })
+	NewGaugeFunc("go_memstats_sys_bytes", "Bytes of memory obtained from the OS", func() float64 { return float64(memStats().Sys) })
+	NewCounterFunc("go_gc_cycles_total", "Number of completed GC cycles", func() float64 { return float64(memStats().NumGC) })
+}
+
+// memStats: ReadMemStats dünyayı durdurur; aynı kazıma içindeki okumalar için 1 sn önbellek
+var ms struct {
+	sync.Mutex
+	at time.Time
+	v  runtime.MemStats
+}
+
+func memStats() *runtime.MemStats {
+	ms.Lock()
+	defer ms.Unlock()
+	if time.Since(ms.at) > time.Second {
+		runtime.ReadMemStats(&ms.v)
+		ms.at = time.Now()
+	}
+	v := ms.v
+	return &v
+}
@@ -0,0 +1,103 @@
+package miner
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/wallet"
+)
+
+// ChainBackendOpts: canlı düğüm zinciri üzerinde iş üreten backend ayarları
+type ChainBackendOpts struct {
+	Difficulty func() int                // blok zorluk biti (zorunlu)
+	OnBlock    func(b *blockchain.Block) // blok zincire eklenince (kaydet/yayınla)
+	Coinbase   string                    // doluysa tüm adaylar bu adrese öder (havuz); boşsa işçinin adresine
+}
+
+// chainBackend: düğümün kendi *Blockchain'i üzerinde çalışır (qcLocal gibi ayrı kopya yüklemez).
+// Stratum sunucusu bu backend'den iş alır ve çözümleri ona iletir.
+type chainBackend struct {
+	bc    *blockchain.Blockchain
+	opt   ChainBackendOpts
+	extra atomic.Uint64 // iş başına coinbase ExtraNonce: aynı coinbase'li işçiler aynı başlığı aramaz
+}
+
+// NewChainBackend: canlı zincir için Backend
+func NewChainBackend(bc *blockchain.Blockchain, opt ChainBackendOpts) (Backend, error) {
+	if bc == nil {
+		return nil, errors.New("miner: blockchain is nil")
+	}
+	if opt.Difficulty == nil {
+		return nil, errors.New("miner: difficulty func required")
+	}
+	return &chainBackend{bc: bc, opt: opt}, nil
+}
+
+// GetWork: coinbase'i address'e ödeyen aday blok; Left = HeaderPrefix (kanonik başlığın
+// nonce öncesi 72 baytı), nonce big-endian. Her iş ayrı ExtraNonce alır.
+func (c *chainBackend) GetWork(_ context.Context, address string) (*Work, error) {
+	address = strings.TrimSpace(address)
+	if _, _, err := wallet.DecodeAddress(address); err != nil {
+		return nil, fmt.Errorf("miner address: %w", err)
+	}
+	payTo := address
+	if c.opt.Coinbase != "" {
+		payTo = c.opt.Coinbase
+	}
+	tmpl, err := c.bc.NewCandidateBlock(payTo, c.opt.Difficulty())
+	if err != nil {
+		return nil, err
+	}
+	tmpl.SetExtraNonce(c.extra.Add(1))
+	return &Work{
+		Left:   tmpl.HeaderPrefix(),
+		Target: targetFromBits(tmpl.Difficulty),
+		Height: tmpl.Index,
+		Miner:  address,
+		tmpl:   tmpl,
+	}, nil
+}
+
+// Submit: nonce'u adaya yazar, PoW'u doğrular ve bloğu zincire ekler.
+// Zincir ucu değişmişse iş bayattır: (false, nil).
+func (c *chainBackend) Submit(_ context.Context, w *Work, nonce uint64, _ string) (bool, error) {
+	if w == nil || w.tmpl == nil {
+		return false, errors.New("miner: work not issued by this backend")
+	}
+	if nonce > uint64(int(^uint(0)>>1)) {
+		return false, errors.New("miner: nonce out of range")
+	}
+	h := w.Hash(nonce)
+	if new(big.Int).SetBytes(h[:]).Cmp(w.Target) >= 0 {
+		return false, nil
+	}
+
+	blk := *w.tmpl
+	blk.Nonce = int(nonce)
+	blk.Hash = h[:]
+	if err := c.bc.AddBlockFromPeer(&blk); err != nil {
+		if errors.Is(err, blockchain.ErrPrevHashMismatch) {
+			return false, nil
+		}
+		return false, err
+	}
+	PublishStatus(&blk)
+	if c.opt.OnBlock != nil {
+		c.opt.OnBlock(&blk)
+	}
+	return true, nil
+}
+
+// Tip: zincir ucu (stratum sunucusu değişince yeni iş gönderir)
+func (c *chainBackend) Tip() string {
+	if last := c.bc.GetLastBlock(); last != nil {
+		return hex.EncodeToString(last.Hash)
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package miner
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"quantumcoin/api/client"
+	"quantumcoin/blockchain"
+)
+
+// httpPollEvery: HTTP backend'in zincir ucunu yoklama aralığı (bayat iş tespiti)
+var httpPollEvery = 2 * time.Second
+
+// httpBackend: düğümün HTTP API'sinden (getblocktemplate/submitblock) iş alan Backend
+type httpBackend struct {
+	c    *client.Client
+	bits int
+}
+
+// NewHTTPBackend: c üzerinden şablon alıp blok gönderen Backend (bits 0 => düğüm varsayılanı).
+// Zincir ucu ilerleyince işin Abort kanalı kapanır.
+func NewHTTPBackend(c *client.Client, bits int) Backend {
+	return &httpBackend{c: c, bits: bits}
+}
+
+// GetWork: şablonun HeaderPrefix'i Left olur; şablon Submit için işte saklanır
+func (h *httpBackend) GetWork(ctx context.Context, address string) (*Work, error) {
+	t, err := h.c.BlockTemplate(ctx, address, h.bits)
+	if err != nil {
+		return nil, err
+	}
+	if t.NonceFormat != blockchain.NonceFormatBE64 {
+		return nil, fmt.Errorf("miner: unsupported nonce format %q", t.NonceFormat)
+	}
+	left, err := hex.DecodeString(t.HeaderPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("miner: headerPrefix: %w", err)
+	}
+	target, ok := new(big.Int).SetString(t.Target, 16)
+	if !ok {
+		return nil, fmt.Errorf("miner: bad target %q", t.Target)
+	}
+	abort := make(chan struct{})
+	go h.watch(ctx, t.Height, abort)
+	return &Work{
+		Left:   left,
+		Target: target,
+		Height: t.Height,
+		Miner:  address,
+		Abort:  abort,
+		htmpl:  t,
+	}, nil
+}
+
+// watch: düğüm yüksekliği height'e ulaşınca (blok başkasınca bulundu) abort'u kapatır
+func (h *httpBackend) watch(ctx context.Context, height int, abort chan struct{}) {
+	tk := time.NewTicker(httpPollEvery)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tk.C:
+		}
+		if hs, err := h.c.Health(ctx); err == nil && hs.Height >= height {
+			close(abort)
+			return
+		}
+	}
+}
+
+// Submit: şablon + nonce ile BlockSubmission gönderir. Bayat iş (409) => (false, nil).
+func (h *httpBackend) Submit(ctx context.Context, w *Work, nonce uint64, _ string) (bool, error) {
+	if w == nil || w.htmpl == nil {
+		return false, errors.New("miner: work has no template")
+	}
+	t := w.htmpl
+	txs := make([]string, 0, len(t.Transactions)+1)
+	txs = append(txs, t.Coinbase.Data)
+	for _, tx := range t.Transactions {
+		txs = append(txs, tx.Data)
+	}
+	res, err := h.c.SubmitBlock(ctx, &blockchain.BlockSubmission{
+		Version:      t.Version,
+		Height:       t.Height,
+		PreviousHash: t.PreviousHash,
+		Timestamp:    t.CurTime,
+		Bits:         t.Bits,
+		Nonce:        nonce,
+		Miner:        t.Miner,
+		Transactions: txs,
+	})
+	if client.IsStatus(err, http.StatusConflict) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return res.Accepted, nil
+}
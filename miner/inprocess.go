@@ -0,0 +1,133 @@
+package miner
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"quantumcoin/blockchain"
+)
+
+// HashMeter: gerçek PoW deneme sayacı ve hız ölçümü (hashes/sn)
+type HashMeter struct {
+	total atomic.Uint64
+
+	mu    sync.Mutex
+	lastN uint64
+	lastT time.Time
+	rate  float64
+}
+
+// hashMeterWindow: hız en az bu aralıkla yeniden hesaplanır
+const hashMeterWindow = time.Second
+
+// Counter: blockchain.MineBlockContext'e verilecek sayaç (ilk çağrı ölçüm penceresini açar)
+func (m *HashMeter) Counter() *atomic.Uint64 {
+	m.mu.Lock()
+	if m.lastT.IsZero() {
+		m.lastN, m.lastT = m.total.Load(), time.Now()
+	}
+	m.mu.Unlock()
+	return &m.total
+}
+
+// Total: toplam deneme
+func (m *HashMeter) Total() uint64 { return m.total.Load() }
+
+// Rate: son ölçümden bu yana ortalama hız
+func (m *HashMeter) Rate() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	n := m.total.Load()
+	if m.lastT.IsZero() {
+		m.lastN, m.lastT = n, now
+		return 0
+	}
+	if dt := now.Sub(m.lastT); dt >= hashMeterWindow {
+		m.rate = float64(n-m.lastN) / dt.Seconds()
+		m.lastN, m.lastT = n, now
+	}
+	return m.rate
+}
+
+// MineConfig: MineContext ayarları
+type MineConfig struct {
+	Threads   int           // 0 => runtime.NumCPU()
+	Meter     *HashMeter    // opsiyonel
+	Poll      time.Duration // zincir ucu/mempool kontrol aralığı; 0 => 250ms
+	OnRestart func()        // bayat arama yeniden başlatılınca (opsiyonel)
+}
+
+const defaultMinePoll = 250 * time.Millisecond
+
+// MineContext: ctx iptal edilene ya da blok bulunana dek kazar. Arama sürerken
+// zincir ucu (peer'den blok) ya da mempool değişirse iş bayatlar: aday yeniden
+// kurulur ve arama baştan başlar.
+func MineContext(ctx context.Context, bc *blockchain.Blockchain, address string, difficulty int, mc MineConfig) (*blockchain.Block, error) {
+	if mc.Threads <= 0 {
+		mc.Threads = runtime.NumCPU()
+	}
+	if mc.Poll <= 0 {
+		mc.Poll = defaultMinePoll
+	}
+	var counter *atomic.Uint64
+	if mc.Meter != nil {
+		counter = mc.Meter.Counter()
+	}
+	for {
+		key := workKey(bc)
+		actx, cancel := context.WithCancel(ctx)
+		go watchWork(actx, cancel, bc, key, mc.Poll)
+		blk, err := bc.MineBlockContext(actx, address, difficulty, mc.Threads, counter)
+		cancel()
+		if err == nil {
+			return blk, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, blockchain.ErrStaleBlock) {
+			return nil, err
+		}
+		if mc.OnRestart != nil {
+			mc.OnRestart()
+		}
+	}
+}
+
+// watchWork: iş anahtarı değişince aramayı iptal eder
+func watchWork(ctx context.Context, cancel context.CancelFunc, bc *blockchain.Blockchain, key string, poll time.Duration) {
+	t := time.NewTicker(poll)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if workKey(bc) != key {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// workKey: adayı belirleyen durum (zincir ucu + mempool)
+func workKey(bc *blockchain.Blockchain) string {
+	key := ""
+	if last := bc.GetLastBlock(); last != nil {
+		key = hex.EncodeToString(last.Hash)
+	}
+	pending := bc.PendingTxs()
+	key += ":" + strconv.Itoa(len(pending))
+	if len(pending) > 0 {
+		key += ":" + hex.EncodeToString(pending[len(pending)-1].ID)
+	}
+	return key
+}
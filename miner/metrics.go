@@ -1,6 +1,10 @@
 package miner
 
-import "time"
+import (
+	"time"
+
+	"quantumcoin/metrics"
+)
 
 type MinerActivity struct {
 	Address     string
@@ -29,3 +33,28 @@ func GetTopMiner() string {
 	}
 	return top
 }
+
+// Madenci metrikleri (GET /metrics): düğüm içi madenci ve Stratum sunucusu
+var (
+	mBlocksFound     = metrics.NewCounter("qc_miner_blocks_found_total", "Blocks found by the node's own miner")
+	mBlockSearch     = metrics.NewHistogram("qc_miner_block_search_seconds", "Time the node's miner spent finding a block", metrics.ExpBuckets(0.5, 2, 12))
+	mStratumSessions = metrics.NewGauge("qc_stratum_sessions", "Connected Stratum clients")
+	mStratumShares   = metrics.NewCounterVec("qc_stratum_shares_total", "Stratum shares by result", "result")
+	mStratumBlocks   = metrics.NewCounter("qc_stratum_blocks_found_total", "Blocks found through Stratum shares")
+)
+
+func init() {
+	metrics.NewGaugeFunc("qc_miner_active", "1 if the node's miner is running", func() float64 {
+		if IsActive() {
+			return 1
+		}
+		return 0
+	})
+	metrics.NewGaugeFunc("qc_miner_hashrate", "Measured hashes per second of the node's miner", state.meter.Rate)
+	metrics.NewCounterFunc("qc_miner_hashes_total", "Proof-of-work attempts made by the node's miner", func() float64 {
+		return float64(state.meter.Total())
+	})
+	metrics.NewCounterFunc("qc_miner_restarts_total", "Searches restarted because the tip or mempool changed", func() float64 {
+		return float64(state.restarts.Load())
+	})
+}
@@ -1,12 +1,13 @@
 package miner
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -15,15 +16,25 @@ import (
 	"quantumcoin/blockchain"
 	"quantumcoin/config"
 	"quantumcoin/consolefx"
+	"quantumcoin/events"
 	qint "quantumcoin/internal"
+	"quantumcoin/logging"
 )
 
+var logger = logging.For(logging.SubMiner)
+
 // MiningStatus: dışa raporlanan durum
 type MiningStatus struct {
 	BlockHeight int
 	BlockHash   []byte
 	Reward      int
 	Timestamp   time.Time
+
+	Active   bool
+	Threads  int
+	Hashrate float64 // ölçülen deneme/sn (HashMeter)
+	Hashes   uint64  // toplam deneme
+	Restarts uint64  // zincir ucu/mempool değişince yeniden başlatılan aramalar
 }
 
 // Options: başlatma seçenekleri ve geri çağrılar
@@ -34,6 +45,7 @@ type Options struct {
 	Interval    time.Duration                                  // Blok bulduktan sonra bekleme
 	Broadcaster func(b *blockchain.Block)                      // P2P yayıncı (opsiyonel)
 	Animate     bool                                           // Terminal animasyon
+	Threads     int                                            // PoW goroutine sayısı; 0 => CPU sayısı
 }
 
 // ---- iç durum ----
@@ -47,6 +59,7 @@ var (
 type minerState struct {
 	active     atomic.Bool
 	stopCh     chan struct{}
+	cancel     context.CancelFunc // sürmekte olan aramayı keser
 	wg         sync.WaitGroup
 	bc         *blockchain.Blockchain
 	address    string
@@ -56,7 +69,9 @@ type minerState struct {
 	// görsel/istatistik
 	effect   *Effect
 	step     int
-	hashrate float64
+	meter    HashMeter
+	restarts atomic.Uint64
+	last     atomic.Pointer[MiningStatus] // son bulunan blok
 }
 
 /*
@@ -137,6 +152,10 @@ func Start(bc *blockchain.Blockchain, minerAddress string, difficulty int, opts
 			merged.Broadcaster = o.Broadcaster
 		}
 		merged.Animate = o.Animate
+		merged.Threads = o.Threads
+	}
+	if merged.Threads <= 0 {
+		merged.Threads = runtime.NumCPU()
 	}
 
 	state.bc = bc
@@ -144,6 +163,8 @@ func Start(bc *blockchain.Blockchain, minerAddress string, difficulty int, opts
 	state.difficulty = difficulty
 	state.opts = merged
 	state.stopCh = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	state.cancel = cancel
 	if merged.Animate {
 		state.effect = NewEffect("QC")
 	} else {
@@ -152,7 +173,8 @@ func Start(bc *blockchain.Blockchain, minerAddress string, difficulty int, opts
 
 	state.active.Store(true)
 	state.wg.Add(1)
-	go loop()
+	go loop(ctx)
+	PublishStatus(nil)
 
 	return nil
 }
@@ -169,11 +191,13 @@ func Stop() {
 	default:
 		close(state.stopCh)
 	}
+	state.cancel() // sürmekte olan PoW aramasını bekletmeden kes
 	state.wg.Wait()
 	state.active.Store(false)
 	if state.effect != nil {
 		state.effect.Clear()
 	}
+	PublishStatus(nil)
 }
 
 // IsActive: çalışıyor mu?
@@ -181,6 +205,31 @@ func IsActive() bool {
 	return state.active.Load()
 }
 
+// CurrentStatus: anlık madenci durumu (son blok + ölçülen hashrate)
+func CurrentStatus() MiningStatus {
+	var st MiningStatus
+	if last := state.last.Load(); last != nil {
+		st = *last
+	}
+	st.Active = IsActive()
+	st.Threads = state.opts.Threads
+	st.Hashrate = state.meter.Rate()
+	st.Hashes = state.meter.Total()
+	st.Restarts = state.restarts.Load()
+	return st
+}
+
+// PublishStatus: madenci durumunu events.Default'a yayınlar; blk nil değilse
+// yeni bulunan blok olarak bildirilir (AI bonusu, token köprüsü vb. dinler)
+func PublishStatus(blk *blockchain.Block) {
+	st := CurrentStatus()
+	ev := events.MinerStatus{Active: st.Active, Threads: st.Threads, Hashrate: st.Hashrate, Hashes: st.Hashes, Block: blk}
+	if blk != nil && len(blk.Transactions) > 0 && len(blk.Transactions[0].Outputs) > 0 {
+		ev.Reward = blk.Transactions[0].Outputs[0].Amount
+	}
+	events.Default.Publish(ev)
+}
+
 // MineOne: tek seferlik blok kazı (paylaşılan bc ile)
 func MineOne(bc *blockchain.Blockchain, address string, difficulty int) (*blockchain.Block, error) {
 	if bc == nil {
@@ -205,9 +254,11 @@ func MineOne(bc *blockchain.Blockchain, address string, difficulty int) (*blockc
 		return nil, err
 	}
 	LogBlock(block)
+	mBlocksFound.Inc()
+	mBlockSearch.Observe(elapsed.Seconds())
 
 	rw := blockchain.GetCurrentReward()
-	fmt.Printf("✨ Reward: %d QC (elapsed %.2fs)\n", rw, elapsed.Seconds())
+	logger.Info("block reward", "height", block.Index, "reward", rw, "secs", elapsed.Seconds())
 	showSplitInfoPreview()
 	checkYearlyBonus(address)
 	TrackMiner(address) // sende varsa
@@ -219,7 +270,7 @@ func MineOne(bc *blockchain.Blockchain, address string, difficulty int) (*blockc
 }
 
 // ---- iç döngü ----
-func loop() {
+func loop(ctx context.Context) {
 	defer state.wg.Done()
 
 	for {
@@ -229,21 +280,20 @@ func loop() {
 		default:
 		}
 
-		// canlı animasyon karesi
-		if state.opts.Animate && state.effect != nil {
-			nextH := state.bc.GetBestHeight() + 1
-			state.effect.Frame(state.step, nextH, state.difficulty, state.hashrate)
-			state.step++
-		}
-
+		stopAnim := animate(ctx)
 		start := time.Now()
-		block, err := state.bc.MineBlock(state.address, state.difficulty)
+		block, err := MineContext(ctx, state.bc, state.address, state.difficulty, MineConfig{
+			Threads:   state.opts.Threads,
+			Meter:     &state.meter,
+			OnRestart: func() { state.restarts.Add(1) },
+		})
 		dur := time.Since(start)
-
-		// kaba hashrate kestirimi (sadece görsel)
-		state.hashrate = estimateHashrate(dur, state.difficulty)
+		stopAnim()
 
 		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
 			if state.opts.OnError != nil {
 				state.opts.OnError(err)
 			}
@@ -251,26 +301,31 @@ func loop() {
 			continue
 		}
 
+		mBlocksFound.Inc()
+		mBlockSearch.Observe(dur.Seconds())
 		status := MiningStatus{
 			BlockHeight: block.Index,
 			BlockHash:   block.Hash,
 			Reward:      blockchain.GetCurrentReward(),
 			Timestamp:   time.Now(),
+			Active:      true,
+			Threads:     state.opts.Threads,
+			Hashrate:    state.meter.Rate(),
+			Hashes:      state.meter.Total(),
+			Restarts:    state.restarts.Load(),
 		}
+		state.last.Store(&status)
+		PublishStatus(block)
 
-		if state.effect != nil {
-			state.effect.Clear()
-		}
-
-		fmt.Printf("🚀 New block #%d mined by %s  (hash=%x, t=%.2fs)\n",
-			block.Index, state.address, block.Hash, dur.Seconds())
+		logger.Info("block mined", "height", block.Index, "miner", state.address,
+			"hash", fmt.Sprintf("%x", block.Hash), "secs", dur.Seconds(), "hashrate", status.Hashrate,
+			"reward", status.Reward)
 
 		// ✨ spinner’ı ALT satırda ve interval süresince göster
 		if state.opts.Interval > 0 {
 			consolefx.SpinFor(state.opts.Interval)
 		}
 
-		fmt.Printf("💰 Reward: %d QC\n", status.Reward)
 		showSplitInfoPreview()
 		checkYearlyBonus(state.address)
 		TrackMiner(state.address) // sende varsa
@@ -294,9 +349,37 @@ func loop() {
 	}
 }
 
-// LogBlock: basit log çıktısı
+// animate: arama sürerken canlı animasyon karesi (ölçülen hashrate ile); dönen fonksiyon durdurur
+func animate(ctx context.Context) func() {
+	if !state.opts.Animate || state.effect == nil {
+		return func() {}
+	}
+	actx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t := time.NewTicker(250 * time.Millisecond)
+		defer t.Stop()
+		for {
+			state.effect.Frame(state.step, state.bc.GetBestHeight()+1, state.difficulty, state.meter.Rate())
+			state.step++
+			select {
+			case <-actx.Done():
+				return
+			case <-t.C:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+		state.effect.Clear()
+	}
+}
+
+// LogBlock: bulunan bloğu miner logger'ına yazar
 func LogBlock(b *blockchain.Block) {
-	log.Printf("🚀 New block: idx=%d hash=%x", b.Index, b.Hash)
+	logger.Info("block mined", "height", b.Index, "miner", b.Miner, "hash", fmt.Sprintf("%x", b.Hash))
 }
 
 // ---- Yıllık bonus + NFT tetikleyici ----
@@ -324,11 +407,11 @@ func checkYearlyBonus(address string) {
 	// NFT hediyesi (stub)
 	GrantNFTReward(address) // sende varsa
 
-	fmt.Println("✨ Annual 100 QC + Rare NFT awarded!")
+	logger.Info("annual bonus awarded", "address", address, "amount", 100, "year", yearIdx)
 	yearlyGiven[address] = yearIdx
 }
 
-// ödül bölüşümü bilgisi (görsel/log; coinbase split chain tarafında uygulanıyor varsayımı)
+// ödül bölüşümü bilgisi (görsel/log; bölüşüm ağ parametresidir, chain tarafında uygulanır)
 func showSplitInfoPreview() {
 	cfg := config.Current()
 	if cfg == nil || cfg.InitialReward <= 0 {
@@ -338,16 +421,16 @@ func showSplitInfoPreview() {
 	if base <= 0 {
 		return
 	}
-	miner := base * float64(cfg.RewardPctMiner) / 100.0
-	stake := base * float64(cfg.RewardPctStake) / 100.0
-	dev := base * float64(cfg.RewardPctDev) / 100.0
-	burn := base * float64(cfg.RewardPctBurn) / 100.0
+	r := cfg.Params().Reward
+	miner := base * float64(r.PctMiner) / 100.0
+	stake := base * float64(r.PctStake) / 100.0
+	dev := base * float64(r.PctDev) / 100.0
+	burn := base * float64(r.PctBurn) / 100.0
 	remain := base - (miner + stake + dev + burn)
 	if remain < 0 {
 		remain = 0
 	}
-	fmt.Printf("🧮 split preview → miner:%.2f stake:%.2f dev:%.2f burn:%.2f community:%.2f\n",
-		miner, stake, dev, burn, remain)
+	logger.Debug("reward split preview", "miner", miner, "stake", stake, "dev", dev, "burn", burn, "community", remain)
 }
 
 // ---- Geriye dönük uyumluluk katmanı ----
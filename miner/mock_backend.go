@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"math/big"
+
+	"quantumcoin/blockchain"
 )
 
-// Basit bir sahte backend: rastgele iş (Left/Right) verir,
+// Basit bir sahte backend: rastgele iş (Left) verir,
 // hedefi (Target) kolay ayarlar ve Submit'te sanity check yapar.
 type mockBackend struct {
 	target *big.Int
@@ -22,16 +24,14 @@ func NewMockBackend(addr string) Backend {
 }
 
 func (m *mockBackend) GetWork(_ context.Context, address string) (*Work, error) {
-	left := make([]byte, 64)
-	right := make([]byte, 48)
+	// Kanonik başlık önekiyle aynı boyda rastgele önek (midstate yolu)
+	left := make([]byte, blockchain.HeaderPrefixSize)
 	_, _ = rand.Read(left)
-	_, _ = rand.Read(right)
 
 	m.height++
 
 	return &Work{
 		Left:   left,
-		Right:  append(right, []byte(address)...), // küçük karışım
 		Target: new(big.Int).Set(m.target),
 		Height: m.height,
 		Miner:  address,
@@ -40,6 +40,6 @@ func (m *mockBackend) GetWork(_ context.Context, address string) (*Work, error)
 
 func (m *mockBackend) Submit(_ context.Context, w *Work, nonce uint64, _ string) (bool, error) {
 	// Worker zaten hedefe göre kontrol etti; yine de sanity check:
-	h := hashCandidate(w.Left, nonce, w.Right)
+	h := w.Hash(nonce)
 	return new(big.Int).SetBytes(h[:]).Cmp(w.Target) <= 0, nil
 }
@@ -2,7 +2,6 @@ package miner
 
 import (
 	"fmt"
-	"log"
 	"time"
 
 	"quantumcoin/blockchain"
@@ -29,9 +28,7 @@ func GrantNFTBonus(address, nftType string, bc *blockchain.Blockchain) {
 		Time:    time.Now(),
 	}
 
-	// Terminal çıktısı
-	fmt.Printf("🎁 %s adresine '%s' türünde NFT ödülü verildi! (%s)\n",
-		reward.Address, reward.Type, reward.Time.Format("02 Jan 2006 15:04:05"))
+	logger.Info("nft reward granted", "address", reward.Address, "type", reward.Type)
 
 	// 1) Zincir üstü kayıt (stub; gerçek mint akışına bağlanacak)
 	if bc != nil {
@@ -40,17 +37,17 @@ func GrantNFTBonus(address, nftType string, bc *blockchain.Blockchain) {
 			"date":   reward.Time.Format(time.RFC3339),
 		})
 		if err != nil {
-			log.Printf("[NFT] zincir üstü kayıt başarısız: %v", err)
+			logger.Warn("nft reward mint failed", "address", reward.Address, "type", reward.Type, "err", err)
 		} else {
 			reward.TxID = txID
-			log.Printf("[NFT] zincir üstü kayıt tamamlandı, TxID: %s", txID)
+			logger.Info("nft reward minted", "address", reward.Address, "type", reward.Type, "txid", txID)
 		}
 	}
 
 	// 2) Veritabanı hook'u (opsiyonel)
 	if SaveNFTRewardHook != nil {
 		if err := SaveNFTRewardHook(reward.Address, reward.Type, reward.TxID, reward.Time); err != nil {
-			log.Printf("[NFT] veritabanı kaydı başarısız: %v", err)
+			logger.Warn("nft reward save failed", "address", reward.Address, "type", reward.Type, "err", err)
 		}
 	}
 
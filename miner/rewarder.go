@@ -1,9 +1,7 @@
 package miner
 
-import "log"
-
 // GrantNFTReward: bonus/NFT tetikleyici (stub – log atar)
 // NOT: Bu pakette aynı isimli ikinci tanım olmamalı (nft_miner.go içinde yok).
 func GrantNFTReward(address string) {
-	log.Printf("🎁 NFT reward granted to %s (stub)", address)
+	logger.Info("nft reward granted (stub)", "address", address)
 }
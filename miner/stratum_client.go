@@ -0,0 +1,245 @@
+package miner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"quantumcoin/blockchain"
+)
+
+// StratumOpts: cmd/miner "stratum" modu için bağlantı ayarları
+type StratumOpts struct {
+	Addr     string // host:port
+	User     string // ödül adresi ("adres.işçi" de olur)
+	Password string
+	Agent    string
+}
+
+// stratumClient: düğümün Stratum sunucusuna bağlanan Backend
+type stratumClient struct {
+	opt  StratumOpts
+	conn net.Conn
+
+	wmu    sync.Mutex
+	mu     sync.Mutex
+	nextID uint64
+	wait   map[uint64]chan StratumMessage
+
+	bits   int
+	cur    *Work
+	abort  chan struct{}
+	workCh chan struct{} // ilk iş gelince kapanır
+	once   sync.Once
+	err    error
+	done   chan struct{}
+}
+
+// NewStratumBackend: bağlanır, abone olur ve yetkilendirir
+func NewStratumBackend(opt StratumOpts) (Backend, error) {
+	if opt.Agent == "" {
+		opt.Agent = "qc-miner/1.0"
+	}
+	conn, err := net.DialTimeout("tcp", opt.Addr, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("stratum dial: %w", err)
+	}
+	c := &stratumClient{
+		opt:    opt,
+		conn:   conn,
+		wait:   map[uint64]chan StratumMessage{},
+		workCh: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go c.readLoop()
+
+	if _, err := c.call("mining.subscribe", []any{opt.Agent}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	res, err := c.call("mining.authorize", []any{opt.User, opt.Password})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if ok, _ := res.(bool); !ok {
+		conn.Close()
+		return nil, errors.New("stratum: authorization rejected")
+	}
+	return c, nil
+}
+
+// GetWork: sunucunun en son bildirdiği iş (ilk iş gelene kadar bekler)
+func (c *stratumClient) GetWork(ctx context.Context, _ string) (*Work, error) {
+	select {
+	case <-c.done:
+		return nil, c.closedErr()
+	default:
+	}
+	select {
+	case <-c.workCh:
+	case <-c.done:
+		return nil, c.closedErr()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cur == nil {
+		return nil, errors.New("stratum: no work")
+	}
+	return c.cur, nil
+}
+
+// Submit: payı gönderir; sunucu kabul ederse true
+func (c *stratumClient) Submit(_ context.Context, w *Work, nonce uint64, _ string) (bool, error) {
+	var nb [8]byte
+	binary.BigEndian.PutUint64(nb[:], nonce)
+	res, err := c.call("mining.submit", []any{c.opt.User, w.JobID, hex.EncodeToString(nb[:])})
+	if err != nil {
+		return false, err
+	}
+	ok, _ := res.(bool)
+	return ok, nil
+}
+
+func (c *stratumClient) call(method string, params []any) (any, error) {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	ch := make(chan StratumMessage, 1)
+	c.wait[id] = ch
+	c.mu.Unlock()
+
+	b, _ := json.Marshal(map[string]any{"id": id, "method": method, "params": params})
+	c.wmu.Lock()
+	_, err := c.conn.Write(append(b, '\n'))
+	c.wmu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("stratum %s: %w", method, err)
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("stratum %s: %v", method, resp.Error)
+		}
+		return resp.Result, nil
+	case <-c.done:
+		return nil, c.closedErr()
+	case <-time.After(30 * time.Second):
+		c.mu.Lock()
+		delete(c.wait, id)
+		c.mu.Unlock()
+		return nil, fmt.Errorf("stratum %s: timeout", method)
+	}
+}
+
+func (c *stratumClient) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	return errors.New("stratum: connection closed")
+}
+
+func (c *stratumClient) readLoop() {
+	defer close(c.done)
+	sc := bufio.NewScanner(c.conn)
+	sc.Buffer(make([]byte, 0, 4096), stratumMaxLineLen)
+	for sc.Scan() {
+		var msg StratumMessage
+		if err := json.Unmarshal(sc.Bytes(), &msg); err != nil {
+			continue
+		}
+		if msg.Method != "" {
+			c.handleNotify(&msg)
+			continue
+		}
+		if msg.ID == nil {
+			continue
+		}
+		c.mu.Lock()
+		ch := c.wait[*msg.ID]
+		delete(c.wait, *msg.ID)
+		c.mu.Unlock()
+		if ch != nil {
+			ch <- msg
+		}
+	}
+	c.mu.Lock()
+	c.err = sc.Err()
+	if c.abort != nil {
+		close(c.abort)
+		c.abort = nil
+	}
+	c.mu.Unlock()
+}
+
+func (c *stratumClient) handleNotify(msg *StratumMessage) {
+	var params []json.RawMessage
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return
+	}
+	switch msg.Method {
+	case "mining.set_difficulty":
+		var bits int
+		if len(params) > 0 && json.Unmarshal(params[0], &bits) == nil {
+			c.mu.Lock()
+			c.bits = bits
+			c.mu.Unlock()
+		}
+	case "mining.notify":
+		var (
+			jobID, left, right, format string
+			height                     int
+			clean                      bool
+		)
+		if len(params) < 6 ||
+			json.Unmarshal(params[0], &jobID) != nil ||
+			json.Unmarshal(params[1], &left) != nil ||
+			json.Unmarshal(params[2], &right) != nil ||
+			json.Unmarshal(params[3], &height) != nil ||
+			json.Unmarshal(params[4], &clean) != nil ||
+			json.Unmarshal(params[5], &format) != nil {
+			logger.Warn("stratum: malformed notify")
+			return
+		}
+		if format != blockchain.NonceFormatBE64 {
+			logger.Warn("stratum: unsupported nonce format", "format", format)
+			return
+		}
+		lb, err1 := hex.DecodeString(left)
+		rb, err2 := hex.DecodeString(right)
+		if err1 != nil || err2 != nil {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		// Her yeni iş öncekini geçersiz kılar (worker aramayı bırakıp GetWork'e döner)
+		if c.abort != nil {
+			close(c.abort)
+		}
+		c.abort = make(chan struct{})
+		c.cur = &Work{
+			Left:   lb,
+			Right:  rb,
+			Target: targetFromBits(c.bits),
+			Height: height,
+			Miner:  strings.SplitN(c.opt.User, ".", 2)[0],
+			JobID:  jobID,
+			Abort:  c.abort,
+		}
+		c.once.Do(func() { close(c.workCh) })
+	}
+}
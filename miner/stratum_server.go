@@ -0,0 +1,473 @@
+package miner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/wallet"
+)
+
+// Stratum tarzı madencilik protokolü (TCP, satır başına bir JSON mesajı).
+//
+// İstemci → sunucu (id'li istek, sunucu {id, result, error} ile yanıtlar):
+//
+//	mining.subscribe  [agent]                 -> [session_id, extranonce1, extranonce2_size]
+//	mining.authorize  [address, password]     -> true
+//	mining.submit     [worker, job_id, nonce] -> true   (nonce: 16 hex = uint64 big-endian)
+//
+// Sunucu → istemci (id'siz bildirim):
+//
+//	mining.set_difficulty [share_bits]
+//	mining.notify         [job_id, left_hex, right_hex, height, clean_jobs, nonce_format]
+//
+// PoW = sha256(left || nonce || right); nonce_format "be64" (8 bayt big-endian).
+// Zincir işlerinde left kanonik başlığın ilk 72 baytıdır (blockchain/header.go),
+// right boştur; iş başına coinbase ExtraNonce farklıdır. share_bits blok zorluğundan küçük
+// olabilir: hedefi tutan her çözüm pay (share) sayılır, blok hedefini tutanlar
+// ayrıca Backend.Submit ile zincire iletilir.
+//
+// Hata kodları: 20 diğer, 21 iş bulunamadı, 22 tekrar pay, 23 düşük zorluk, 24 yetkisiz, 25 abone değil.
+
+const (
+	stratumErrOther         = 20
+	stratumErrJobNotFound   = 21
+	stratumErrDuplicate     = 22
+	stratumErrLowDiff       = 23
+	stratumErrUnauthorized  = 24
+	stratumErrNotSubscribed = 25
+
+	stratumMaxJobs    = 8
+	stratumMaxLineLen = 16 * 1024
+)
+
+// StratumMessage: tel formatı (istek, yanıt ve bildirim aynı zarfı kullanır)
+type StratumMessage struct {
+	ID     *uint64         `json:"id"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result any             `json:"result,omitempty"`
+	Error  any             `json:"error,omitempty"`
+}
+
+// StratumShare: kabul edilen her pay için OnShare'e iletilen bilgi
+type StratumShare struct {
+	Worker    string
+	Address   string
+	JobID     string
+	Height    int
+	ShareBits int
+	Hash      string
+	Block     bool // blok hedefini de tuttu ve zincire eklendi
+	Time      time.Time
+}
+
+// StratumServerOpts: sunucu ayarları
+type StratumServerOpts struct {
+	ShareBits func(worker string) int    // işçi başına pay zorluğu; 0/nil => blok zorluğu (solo)
+	Refresh   time.Duration              // uç değişmese de yeni iş (mempool) aralığı; varsayılan 30s
+	OnShare   func(s StratumShare) error // geçerli paylar (havuz muhasebesi vb.); hata => pay reddedilir (bayat vb.)
+}
+
+// StratumServer: Backend'den iş alıp Stratum istemcilerine dağıtır
+type StratumServer struct {
+	backend Backend
+	opt     StratumServerOpts
+
+	ln       net.Listener
+	mu       sync.Mutex
+	sessions map[*stratumSession]struct{}
+	nextSess atomic.Uint64
+	nextJob  atomic.Uint64
+	closed   chan struct{}
+}
+
+// NewStratumServer: backend genellikle NewChainBackend ile kurulur
+func NewStratumServer(backend Backend, opt StratumServerOpts) *StratumServer {
+	if opt.Refresh <= 0 {
+		opt.Refresh = 30 * time.Second
+	}
+	return &StratumServer{
+		backend:  backend,
+		opt:      opt,
+		sessions: map[*stratumSession]struct{}{},
+		closed:   make(chan struct{}),
+	}
+}
+
+// ListenAndServe: addr (":3333") üzerinde bağlantı kabul eder; Close'a kadar döner
+func (s *StratumServer) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve: hazır listener üzerinde çalışır
+func (s *StratumServer) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	s.ln = ln
+	s.mu.Unlock()
+	go s.watchTip()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			select {
+			case <-s.closed:
+				return nil
+			default:
+			}
+			return err
+		}
+		sess := &stratumSession{
+			srv:  s,
+			conn: conn,
+			id:   s.nextSess.Add(1),
+			jobs: map[string]*Work{},
+		}
+		s.mu.Lock()
+		s.sessions[sess] = struct{}{}
+		s.mu.Unlock()
+		mStratumSessions.Inc()
+		go sess.serve()
+	}
+}
+
+// Close: dinlemeyi ve tüm oturumları kapatır
+func (s *StratumServer) Close() error {
+	select {
+	case <-s.closed:
+		return nil
+	default:
+		close(s.closed)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sess := range s.sessions {
+		_ = sess.conn.Close()
+	}
+	if s.ln != nil {
+		return s.ln.Close()
+	}
+	return nil
+}
+
+// Sessions: bağlı istemci sayısı
+func (s *StratumServer) Sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+func (s *StratumServer) shareBits(worker string, blockBits int) int {
+	if s.opt.ShareBits == nil {
+		return blockBits
+	}
+	b := s.opt.ShareBits(worker)
+	if b <= 0 || b > blockBits {
+		return blockBits
+	}
+	return b
+}
+
+// watchTip: zincir ucu değişince (ya da Refresh dolunca) herkese yeni iş
+func (s *StratumServer) watchTip() {
+	tipper, _ := s.backend.(interface{ Tip() string })
+	last := ""
+	if tipper != nil {
+		last = tipper.Tip()
+	}
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	lastPush := time.Now()
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-tick.C:
+		}
+		clean := false
+		if tipper != nil {
+			if t := tipper.Tip(); t != last {
+				last, clean = t, true
+			}
+		}
+		if !clean && time.Since(lastPush) < s.opt.Refresh {
+			continue
+		}
+		lastPush = time.Now()
+		s.mu.Lock()
+		list := make([]*stratumSession, 0, len(s.sessions))
+		for sess := range s.sessions {
+			list = append(list, sess)
+		}
+		s.mu.Unlock()
+		for _, sess := range list {
+			sess.pushJob(clean)
+		}
+	}
+}
+
+func (s *StratumServer) drop(sess *stratumSession) {
+	s.mu.Lock()
+	if _, ok := s.sessions[sess]; ok {
+		delete(s.sessions, sess)
+		mStratumSessions.Dec()
+	}
+	s.mu.Unlock()
+}
+
+// ---- oturum ----
+
+type stratumSession struct {
+	srv  *StratumServer
+	conn net.Conn
+	id   uint64
+
+	wmu sync.Mutex // yazma sırası
+
+	mu         sync.Mutex
+	subscribed bool
+	address    string
+	worker     string
+	jobs       map[string]*Work
+	seen       map[string]map[uint64]bool // iş -> gönderilmiş nonce'lar (tekrar pay engeli)
+	order      []string
+	bits       int
+}
+
+func (c *stratumSession) serve() {
+	defer c.srv.drop(c)
+	defer c.conn.Close()
+
+	sc := bufio.NewScanner(c.conn)
+	sc.Buffer(make([]byte, 0, 1024), stratumMaxLineLen)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var msg StratumMessage
+		if err := json.Unmarshal([]byte(line), &msg); err != nil || msg.ID == nil {
+			c.reply(msg.ID, nil, stratumErr(stratumErrOther, "malformed request"))
+			continue
+		}
+		switch msg.Method {
+		case "mining.subscribe":
+			c.mu.Lock()
+			c.subscribed = true
+			c.mu.Unlock()
+			sid := strconv.FormatUint(c.id, 16)
+			c.reply(msg.ID, []any{sid, fmt.Sprintf("%08x", c.id), 0}, nil)
+		case "mining.authorize":
+			c.authorize(&msg)
+		case "mining.submit":
+			c.submit(&msg)
+		default:
+			c.reply(msg.ID, nil, stratumErr(stratumErrOther, "unknown method "+msg.Method))
+		}
+	}
+}
+
+func (c *stratumSession) authorize(msg *StratumMessage) {
+	var params []string
+	if err := json.Unmarshal(msg.Params, &params); err != nil || len(params) == 0 {
+		c.reply(msg.ID, nil, stratumErr(stratumErrOther, "params: [address, password]"))
+		return
+	}
+	c.mu.Lock()
+	subscribed := c.subscribed
+	c.mu.Unlock()
+	if !subscribed {
+		c.reply(msg.ID, nil, stratumErr(stratumErrNotSubscribed, "not subscribed"))
+		return
+	}
+	// "adres.işçi" biçimi desteklenir
+	addr, worker, _ := strings.Cut(strings.TrimSpace(params[0]), ".")
+	if _, _, err := wallet.DecodeAddress(addr); err != nil {
+		c.reply(msg.ID, false, stratumErr(stratumErrUnauthorized, "invalid address"))
+		return
+	}
+	c.mu.Lock()
+	c.address = addr
+	c.worker = params[0]
+	if worker == "" {
+		c.worker = addr
+	}
+	c.mu.Unlock()
+	c.reply(msg.ID, true, nil)
+	c.pushJob(true)
+}
+
+// pushJob: backend'den yeni iş alır, gerekirse zorluğu ve işi bildirir
+func (c *stratumSession) pushJob(clean bool) {
+	c.mu.Lock()
+	addr, worker := c.address, c.worker
+	c.mu.Unlock()
+	if addr == "" {
+		return
+	}
+	w, err := c.srv.backend.GetWork(context.Background(), addr)
+	if err != nil || w == nil || w.Target == nil {
+		logger.Warn("stratum: getwork failed", "address", addr, "err", err)
+		return
+	}
+	blockBits := bitsFromTarget(w.Target)
+	bits := c.srv.shareBits(worker, blockBits)
+
+	jobID := strconv.FormatUint(c.srv.nextJob.Add(1), 16)
+	w.JobID = jobID
+
+	c.mu.Lock()
+	if clean || c.seen == nil {
+		c.jobs = map[string]*Work{}
+		c.seen = map[string]map[uint64]bool{}
+		c.order = nil
+	}
+	c.jobs[jobID] = w
+	c.seen[jobID] = map[uint64]bool{}
+	c.order = append(c.order, jobID)
+	if len(c.order) > stratumMaxJobs {
+		delete(c.jobs, c.order[0])
+		delete(c.seen, c.order[0])
+		c.order = c.order[1:]
+	}
+	bitsChanged := c.bits != bits
+	c.bits = bits
+	c.mu.Unlock()
+
+	if bitsChanged {
+		c.notify("mining.set_difficulty", []any{bits})
+	}
+	c.notify("mining.notify", []any{
+		jobID, hex.EncodeToString(w.Left), hex.EncodeToString(w.Right), w.Height, clean, blockchain.NonceFormatBE64,
+	})
+}
+
+func (c *stratumSession) submit(msg *StratumMessage) {
+	var params []string
+	if err := json.Unmarshal(msg.Params, &params); err != nil || len(params) < 3 {
+		c.reply(msg.ID, nil, stratumErr(stratumErrOther, "params: [worker, job_id, nonce]"))
+		return
+	}
+	c.mu.Lock()
+	addr, worker, bits := c.address, c.worker, c.bits
+	w := c.jobs[params[1]]
+	c.mu.Unlock()
+	if addr == "" {
+		mStratumShares.With("unauthorized").Inc()
+		c.reply(msg.ID, nil, stratumErr(stratumErrUnauthorized, "unauthorized worker"))
+		return
+	}
+	if w == nil {
+		mStratumShares.With("stale").Inc()
+		c.reply(msg.ID, nil, stratumErr(stratumErrJobNotFound, "job not found"))
+		return
+	}
+	nonce, err := parseStratumNonce(params[2])
+	if err != nil {
+		mStratumShares.With("invalid").Inc()
+		c.reply(msg.ID, nil, stratumErr(stratumErrOther, err.Error()))
+		return
+	}
+	c.mu.Lock()
+	dup := c.seen[params[1]][nonce]
+	if seen := c.seen[params[1]]; seen != nil {
+		seen[nonce] = true
+	}
+	c.mu.Unlock()
+	if dup {
+		mStratumShares.With("duplicate").Inc()
+		c.reply(msg.ID, nil, stratumErr(stratumErrDuplicate, "duplicate share"))
+		return
+	}
+
+	h := w.Hash(nonce)
+	hv := new(big.Int).SetBytes(h[:])
+	if hv.Cmp(targetFromBits(bits)) >= 0 {
+		mStratumShares.With("low_difficulty").Inc()
+		c.reply(msg.ID, nil, stratumErr(stratumErrLowDiff, "low difficulty share"))
+		return
+	}
+	hashHex := hex.EncodeToString(h[:])
+
+	isBlock := false
+	if hv.Cmp(w.Target) < 0 {
+		ok, err := c.srv.backend.Submit(context.Background(), w, nonce, hashHex)
+		if err != nil {
+			logger.Error("stratum: block submit failed", "worker", worker, "height", w.Height, "err", err)
+		}
+		isBlock = ok
+		if ok {
+			mStratumBlocks.Inc()
+			logger.Info("stratum: block found", "worker", worker, "height", w.Height, "hash", hashHex)
+		}
+	}
+
+	if c.srv.opt.OnShare != nil {
+		err := c.srv.opt.OnShare(StratumShare{
+			Worker: worker, Address: addr, JobID: w.JobID, Height: w.Height,
+			ShareBits: bits, Hash: hashHex, Block: isBlock, Time: time.Now(),
+		})
+		if err != nil {
+			mStratumShares.With("stale").Inc()
+			c.reply(msg.ID, nil, stratumErr(stratumErrJobNotFound, err.Error()))
+			return
+		}
+	}
+	mStratumShares.With("accepted").Inc()
+	c.reply(msg.ID, true, nil)
+}
+
+func (c *stratumSession) reply(id *uint64, result any, errv any) {
+	c.write(map[string]any{"id": id, "result": result, "error": errv})
+}
+
+func (c *stratumSession) notify(method string, params []any) {
+	c.write(map[string]any{"id": nil, "method": method, "params": params})
+}
+
+func (c *stratumSession) write(v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.conn.Write(append(b, '\n')); err != nil {
+		_ = c.conn.Close()
+	}
+}
+
+func stratumErr(code int, msg string) []any { return []any{code, msg, nil} }
+
+// parseStratumNonce: 16 hex karakter (uint64 big-endian)
+func parseStratumNonce(s string) (uint64, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil || len(b) != 8 {
+		return 0, errors.New("nonce must be 16 hex chars")
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// bitsFromTarget: targetFromBits'in tersi (2^(256-bits))
+func bitsFromTarget(t *big.Int) int {
+	if t == nil || t.Sign() <= 0 {
+		return 0
+	}
+	return 256 - (t.BitLen() - 1)
+}
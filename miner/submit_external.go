@@ -1,18 +0,0 @@
-// miner/submit_external.go
-package miner
-
-// Bu dosya, web’den gelen geçerli bir çözümü chain’e entegre etme köprüsü.
-// Aşağıda sadece iskelet; kendi blok oluşturma/ödül akışına bağla.
-
-func SubmitExternalSolution(address string, challenge string, nonce uint32, hashHex string) (blockHash string, rewarded bool) {
-	// 1) challenge'ın şu anki aktif iş’e ait olduğunu doğrula (replay koruması).
-	// 2) assemble_block.go üzerinden bir block template oluştur.
-	// 3) coinbase'i 'address'e yönlendir (ödül + yakım/fee kuralın).
-	// 4) header.nonce = nonce, header.extra = challenge (istersen).
-	// 5) difficulty/target doğrulaması zaten VerifyWebSolution ile uyumlu.
-	// 6) chain'e ekle, P2P broadcast et.
-	// 7) başarılıysa (blockHash != "") rewarded = true.
-
-	// Şimdilik stub:
-	return "", false
-}
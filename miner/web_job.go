@@ -1,60 +1,114 @@
 package miner
 
 import (
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/binary"
+	"context"
 	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"quantumcoin/api/wire"
+	"quantumcoin/blockchain"
 )
 
-// WebDifficulty: tarayıcı miner için basit model — leading HEX '0' sayısı.
-var WebDifficulty = 5 // config ile besleyebilirsin
+// WebJobs: tarayıcı/HTTP madencileri için solo iş yöneticisi. İş, Backend'in
+// ürettiği blok adayıdır (Left = kanonik başlık öneki); istemci
+// sha256(left || nonce(8 bayt big-endian) || right) < 2^(256-bits) arar.
+// Çözüm blok hedefini tuttuğu için Submit bloğu doğrudan zincire ekler.
+// Havuz modunda bunun yerine pool.Pool işleri (pay zorluğu + muhasebe) kullanılır.
+type WebJobs struct {
+	backend Backend
 
-// CurrentWebChallenge: 32 baytlık rastgele challenge üretir (hex).
-func CurrentWebChallenge() (challengeHex string, difficulty int) {
-	buf := make([]byte, 32)
-	_, _ = rand.Read(buf)
-	return hex.EncodeToString(buf), WebDifficulty
+	mu   sync.Mutex
+	seq  uint64
+	jobs map[string]*webWork
 }
 
-// doubleSHA256(ch||nonceLE)
-func doubleSHA256(input []byte) [32]byte {
-	h1 := sha256.Sum256(input)
-	h2 := sha256.Sum256(h1[:])
-	return h2
+type webWork struct {
+	work    *Work
+	expires time.Time
+	seen    map[uint64]bool
 }
 
-// meetsDifficulty: hex başındaki '0' sayısını say
-func meetsDifficulty(hexHash string, diff int) bool {
-	if diff <= 0 {
-		return true
-	}
-	if len(hexHash) < diff {
-		return false
+const (
+	webJobTTL  = 60 * time.Second
+	webMaxJobs = 1024
+)
+
+// WebJob / WebResult: istemciye dönen iş ve çözüm sonucu (api/wire'da; HTTP
+// istemcisiyle ortak)
+type (
+	WebJob    = wire.WebJob
+	WebResult = wire.WebResult
+)
+
+func NewWebJobs(b Backend) *WebJobs {
+	return &WebJobs{backend: b, jobs: map[string]*webWork{}}
+}
+
+// NewJob: address için yeni iş
+func (wj *WebJobs) NewJob(address string) (*WebJob, error) {
+	w, err := wj.backend.GetWork(context.Background(), strings.TrimSpace(address))
+	if err != nil {
+		return nil, err
 	}
-	for i := 0; i < diff; i++ {
-		if hexHash[i] != '0' {
-			return false
+	now := time.Now()
+	wj.mu.Lock()
+	defer wj.mu.Unlock()
+	for id, j := range wj.jobs {
+		if now.After(j.expires) || len(wj.jobs) >= webMaxJobs {
+			delete(wj.jobs, id)
 		}
 	}
-	return true
+	wj.seq++
+	id := strconv.FormatUint(wj.seq, 16)
+	w.JobID = id
+	ww := &webWork{work: w, expires: now.Add(webJobTTL), seen: map[uint64]bool{}}
+	wj.jobs[id] = ww
+	return &WebJob{
+		ID:          id,
+		Miner:       w.Miner,
+		Left:        hex.EncodeToString(w.Left),
+		Right:       hex.EncodeToString(w.Right),
+		NonceFormat: blockchain.NonceFormatBE64,
+		Bits:        bitsFromTarget(w.Target),
+		Height:      w.Height,
+		Expires:     ww.expires.Unix(),
+	}, nil
 }
 
-// VerifyWebSolution: challenge(hex) + nonce(LE uint32) ⇒ doubleSHA256
-// dönen: (valid, hashHex)
-func VerifyWebSolution(challengeHex string, nonce uint32, diff int) (bool, string) {
-	ch, err := hex.DecodeString(challengeHex)
-	if err != nil || len(ch) == 0 {
-		return false, ""
+// Submit: nonce'u işin hedefine göre doğrular, tutuyorsa bloğu Backend'e iletir
+func (wj *WebJobs) Submit(jobID string, nonce uint64) WebResult {
+	wj.mu.Lock()
+	ww := wj.jobs[jobID]
+	if ww == nil || time.Now().After(ww.expires) {
+		wj.mu.Unlock()
+		return WebResult{Message: "job expired"}
+	}
+	if ww.seen[nonce] {
+		wj.mu.Unlock()
+		return WebResult{Message: "duplicate"}
 	}
-	in := make([]byte, 0, len(ch)+4)
-	in = append(in, ch...)
-	nonceLE := make([]byte, 4)
-	binary.LittleEndian.PutUint32(nonceLE, nonce)
-	in = append(in, nonceLE...)
+	ww.seen[nonce] = true
+	wj.mu.Unlock()
 
-	h := doubleSHA256(in)
+	h := ww.work.Hash(nonce)
 	hashHex := hex.EncodeToString(h[:])
-	ok := meetsDifficulty(hashHex, diff)
-	return ok, hashHex
+	if new(big.Int).SetBytes(h[:]).Cmp(ww.work.Target) >= 0 {
+		return WebResult{Hash: hashHex, Message: "below difficulty"}
+	}
+	ok, err := wj.backend.Submit(context.Background(), ww.work, nonce, hashHex)
+	switch {
+	case err != nil:
+		return WebResult{Hash: hashHex, Message: fmt.Sprintf("block rejected: %v", err)}
+	case !ok:
+		return WebResult{Hash: hashHex, Message: "stale job"}
+	}
+	wj.mu.Lock()
+	delete(wj.jobs, jobID)
+	wj.mu.Unlock()
+	return WebResult{Accepted: true, Hash: hashHex}
 }
@@ -3,15 +3,16 @@ package miner
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
-	"fmt"
 	"math/big"
 	"math/rand"
 	"runtime"
-	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"quantumcoin/blockchain"
 )
 
 type Work struct {
@@ -20,6 +21,32 @@ type Work struct {
 	Target *big.Int
 	Height int
 	Miner  string
+
+	JobID string          // harici iş kimliği (stratum)
+	Abort <-chan struct{} // kapanırsa iş bayatlamıştır (yeni tip / yeni iş); nil = hiç
+
+	tmpl  *blockchain.Block         // chainBackend: işin dayandığı aday blok
+	htmpl *blockchain.BlockTemplate // httpBackend: işin dayandığı şablon
+}
+
+// Hash: sha256(Left || nonce(8 bayt big-endian) || Right). Zincir işlerinde
+// Left = Block.HeaderPrefix(), Right boştur (bkz. blockchain/header.go).
+func (w *Work) Hash(nonce uint64) [32]byte {
+	var nb [8]byte
+	binary.BigEndian.PutUint64(nb[:], nonce)
+	buf := make([]byte, 0, len(w.Left)+len(nb)+len(w.Right))
+	buf = append(buf, w.Left...)
+	buf = append(buf, nb[:]...)
+	buf = append(buf, w.Right...)
+	return sha256.Sum256(buf)
+}
+
+// Hasher: tek goroutine için Hash eşdeğeri; Right boşsa Left'in midstate'ini kullanır
+func (w *Work) Hasher() func(nonce uint64) [32]byte {
+	if len(w.Right) > 0 {
+		return w.Hash
+	}
+	return blockchain.NewMidstate(w.Left).NewHasher().Sum
 }
 
 type Backend interface {
@@ -28,9 +55,8 @@ type Backend interface {
 }
 
 type WorkerConfig struct {
-	Threads      int
-	Address      string
-	GreenOnFound bool
+	Threads int
+	Address string
 }
 
 type Worker struct {
@@ -75,16 +101,19 @@ func (w *Worker) solve(ctx context.Context, work *Work) error {
 		seed := rand.New(rand.NewSource(time.Now().UnixNano() + int64(i)))
 		go func() {
 			defer wg.Done()
+			hash := work.Hasher()
 			for {
 				select {
 				case <-ctx.Done():
 					return
 				case <-stopCh:
 					return
+				case <-work.Abort:
+					return
 				default:
 				}
 				nonce := uint64(seed.Int63())
-				h := hashCandidate(work.Left, nonce, work.Right)
+				h := hash(nonce)
 				atomic.AddUint64(&w.HashCount, 1)
 				if new(big.Int).SetBytes(h[:]).Cmp(work.Target) <= 0 {
 					hashHex := hex.EncodeToString(h[:])
@@ -93,15 +122,9 @@ func (w *Worker) solve(ctx context.Context, work *Work) error {
 						ok, _ := w.backend.Submit(ctx, work, nonce, hashHex)
 						if ok {
 							atomic.AddUint64(&w.found, 1)
-							if w.cfg.GreenOnFound {
-								fmt.Print("\x1b[32m")
-							}
-							fmt.Printf("\n[BLOK BULUNDU] h=%d nonce=%d hash=%s height=%d\n", w.HashCount, nonce, hashHex, work.Height)
-							if w.cfg.GreenOnFound {
-								fmt.Print("\x1b[0m")
-							}
+							logger.Info("block found", "height", work.Height, "nonce", nonce, "hash", hashHex, "hashes", atomic.LoadUint64(&w.HashCount))
 						} else {
-							fmt.Printf("\n[GEÇERSİZ] nonce=%d hash=%s\n", nonce, hashHex)
+							logger.Warn("solution rejected", "height", work.Height, "nonce", nonce, "hash", hashHex)
 						}
 					})
 					return
@@ -112,13 +135,3 @@ func (w *Worker) solve(ctx context.Context, work *Work) error {
 	wg.Wait()
 	return nil
 }
-
-// Zincirin prepareData kuralıyla uyumlu: SHA256(Left || strconv.Itoa(nonce) || Right)
-func hashCandidate(left []byte, nonce uint64, right []byte) [32]byte {
-	nb := []byte(strconv.Itoa(int(nonce)))
-	buf := make([]byte, 0, len(left)+len(nb)+len(right))
-	buf = append(buf, left...)
-	buf = append(buf, nb...)
-	buf = append(buf, right...)
-	return sha256.Sum256(buf)
-}
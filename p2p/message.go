@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"quantumcoin/blockchain"
+	"quantumcoin/config"
 )
 
 type MessageType string
@@ -19,8 +20,17 @@ const (
 	MsgPong     MessageType = "pong"
 	MsgPeerList MessageType = "peerlist"
 	MsgError    MessageType = "error"
+	MsgHello    MessageType = "hello" // bağlantının ilk mesajı; genesis el sıkışması
 )
 
+// Hello: bağlantı açılınca iki taraf da gönderir. Ağ sihirli baytları ya da
+// genesis'i farklı olan peer'in mesajları işlenmez, bağlantı kapatılır.
+type Hello struct {
+	Magic   [4]byte
+	Genesis []byte
+	Height  int
+}
+
 // P2P mesaj yapısı
 type Message struct {
 	Type MessageType
@@ -70,6 +80,17 @@ func RequestMessage() Message {
 	return Message{Type: MsgRequest, Data: nil}
 }
 
+// HelloMessage: yerel zincirin genesis hash'i ve yüksekliği
+func HelloMessage(bc *blockchain.Blockchain) Message {
+	var buf bytes.Buffer
+	_ = gob.NewEncoder(&buf).Encode(Hello{
+		Magic:   config.Current().Params().Magic,
+		Genesis: bc.GenesisHash(),
+		Height:  bc.GetBestHeight(),
+	})
+	return Message{Type: MsgHello, Data: buf.Bytes()}
+}
+
 // Ping/Pong
 func PingMessage() Message { return Message{Type: MsgPing} }
 func PongMessage() Message { return Message{Type: MsgPong} }
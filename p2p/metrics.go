@@ -0,0 +1,29 @@
+package p2p
+
+import "quantumcoin/metrics"
+
+// P2P metrikleri (GET /metrics)
+var (
+	mPeersConnected = metrics.NewCounter("qc_p2p_handshakes_total", "Peers that completed the hello/genesis handshake")
+	mPeersRefused   = metrics.NewCounter("qc_p2p_handshakes_refused_total", "Peers refused during the handshake")
+	mMsgRecv        = metrics.NewCounterVec("qc_p2p_messages_received_total", "P2P messages received", "type")
+	mMsgSent        = metrics.NewCounterVec("qc_p2p_messages_sent_total", "P2P messages sent", "type")
+	mSendErrors     = metrics.NewCounter("qc_p2p_send_errors_total", "P2P message sends that failed")
+)
+
+func init() {
+	metrics.NewGaugeFunc("qc_p2p_peers", "Open peer connections", func() float64 {
+		peersMu.Lock()
+		defer peersMu.Unlock()
+		return float64(len(peers))
+	})
+}
+
+// typeLabel: bilinmeyen (karşı tarafın uydurduğu) türler tek etikette toplanır
+func typeLabel(t MessageType) string {
+	switch t {
+	case MsgBlock, MsgTx, MsgChain, MsgRequest, MsgPing, MsgPong, MsgPeerList, MsgError, MsgHello:
+		return string(t)
+	}
+	return "unknown"
+}
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
-	"log"
 	"net"
 	"sync"
 
 	"quantumcoin/blockchain"
+	"quantumcoin/config"
+	"quantumcoin/events"
+	"quantumcoin/logging"
 )
 
 // peer: aynı bağlantı üzerinden eşzamanlı Encode yarışlarını önlemek için
@@ -21,10 +23,17 @@ type peer struct {
 func (p *peer) send(msg Message) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	return p.enc.Encode(msg)
+	if err := p.enc.Encode(msg); err != nil {
+		mSendErrors.Inc()
+		return err
+	}
+	mMsgSent.With(typeLabel(msg.Type)).Inc()
+	return nil
 }
 
 var (
+	logger = logging.For(logging.SubP2P)
+
 	peersMu sync.Mutex
 	peers   = make(map[string]*peer) // key: remote addr string
 )
@@ -35,7 +44,7 @@ func BroadcastMessage(msg Message) {
 	defer peersMu.Unlock()
 	for addr, p := range peers {
 		if err := p.send(msg); err != nil {
-			log.Printf("Broadcast send to %s failed: %v", addr, err)
+			logger.Warn("broadcast send failed", "peer", addr, "type", msg.Type, "err", err)
 			_ = p.conn.Close()
 			delete(peers, addr)
 		}
@@ -51,7 +60,7 @@ func broadcastExcept(msg Message, except net.Addr) {
 			continue
 		}
 		if err := p.send(msg); err != nil {
-			log.Printf("Broadcast(send) to %s failed: %v", addr, err)
+			logger.Warn("broadcast send failed", "peer", addr, "type", msg.Type, "err", err)
 			_ = p.conn.Close()
 			delete(peers, addr)
 		}
@@ -67,58 +76,101 @@ func sendToPeer(conn net.Conn, msg Message) {
 	if p == nil {
 		// güvenlik: kayıtlı değilse geçici encoder ile deneyelim
 		if err := gob.NewEncoder(conn).Encode(msg); err != nil {
-			log.Println("Failed to send message:", err)
+			mSendErrors.Inc()
+			logger.Warn("send failed", "peer", conn.RemoteAddr().String(), "type", msg.Type, "err", err)
+			return
 		}
+		mMsgSent.With(typeLabel(msg.Type)).Inc()
 		return
 	}
 	if err := p.send(msg); err != nil {
-		log.Println("Failed to send message:", err)
+		logger.Warn("send failed", "peer", conn.RemoteAddr().String(), "type", msg.Type, "err", err)
 	}
 }
 
-// HandleConnection: Gelen bağlantıyı dinler, mesajları işler
+// HandleConnection: Gelen bağlantıyı dinler, mesajları işler.
+// İlk iş Hello gönderilir; karşı tarafın Hello'su gelip genesis'i doğrulanana
+// dek diğer mesajlar yok sayılır.
 func HandleConnection(conn net.Conn, bc *blockchain.Blockchain) {
 	defer func() {
 		unregisterPeer(conn)
 	}()
 
+	sendToPeer(conn, HelloMessage(bc))
+	verified := false
 	dec := gob.NewDecoder(conn)
 	for {
 		var msg Message
 		if err := dec.Decode(&msg); err != nil {
-			log.Println("Connection closed or decode error:", err)
+			logger.Info("peer disconnected", "peer", conn.RemoteAddr().String(), "err", err)
 			return
 		}
-		go handleMessage(msg, bc, conn)
+		mMsgRecv.With(typeLabel(msg.Type)).Inc()
+		switch {
+		case msg.Type == MsgHello:
+			if err := checkHello(msg, bc); err != nil {
+				logger.Warn("peer refused", "peer", conn.RemoteAddr().String(), "err", err)
+				mPeersRefused.Inc()
+				sendToPeer(conn, Message{Type: MsgError, Data: []byte(err.Error())})
+				return
+			}
+			if !verified {
+				verified = true
+				mPeersConnected.Inc()
+				logger.Info("peer connected", "peer", conn.RemoteAddr().String())
+				events.Default.Publish(events.PeerConnected{Addr: conn.RemoteAddr().String()})
+				// el sıkışması tamam: zinciri iste (uzunsa ReplaceChain devralır)
+				sendToPeer(conn, RequestMessage())
+			}
+		case !verified:
+			logger.Debug("message before hello ignored", "peer", conn.RemoteAddr().String(), "type", msg.Type)
+		default:
+			go handleMessage(msg, bc, conn)
+		}
 	}
 }
 
+// checkHello: peer aynı ağda ve aynı genesis'ten mi başlıyor
+func checkHello(msg Message, bc *blockchain.Blockchain) error {
+	var h Hello
+	if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(&h); err != nil {
+		return fmt.Errorf("bad hello: %w", err)
+	}
+	if p := config.Current().Params(); h.Magic != p.Magic {
+		return fmt.Errorf("network magic %q, local %s %q", h.Magic[:], p.Name, p.Magic[:])
+	}
+	if !bytes.Equal(h.Genesis, bc.GenesisHash()) {
+		return fmt.Errorf("%w: peer %x, local %x", blockchain.ErrGenesisMismatch, h.Genesis, bc.GenesisHash())
+	}
+	return nil
+}
+
 // handleMessage: Gelen mesaj türüne göre işlem
 func handleMessage(msg Message, bc *blockchain.Blockchain, src net.Conn) {
 	switch msg.Type {
 	case MsgBlock:
 		var blk blockchain.Block
 		if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(&blk); err != nil {
-			log.Println("Block decode error:", err)
+			logger.Warn("block decode failed", "peer", src.RemoteAddr().String(), "err", err)
 			return
 		}
 		// Minimum doğrulama: PoW + bağlanırlık
 		if err := bc.AddBlockFromPeer(&blk); err != nil {
-			log.Printf("Rejected peer block: %v", err)
+			logger.Warn("peer block rejected", "peer", src.RemoteAddr().String(), "height", blk.Index, "err", err)
 			return
 		}
-		fmt.Println("✓ New block accepted from peer")
+		logger.Info("peer block accepted", "peer", src.RemoteAddr().String(), "height", blk.Index, "hash", fmt.Sprintf("%x", blk.Hash))
 		// Diğer peer’lara da (kaynak hariç) yay
 		broadcastExcept(BlockMessage(&blk), src.RemoteAddr())
 
 	case MsgTx:
 		var tx blockchain.Transaction
 		if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(&tx); err != nil {
-			log.Println("Transaction decode error:", err)
+			logger.Warn("tx decode failed", "peer", src.RemoteAddr().String(), "err", err)
 			return
 		}
-		if !tx.Verify() {
-			log.Println("Invalid tx from peer")
+		if !bc.VerifyTransaction(&tx) {
+			logger.Warn("invalid tx from peer", "peer", src.RemoteAddr().String(), "txid", fmt.Sprintf("%x", tx.ID))
 			return
 		}
 		// (Gelecekte: mempool'a ekle)
@@ -130,10 +182,10 @@ func handleMessage(msg Message, bc *blockchain.Blockchain, src net.Conn) {
 		// Basit kural: geçerli ve daha uzunsa değiştir
 		if peerBC != nil && peerBC.IsValidChain() && peerBC.GetHeight() > bc.GetBestHeight() {
 			if err := bc.ReplaceChain(peerBC.GetAllBlocks()); err != nil {
-				log.Println("Chain replace failed:", err)
+				logger.Warn("chain replace failed", "peer", src.RemoteAddr().String(), "err", err)
 				return
 			}
-			fmt.Println("✓ Replaced chain with longer valid peer chain")
+			logger.Info("replaced chain with longer peer chain", "peer", src.RemoteAddr().String(), "height", bc.GetBestHeight())
 		}
 
 	case MsgRequest:
@@ -146,8 +198,11 @@ func handleMessage(msg Message, bc *blockchain.Blockchain, src net.Conn) {
 	case MsgPong:
 		// no-op
 
+	case MsgError:
+		logger.Warn("peer reported error", "peer", src.RemoteAddr().String(), "msg", string(msg.Data))
+
 	default:
-		log.Println("Unknown message type:", msg.Type)
+		logger.Warn("unknown message type", "peer", src.RemoteAddr().String(), "type", msg.Type)
 	}
 }
 
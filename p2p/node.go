@@ -1,7 +1,6 @@
 package p2p
 
 import (
-	"fmt"
 	"log"
 	"net"
 	"strings"
@@ -21,12 +20,12 @@ func RunNode(port string, bc *blockchain.Blockchain) {
 	}
 	defer listener.Close()
 
-	fmt.Println("Node running on port:", strings.TrimPrefix(addr, ":"))
+	logger.Info("p2p listening", "port", strings.TrimPrefix(addr, ":"))
 
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
-			log.Println("Connection error:", err)
+			logger.Warn("accept failed", "err", err)
 			continue
 		}
 		registerPeer(conn)
@@ -47,14 +46,12 @@ func ConnectToPeer(port string, address string, bc *blockchain.Blockchain) {
 	// 2) Uzak düğüme bağlan
 	conn, err := net.Dial("tcp", address)
 	if err != nil {
-		log.Println("Connection failed:", err)
+		logger.Warn("connect failed", "peer", address, "err", err)
 		return
 	}
-	fmt.Println("Connected to:", address)
+	logger.Info("connected", "peer", address)
 
+	// 3) Hello gönderilir; genesis doğrulanınca zincir istenir (HandleConnection)
 	registerPeer(conn)
 	go HandleConnection(conn, bc)
-
-	// 3) Zinciri iste (sade haliyle)
-	sendToPeer(conn, RequestMessage())
 }
@@ -0,0 +1,166 @@
+package pool
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/miner"
+	"quantumcoin/wallet"
+)
+
+// Web madencileri için HTTP işleri: stratum oturumunun HTTP karşılığı.
+// İş gerçek blok adayına (havuz coinbase'i) dayanır; pay
+// sha256(left || nonce(8 bayt big-endian) || right) < 2^(256-shareBits) olmalıdır.
+
+const (
+	jobTTL     = 2 * time.Minute
+	maxJobs    = 4096
+	jobIDRadix = 16
+)
+
+type job struct {
+	id      string
+	worker  string
+	address string
+	bits    int
+	work    *miner.Work
+	expires time.Time
+	seen    map[uint64]bool
+}
+
+// Job: istemciye dönen iş
+type Job struct {
+	ID          string `json:"jobId"`
+	Worker      string `json:"worker"`
+	Left        string `json:"left"` // hex
+	Right       string `json:"right"`
+	NonceFormat string `json:"nonceFormat"`
+	ShareBits   int    `json:"shareBits"`
+	BlockBits   int    `json:"blockBits"`
+	Height      int    `json:"height"`
+	Expires     int64  `json:"expires"`
+}
+
+// SubmitResult: pay gönderimi sonucu
+type SubmitResult struct {
+	Accepted bool   `json:"accepted"`
+	Block    bool   `json:"block"`
+	Hash     string `json:"hash"`
+	Message  string `json:"message,omitempty"`
+}
+
+// NewJob: worker ("adres" ya da "adres.işçi") için iş
+func (p *Pool) NewJob(worker string) (*Job, error) {
+	worker = strings.TrimSpace(worker)
+	addr := workerAddress(worker)
+	if _, _, err := wallet.DecodeAddress(addr); err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	w, err := p.backend.GetWork(context.Background(), addr)
+	if err != nil {
+		return nil, err
+	}
+	blockBits := p.opt.BlockBits()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	p.pruneJobsLocked(now)
+	p.jobSeq++
+	j := &job{
+		id:      strconv.FormatUint(p.jobSeq, jobIDRadix),
+		worker:  worker,
+		address: addr,
+		bits:    p.workerLocked(worker, now).bits,
+		work:    w,
+		expires: now.Add(jobTTL),
+		seen:    map[uint64]bool{},
+	}
+	w.JobID = j.id
+	p.jobs[j.id] = j
+	return &Job{
+		ID:          j.id,
+		Worker:      worker,
+		Left:        hex.EncodeToString(w.Left),
+		Right:       hex.EncodeToString(w.Right),
+		NonceFormat: blockchain.NonceFormatBE64,
+		ShareBits:   j.bits,
+		BlockBits:   blockBits,
+		Height:      w.Height,
+		Expires:     j.expires.Unix(),
+	}, nil
+}
+
+// Submit: web payını doğrular, kaydeder; blok hedefini tutuyorsa zincire ekler
+func (p *Pool) Submit(jobID string, nonce uint64) (SubmitResult, error) {
+	p.mu.Lock()
+	j := p.jobs[jobID]
+	if j == nil || time.Now().After(j.expires) {
+		p.mu.Unlock()
+		return SubmitResult{Message: "job expired"}, ErrUnknownJob
+	}
+	if j.seen[nonce] {
+		p.mu.Unlock()
+		return SubmitResult{Message: "duplicate"}, ErrDuplicate
+	}
+	j.seen[nonce] = true
+	p.mu.Unlock()
+	if p.stale(j.work.Height) {
+		return SubmitResult{Message: "stale"}, ErrStale
+	}
+
+	h := j.work.Hash(nonce)
+	hashHex := hex.EncodeToString(h[:])
+	hv := new(big.Int).SetBytes(h[:])
+	shareTarget := new(big.Int).Lsh(big.NewInt(1), uint(256-j.bits))
+	if hv.Cmp(shareTarget) >= 0 {
+		return SubmitResult{Hash: hashHex, Message: "below share difficulty"}, ErrLowDiff
+	}
+
+	res := SubmitResult{Accepted: true, Hash: hashHex}
+	if hv.Cmp(j.work.Target) < 0 {
+		ok, err := p.backend.Submit(context.Background(), j.work, nonce, hashHex)
+		if err != nil {
+			res.Message = "block rejected: " + err.Error()
+		}
+		res.Block = ok
+	}
+	err := p.RecordShare(Share{
+		Worker:  j.worker,
+		Address: j.address,
+		Height:  j.work.Height,
+		Bits:    j.bits,
+		Hash:    hashHex,
+		Block:   res.Block,
+		Time:    time.Now(),
+	})
+	if err != nil {
+		return SubmitResult{Hash: hashHex, Message: "stale"}, err
+	}
+	return res, nil
+}
+
+func (p *Pool) pruneJobsLocked(now time.Time) {
+	for id, j := range p.jobs {
+		if now.After(j.expires) {
+			delete(p.jobs, id)
+		}
+	}
+	// Aşırı birikme (ör. istemci iş üretip göndermiyor): en eskileri at
+	for len(p.jobs) >= maxJobs {
+		var oldest string
+		var t time.Time
+		for id, j := range p.jobs {
+			if oldest == "" || j.expires.Before(t) {
+				oldest, t = id, j.expires
+			}
+		}
+		delete(p.jobs, oldest)
+	}
+}
@@ -0,0 +1,211 @@
+package pool
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/utils"
+	"quantumcoin/wallet"
+)
+
+// Run: olgunlaşma/ödeme döngüsü (ctx iptal edilene kadar)
+func (p *Pool) Run(ctx context.Context, every time.Duration) {
+	if every <= 0 {
+		every = 30 * time.Second
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		if _, err := p.ProcessPayouts(); err != nil {
+			logger.Error("payouts failed", "err", err)
+		}
+	}
+}
+
+// ProcessPayouts: olgun blokların kredilerini bakiyeye geçirir, bekleyen ödemeleri
+// sonuçlandırır, MinPayout'u aşan bakiyeleri havuz cüzdanından tek işlemle öder.
+// Ödenen tutar işlem payoutConfirmations onay alana kadar Reserved'da tutulur;
+// işlem düşerse bakiyeye iade edilir. Ödeme yapılmadıysa nil döner.
+func (p *Pool) ProcessPayouts() (*Payout, error) {
+	p.matureBlocks()
+	p.settlePayouts()
+
+	p.mu.Lock()
+	var pays []blockchain.Payment
+	total := 0
+	for _, addr := range utils.SortedKeys(p.st.Balances) {
+		if amt := p.st.Balances[addr]; amt >= p.opt.MinPayout {
+			pays = append(pays, blockchain.Payment{Address: addr, Amount: amt})
+			total += amt
+		}
+	}
+	err := p.saveLocked()
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(pays) == 0 {
+		return nil, nil
+	}
+
+	w, ok := wallet.LoadWalletByAddress(p.opt.Address)
+	if !ok {
+		return nil, ErrNoPoolWallet
+	}
+
+	// Harcanabilir bakiye yetmiyorsa (olgunlaşmamış coinbase vb.) sığan kadarını öde
+	avail := p.bc.GetSpendableBalance(p.opt.Address)
+	for total > avail && len(pays) > 0 {
+		total -= pays[len(pays)-1].Amount
+		pays = pays[:len(pays)-1]
+	}
+	if len(pays) == 0 {
+		return nil, nil
+	}
+	tx, err := blockchain.NewPaymentTransaction(p.opt.Address, pays, p.bc)
+	if err == nil {
+		err = p.bc.SignTransaction(tx, w.PrivateKey)
+	}
+	if err == nil {
+		err = p.bc.AddTransaction(tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.opt.Broadcast != nil {
+		p.opt.Broadcast(tx)
+	}
+
+	po := Payout{TxID: hex.EncodeToString(tx.ID), Time: time.Now(), Status: PayoutPending}
+	p.mu.Lock()
+	for _, pay := range pays {
+		p.st.Balances[pay.Address] -= pay.Amount
+		if p.st.Balances[pay.Address] <= 0 {
+			delete(p.st.Balances, pay.Address)
+		}
+		p.st.Reserved[pay.Address] += pay.Amount
+		po.Outputs = append(po.Outputs, PayoutOutput{Address: pay.Address, Amount: pay.Amount})
+	}
+	p.st.Payouts = append(p.st.Payouts, po)
+	if len(p.st.Payouts) > maxHistory {
+		p.st.Payouts = p.st.Payouts[len(p.st.Payouts)-maxHistory:]
+	}
+	err = p.saveLocked()
+	p.mu.Unlock()
+	logger.Info("payout sent", "txid", po.TxID, "miners", len(pays), "amount", total)
+	return &po, err
+}
+
+// settlePayouts: bekleyen ödemeler payoutConfirmations onay aldıysa Reserved'dan
+// Paid'e geçer; işlem ne zincirde ne mempool'da ise tutarlar bakiyeye iade edilir.
+func (p *Pool) settlePayouts() {
+	p.mu.Lock()
+	var ids []string
+	for _, po := range p.st.Payouts {
+		if po.Status == PayoutPending {
+			ids = append(ids, po.TxID)
+		}
+	}
+	p.mu.Unlock()
+	if len(ids) == 0 {
+		return
+	}
+
+	status := map[string]string{}
+	tip := p.bc.GetBestHeight()
+	pending := map[string]bool{}
+	for _, tx := range p.bc.PendingTxs() {
+		pending[hex.EncodeToString(tx.ID)] = true
+	}
+	for _, id := range ids {
+		txid, err := hex.DecodeString(id)
+		if err != nil {
+			status[id] = PayoutFailed
+			continue
+		}
+		if _, height, ok := p.bc.FindTransaction(txid); ok {
+			if tip-height+1 >= payoutConfirmations {
+				status[id] = PayoutConfirmed
+			}
+		} else if !pending[id] {
+			status[id] = PayoutFailed
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := false
+	for i := range p.st.Payouts {
+		po := &p.st.Payouts[i]
+		st, ok := status[po.TxID]
+		if po.Status != PayoutPending || !ok {
+			continue
+		}
+		for _, o := range po.Outputs {
+			p.st.Reserved[o.Address] -= o.Amount
+			if p.st.Reserved[o.Address] <= 0 {
+				delete(p.st.Reserved, o.Address)
+			}
+			if st == PayoutConfirmed {
+				p.st.Paid[o.Address] += o.Amount
+			} else {
+				p.st.Balances[o.Address] += o.Amount
+			}
+		}
+		po.Status = st
+		changed = true
+		if st == PayoutFailed {
+			logger.Warn("payout dropped, balances restored", "txid", po.TxID, "outputs", len(po.Outputs))
+		}
+	}
+	if changed {
+		if err := p.saveLocked(); err != nil {
+			logger.Error("save state failed", "err", err)
+		}
+	}
+}
+
+// matureBlocks: Maturity kadar onay alan bekleyen blokları kredilendirir,
+// zincirden düşenleri orphaned işaretler.
+func (p *Pool) matureBlocks() {
+	tip := p.bc.GetBestHeight()
+	hashes := map[int]string{}
+	p.mu.Lock()
+	for _, b := range p.st.Blocks {
+		if b.Status == BlockPending {
+			if blk := p.bc.GetBlockByIndex(b.Height); blk != nil {
+				hashes[b.Height] = hex.EncodeToString(blk.Hash)
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.st.Blocks {
+		b := &p.st.Blocks[i]
+		if b.Status != BlockPending {
+			continue
+		}
+		if h, ok := hashes[b.Height]; !ok || h != b.Hash {
+			if b.Height <= tip {
+				b.Status = BlockOrphaned
+			}
+			continue
+		}
+		if tip-b.Height < p.opt.Maturity {
+			continue
+		}
+		for addr, amt := range b.Credits {
+			p.st.Balances[addr] += amt
+		}
+		b.Status = BlockCredited
+	}
+}
@@ -0,0 +1,581 @@
+// Package pool: havuz madenciliği — pay (share) muhasebesi, PPLNS/PPS ödül
+// hesabı ve havuz cüzdanından ödeme işlemleri.
+//
+// Akış:
+//
+//	işçi payı (stratum / web iş) -> RecordShare -> pay günlüğü (diske ekleme)
+//	blok bulundu -> blockFound -> PPLNS: son N payın ağırlığına göre kredi (olgunlaşınca bakiyeye)
+//	                              PPS:   her pay anında beklenen değeri kadar kredi
+//	ProcessPayouts -> MinPayout'u aşan bakiyeler tek çok-çıktılı işlemle ödenir
+package pool
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/config"
+	"quantumcoin/logging"
+	"quantumcoin/miner"
+	"quantumcoin/wallet"
+)
+
+var logger = logging.For(logging.SubPool)
+
+const (
+	SchemePPLNS = "pplns"
+	SchemePPS   = "pps"
+
+	maxHistory = 1000 // durum dosyasında tutulan blok/ödeme kaydı
+
+	payoutConfirmations = 6 // ödeme işlemi bu kadar onay alınca kesinleşir
+)
+
+var (
+	ErrUnknownJob   = errors.New("pool: unknown or expired job")
+	ErrDuplicate    = errors.New("pool: duplicate share")
+	ErrLowDiff      = errors.New("pool: low difficulty share")
+	ErrStale        = errors.New("pool: stale share")
+	ErrNoPoolWallet = errors.New("pool: pool wallet key not found in wallet store")
+)
+
+// Options: havuz ayarları (bkz. OptionsFromConfig)
+type Options struct {
+	Address   string // coinbase + ödeme adresi
+	Scheme    string // SchemePPLNS | SchemePPS
+	FeePct    int
+	Window    int // PPLNS: son N pay
+	MinPayout int
+	Maturity  int // coinbase olgunluğu (blok)
+	StateFile string
+	ShareLog  string
+
+	BlockBits        func() int // blok zorluk biti
+	InitialShareBits int        // yeni işçinin pay zorluğu; 0 => BlockBits()-4
+
+	Broadcast func(tx *blockchain.Transaction) // ödeme işlemi ağa (opsiyonel)
+	OnBlock   func(b *blockchain.Block)        // havuz bloğu zincire eklenince (kaydet/yayınla)
+}
+
+// OptionsFromConfig: config alanlarından Options
+func OptionsFromConfig(c *config.Config) Options {
+	return Options{
+		Address:   c.PoolAddress,
+		Scheme:    c.PoolScheme,
+		FeePct:    c.PoolFeePct,
+		Window:    c.PoolWindow,
+		MinPayout: c.PoolMinPayout,
+		Maturity:  c.CoinbaseMaturity,
+		StateFile: c.PoolStateFile,
+		ShareLog:  c.PoolShareLog,
+		BlockBits: func() int { return c.DefaultDifficultyBits },
+
+		InitialShareBits: c.StratumShareBits,
+	}
+}
+
+// Share: kabul edilmiş pay
+type Share struct {
+	Worker  string    `json:"worker"`
+	Address string    `json:"address"`
+	Height  int       `json:"height"`
+	Bits    int       `json:"bits"`
+	Hash    string    `json:"hash"`
+	Block   bool      `json:"block,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// weight: pay zorluğuna göre ağırlık (2^bits)
+func (s Share) weight() float64 { return math.Ldexp(1, s.Bits) }
+
+// Blok durumları
+const (
+	BlockPending  = "pending"  // olgunlaşma bekliyor
+	BlockCredited = "credited" // krediler bakiyelere geçti
+	BlockOrphaned = "orphaned" // zincirden düştü
+)
+
+// FoundBlock: havuzun bulduğu blok ve dağıtımı
+type FoundBlock struct {
+	Height  int            `json:"height"`
+	Hash    string         `json:"hash"`
+	Reward  int            `json:"reward"`
+	Fee     int            `json:"fee"`
+	Finder  string         `json:"finder"`
+	Credits map[string]int `json:"credits"`
+	Status  string         `json:"status"`
+	Time    time.Time      `json:"time"`
+}
+
+// PayoutOutput / Payout: ödeme geçmişi
+type PayoutOutput struct {
+	Address string `json:"address"`
+	Amount  int    `json:"amount"`
+}
+
+type Payout struct {
+	TxID    string         `json:"txid"`
+	Time    time.Time      `json:"time"`
+	Outputs []PayoutOutput `json:"outputs"`
+	Status  string         `json:"status,omitempty"` // PayoutPending | PayoutConfirmed | PayoutFailed ("" = eski kayıt, ödendi)
+}
+
+// Ödeme durumları
+const (
+	PayoutPending   = "pending"   // işlem mempool'da / yeterli onayı yok; tutar Reserved'da
+	PayoutConfirmed = "confirmed" // payoutConfirmations onay aldı; tutar Paid'e geçti
+	PayoutFailed    = "failed"    // işlem ne zincirde ne mempool'da; tutar bakiyeye iade edildi
+)
+
+// state: diske yazılan kalıcı durum
+type state struct {
+	Balances map[string]int     `json:"balances"`           // ödenmeyi bekleyen
+	Reserved map[string]int     `json:"reserved,omitempty"` // onay bekleyen ödemelerde
+	Paid     map[string]int     `json:"paid"`               // toplam ödenen
+	PPSFrac  map[string]float64 `json:"pps_frac,omitempty"` // PPS küsurat birikimi
+	Blocks   []FoundBlock       `json:"blocks"`
+	Payouts  []Payout           `json:"payouts"`
+}
+
+// Pool: havuz alt sistemi
+type Pool struct {
+	bc      *blockchain.Blockchain
+	opt     Options
+	backend miner.Backend
+
+	mu      sync.Mutex
+	st      state
+	window  []Share // PPLNS penceresi (son Window pay)
+	recent  []Share // istatistik (son 10 dk)
+	workers map[string]*workerDiff
+	jobs    map[string]*job
+	jobSeq  uint64
+}
+
+// New: havuzu kurar, durum ve pay günlüğünü diskten yükler
+func New(bc *blockchain.Blockchain, opt Options) (*Pool, error) {
+	if bc == nil {
+		return nil, errors.New("pool: blockchain is nil")
+	}
+	if _, _, err := wallet.DecodeAddress(opt.Address); err != nil {
+		return nil, fmt.Errorf("pool address: %w", err)
+	}
+	if opt.Scheme == "" {
+		opt.Scheme = SchemePPLNS
+	}
+	if opt.Scheme != SchemePPLNS && opt.Scheme != SchemePPS {
+		return nil, fmt.Errorf("pool: unknown scheme %q", opt.Scheme)
+	}
+	if opt.Window <= 0 {
+		opt.Window = 1000
+	}
+	if opt.MinPayout <= 0 {
+		opt.MinPayout = 1
+	}
+	if opt.BlockBits == nil {
+		return nil, errors.New("pool: BlockBits required")
+	}
+
+	p := &Pool{
+		bc:      bc,
+		opt:     opt,
+		workers: map[string]*workerDiff{},
+		jobs:    map[string]*job{},
+		st: state{
+			Balances: map[string]int{},
+			Reserved: map[string]int{},
+			Paid:     map[string]int{},
+			PPSFrac:  map[string]float64{},
+		},
+	}
+	backend, err := miner.NewChainBackend(bc, miner.ChainBackendOpts{
+		Difficulty: opt.BlockBits,
+		Coinbase:   opt.Address,
+		OnBlock:    opt.OnBlock,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.backend = backend
+
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Backend: havuz adresine coinbase yazan iş kaynağı (stratum sunucusu için)
+func (p *Pool) Backend() miner.Backend { return p.backend }
+
+// Address: havuz adresi
+func (p *Pool) Address() string { return p.opt.Address }
+
+// OnStratumShare: miner.StratumServerOpts.OnShare için
+func (p *Pool) OnStratumShare(s miner.StratumShare) error {
+	return p.RecordShare(Share{
+		Worker:  s.Worker,
+		Address: s.Address,
+		Height:  s.Height,
+		Bits:    s.ShareBits,
+		Hash:    s.Hash,
+		Block:   s.Block,
+		Time:    s.Time,
+	})
+}
+
+// RecordShare: payı günlüğe yazar, pencereyi/istatistiği günceller; blok payıysa dağıtım yapar.
+// Zincir ucu işin yüksekliğine ulaşmışsa (blok payı değilse) pay bayattır: ErrStale.
+func (p *Pool) RecordShare(s Share) error {
+	if !s.Block && p.stale(s.Height) {
+		return ErrStale
+	}
+	if s.Time.IsZero() {
+		s.Time = time.Now()
+	}
+	p.mu.Lock()
+	p.window = append(p.window, s)
+	if len(p.window) > p.opt.Window {
+		p.window = append([]Share(nil), p.window[len(p.window)-p.opt.Window:]...)
+	}
+	p.recent = append(p.recent, s)
+	p.pruneRecentLocked(s.Time)
+	p.observeShareLocked(s.Worker, s.Time)
+	if p.opt.Scheme == SchemePPS {
+		p.creditPPSLocked(s)
+	}
+	p.mu.Unlock()
+
+	p.appendShareLog(s)
+
+	if s.Block {
+		p.blockFound(s)
+	}
+	return nil
+}
+
+// stale: height yüksekliğindeki aday için iş, zincir ucu oraya ulaştıysa bayattır
+func (p *Pool) stale(height int) bool {
+	return height <= p.bc.GetBestHeight()
+}
+
+// creditPPSLocked: payın beklenen değeri = madenci payı × (1-fee) × 2^(shareBits-blockBits).
+// Madenci payı, coinbase bölüşümünde havuz adresine düşen kısımdır (staker/dev/yakım hariç).
+func (p *Pool) creditPPSLocked(s Share) {
+	rb := blockchain.ComputeRewardBridge(int64(s.Height), s.Time.Unix(), 0, nil, 0, nil)
+	reward := float64(rb.ToMiner)
+	ratio := math.Ldexp(1, s.Bits-p.opt.BlockBits())
+	v := reward * float64(100-p.opt.FeePct) / 100 * ratio
+	p.st.PPSFrac[s.Address] += v
+	if whole := int(p.st.PPSFrac[s.Address]); whole > 0 {
+		p.st.Balances[s.Address] += whole
+		p.st.PPSFrac[s.Address] -= float64(whole)
+	}
+}
+
+// blockFound: zincirdeki bloğun havuza ödenen coinbase'ini bulur ve (PPLNS) dağıtımı kaydeder
+func (p *Pool) blockFound(s Share) {
+	blk := p.bc.GetBlockByIndex(s.Height)
+	if blk == nil {
+		logger.Warn("found block not on chain", "height", s.Height)
+		return
+	}
+	reward := p.coinbaseReward(blk)
+
+	fb := FoundBlock{
+		Height:  blk.Index,
+		Hash:    fmt.Sprintf("%x", blk.Hash),
+		Reward:  reward,
+		Finder:  s.Worker,
+		Credits: map[string]int{},
+		Status:  BlockPending,
+		Time:    time.Now(),
+	}
+
+	p.mu.Lock()
+	if p.opt.Scheme == SchemePPLNS {
+		fb.Credits, fb.Fee = pplnsCredits(p.window, reward, p.opt.FeePct)
+	} else {
+		fb.Fee = reward // PPS: blok ödülü havuzun, paylar zaten kredilendi
+	}
+	p.st.Blocks = append(p.st.Blocks, fb)
+	if len(p.st.Blocks) > maxHistory {
+		p.st.Blocks = p.st.Blocks[len(p.st.Blocks)-maxHistory:]
+	}
+	err := p.saveLocked()
+	p.mu.Unlock()
+	if err != nil {
+		logger.Error("save state failed", "err", err)
+	}
+	logger.Info("block credited (pending maturity)", "height", fb.Height, "reward", fb.Reward,
+		"fee", fb.Fee, "miners", len(fb.Credits))
+}
+
+func (p *Pool) coinbaseReward(blk *blockchain.Block) int {
+	_, pkh, _ := wallet.DecodeAddress(p.opt.Address)
+	total := 0
+	for _, tx := range blk.Transactions {
+		if !tx.IsCoinbase() {
+			continue
+		}
+		for _, out := range tx.Outputs {
+			if out.IsLockedWithKey(pkh) {
+				total += out.Amount
+			}
+		}
+	}
+	return total
+}
+
+// pplnsCredits: ödül×(1-fee) penceredeki payların ağırlığına göre bölünür;
+// tam sayıya yuvarlamadan kalan havuz payına eklenir.
+func pplnsCredits(window []Share, reward, feePct int) (map[string]int, int) {
+	credits := map[string]int{}
+	var total float64
+	weights := map[string]float64{}
+	for _, s := range window {
+		w := s.weight()
+		weights[s.Address] += w
+		total += w
+	}
+	distributable := reward * (100 - feePct) / 100
+	if total <= 0 || distributable <= 0 {
+		return credits, reward
+	}
+	paid := 0
+	for addr, w := range weights {
+		amt := int(float64(distributable) * w / total)
+		if amt > 0 {
+			credits[addr] = amt
+			paid += amt
+		}
+	}
+	return credits, reward - paid
+}
+
+// ---- sorgular ----
+
+// Stats: havuz özeti
+type Stats struct {
+	Address     string  `json:"address"`
+	Scheme      string  `json:"scheme"`
+	FeePct      int     `json:"feePct"`
+	Window      int     `json:"window"`
+	MinPayout   int     `json:"minPayout"`
+	Workers     int     `json:"workers"`        // son 10 dk pay gönderen
+	Hashrate    float64 `json:"hashrate"`       // H/s (son 10 dk pay ağırlığından)
+	SharesTotal int     `json:"sharesInWindow"` //
+	BlocksFound int     `json:"blocksFound"`    //
+	Pending     int     `json:"pendingBlocks"`  //
+	Owed        int     `json:"owed"`           // ödenmeyi bekleyen toplam bakiye
+	PaidTotal   int     `json:"paidTotal"`      //
+	PoolBalance int     `json:"poolBalance"`    // havuz cüzdanının harcanabilir bakiyesi
+}
+
+func (p *Pool) Stats() Stats {
+	bal := p.bc.GetSpendableBalance(p.opt.Address)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pruneRecentLocked(time.Now())
+	st := Stats{
+		Address: p.opt.Address, Scheme: p.opt.Scheme, FeePct: p.opt.FeePct,
+		Window: p.opt.Window, MinPayout: p.opt.MinPayout,
+		SharesTotal: len(p.window), BlocksFound: len(p.st.Blocks), PoolBalance: bal,
+	}
+	workers := map[string]bool{}
+	var work float64
+	for _, s := range p.recent {
+		workers[s.Worker] = true
+		work += s.weight()
+	}
+	st.Workers = len(workers)
+	st.Hashrate = work / recentWindow.Seconds()
+	for _, b := range p.st.Blocks {
+		if b.Status == BlockPending {
+			st.Pending++
+		}
+	}
+	for _, v := range p.st.Balances {
+		st.Owed += v
+	}
+	for _, v := range p.st.Reserved {
+		st.Owed += v
+	}
+	for _, v := range p.st.Paid {
+		st.PaidTotal += v
+	}
+	return st
+}
+
+// MinerStats: tek adresin havuzdaki durumu
+type MinerStats struct {
+	Address   string         `json:"address"`
+	Balance   int            `json:"balance"`   // ödenecek
+	Reserved  int            `json:"reserved"`  // onay bekleyen ödemelerde
+	Pending   int            `json:"pending"`   // olgunlaşmamış bloklardan
+	Paid      int            `json:"paid"`      //
+	Shares    int            `json:"shares"`    // penceredeki pay sayısı
+	ShareBits map[string]int `json:"shareBits"` // işçi -> güncel pay zorluğu
+}
+
+func (p *Pool) Miner(address string) MinerStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ms := MinerStats{
+		Address:   address,
+		Balance:   p.st.Balances[address],
+		Reserved:  p.st.Reserved[address],
+		Paid:      p.st.Paid[address],
+		ShareBits: map[string]int{},
+	}
+	for _, b := range p.st.Blocks {
+		if b.Status == BlockPending {
+			ms.Pending += b.Credits[address]
+		}
+	}
+	for _, s := range p.window {
+		if s.Address == address {
+			ms.Shares++
+		}
+	}
+	for name, wd := range p.workers {
+		if workerAddress(name) == address {
+			ms.ShareBits[name] = wd.bits
+		}
+	}
+	return ms
+}
+
+// Blocks: bulunan bloklar (yeniden eskiye)
+func (p *Pool) Blocks() []FoundBlock {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]FoundBlock, 0, len(p.st.Blocks))
+	for i := len(p.st.Blocks) - 1; i >= 0; i-- {
+		out = append(out, p.st.Blocks[i])
+	}
+	return out
+}
+
+// Payouts: ödeme geçmişi (yeniden eskiye); address boş değilse yalnız ona ait çıktılar
+func (p *Pool) Payouts(address string) []Payout {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := []Payout{}
+	for i := len(p.st.Payouts) - 1; i >= 0; i-- {
+		po := p.st.Payouts[i]
+		if address == "" {
+			out = append(out, po)
+			continue
+		}
+		var mine []PayoutOutput
+		for _, o := range po.Outputs {
+			if o.Address == address {
+				mine = append(mine, o)
+			}
+		}
+		if len(mine) > 0 {
+			out = append(out, Payout{TxID: po.TxID, Time: po.Time, Outputs: mine, Status: po.Status})
+		}
+	}
+	return out
+}
+
+// ---- kalıcılık ----
+
+func (p *Pool) load() error {
+	if p.opt.StateFile != "" {
+		b, err := os.ReadFile(p.opt.StateFile)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(b, &p.st); err != nil {
+				return fmt.Errorf("pool state: %w", err)
+			}
+		case !os.IsNotExist(err):
+			return fmt.Errorf("pool state: %w", err)
+		}
+		if p.st.Balances == nil {
+			p.st.Balances = map[string]int{}
+		}
+		if p.st.Reserved == nil {
+			p.st.Reserved = map[string]int{}
+		}
+		if p.st.Paid == nil {
+			p.st.Paid = map[string]int{}
+		}
+		if p.st.PPSFrac == nil {
+			p.st.PPSFrac = map[string]float64{}
+		}
+	}
+	if p.opt.ShareLog == "" {
+		return nil
+	}
+	f, err := os.Open(p.opt.ShareLog)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("pool share log: %w", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var s Share
+		if json.Unmarshal(sc.Bytes(), &s) != nil {
+			continue // yarım yazılmış son satır vb.
+		}
+		p.window = append(p.window, s)
+		if len(p.window) > 2*p.opt.Window {
+			p.window = append([]Share(nil), p.window[len(p.window)-p.opt.Window:]...)
+		}
+	}
+	if len(p.window) > p.opt.Window {
+		p.window = append([]Share(nil), p.window[len(p.window)-p.opt.Window:]...)
+	}
+	return sc.Err()
+}
+
+func (p *Pool) appendShareLog(s Share) {
+	if p.opt.ShareLog == "" {
+		return
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(p.opt.ShareLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		logger.Error("share log open failed", "file", p.opt.ShareLog, "err", err)
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(b, '\n'))
+}
+
+func (p *Pool) saveLocked() error {
+	if p.opt.StateFile == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(p.st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := p.opt.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.opt.StateFile) // atomik güncelleme
+}
+
+// workerAddress: "adres.işçi" -> adres
+func workerAddress(worker string) string {
+	addr, _, _ := strings.Cut(worker, ".")
+	return addr
+}
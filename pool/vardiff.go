@@ -0,0 +1,75 @@
+package pool
+
+import "time"
+
+// Değişken pay zorluğu (vardiff): her işçi dakikada ~vardiffTargetPerMin pay
+// gönderecek şekilde pay zorluk biti ±1 ayarlanır. Yeni değer bir sonraki işte geçerli olur.
+const (
+	vardiffTargetPerMin = 6
+	vardiffRetarget     = 60 * time.Second
+	vardiffMinBits      = 1
+
+	recentWindow = 10 * time.Minute // istatistik penceresi
+)
+
+type workerDiff struct {
+	bits   int
+	since  time.Time
+	shares int
+}
+
+// ShareBits: işçinin güncel pay zorluğu (blok zorluğunu aşmaz)
+func (p *Pool) ShareBits(worker string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.workerLocked(worker, time.Now()).bits
+}
+
+func (p *Pool) workerLocked(worker string, now time.Time) *workerDiff {
+	blockBits := p.opt.BlockBits()
+	wd, ok := p.workers[worker]
+	if !ok {
+		bits := p.opt.InitialShareBits
+		if bits <= 0 {
+			bits = blockBits - 4
+		}
+		wd = &workerDiff{bits: bits, since: now}
+		p.workers[worker] = wd
+	}
+	if wd.bits > blockBits {
+		wd.bits = blockBits
+	}
+	if wd.bits < vardiffMinBits {
+		wd.bits = vardiffMinBits
+	}
+	return wd
+}
+
+// observeShareLocked: pay hızını ölçer, süre dolunca zorluğu ayarlar
+func (p *Pool) observeShareLocked(worker string, now time.Time) {
+	wd := p.workerLocked(worker, now)
+	wd.shares++
+	elapsed := now.Sub(wd.since)
+	if elapsed < vardiffRetarget && wd.shares < 4*vardiffTargetPerMin {
+		return
+	}
+	rate := float64(wd.shares) / elapsed.Minutes()
+	switch {
+	case rate > 2*vardiffTargetPerMin:
+		wd.bits++
+	case rate < vardiffTargetPerMin/2 && elapsed >= vardiffRetarget:
+		wd.bits--
+	}
+	wd.since, wd.shares = now, 0
+	p.workerLocked(worker, now) // sınırla
+}
+
+func (p *Pool) pruneRecentLocked(now time.Time) {
+	cut := 0
+	for cut < len(p.recent) && now.Sub(p.recent[cut].Time) > recentWindow {
+		cut++
+	}
+	if cut > 0 {
+		p.recent = append([]Share(nil), p.recent[cut:]...)
+	}
+}
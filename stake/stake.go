@@ -0,0 +1,292 @@
+// Package stake: stake havuzu dağıtımı. Coinbase'in stake payı
+// (Reward.PctStake) ağın Reward.StakePool adresine birikir; her epoch'ta (StakeEpochBlocks)
+// havuzun harcanabilir bakiyesi aktif stake sahiplerine stake ağırlığıyla
+// orantılı olarak tek çok-çıktılı işlemle ödenir.
+//
+// Stake/unstake/withdraw kuralları zincirdedir (blockchain/stake.go); bu paket
+// yalnız dağıtımı ve geçmişini tutar.
+package stake
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/config"
+	"quantumcoin/logging"
+	"quantumcoin/utils"
+	"quantumcoin/wallet"
+)
+
+const maxHistory = 1000 // durum dosyasında tutulan dağıtım kaydı
+
+var logger = logging.For(logging.SubStake)
+
+var ErrNoStakeWallet = errors.New("stake: stake pool wallet key not found in wallet store")
+
+// Options: dağıtıcı ayarları (bkz. OptionsFromConfig)
+type Options struct {
+	PoolAddress string // coinbase stake payının gittiği adres
+	PoolKey     string // havuz adresinin özel anahtarı (hex); boşsa anahtar cüzdan deposundan okunur
+	EpochBlocks int
+	StateFile   string
+
+	Broadcast func(tx *blockchain.Transaction) // dağıtım işlemi ağa (opsiyonel)
+}
+
+// OptionsFromConfig: config alanlarından Options
+func OptionsFromConfig(c *config.Config) Options {
+	return Options{
+		PoolAddress: c.Params().Reward.StakePool,
+		PoolKey:     c.Params().StakePoolKey,
+		EpochBlocks: c.StakeEpochBlocks,
+		StateFile:   c.StakeStateFile,
+	}
+}
+
+// Distribution: bir epoch'un dağıtımı
+type Distribution struct {
+	Epoch   int            `json:"epoch"`
+	Height  int            `json:"height"`
+	TxID    string         `json:"txid"`
+	Total   int            `json:"total"`
+	Weight  int            `json:"weight"` // toplam aktif stake
+	Payouts map[string]int `json:"payouts"`
+	Time    time.Time      `json:"time"`
+}
+
+// state: diske yazılan kalıcı durum
+type state struct {
+	LastEpoch int            `json:"last_epoch"`
+	Earned    map[string]int `json:"earned"` // adres -> toplam stake ödülü
+	History   []Distribution `json:"history"`
+}
+
+// Distributor: stake havuzu dağıtıcısı
+type Distributor struct {
+	bc  *blockchain.Blockchain
+	opt Options
+	key *ecdsa.PrivateKey // Options.PoolKey; nil => cüzdan deposu
+
+	mu sync.Mutex
+	st state
+}
+
+// New: dağıtıcıyı kurar, durumu diskten yükler
+func New(bc *blockchain.Blockchain, opt Options) (*Distributor, error) {
+	if bc == nil {
+		return nil, errors.New("stake: blockchain is nil")
+	}
+	if _, _, err := wallet.DecodeAddress(opt.PoolAddress); err != nil {
+		return nil, fmt.Errorf("stake pool address: %w", err)
+	}
+	if opt.EpochBlocks <= 0 {
+		opt.EpochBlocks = 100
+	}
+	d := &Distributor{bc: bc, opt: opt, st: state{Earned: map[string]int{}}}
+	if opt.PoolKey != "" {
+		priv, err := wallet.ImportPrivateKeyHex(opt.PoolKey)
+		if err != nil {
+			return nil, fmt.Errorf("stake pool key: %w", err)
+		}
+		if addr := wallet.WalletFromKey(priv).GetAddress(); addr != opt.PoolAddress {
+			return nil, fmt.Errorf("stake pool key belongs to %s, not %s", addr, opt.PoolAddress)
+		}
+		d.key = priv
+	}
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// PoolAddress: stake havuzu adresi
+func (d *Distributor) PoolAddress() string { return d.opt.PoolAddress }
+
+// Run: epoch döngüsü (ctx iptal edilene kadar)
+func (d *Distributor) Run(ctx context.Context, every time.Duration) {
+	if every <= 0 {
+		every = 30 * time.Second
+	}
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		if _, err := d.Distribute(); err != nil {
+			logger.Error("distribute failed", "err", err)
+		}
+	}
+}
+
+// Distribute: yeni bir epoch tamamlandıysa havuz bakiyesini aktif stake
+// ağırlığına göre dağıtır. Dağıtım yapılmadıysa nil döner (aktif stake yoksa
+// bakiye sonraki epoch'a devreder).
+func (d *Distributor) Distribute() (*Distribution, error) {
+	tip := d.bc.GetBestHeight()
+	epoch := tip / d.opt.EpochBlocks
+	d.mu.Lock()
+	due := epoch > d.st.LastEpoch
+	d.mu.Unlock()
+	if !due {
+		return nil, nil
+	}
+
+	weights := d.bc.StakeWeights()
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	avail := d.bc.GetSpendableBalance(d.opt.PoolAddress)
+	if total == 0 || avail <= 0 {
+		return nil, nil
+	}
+
+	dist := Distribution{Epoch: epoch, Height: tip, Weight: total, Payouts: map[string]int{}, Time: time.Now()}
+	var pays []blockchain.Payment
+	for _, addr := range utils.SortedKeys(weights) {
+		amt := avail * weights[addr] / total
+		if amt <= 0 {
+			continue // küsurat havuzda kalır
+		}
+		pays = append(pays, blockchain.Payment{Address: addr, Amount: amt})
+		dist.Payouts[addr] = amt
+		dist.Total += amt
+	}
+	if len(pays) == 0 {
+		return nil, nil
+	}
+
+	priv := d.key
+	if priv == nil {
+		w, ok := wallet.LoadWalletByAddress(d.opt.PoolAddress)
+		if !ok {
+			return nil, ErrNoStakeWallet
+		}
+		priv = w.PrivateKey
+	}
+	tx, err := blockchain.NewPaymentTransaction(d.opt.PoolAddress, pays, d.bc)
+	if err == nil {
+		err = d.bc.SignTransaction(tx, priv)
+	}
+	if err == nil {
+		err = d.bc.AddTransaction(tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if d.opt.Broadcast != nil {
+		d.opt.Broadcast(tx)
+	}
+	dist.TxID = hex.EncodeToString(tx.ID)
+
+	d.mu.Lock()
+	d.st.LastEpoch = epoch
+	for addr, amt := range dist.Payouts {
+		d.st.Earned[addr] += amt
+	}
+	d.st.History = append(d.st.History, dist)
+	if len(d.st.History) > maxHistory {
+		d.st.History = d.st.History[len(d.st.History)-maxHistory:]
+	}
+	err = d.saveLocked()
+	d.mu.Unlock()
+	logger.Info("epoch distributed", "epoch", epoch, "stakers", len(pays), "amount", dist.Total, "txid", dist.TxID)
+	return &dist, err
+}
+
+// Status: dağıtıcı özeti
+type Status struct {
+	PoolAddress string `json:"poolAddress"`
+	PoolBalance int    `json:"poolBalance"`
+	EpochBlocks int    `json:"epochBlocks"`
+	LastEpoch   int    `json:"lastEpoch"`
+	NextEpochAt int    `json:"nextEpochAt"` // dağıtımın yapılabileceği ilk yükseklik
+	TotalStaked int    `json:"totalStaked"`
+	Stakers     int    `json:"stakers"`
+}
+
+// Status: havuz bakiyesi, epoch ve toplam aktif stake
+func (d *Distributor) Status() Status {
+	weights := d.bc.StakeWeights()
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	d.mu.Lock()
+	last := d.st.LastEpoch
+	d.mu.Unlock()
+	return Status{
+		PoolAddress: d.opt.PoolAddress,
+		PoolBalance: d.bc.GetBalance(d.opt.PoolAddress),
+		EpochBlocks: d.opt.EpochBlocks,
+		LastEpoch:   last,
+		NextEpochAt: (last + 1) * d.opt.EpochBlocks,
+		TotalStaked: total,
+		Stakers:     len(weights),
+	}
+}
+
+// Earned: adresin toplam stake ödülü
+func (d *Distributor) Earned(address string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.st.Earned[address]
+}
+
+// History: dağıtım geçmişi (address boş değilse yalnız o adrese ödeme yapılanlar)
+func (d *Distributor) History(address string) []Distribution {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]Distribution, 0, len(d.st.History))
+	for _, h := range d.st.History {
+		if address == "" || h.Payouts[address] > 0 {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+func (d *Distributor) load() error {
+	if d.opt.StateFile == "" {
+		return nil
+	}
+	b, err := os.ReadFile(d.opt.StateFile)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(b, &d.st); err != nil {
+			return fmt.Errorf("stake state: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("stake state: %w", err)
+	}
+	if d.st.Earned == nil {
+		d.st.Earned = map[string]int{}
+	}
+	return nil
+}
+
+func (d *Distributor) saveLocked() error {
+	if d.opt.StateFile == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(d.st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := d.opt.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.opt.StateFile) // atomik güncelleme
+}
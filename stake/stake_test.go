@@ -0,0 +1,120 @@
+package stake
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/config"
+	"quantumcoin/wallet"
+)
+
+// TestDistributeRegtest: stake -> havuz payı birikir -> epoch dağıtımı -> ödeme zincire girer
+func TestDistributeRegtest(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	prev := config.Current()
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		config.Set(prev)
+		wallet.UseNetwork(prev.Params())
+	})
+	c := config.Default()
+	if err := c.UseNetwork(config.NetworkRegtest); err != nil {
+		t.Fatal(err)
+	}
+	c.StakeEpochBlocks = 5
+	c.StakeStateFile = filepath.Join(dir, "stake_state.json")
+	config.Set(c)
+	wallet.UseNetwork(c.Params())
+
+	bc, err := blockchain.NewBlockchain(config.NetworkRegtest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mine := func(miner string) {
+		t.Helper()
+		if _, err := bc.MineBlock(miner, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	staker := wallet.NewWallet()
+	other := wallet.NewWallet().GetAddress()
+	mine(staker.GetAddress())
+	mine(staker.GetAddress())
+
+	stx, err := blockchain.NewStakeTransaction(staker.GetAddress(), 20, 0, bc)
+	if err == nil {
+		err = bc.SignTransaction(stx, staker.PrivateKey)
+	}
+	if err == nil {
+		err = bc.AddTransaction(stx)
+	}
+	if err != nil {
+		t.Fatalf("stake tx: %v", err)
+	}
+	for bc.GetBestHeight() < c.StakeEpochBlocks {
+		mine(other)
+	}
+
+	d, err := New(bc, OptionsFromConfig(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := d.PoolAddress()
+	avail := bc.GetSpendableBalance(pool)
+	if avail <= 0 {
+		t.Fatalf("stake pool %s has no balance", pool)
+	}
+	dist, err := d.Distribute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dist == nil {
+		t.Fatal("no distribution at epoch boundary")
+	}
+	if got := dist.Payouts[staker.GetAddress()]; got != avail || dist.Total != avail {
+		t.Fatalf("staker paid %d (total %d), want %d", got, dist.Total, avail)
+	}
+	if again, err := d.Distribute(); err != nil || again != nil {
+		t.Fatalf("second distribution in the same epoch: %v, %v", again, err)
+	}
+
+	mine(other)
+	id, _ := hex.DecodeString(dist.TxID)
+	if _, _, ok := bc.FindTransaction(id); !ok {
+		t.Fatal("distribution tx not mined")
+	}
+	if d.Earned(staker.GetAddress()) != avail {
+		t.Fatalf("earned %d, want %d", d.Earned(staker.GetAddress()), avail)
+	}
+	if _, err := os.Stat(c.StakeStateFile); err != nil {
+		t.Fatalf("state not saved: %v", err)
+	}
+}
+
+func TestNewRejectsForeignPoolKey(t *testing.T) {
+	p, err := config.Network(config.NetworkRegtest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := config.Current().Params()
+	wallet.UseNetwork(p)
+	t.Cleanup(func() { wallet.UseNetwork(prev) })
+	bc, err := blockchain.NewBlockchain(config.NetworkRegtest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opt := Options{PoolAddress: wallet.NewWallet().GetAddress(), PoolKey: p.StakePoolKey}
+	if _, err := New(bc, opt); err == nil {
+		t.Fatal("pool key for another address accepted")
+	}
+}
@@ -1,9 +1,21 @@
 package telegram_game
 
-import "log"
+import (
+	"encoding/hex"
+	"log"
+
+	"quantumcoin/events"
+)
 
 // NotifyBlockMined: zincirde yeni blok üretildiğinde Telegram oyun entegrasyonuna bildirim.
 // İmza: (height, hash, miner)
 func NotifyBlockMined(height int, hash string, miner string) {
 	log.Printf("[TelegramGame] Block mined: height=%d hash=%s miner=%s", height, hash, miner)
 }
+
+// Subscribe: zincire bağlanan her blok için NotifyBlockMined çağırır
+func Subscribe(bus *events.Bus) *events.Subscription {
+	return events.Handle(bus, "telegram-game", 0, func(e events.BlockConnected) {
+		NotifyBlockMined(e.Block.Index, hex.EncodeToString(e.Block.Hash), e.Block.Miner)
+	})
+}
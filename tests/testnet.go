@@ -8,7 +8,11 @@ import (
 // StartTestnet: Örnek test zinciri başlatır
 func StartTestnet() *blockchain.Blockchain {
 	fmt.Println("[Testnet] Yerel test ağı başlatılıyor...")
-	bc := blockchain.NewBlockchain(50, 25_500_000)
+	bc, err := blockchain.NewBlockchain(blockchain.NetworkTestnet)
+	if err != nil {
+		fmt.Println("[Testnet] genesis:", err)
+		return nil
+	}
 
 	// Örnek işlemler oluştur
 	tx1, err1 := blockchain.NewTransaction("genesis_wallet", "alice", 10, bc)
@@ -31,7 +35,7 @@ func StartTestnet() *blockchain.Blockchain {
 	}
 
 	// Blok kaz
-	_, err := bc.MineBlock("tester", 16)
+	_, err = bc.MineBlock("tester", 16)
 	if err != nil {
 		fmt.Println("[Testnet] Blok kazılamadı:", err)
 	} else {
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"quantumcoin/blockchain"
+	"quantumcoin/events"
 	"quantumcoin/miner"
 )
 
@@ -20,7 +21,7 @@ type MinerTokenConfig struct {
 	SaveDir        string // registry dosyasını yazacağın dizin ("" => çalışma dizini)
 }
 
-// MinerTokenBridge: miner.OnBlock → token mint köprüsü
+// MinerTokenBridge: madenci olayları (events.MinerStatus) → token mint köprüsü
 type MinerTokenBridge struct {
 	cfg MinerTokenConfig
 
@@ -63,9 +64,9 @@ func NewMinerTokenBridge(cfg MinerTokenConfig) (*MinerTokenBridge, error) {
 	}, nil
 }
 
-// onBlock: miner.Options.OnBlock içine verilecek callback.
-// Her blokta block.Miner adresine RewardPerBlock kadar token mint eder.
-func (b *MinerTokenBridge) onBlock(blk *blockchain.Block, _ miner.MiningStatus) {
+// onBlock: madencinin bulduğu her blokta block.Miner adresine RewardPerBlock
+// kadar token mint eder.
+func (b *MinerTokenBridge) onBlock(blk *blockchain.Block) {
 	if blk == nil || blk.Miner == "" {
 		return
 	}
@@ -81,70 +82,37 @@ func (b *MinerTokenBridge) onBlock(blk *blockchain.Block, _ miner.MiningStatus)
 	_ = b.reg.Save(b.cfg.SaveDir)
 }
 
-// Options: miner.Start(...) çağrısına verilecek opsiyonları döndürür.
-// İstersen Broadcaster vb. dışarıdan set edebilirsin.
-func (b *MinerTokenBridge) Options() miner.Options {
-	return miner.Options{
-		OnBlock:     b.onBlock,
-		OnError:     nil, // istersen burada da log atabilirsin
-		OnTick:      nil,
-		Interval:    0,   // miner tarafındaki varsayılanı kullan
-		Broadcaster: nil, // dışarıdan ekleyebilirsin
-	}
+// Subscribe: köprüyü veri yoluna bağlar; yerel madencinin bulduğu bloklar
+// (events.MinerStatus.Block) için mint eder. Close ile ayrılır.
+func (b *MinerTokenBridge) Subscribe(bus *events.Bus) *events.Subscription {
+	return events.Handle(bus, "token-bridge", 0, func(e events.MinerStatus) {
+		b.onBlock(e.Block)
+	})
 }
 
-// StartMiningWithToken: tek satırda köprüyü kurar ve madenciliği başlatır.
-// Not: main.go'da bc yüklendikten sonra miner.SetGlobalBlockchain(bc) çağrın mevcutsa,
-// buradan doğrudan miner.Start kullanmak için gerekmez ama biz explicit bc veriyoruz.
+// StartMiningWithToken: tek satırda köprüyü kurar (events.Default'a abone) ve
+// madenciliği başlatır. userOpts miner.Start'a olduğu gibi geçer.
 func StartMiningWithToken(
 	bc *blockchain.Blockchain,
 	minerAddress string,
 	difficulty int,
 	cfg MinerTokenConfig,
-	userOpts ...miner.Options, // (opsiyonel) kendi OnBlock/Broadcaster eklemek istersen
+	userOpts ...miner.Options,
 ) (*MinerTokenBridge, error) {
 	bridge, err := NewMinerTokenBridge(cfg)
 	if err != nil {
 		return nil, err
 	}
-
-	// Köprü opsiyonları + kullanıcı opsiyonlarını birleştir
-	opts := bridge.Options()
-	if len(userOpts) > 0 {
-		u := userOpts[0]
-		// OnBlock birleştirme: önce köprü, sonra kullanıcı callback’i
-		if u.OnBlock != nil {
-			userOnBlock := u.OnBlock
-			opts.OnBlock = func(b *blockchain.Block, st miner.MiningStatus) {
-				bridge.onBlock(b, st)
-				userOnBlock(b, st)
-			}
-		}
-		if u.OnError != nil {
-			opts.OnError = u.OnError
-		}
-		if u.OnTick != nil {
-			opts.OnTick = u.OnTick
-		}
-		if u.Interval > 0 {
-			opts.Interval = u.Interval
-		}
-		if u.Broadcaster != nil {
-			opts.Broadcaster = u.Broadcaster
-		}
-	}
-
-	if err := miner.Start(bc, minerAddress, difficulty, opts); err != nil {
+	sub := bridge.Subscribe(events.Default)
+	if err := miner.Start(bc, minerAddress, difficulty, userOpts...); err != nil {
+		sub.Close()
 		return nil, err
 	}
 	return bridge, nil
 }
 
-// AttachToRunningMiner: halihazırda çalışan miner’a köprüyü eklemek istersen,
-// miner’ı durdurmadan yapmak mümkün olmadığı için genellikle önerilmez.
-// Bunun yerine miner.Stop → miner.Start(..., bridge.Options()) yapman sağlıklı.
-// Yine de minimal bir yardımcı bırakıyorum (kullanıcı kendi kontrolünde birleştirir).
-func AttachToRunningMiner(_ *MinerTokenBridge) {
-	// Boş: aktif miner süreç içinde callback değiştirme desteği yok.
-	// İhtiyaç olursa miner paketine "UpdateOptions" gibi bir API ekleyebiliriz.
+// AttachToRunningMiner: köprüyü çalışan madenciye bağlar (madenci durdurulmaz;
+// bir sonraki bulunan bloktan itibaren mint eder)
+func AttachToRunningMiner(b *MinerTokenBridge) *events.Subscription {
+	return b.Subscribe(events.Default)
 }
@@ -9,17 +9,30 @@ import (
 
 func PrintExplorer(bc *blockchain.Blockchain) {
 	fmt.Println(i18n.T(CurrentLang, "explorer_title"))
+	s := bc.Supply()
+	fmt.Printf(i18n.T(CurrentLang, "explorer_supply")+"\n", s.Minted, s.Burned, s.Circulating)
 	fmt.Println("------------------------------------------------")
 
-	for _, block := range bc.Blocks {
+	for _, block := range bc.GetAllBlocks() {
 		fmt.Printf(i18n.T(CurrentLang, "explorer_block")+"\n", block.Index, block.Miner, block.Hash, block.PrevHash)
+		if rb, ok := block.RewardFromMetadata(); ok {
+			fmt.Printf(i18n.T(CurrentLang, "explorer_reward")+"\n", rb.ToMiner, rb.ToStakers, rb.ToDev, rb.ToBurn, rb.ToCommunity)
+		}
 		for _, tx := range block.Transactions {
 			fmt.Printf(i18n.T(CurrentLang, "explorer_tx")+"\n", tx.ID)
 			for _, out := range tx.Outputs {
-				fmt.Printf(i18n.T(CurrentLang, "explorer_tx_out")+"\n", out.Amount)
+				fmt.Printf(i18n.T(CurrentLang, txOutKey(out))+"\n", out.Amount)
 			}
 			fmt.Println("  --------------------")
 		}
 		fmt.Println("------------------------------------------------")
 	}
 }
+
+// txOutKey: yakım çıktıları ayrı satırla gösterilir
+func txOutKey(out blockchain.TransactionOutput) string {
+	if out.LockType == blockchain.LockBurn {
+		return "explorer_tx_burn"
+	}
+	return "explorer_tx_out"
+}
@@ -17,12 +17,18 @@ func ShowExplorerWindow(a fyne.App, w fyne.Window, bc *blockchain.Blockchain) {
 	scroll := container.NewVScroll(content)
 
 	if bc != nil {
-		for _, block := range bc.Blocks {
+		s := bc.Supply()
+		content.Add(widget.NewLabelWithStyle(fmt.Sprintf(i18n.T(CurrentLang, "explorer_supply"), s.Minted, s.Burned, s.Circulating), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		content.Add(widget.NewSeparator())
+		for _, block := range bc.GetAllBlocks() {
 			content.Add(widget.NewLabel(fmt.Sprintf(i18n.T(CurrentLang, "explorer_block"), block.Index, block.Miner, block.Hash, block.PrevHash)))
+			if rb, ok := block.RewardFromMetadata(); ok {
+				content.Add(widget.NewLabel(fmt.Sprintf(i18n.T(CurrentLang, "explorer_reward"), rb.ToMiner, rb.ToStakers, rb.ToDev, rb.ToBurn, rb.ToCommunity)))
+			}
 			for _, tx := range block.Transactions {
 				content.Add(widget.NewLabel(fmt.Sprintf(i18n.T(CurrentLang, "explorer_tx"), tx.ID)))
 				for _, out := range tx.Outputs {
-					content.Add(widget.NewLabel(fmt.Sprintf(i18n.T(CurrentLang, "explorer_tx_out"), out.Amount)))
+					content.Add(widget.NewLabel(fmt.Sprintf(i18n.T(CurrentLang, txOutKey(out)), out.Amount)))
 				}
 			}
 			content.Add(widget.NewSeparator())
@@ -33,6 +33,11 @@ func LaunchMainUI(a fyne.App, w fyne.Window, wlt *wallet.Wallet, bc *blockchain.
 		ShowMineWindow(a, mineWin, wlt.GetAddress(), bc)
 	}))
 
+	stakeTab := container.NewCenter(widget.NewButton(i18n.T(CurrentLang, "stake_title"), func() {
+		stakeWin := a.NewWindow(i18n.T(CurrentLang, "stake_title"))
+		ShowStakeWindow(a, stakeWin, wlt, bc)
+	}))
+
 	explorerTab := container.NewCenter(widget.NewButton(i18n.T(CurrentLang, "explorer_title"), func() {
 		expWin := a.NewWindow(i18n.T(CurrentLang, "explorer_title"))
 		ShowExplorerWindow(a, expWin, bc)
@@ -49,6 +54,7 @@ func LaunchMainUI(a fyne.App, w fyne.Window, wlt *wallet.Wallet, bc *blockchain.
 		container.NewTabItem(i18n.T(CurrentLang, "wallet_tab"), walletTab),
 		container.NewTabItem(i18n.T(CurrentLang, "send_tab"), sendTab),
 		container.NewTabItem(i18n.T(CurrentLang, "mine_tab"), mineTab),
+		container.NewTabItem(i18n.T(CurrentLang, "stake_tab"), stakeTab),
 		container.NewTabItem(i18n.T(CurrentLang, "explorer_tab"), explorerTab),
 		container.NewTabItem(i18n.T(CurrentLang, "settings_tab"), settingsTab),
 	)
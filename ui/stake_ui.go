@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/config"
+	"quantumcoin/i18n"
+	"quantumcoin/wallet"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ShowStakeWindow: stake başlat, stake'leri listele, unstake / withdraw
+func ShowStakeWindow(a fyne.App, w fyne.Window, wlt *wallet.Wallet, bc *blockchain.Blockchain) {
+	w.SetTitle(i18n.T(CurrentLang, "stake_title"))
+	cfg := config.Current()
+	if bc == nil || wlt == nil {
+		dialog.ShowError(fmt.Errorf(i18n.T(CurrentLang, "error_blockchain_not_connected")), w)
+		return
+	}
+	addr := wlt.GetAddress()
+
+	summary := widget.NewLabel("")
+	list := container.NewVBox()
+
+	amountEntry := widget.NewEntry()
+	amountEntry.SetPlaceHolder(fmt.Sprintf(i18n.T(CurrentLang, "stake_amount_placeholder"), cfg.Params().Stake.MinAmount))
+	lockEntry := widget.NewEntry()
+	lockEntry.SetPlaceHolder(fmt.Sprintf(i18n.T(CurrentLang, "stake_lock_placeholder"), cfg.Params().Stake.MinLockBlocks))
+
+	var refresh func()
+	submit := func(tx *blockchain.Transaction, err error) {
+		if err == nil {
+			err = bc.SignTransaction(tx, wlt.PrivateKey)
+		}
+		if err == nil {
+			err = bc.AddTransaction(tx)
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("%s: %v", i18n.T(CurrentLang, "error_tx_add"), err), w)
+			return
+		}
+		dialog.ShowInformation(i18n.T(CurrentLang, "success"), i18n.T(CurrentLang, "stake_success"), w)
+		refresh()
+	}
+
+	refresh = func() {
+		next := bc.GetBestHeight() + 1
+		active, unbonding, ready := 0, 0, 0
+		list.RemoveAll()
+		for _, st := range bc.Stakes(addr) {
+			st := st
+			row := widget.NewLabel(fmt.Sprintf(i18n.T(CurrentLang, "stake_row"), st.Amount, st.Status, st.UnlockHeight))
+			switch {
+			case st.Status == blockchain.StakeActive:
+				active += st.Amount
+				btn := widget.NewButton(i18n.T(CurrentLang, "stake_unstake"), func() {
+					submit(blockchain.NewUnstakeTransaction(addr, st.TxID, st.Index, bc))
+				})
+				if next < st.UnlockHeight {
+					btn.Disable()
+				}
+				list.Add(container.NewHBox(row, btn))
+				continue
+			case next >= st.UnlockHeight:
+				ready += st.Amount
+			default:
+				unbonding += st.Amount
+			}
+			list.Add(row)
+		}
+		summary.SetText(fmt.Sprintf(i18n.T(CurrentLang, "stake_summary"), active, unbonding, ready, next-1))
+	}
+
+	stakeBtn := widget.NewButton(i18n.T(CurrentLang, "stake_button"), func() {
+		amount, err := strconv.Atoi(strings.TrimSpace(amountEntry.Text))
+		if err != nil || amount <= 0 {
+			dialog.ShowError(fmt.Errorf(i18n.T(CurrentLang, "error_invalid_amount")), w)
+			return
+		}
+		lock, _ := strconv.Atoi(strings.TrimSpace(lockEntry.Text)) // boş => en az
+		submit(blockchain.NewStakeTransaction(addr, amount, lock, bc))
+		amountEntry.SetText("")
+	})
+	withdrawBtn := widget.NewButton(i18n.T(CurrentLang, "stake_withdraw"), func() {
+		submit(blockchain.NewWithdrawTransaction(addr, bc))
+	})
+
+	refresh()
+	w.SetContent(container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle(i18n.T(CurrentLang, "stake_title"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+			summary,
+			amountEntry,
+			lockEntry,
+			container.NewHBox(stakeBtn, withdrawBtn),
+		),
+		nil, nil, nil,
+		container.NewVScroll(list),
+	))
+	w.Resize(fyne.NewSize(520, 420))
+	w.Show()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math/big"
+	"sort"
 )
 
 // Uint64ToBytes: uint64 → []byte (big-endian)
@@ -31,3 +32,13 @@ func BytesToUint64(b []byte) uint64 {
 func BigIntToBytes(n *big.Int) []byte {
 	return n.Bytes()
 }
+
+// SortedKeys: map anahtarları sıralı (deterministik gezinme için)
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
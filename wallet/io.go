@@ -1,30 +1,35 @@
 package wallet
 
 import (
-	"crypto/elliptic"
-	"crypto/x509"
-	"encoding/hex"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 
 	"quantumcoin/config"
+	"quantumcoin/logging"
 	"quantumcoin/utils"
 )
 
-var storeMu sync.Mutex
+var (
+	storeMu sync.Mutex
+	logger  = logging.For(logging.SubWallet)
+)
 
 // Disk formatı:
 //
 //	{
-//	  "wallets": { "<address>": "<priv_hex>", ... },
-//	  "default": "<address>"
+//	  "wallets":  { "<address>": "<priv_hex>", ... },
+//	  "default":  "<address>",
+//	  "multisig": { "<address>": "m:pubhex,pubhex,..." }
 //	}
 type diskStore struct {
-	Wallets map[string]string `json:"wallets"`
-	Default string            `json:"default"`
+	Wallets  map[string]string `json:"wallets"`
+	Default  string            `json:"default"`
+	Multisig map[string]string `json:"multisig,omitempty"`
 }
 
 func walletFilePath() string {
@@ -73,11 +78,8 @@ func SaveWallet(w *Wallet) error {
 	if w == nil || w.PrivateKey == nil {
 		return errors.New("wallet/save: invalid wallet")
 	}
-	privBytes, err := x509.MarshalECPrivateKey(w.PrivateKey)
-	if err != nil {
-		return err
-	}
-	privHex := hex.EncodeToString(privBytes)
+	// Ham 32 bayt D (x509 secp256k1 eğrisini desteklemiyor)
+	privHex := w.ExportPrivateKeyHex()
 	addr := w.GetAddress()
 
 	storeMu.Lock()
@@ -94,7 +96,12 @@ func SaveWallet(w *Wallet) error {
 	if st.Default == "" {
 		st.Default = addr
 	}
-	return writeStore(st)
+	if err := writeStore(st); err != nil {
+		logger.Error("key store write failed", "address", addr, "file", walletFilePath(), "err", err)
+		return err
+	}
+	logger.Info("key stored", "address", addr, "file", walletFilePath())
+	return nil
 }
 
 // Depodan cüzdan yükle:
@@ -107,6 +114,7 @@ func LoadWalletFromFile() *Wallet {
 
 	st, err := readStore()
 	if err != nil {
+		logger.Warn("wallet store unreadable, using new key", "file", walletFilePath(), "err", err)
 		nw := NewWallet()
 		_ = SaveWallet(nw)
 		return nw
@@ -148,30 +156,13 @@ func loadWalletByAddress(st *diskStore, address string) (*Wallet, bool) {
 	if !ok || privHex == "" {
 		return nil, false
 	}
-	privBytes, err := hex.DecodeString(privHex)
-	if err != nil {
-		return nil, false
-	}
-	priv, err := x509.ParseECPrivateKey(privBytes)
+	// ImportPrivateKeyHex eski SEC1/PKCS#8 kayıtlarını da okur
+	priv, err := ImportPrivateKeyHex(privHex)
 	if err != nil {
 		return nil, false
 	}
-	// Uncompressed pubkey (0x04||X||Y), P-256
-	pub := make([]byte, 0, 1+2*((priv.Curve.Params().BitSize+7)/8))
-	pub = append(pub, 0x04)
-	byteLen := (elliptic.P256().Params().BitSize + 7) / 8
-	x := priv.PublicKey.X.Bytes()
-	y := priv.PublicKey.Y.Bytes()
-	if lx := len(x); lx < byteLen {
-		pad := make([]byte, byteLen-lx)
-		x = append(pad, x...)
-	}
-	if ly := len(y); ly < byteLen {
-		pad := make([]byte, byteLen-ly)
-		y = append(pad, y...)
-	}
-	pub = append(pub, x...)
-	pub = append(pub, y...)
+	pub := append([]byte{0x04}, pad32(priv.PublicKey.X.Bytes())...)
+	pub = append(pub, pad32(priv.PublicKey.Y.Bytes())...)
 
 	return &Wallet{PrivateKey: priv, PublicKey: pub}, true
 }
@@ -192,6 +183,97 @@ func SetDefaultWallet(address string) error {
 	return writeStore(st)
 }
 
+// FindWalletByPubKey: depodaki anahtarlardan verilen public key'e ait olanı bulur
+// (multisig descriptor'ında hangi anahtarların bize ait olduğunu görmek için).
+func FindWalletByPubKey(pub []byte) (*Wallet, bool) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	st, err := readStore()
+	if err != nil {
+		return nil, false
+	}
+	// Depo uncompressed anahtarın adresiyle tutulur; compressed anahtar da kabul edilir
+	u := DecompressPubKey(pub)
+	if u == nil {
+		return nil, false
+	}
+	w, ok := loadWalletByAddress(st, GetAddressFromPub(u))
+	if !ok || !bytes.Equal(w.PublicKey, u) {
+		return nil, false
+	}
+	return w, true
+}
+
+// SaveMultisig: multisig descriptor'ını adresiyle birlikte depoya yazar.
+func SaveMultisig(d *MultisigDescriptor) error {
+	if d == nil {
+		return errors.New("wallet/multisig: nil descriptor")
+	}
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	st, err := readStore()
+	if err != nil {
+		return err
+	}
+	if st.Multisig == nil {
+		st.Multisig = map[string]string{}
+	}
+	addr := d.Address()
+	if st.Multisig[addr] == d.String() {
+		return nil
+	}
+	st.Multisig[addr] = d.String()
+	if err := writeStore(st); err != nil {
+		return err
+	}
+	logger.Info("multisig stored", "address", addr, "m", d.M, "n", len(d.PubKeys))
+	return nil
+}
+
+// LookupMultisig: adrese kayıtlı descriptor
+func LookupMultisig(address string) (*MultisigDescriptor, bool) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	st, err := readStore()
+	if err != nil {
+		return nil, false
+	}
+	spec, ok := st.Multisig[address]
+	if !ok {
+		return nil, false
+	}
+	d, err := ParseMultisigSpec(spec)
+	if err != nil || d.Address() != address {
+		return nil, false
+	}
+	return d, true
+}
+
+// FindMultisigByHash: Hash160(descriptor) ile kayıtlı descriptor
+func FindMultisigByHash(h []byte) (*MultisigDescriptor, bool) {
+	return LookupMultisig(AddressFromMultisigHash(h))
+}
+
+// ListMultisig: kayıtlı multisig adresleri (sıralı)
+func ListMultisig() []string {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	st, err := readStore()
+	if err != nil {
+		return nil
+	}
+	out := make([]string, 0, len(st.Multisig))
+	for addr := range st.Multisig {
+		out = append(out, addr)
+	}
+	sort.Strings(out)
+	return out
+}
+
 // QC adresinden pubKeyHash'i çıkar (Base58Check decode)
 func Base58DecodeAddress(address string) []byte {
 	decoded, err := utils.Base58Decode([]byte(address))
@@ -0,0 +1,225 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"quantumcoin/config"
+	"quantumcoin/utils"
+)
+
+// Adres sürüm baytları (Base58Check önek); varsayılan mainnet, UseNetwork ile değişir
+var (
+	AddrVersionP2PKH    byte = 0x00 // tek anahtar: Hash160(pubkey)
+	AddrVersionMultisig byte = 0x05 // m-of-n: Hash160(descriptor)
+)
+
+// UseNetwork: adres öneklerini ağın parametrelerine ayarlar (düğüm açılışında bir kez)
+func UseNetwork(p config.NetworkParams) {
+	AddrVersionP2PKH, AddrVersionMultisig = p.AddrVersion, p.MultisigVersion
+}
+
+// MaxMultisigKeys: bir descriptor'daki en fazla anahtar sayısı
+const MaxMultisigKeys = 15
+
+// MultisigDescriptor: m-of-n kilit tanımı (redeem descriptor).
+// Anahtarlar sıralı tutulur; aynı küme her zaman aynı adresi verir.
+type MultisigDescriptor struct {
+	M       int
+	PubKeys [][]byte
+}
+
+// NewMultisigDescriptor: 1 <= m <= n <= MaxMultisigKeys, tekrar eden anahtar yok.
+func NewMultisigDescriptor(m int, pubs [][]byte) (*MultisigDescriptor, error) {
+	n := len(pubs)
+	if n == 0 || n > MaxMultisigKeys {
+		return nil, fmt.Errorf("multisig: key count must be 1..%d", MaxMultisigKeys)
+	}
+	if m < 1 || m > n {
+		return nil, fmt.Errorf("multisig: m must be 1..%d", n)
+	}
+	keys := make([][]byte, 0, n)
+	for i, p := range pubs {
+		if !isCanonicalPubKey(p) {
+			return nil, fmt.Errorf("multisig: key #%d is not a valid compressed/uncompressed public key", i)
+		}
+		keys = append(keys, append([]byte(nil), p...))
+	}
+	sort.Slice(keys, func(a, b int) bool { return bytes.Compare(keys[a], keys[b]) < 0 })
+	seen := make(map[string]bool, n)
+	for _, k := range keys {
+		u := string(DecompressPubKey(k))
+		if seen[u] {
+			return nil, errors.New("multisig: duplicate public key")
+		}
+		seen[u] = true
+	}
+	return &MultisigDescriptor{M: m, PubKeys: keys}, nil
+}
+
+// Bytes: [m][n] + n × ([len][pubkey]) — Hash160'ı adresi belirler
+func (d *MultisigDescriptor) Bytes() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(byte(d.M))
+	buf.WriteByte(byte(len(d.PubKeys)))
+	for _, p := range d.PubKeys {
+		buf.WriteByte(byte(len(p)))
+		buf.Write(p)
+	}
+	return buf.Bytes()
+}
+
+// ParseMultisigDescriptor: Bytes() çıktısını çözer ve kuralları yeniden uygular.
+func ParseMultisigDescriptor(b []byte) (*MultisigDescriptor, error) {
+	if len(b) < 2 {
+		return nil, errors.New("multisig: descriptor too short")
+	}
+	m, n := int(b[0]), int(b[1])
+	rest := b[2:]
+	pubs := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+			return nil, errors.New("multisig: truncated descriptor")
+		}
+		l := int(rest[0])
+		pubs = append(pubs, rest[1:1+l])
+		rest = rest[1+l:]
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("multisig: trailing bytes in descriptor")
+	}
+	d, err := NewMultisigDescriptor(m, pubs)
+	if err != nil {
+		return nil, err
+	}
+	// Kanonik olmayan (sırasız) kodlamalar reddedilir: aynı kilit tek bir bayt dizisine karşılık gelmeli
+	if !bytes.Equal(d.Bytes(), b) {
+		return nil, errors.New("multisig: descriptor is not canonical")
+	}
+	return d, nil
+}
+
+// ParseMultisigSpec: "m:pubhex,pubhex,..." (config / CLI formatı)
+func ParseMultisigSpec(spec string) (*MultisigDescriptor, error) {
+	mStr, keys, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	if !ok {
+		return nil, errors.New("multisig: spec must be m:pubhex,pubhex,...")
+	}
+	m, err := strconv.Atoi(strings.TrimSpace(mStr))
+	if err != nil {
+		return nil, fmt.Errorf("multisig: bad m: %w", err)
+	}
+	var pubs [][]byte
+	for _, k := range strings.Split(keys, ",") {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		p, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, fmt.Errorf("multisig: bad pubkey hex: %w", err)
+		}
+		pubs = append(pubs, p)
+	}
+	return NewMultisigDescriptor(m, pubs)
+}
+
+// String: ParseMultisigSpec ile geri okunabilir biçim
+func (d *MultisigDescriptor) String() string {
+	keys := make([]string, len(d.PubKeys))
+	for i, p := range d.PubKeys {
+		keys[i] = hex.EncodeToString(p)
+	}
+	return strconv.Itoa(d.M) + ":" + strings.Join(keys, ",")
+}
+
+// Hash: Hash160(descriptor) — çıktının kilidi
+func (d *MultisigDescriptor) Hash() []byte { return utils.Hash160(d.Bytes()) }
+
+// Address: Base58Check(AddrVersionMultisig || Hash160(descriptor))
+func (d *MultisigDescriptor) Address() string { return AddressFromMultisigHash(d.Hash()) }
+
+// IndexOf: anahtarın descriptor içindeki sırası (yoksa -1).
+// Anahtar compressed ya da uncompressed verilebilir.
+func (d *MultisigDescriptor) IndexOf(pub []byte) int {
+	u := DecompressPubKey(pub)
+	if u == nil {
+		return -1
+	}
+	for i, p := range d.PubKeys {
+		if bytes.Equal(DecompressPubKey(p), u) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isCanonicalPubKey: 65B (0x04) ya da 33B (0x02/0x03) ve eğri üzerinde
+func isCanonicalPubKey(p []byte) bool {
+	switch {
+	case len(p) == 65 && p[0] == 0x04:
+	case len(p) == 33 && (p[0] == 0x02 || p[0] == 0x03):
+	default:
+		return false
+	}
+	return DecompressPubKey(p) != nil
+}
+
+// AddressFromMultisigHash: Hash160(descriptor) -> Base58Check (version=AddrVersionMultisig)
+func AddressFromMultisigHash(h []byte) string {
+	versioned := append([]byte{AddrVersionMultisig}, h...)
+	full := append(versioned, utils.Checksum(versioned)...)
+	return string(utils.Base58Encode(full))
+}
+
+// DecodeAddress: panik yerine hata döndüren Base58Check çözücü.
+func DecodeAddress(address string) (version byte, hash []byte, err error) {
+	decoded, err := utils.Base58Decode([]byte(strings.TrimSpace(address)))
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid address: %w", err)
+	}
+	if len(decoded) < 5 {
+		return 0, nil, errors.New("invalid address")
+	}
+	payload := decoded[:len(decoded)-4]
+	if !bytes.Equal(decoded[len(decoded)-4:], calculateChecksum(payload)) {
+		return 0, nil, errors.New("invalid address checksum")
+	}
+	return payload[0], payload[1:], nil
+}
+
+// IsMultisigAddress: adres AddrVersionMultisig sürümlü mü?
+func IsMultisigAddress(address string) bool {
+	v, _, err := DecodeAddress(address)
+	return err == nil && v == AddrVersionMultisig
+}
+
+// RegisterFundMultisigs: ağın dev fonu / community descriptor'larını depoya kaydeder
+// (PSBT oluştururken redeem bilgisinin bulunabilmesi için). Descriptor'ın adresi
+// ağın fon adresiyle tutmazsa hata (ağ parametreleri bozuk).
+func RegisterFundMultisigs(r config.RewardSplit) error {
+	for _, f := range []struct{ name, spec, address string }{
+		{"dev fund", r.DevFundMultisig, r.DevFund},
+		{"community", r.CommunityMultisig, r.Community},
+	} {
+		if strings.TrimSpace(f.spec) == "" {
+			continue
+		}
+		d, err := ParseMultisigSpec(f.spec)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+		if addr := d.Address(); addr != f.address {
+			return fmt.Errorf("%s: descriptor address %s does not match network address %q", f.name, addr, f.address)
+		}
+		if err := SaveMultisig(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
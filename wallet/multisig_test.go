@@ -0,0 +1,48 @@
+package wallet
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"quantumcoin/config"
+)
+
+func TestRegisterFundMultisigs(t *testing.T) {
+	prev := config.Current()
+	t.Cleanup(func() {
+		config.Set(prev)
+		UseNetwork(prev.Params())
+	})
+	for _, name := range []string{config.NetworkMainnet, config.NetworkTestnet, config.NetworkRegtest} {
+		c := config.Default()
+		if err := c.UseNetwork(name); err != nil {
+			t.Fatal(err)
+		}
+		c.WalletFile = filepath.Join(t.TempDir(), "wallet.json")
+		config.Set(c)
+		p := c.Params()
+		UseNetwork(p)
+
+		if err := RegisterFundMultisigs(p.Reward); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, addr := range []string{p.Reward.DevFund, p.Reward.Community} {
+			if addr == "" {
+				continue
+			}
+			if !IsMultisigAddress(addr) {
+				t.Errorf("%s: fund address %s is not multisig", name, addr)
+			}
+			if _, ok := LookupMultisig(addr); !ok {
+				t.Errorf("%s: descriptor for %s not stored", name, addr)
+			}
+		}
+	}
+
+	r := config.Current().Params().Reward
+	r.DevFund = r.Community
+	if err := RegisterFundMultisigs(r); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("mismatched descriptor: got %v", err)
+	}
+}
@@ -34,15 +34,22 @@ func NewWallet() *Wallet {
 	if err != nil {
 		log.Panicf("Cüzdan anahtarı oluşturulamadı: %v", err)
 	}
+	return WalletFromKey(priv)
+}
+
+// WalletFromKey: özel anahtardan cüzdan (65 bayt uncompressed public key ile)
+func WalletFromKey(priv *ecdsa.PrivateKey) *Wallet {
 	pub := append([]byte{0x04}, pad32(priv.PublicKey.X.Bytes())...)
 	pub = append(pub, pad32(priv.PublicKey.Y.Bytes())...)
 	return &Wallet{PrivateKey: priv, PublicKey: pub}
 }
 
-// PubKey -> HASH160 -> Base58Check (version=0x00)
-func GetAddressFromPub(pub []byte) string {
-	pubKeyHash := HashPubKey(pub)
-	versioned := append([]byte{0x00}, pubKeyHash...)
+// PubKey -> HASH160 -> Base58Check (version=AddrVersionP2PKH)
+func GetAddressFromPub(pub []byte) string { return AddressFromPubKeyHash(HashPubKey(pub)) }
+
+// HASH160 -> Base58Check (version=AddrVersionP2PKH)
+func AddressFromPubKeyHash(pubKeyHash []byte) string {
+	versioned := append([]byte{AddrVersionP2PKH}, pubKeyHash...)
 	checksum := utils.Checksum(versioned)
 	full := append(versioned, checksum...)
 	return string(utils.Base58Encode(full))
@@ -77,3 +84,38 @@ func HashAndEncode(pubKey []byte) string { return GetAddressFromPub(pubKey) }
 
 // 65B uncompressed public key (0x04||X||Y)
 func (w *Wallet) UncompressedPub() []byte { return w.PublicKey }
+
+// 33B compressed public key (0x02/0x03||X)
+func (w *Wallet) CompressedPub() []byte { return CompressPubKey(w.PublicKey) }
+
+// CompressPubKey: 33 ya da 65 baytlık geçerli anahtarı 33 bayta indirir (geçersizse nil)
+func CompressPubKey(pub []byte) []byte {
+	key, err := secp256k1.ParsePubKey(pub)
+	if err != nil {
+		return nil
+	}
+	return key.SerializeCompressed()
+}
+
+// DecompressPubKey: 33 ya da 65 baytlık geçerli anahtarı 65 bayta açar (geçersizse nil)
+func DecompressPubKey(pub []byte) []byte {
+	key, err := secp256k1.ParsePubKey(pub)
+	if err != nil {
+		return nil
+	}
+	return key.SerializeUncompressed()
+}
+
+// PubKeyHashes: anahtarın her iki kodlamasının Hash160'ı.
+// Eski adresler uncompressed anahtardan türetildiği için, aynı anahtarın
+// compressed biçimi de bu adreslere kilitli çıktıları açabilmelidir.
+func PubKeyHashes(pub []byte) [][]byte {
+	key, err := secp256k1.ParsePubKey(pub)
+	if err != nil {
+		return nil
+	}
+	return [][]byte{
+		HashPubKey(key.SerializeUncompressed()),
+		HashPubKey(key.SerializeCompressed()),
+	}
+}
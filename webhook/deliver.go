@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Başlıklar
+const (
+	HeaderSignature = "X-QC-Signature" // "sha256=<hex>"
+	HeaderEvent     = "X-QC-Event"
+	HeaderDelivery  = "X-QC-Delivery"
+	HeaderAttempt   = "X-QC-Attempt"
+)
+
+const (
+	deliveryWorkers = 4                // aynı anda en çok bu kadar istek
+	backoffBase     = 5 * time.Second  // 1. başarısızlıktan sonra bekleme; her denemede iki katı
+	backoffMax      = 30 * time.Minute // bekleme üst sınırı
+	maxBody         = 1 << 20          // Receiver'ın kabul ettiği en büyük gövde
+)
+
+// Sign: body için X-QC-Signature değeri
+func Sign(secret string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write(body)
+	return "sha256=" + hex.EncodeToString(m.Sum(nil))
+}
+
+// Verify: X-QC-Signature başlığı body ve secret ile uyuşuyor mu (sabit zamanlı)
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(strings.TrimSpace(signature)))
+}
+
+// Receiver: imzayı doğrulayıp bildirimi fn'e veren alıcı (yerel test ve
+// entegrasyon için). İmza geçersizse 401, gövde bozuksa 400, aksi halde 204.
+func Receiver(secret string, fn func(Notification)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBody))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !Verify(secret, body, r.Header.Get(HeaderSignature)) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fn(n)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// backoff: attempts başarısız denemeden sonraki bekleme
+func backoff(attempts int) time.Duration {
+	d := backoffBase
+	for i := 1; i < attempts && d < backoffMax; i++ {
+		d *= 2
+	}
+	return min(d, backoffMax)
+}
+
+// deliverLoop: vadesi gelen teslimleri gönderir (yeni teslimde ya da saniyede bir)
+func (s *Service) deliverLoop(ctx context.Context) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		case <-s.wake:
+		}
+		s.deliverDue(ctx)
+	}
+}
+
+// job: kilit dışında gönderilecek teslimin kopyası
+type job struct {
+	id, url, secret, event string
+	attempt                int
+	payload                []byte
+}
+
+// deliverDue: vadesi gelen bekleyen teslimleri deliveryWorkers paralellikle
+// gönderir ve sonuçları işler
+func (s *Service) deliverDue(ctx context.Context) {
+	now := time.Now()
+	var jobs []job
+	s.mu.Lock()
+	for _, d := range s.st.Deliveries {
+		if d.Status != StatusPending || d.NextAttempt.After(now) {
+			continue
+		}
+		hk := s.hookLocked(d.HookID)
+		if hk == nil {
+			s.finishLocked(d, StatusCancelled, "hook removed")
+			continue
+		}
+		jobs = append(jobs, job{id: d.ID, url: hk.URL, secret: hk.Secret, event: d.Event, attempt: d.Attempts + 1, payload: d.Payload})
+	}
+	s.mu.Unlock()
+	if len(jobs) == 0 {
+		return
+	}
+
+	type result struct {
+		code int
+		err  error
+	}
+	results := make([]result, len(jobs))
+	sem := make(chan struct{}, deliveryWorkers)
+	var wg sync.WaitGroup
+	for i := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			code, err := s.post(ctx, jobs[i])
+			results[i] = result{code, err}
+		}(i)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return // kapanışta kesilen denemeler sayılmaz
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, jb := range jobs {
+		var d *Delivery
+		for _, x := range s.st.Deliveries {
+			if x.ID == jb.id {
+				d = x
+				break
+			}
+		}
+		if d == nil || d.Status != StatusPending {
+			continue // bu arada iptal edildi ya da budandı
+		}
+		res := results[i]
+		d.Attempts++
+		d.LastCode, d.Updated = res.code, time.Now()
+		switch {
+		case res.err == nil:
+			d.Status, d.LastError = StatusDelivered, ""
+		case d.Attempts >= s.opt.MaxAttempts:
+			s.finishLocked(d, StatusFailed, res.err.Error())
+			logger.Warn("delivery failed", "delivery", d.ID, "hook", d.HookID, "attempts", d.Attempts, "err", res.err)
+		default:
+			d.LastError = res.err.Error()
+			d.NextAttempt = d.Updated.Add(backoff(d.Attempts))
+		}
+	}
+	if err := s.saveLocked(); err != nil {
+		logger.Error("save state failed", "err", err)
+	}
+}
+
+// post: tek teslim denemesi; 2xx dışındaki yanıtlar hatadır
+func (s *Service) post(ctx context.Context, jb job) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, jb.url, bytes.NewReader(jb.payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "quantumcoin-webhook/1")
+	req.Header.Set(HeaderSignature, Sign(jb.secret, jb.payload))
+	req.Header.Set(HeaderEvent, jb.event)
+	req.Header.Set(HeaderDelivery, jb.id)
+	req.Header.Set(HeaderAttempt, strconv.Itoa(jb.attempt))
+	resp, err := s.opt.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, fmt.Errorf("http %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,564 @@
+// Package webhook: ödeme bildirimleri için giden webhook'lar. Kullanıcı bir
+// URL'yi adres ve/veya txid filtreleri ve gereken onay sayısıyla kaydeder;
+// eşleşen işlem bu onaya ulaştığında URL'ye imzalı JSON (Notification) POST
+// edilir. Teslimler kalıcı bir kuyrukta tutulur; başarısız olanlar üstel geri
+// çekilmeyle MaxAttempts'e kadar yeniden denenir.
+//
+// Her bildirim (kanca, txid, adres) başına bir kez üretilir. Reorg'da zincirden
+// düşen işlemin bekleyen teslimi iptal edilir; işlem yeniden onaylanırsa
+// bildirim tekrar üretilir.
+//
+// İmza: X-QC-Signature: sha256=hex(HMAC-SHA256(secret, gövde)); alıcı Verify ya
+// da Receiver ile doğrular.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/config"
+	"quantumcoin/events"
+	"quantumcoin/logging"
+	"quantumcoin/wallet"
+)
+
+var logger = logging.For(logging.SubWebhook)
+
+// Teslim durumları
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled" // işlem reorg'da zincirden düştü ya da kanca silindi
+)
+
+// Olay türleri (X-QC-Event ve Notification.Event)
+const (
+	EventPayment = "payment"
+	EventPing    = "ping"
+)
+
+const (
+	maxDeliveries = 5000 // durum dosyasında tutulan teslim (bekleyenler atılmaz)
+	maxFilters    = 1000 // kanca başına adres + txid
+)
+
+var (
+	ErrNotFound = errors.New("webhook: not found")
+	ErrNoFilter = errors.New("webhook: at least one address or txid filter is required")
+)
+
+// Options: webhook servisi ayarları (bkz. OptionsFromConfig)
+type Options struct {
+	StateFile   string
+	MaxAttempts int
+	Timeout     time.Duration
+
+	Bus    *events.Bus  // nil => events.Default
+	Client *http.Client // nil => Timeout'lu istemci
+}
+
+// OptionsFromConfig: config alanlarından Options
+func OptionsFromConfig(c *config.Config) Options {
+	return Options{
+		StateFile:   c.WebhookStateFile,
+		MaxAttempts: c.WebhookMaxAttempts,
+		Timeout:     time.Duration(c.WebhookTimeoutSecs) * time.Second,
+	}
+}
+
+// Hook: kayıtlı webhook
+type Hook struct {
+	ID            string    `json:"id"`
+	URL           string    `json:"url"`
+	Secret        string    `json:"secret,omitempty"` // yalnız kayıt yanıtında döner
+	Addresses     []string  `json:"addresses,omitempty"`
+	TxIDs         []string  `json:"txids,omitempty"`
+	Confirmations int       `json:"confirmations"` // 0 => işlem mempool'a girince
+	FromHeight    int       `json:"fromHeight"`    // yalnız bu yükseklikten sonraki bloklar
+	Created       time.Time `json:"created"`
+}
+
+// Notification: alıcıya POST edilen gövde
+type Notification struct {
+	Delivery      string `json:"delivery"`
+	Event         string `json:"event"`
+	Hook          string `json:"hook"`
+	TxID          string `json:"txid,omitempty"`
+	Address       string `json:"address,omitempty"` // txid filtresiyle eşleşmede boş
+	Amount        int    `json:"amount"`            // adrese gelen (adres boşsa işlemin toplam çıktısı)
+	Coinbase      bool   `json:"coinbase,omitempty"`
+	Confirmations int    `json:"confirmations"`
+	Height        int    `json:"height"` // -1 => mempool
+	BlockHash     string `json:"blockhash,omitempty"`
+	Timestamp     int64  `json:"timestamp"`
+}
+
+// Delivery: kuyruktaki bir bildirim ve teslim geçmişi
+type Delivery struct {
+	ID          string          `json:"id"`
+	HookID      string          `json:"hookId"`
+	Event       string          `json:"event"`
+	Key         string          `json:"key"` // kanca|txid|adres — aynı bildirim bir kez
+	TxID        string          `json:"txid,omitempty"`
+	Address     string          `json:"address,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	LastCode    int             `json:"lastCode,omitempty"` // son HTTP durum kodu
+	LastError   string          `json:"lastError,omitempty"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+	Created     time.Time       `json:"created"`
+	Updated     time.Time       `json:"updated"`
+}
+
+// state: diske yazılan kalıcı durum
+type state struct {
+	LastHeight int         `json:"last_height"` // taranan son zincir yüksekliği
+	Hooks      []*Hook     `json:"hooks"`
+	Deliveries []*Delivery `json:"deliveries"`
+}
+
+// Service: kanca kayıtları, eşleştirme ve teslim kuyruğu
+type Service struct {
+	bc   *blockchain.Blockchain
+	opt  Options
+	wake chan struct{}
+
+	mu   sync.Mutex
+	st   state
+	keys map[string]bool // iptal edilmemiş teslimlerin Key'leri
+}
+
+// New: servisi kurar, durumu diskten yükler. Yeni durum dosyasında tarama
+// mevcut uçtan başlar (geçmiş bloklar bildirilmez).
+func New(bc *blockchain.Blockchain, opt Options) (*Service, error) {
+	if bc == nil {
+		return nil, errors.New("webhook: blockchain is nil")
+	}
+	if opt.MaxAttempts <= 0 {
+		opt.MaxAttempts = 8
+	}
+	if opt.Timeout <= 0 {
+		opt.Timeout = 10 * time.Second
+	}
+	if opt.Bus == nil {
+		opt.Bus = events.Default
+	}
+	if opt.Client == nil {
+		opt.Client = &http.Client{Timeout: opt.Timeout}
+	}
+	s := &Service{bc: bc, opt: opt, wake: make(chan struct{}, 1), st: state{LastHeight: -1}}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if s.st.LastHeight < 0 {
+		s.st.LastHeight = bc.GetBestHeight()
+	}
+	s.reindexLocked()
+	return s, nil
+}
+
+// Register: h.URL için kanca kaydeder; Secret boşsa üretilir. Dönen kopya
+// Secret'ı içerir (sonraki listelemelerde gizlenir).
+func (s *Service) Register(h Hook) (Hook, error) {
+	u, err := url.Parse(strings.TrimSpace(h.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Hook{}, fmt.Errorf("webhook: url must be an absolute http(s) URL")
+	}
+	h.URL = u.String()
+	if h.Confirmations < 0 {
+		return Hook{}, errors.New("webhook: confirmations cannot be negative")
+	}
+	if len(h.Addresses)+len(h.TxIDs) == 0 {
+		return Hook{}, ErrNoFilter
+	}
+	if len(h.Addresses)+len(h.TxIDs) > maxFilters {
+		return Hook{}, fmt.Errorf("webhook: at most %d filters per hook", maxFilters)
+	}
+	// çağıranın dilimleri değiştirilmez (kayıt kendi kopyasını tutar)
+	h.Addresses = append([]string(nil), h.Addresses...)
+	h.TxIDs = append([]string(nil), h.TxIDs...)
+	for i, a := range h.Addresses {
+		a = strings.TrimSpace(a)
+		if _, _, err := wallet.DecodeAddress(a); err != nil {
+			return Hook{}, fmt.Errorf("webhook: address %s: %w", a, err)
+		}
+		h.Addresses[i] = a
+	}
+	for i, id := range h.TxIDs {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if b, err := hex.DecodeString(id); err != nil || len(b) == 0 {
+			return Hook{}, fmt.Errorf("webhook: bad txid %s", id)
+		}
+		h.TxIDs[i] = id
+	}
+	if h.Secret == "" {
+		h.Secret = randomHex(32)
+	}
+	h.ID = randomHex(8)
+	h.FromHeight = s.bc.GetBestHeight()
+	h.Created = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.st.Hooks = append(s.st.Hooks, &h)
+	return h, s.saveLocked()
+}
+
+// Remove: kancayı siler; bekleyen teslimleri iptal edilir
+func (s *Service) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.hookIndexLocked(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	s.st.Hooks = append(s.st.Hooks[:i], s.st.Hooks[i+1:]...)
+	for _, d := range s.st.Deliveries {
+		if d.HookID == id && d.Status == StatusPending {
+			s.finishLocked(d, StatusCancelled, "hook removed")
+		}
+	}
+	return s.saveLocked()
+}
+
+// Hooks: kayıtlı kancalar (Secret gizli)
+func (s *Service) Hooks() []Hook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Hook, 0, len(s.st.Hooks))
+	for _, h := range s.st.Hooks {
+		c := *h
+		c.Secret = ""
+		out = append(out, c)
+	}
+	return out
+}
+
+// Deliveries: teslimler, en yeniden eskiye (hookID/status boşsa filtre yok; limit <= 0 => tümü)
+func (s *Service) Deliveries(hookID, status string, limit int) []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []Delivery{}
+	for i := len(s.st.Deliveries) - 1; i >= 0; i-- {
+		d := s.st.Deliveries[i]
+		if (hookID != "" && d.HookID != hookID) || (status != "" && d.Status != status) {
+			continue
+		}
+		out = append(out, *d)
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out
+}
+
+// Redeliver: teslimi (durumu ne olursa olsun) deneme sayacı sıfırlanmış olarak kuyruğa geri alır
+func (s *Service) Redeliver(id string) (Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.st.Deliveries {
+		if d.ID != id {
+			continue
+		}
+		if s.hookIndexLocked(d.HookID) < 0 {
+			return Delivery{}, fmt.Errorf("webhook: hook %s was removed", d.HookID)
+		}
+		d.Status, d.Attempts, d.LastError, d.LastCode = StatusPending, 0, "", 0
+		d.NextAttempt, d.Updated = time.Now(), time.Now()
+		s.keys[d.Key] = true
+		s.signal()
+		return *d, s.saveLocked()
+	}
+	return Delivery{}, ErrNotFound
+}
+
+// Ping: kancaya "ping" olayı kuyruğa alır (alıcı ve imza testi için)
+func (s *Service) Ping(hookID string) (Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.hookIndexLocked(hookID) < 0 {
+		return Delivery{}, ErrNotFound
+	}
+	id := randomHex(8)
+	d := s.enqueueLocked(hookID, EventPing, EventPing+"|"+id, Notification{Hook: hookID, Height: -1})
+	return *d, s.saveLocked()
+}
+
+// Run: olay aboneliği ve teslim döngüsü (ctx iptal edilene kadar)
+func (s *Service) Run(ctx context.Context) {
+	sub := s.opt.Bus.Subscribe("webhook", 256, events.BlockConnected{}, events.BlockDisconnected{}, events.TxAccepted{})
+	defer sub.Close()
+	go s.deliverLoop(ctx)
+
+	s.scanTo(s.bc.GetBestHeight()) // kapalıyken bağlanan bloklar
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			switch e := ev.(type) {
+			case events.BlockConnected:
+				s.scanTo(e.Block.Index)
+			case events.BlockDisconnected:
+				s.blockDisconnected(e.Block)
+			case events.TxAccepted:
+				s.txAccepted(e.Tx)
+			}
+		}
+	}
+}
+
+// scanTo: tip yüksekliğine bağlanan blokları tarar. Son taranandan büyük
+// boşluklar (kapalıyken ya da düşen olaylar) doldurulur; reorg'da tip
+// yeniden taranır (Key tekrarları engeller).
+func (s *Service) scanTo(tip int) {
+	s.mu.Lock()
+	from := min(tip, s.st.LastHeight+1)
+	s.mu.Unlock()
+	for h := from; h <= tip; h++ {
+		s.scanHeight(h)
+	}
+	s.mu.Lock()
+	s.st.LastHeight = max(s.st.LastHeight, tip)
+	if err := s.saveLocked(); err != nil {
+		logger.Error("save state failed", "err", err)
+	}
+	s.mu.Unlock()
+}
+
+// scanHeight: zincir ucu h iken Confirmations onaya ulaşan işlemleri kuyruğa alır
+// (N onay isteyen kanca için h-N+1 yüksekliğindeki blok)
+func (s *Service) scanHeight(h int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	best := s.bc.GetBestHeight()
+	for _, hk := range s.st.Hooks {
+		if hk.Confirmations == 0 {
+			continue
+		}
+		target := h - hk.Confirmations + 1
+		if target <= hk.FromHeight || target < 0 {
+			continue
+		}
+		blk := s.bc.GetBlockByIndex(target)
+		if blk == nil {
+			continue
+		}
+		for _, tx := range blk.Transactions {
+			s.matchLocked(hk, tx, Notification{
+				Confirmations: best - target + 1,
+				Height:        target,
+				BlockHash:     hex.EncodeToString(blk.Hash),
+			})
+		}
+	}
+}
+
+// txAccepted: 0 onaylı kancalar için mempool eşleşmesi
+func (s *Service) txAccepted(tx *blockchain.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.st.Deliveries)
+	for _, hk := range s.st.Hooks {
+		if hk.Confirmations == 0 {
+			s.matchLocked(hk, tx, Notification{Height: -1})
+		}
+	}
+	if len(s.st.Deliveries) != n {
+		if err := s.saveLocked(); err != nil {
+			logger.Error("save state failed", "err", err)
+		}
+	}
+}
+
+// blockDisconnected: bloktaki işlemlerden yeni zincirde olmayanların bekleyen
+// teslimlerini iptal eder; Key serbest kalır, işlem yeniden onaylanırsa bildirilir
+func (s *Service) blockDisconnected(blk *blockchain.Block) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gone := map[string]bool{}
+	for _, tx := range blk.Transactions {
+		if _, _, ok := s.bc.FindTransaction(tx.ID); !ok {
+			gone[hex.EncodeToString(tx.ID)] = true
+		}
+	}
+	if len(gone) == 0 {
+		return
+	}
+	for _, d := range s.st.Deliveries {
+		if gone[d.TxID] && d.Event == EventPayment && d.Status == StatusPending {
+			s.finishLocked(d, StatusCancelled, "transaction reorged out")
+			delete(s.keys, d.Key)
+		}
+	}
+	if err := s.saveLocked(); err != nil {
+		logger.Error("save state failed", "err", err)
+	}
+}
+
+// matchLocked: tx kancanın adres/txid filtrelerine uyuyorsa eşleşme başına bildirim
+func (s *Service) matchLocked(hk *Hook, tx *blockchain.Transaction, n Notification) {
+	txid := hex.EncodeToString(tx.ID)
+	n.Hook, n.TxID, n.Coinbase = hk.ID, txid, tx.IsCoinbase()
+	for _, id := range hk.TxIDs {
+		if id != txid {
+			continue
+		}
+		n.Address, n.Amount = "", 0
+		for _, out := range tx.Outputs {
+			n.Amount += out.Amount
+		}
+		s.enqueueLocked(hk.ID, EventPayment, hk.ID+"|"+txid+"|", n)
+	}
+	for _, a := range hk.Addresses {
+		amount, found := 0, false
+		for i := range tx.Outputs {
+			if tx.Outputs[i].Address() == a {
+				amount += tx.Outputs[i].Amount
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		n.Address, n.Amount = a, amount
+		s.enqueueLocked(hk.ID, EventPayment, hk.ID+"|"+txid+"|"+a, n)
+	}
+}
+
+// enqueueLocked: Key daha önce kuyruğa alınmadıysa yeni teslim ekler (kaydetmek çağırana kalır)
+func (s *Service) enqueueLocked(hookID, event, key string, n Notification) *Delivery {
+	if s.keys[key] {
+		return nil
+	}
+	now := time.Now()
+	n.Delivery, n.Event, n.Timestamp = randomHex(8), event, now.Unix()
+	payload, _ := json.Marshal(n)
+	d := &Delivery{
+		ID: n.Delivery, HookID: hookID, Event: event, Key: key,
+		TxID: n.TxID, Address: n.Address, Payload: payload,
+		Status: StatusPending, NextAttempt: now, Created: now, Updated: now,
+	}
+	s.st.Deliveries = append(s.st.Deliveries, d)
+	s.keys[key] = true
+	s.pruneLocked()
+	s.signal()
+	return d
+}
+
+func (s *Service) finishLocked(d *Delivery, status, reason string) {
+	d.Status, d.Updated = status, time.Now()
+	if reason != "" {
+		d.LastError = reason
+	}
+}
+
+// pruneLocked: maxDeliveries'i aşan en eski tamamlanmış teslimleri atar
+func (s *Service) pruneLocked() {
+	extra := len(s.st.Deliveries) - maxDeliveries
+	if extra <= 0 {
+		return
+	}
+	kept := s.st.Deliveries[:0]
+	for _, d := range s.st.Deliveries {
+		if extra > 0 && d.Status != StatusPending {
+			extra--
+			continue
+		}
+		kept = append(kept, d)
+	}
+	s.st.Deliveries = kept
+	s.reindexLocked()
+}
+
+func (s *Service) reindexLocked() {
+	s.keys = map[string]bool{}
+	for _, d := range s.st.Deliveries {
+		if d.Status != StatusCancelled {
+			s.keys[d.Key] = true
+		}
+	}
+}
+
+func (s *Service) hookIndexLocked(id string) int {
+	for i, h := range s.st.Hooks {
+		if h.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Service) hookLocked(id string) *Hook {
+	if i := s.hookIndexLocked(id); i >= 0 {
+		return s.st.Hooks[i]
+	}
+	return nil
+}
+
+// signal: teslim döngüsünü uyandırır
+func (s *Service) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) load() error {
+	if s.opt.StateFile == "" {
+		return nil
+	}
+	b, err := os.ReadFile(s.opt.StateFile)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(b, &s.st); err != nil {
+			return fmt.Errorf("webhook state: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("webhook state: %w", err)
+	}
+	sort.SliceStable(s.st.Deliveries, func(i, j int) bool {
+		return s.st.Deliveries[i].Created.Before(s.st.Deliveries[j].Created)
+	})
+	return nil
+}
+
+func (s *Service) saveLocked() error {
+	if s.opt.StateFile == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(s.st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.opt.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil { // kanca sırlarını içerir
+		return err
+	}
+	return os.Rename(tmp, s.opt.StateFile) // atomik güncelleme
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
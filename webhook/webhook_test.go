@@ -0,0 +1,302 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"quantumcoin/blockchain"
+	"quantumcoin/config"
+	"quantumcoin/wallet"
+)
+
+// newRegtestChain: geçici dizinde regtest zinciri ve kalıcı durum dosyası yolu
+func newRegtestChain(t *testing.T) (*blockchain.Blockchain, string) {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	prev := config.Current()
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		config.Set(prev)
+		wallet.UseNetwork(prev.Params())
+	})
+	c := config.Default()
+	if err := c.UseNetwork(config.NetworkRegtest); err != nil {
+		t.Fatal(err)
+	}
+	config.Set(c)
+	wallet.UseNetwork(c.Params())
+	bc, err := blockchain.NewBlockchain(config.NetworkRegtest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bc, filepath.Join(dir, "webhook_state.json")
+}
+
+// request: alıcının gördüğü tek teslim denemesi
+type request struct {
+	body    []byte
+	sig     string
+	event   string
+	id      string
+	attempt int
+}
+
+// recorder: ilk failFirst isteğe 500 döner, sonra 204; tüm istekleri kaydeder
+type recorder struct {
+	mu        sync.Mutex
+	failFirst int
+	reqs      []request
+}
+
+func (rc *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	attempt, _ := strconv.Atoi(r.Header.Get(HeaderAttempt))
+	rc.mu.Lock()
+	rc.reqs = append(rc.reqs, request{
+		body: body, sig: r.Header.Get(HeaderSignature), event: r.Header.Get(HeaderEvent),
+		id: r.Header.Get(HeaderDelivery), attempt: attempt,
+	})
+	fail := len(rc.reqs) <= rc.failFirst
+	rc.mu.Unlock()
+	if fail {
+		http.Error(w, "try later", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *recorder) requests() []request {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]request(nil), rc.reqs...)
+}
+
+// makeDue: bekleyen teslimlerin geri çekilme süresini atlar
+func makeDue(s *Service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.st.Deliveries {
+		d.NextAttempt = time.Now().Add(-time.Second)
+	}
+}
+
+func onlyDelivery(t *testing.T, s *Service) Delivery {
+	t.Helper()
+	ds := s.Deliveries("", "", 0)
+	if len(ds) != 1 {
+		t.Fatalf("%d deliveries, want 1", len(ds))
+	}
+	return ds[0]
+}
+
+func TestDeliverySignedAndRetried(t *testing.T) {
+	bc, stateFile := newRegtestChain(t)
+	rc := &recorder{failFirst: 2}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	s, err := New(bc, Options{StateFile: stateFile, MaxAttempts: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hk, err := s.Register(Hook{URL: srv.URL, Addresses: []string{wallet.NewWallet().GetAddress()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := s.Ping(hk.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	s.deliverDue(ctx)
+	got := onlyDelivery(t, s)
+	if got.Status != StatusPending || got.Attempts != 1 || got.LastCode != http.StatusInternalServerError {
+		t.Fatalf("after first failure: %+v", got)
+	}
+	if !got.NextAttempt.After(time.Now()) {
+		t.Fatal("failed delivery was not backed off")
+	}
+	s.deliverDue(ctx) // geri çekilme dolmadan gönderilmez
+	if n := len(rc.requests()); n != 1 {
+		t.Fatalf("%d requests before backoff elapsed, want 1", n)
+	}
+
+	for i := 0; i < 2; i++ {
+		makeDue(s)
+		s.deliverDue(ctx)
+	}
+	got = onlyDelivery(t, s)
+	if got.Status != StatusDelivered || got.Attempts != 3 || got.LastError != "" {
+		t.Fatalf("after retries: %+v", got)
+	}
+
+	reqs := rc.requests()
+	if len(reqs) != 3 {
+		t.Fatalf("%d requests, want 3", len(reqs))
+	}
+	for i, r := range reqs {
+		if !Verify(hk.Secret, r.body, r.sig) {
+			t.Fatalf("request %d: bad signature %q", i, r.sig)
+		}
+		if Verify("wrong secret", r.body, r.sig) {
+			t.Fatalf("request %d: signature verified with another secret", i)
+		}
+		if r.id != d.ID || r.event != EventPing || r.attempt != i+1 {
+			t.Fatalf("request %d: id=%s event=%s attempt=%d", i, r.id, r.event, r.attempt)
+		}
+		var n Notification
+		if err := json.Unmarshal(r.body, &n); err != nil {
+			t.Fatal(err)
+		}
+		if n.Delivery != d.ID || n.Hook != hk.ID {
+			t.Fatalf("request %d: notification %+v", i, n)
+		}
+	}
+
+	// Receiver aynı imzayı kabul eder, kurcalanmış gövdeyi reddeder
+	var received []Notification
+	h := Receiver(hk.Secret, func(n Notification) { received = append(received, n) })
+	for _, tc := range []struct {
+		body []byte
+		code int
+	}{
+		{reqs[0].body, http.StatusNoContent},
+		{append(append([]byte{}, reqs[0].body...), ' '), http.StatusUnauthorized},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tc.body))
+		req.Header.Set(HeaderSignature, reqs[0].sig)
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.code {
+			t.Fatalf("receiver code = %d, want %d", rec.Code, tc.code)
+		}
+	}
+	if len(received) != 1 || received[0].Delivery != d.ID {
+		t.Fatalf("receiver got %+v", received)
+	}
+}
+
+func TestDeliveryGivesUpAfterMaxAttempts(t *testing.T) {
+	bc, stateFile := newRegtestChain(t)
+	rc := &recorder{failFirst: 100}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	s, err := New(bc, Options{StateFile: stateFile, MaxAttempts: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hk, err := s.Register(Hook{URL: srv.URL, Addresses: []string{wallet.NewWallet().GetAddress()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Ping(hk.ID); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		makeDue(s)
+		s.deliverDue(context.Background())
+	}
+	got := onlyDelivery(t, s)
+	if got.Status != StatusFailed || got.Attempts != 2 {
+		t.Fatalf("delivery %+v, want failed after 2 attempts", got)
+	}
+	if n := len(rc.requests()); n != 2 {
+		t.Fatalf("%d requests, want 2", n)
+	}
+}
+
+// TestDeliveryReplayedAfterRestart: ödeme bildirimi kalıcı kuyruktan yeni
+// servis örneğiyle (aynı durum dosyası) teslim edilir ve tekrar üretilmez
+func TestDeliveryReplayedAfterRestart(t *testing.T) {
+	bc, stateFile := newRegtestChain(t)
+	rc := &recorder{failFirst: 1}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	s1, err := New(bc, Options{StateFile: stateFile, MaxAttempts: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	miner := wallet.NewWallet().GetAddress()
+	hk, err := s1.Register(Hook{URL: srv.URL, Addresses: []string{miner}, Confirmations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bc.MineBlock(miner, 1); err != nil {
+		t.Fatal(err)
+	}
+	s1.scanTo(bc.GetBestHeight())
+	s1.deliverDue(context.Background())
+	first := onlyDelivery(t, s1)
+	if first.Event != EventPayment || first.Status != StatusPending || first.Attempts != 1 {
+		t.Fatalf("before restart: %+v", first)
+	}
+
+	// yeniden başlatma: aynı durum dosyası
+	s2, err := New(bc, Options{StateFile: stateFile, MaxAttempts: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2.scanTo(bc.GetBestHeight()) // aynı blok yeniden taranır; Key tekrar kuyruğa almaz
+	makeDue(s2)
+	s2.deliverDue(context.Background())
+	got := onlyDelivery(t, s2)
+	if got.ID != first.ID || got.Status != StatusDelivered || got.Attempts != 2 {
+		t.Fatalf("after restart: %+v", got)
+	}
+
+	reqs := rc.requests()
+	if len(reqs) != 2 {
+		t.Fatalf("%d requests, want 2", len(reqs))
+	}
+	last := reqs[1]
+	if !Verify(hk.Secret, last.body, last.sig) || last.id != first.ID || last.attempt != 2 {
+		t.Fatalf("replayed request: id=%s attempt=%d sig ok=%v", last.id, last.attempt, Verify(hk.Secret, last.body, last.sig))
+	}
+	var n Notification
+	if err := json.Unmarshal(last.body, &n); err != nil {
+		t.Fatal(err)
+	}
+	if n.Address != miner || n.Amount <= 0 || !n.Coinbase || n.Height != bc.GetBestHeight() || n.Confirmations != 1 {
+		t.Fatalf("replayed notification %+v", n)
+	}
+}
+
+func TestRegisterDoesNotModifyFilters(t *testing.T) {
+	bc, _ := newRegtestChain(t)
+	s, err := New(bc, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs := []string{"  " + wallet.NewWallet().GetAddress() + " "}
+	txids := []string{" ABCDEF "}
+	hk, err := s.Register(Hook{URL: "http://127.0.0.1:1/hook", Addresses: addrs, TxIDs: txids})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addrs[0][0] != ' ' || txids[0] != " ABCDEF " {
+		t.Fatalf("caller slices modified: %q %q", addrs, txids)
+	}
+	if hk.TxIDs[0] != "abcdef" || hk.Addresses[0][0] == ' ' {
+		t.Fatalf("filters not normalized: %q %q", hk.Addresses, hk.TxIDs)
+	}
+}